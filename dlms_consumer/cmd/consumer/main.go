@@ -2,22 +2,69 @@ package main
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"dlms_consumer/proto"
+	"flag"
 	"fmt"
 	"io"
 	"log"
 	"log/slog"
+	"os"
 	"time"
 
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/credentials/insecure"
 	"google.golang.org/grpc/status"
 )
 
+var (
+	addr       = flag.String("addr", "localhost:50051", "processor address")
+	useTLS     = flag.Bool("tls", false, "connect over TLS")
+	caFile     = flag.String("ca-file", "", "CA certificate that signed the server certificate; system roots if empty")
+	certFile   = flag.String("cert-file", "", "client certificate, for servers that require one")
+	keyFile    = flag.String("key-file", "", "client certificate key")
+	serverName = flag.String("server-name", "", "expected server name, if it differs from the address")
+	token      = flag.String("token", "", "bearer token, for servers with an authorization policy")
+	keyRef     = flag.Bool("key-ref", false, "send key references for the processor's key provider instead of the meter keys")
+	otlpAddr   = flag.String("otlp-endpoint", "", "OTLP/gRPC collector to send traces to, e.g. localhost:4317; tracing is off if empty")
+)
+
 func main() {
+	flag.Parse()
+
 	fmt.Println("Starting DLMS Consumer")
-	conn, err := grpc.NewClient("localhost:50051", grpc.WithTransportCredentials(insecure.NewCredentials()))
+	creds, err := transportCredentials()
+	if err != nil {
+		log.Fatalf("Failed to set up TLS: %v", err)
+	}
+	shutdownTracing, err := setupTracing(context.Background())
+	if err != nil {
+		log.Fatalf("Failed to set up tracing: %v", err)
+	}
+	defer shutdownTracing()
+
+	// Every call below belongs to one trace, continued by the processor
+	ctx, span := otel.Tracer("dlms_consumer").Start(context.Background(), "consumer run")
+	defer span.End()
+
+	opts := []grpc.DialOption{
+		grpc.WithTransportCredentials(creds),
+		grpc.WithStatsHandler(otelgrpc.NewClientHandler()),
+	}
+	if *token != "" {
+		opts = append(opts, grpc.WithPerRPCCredentials(bearerToken(*token)))
+	}
+	conn, err := grpc.NewClient(*addr, opts...)
 	if err != nil {
 		log.Fatalf("Failed to connect to server: %v", err)
 	}
@@ -32,9 +79,10 @@ func main() {
 
 	fmt.Println("Getting OBIS")
 
-	stream, err := client.GetOBIS(context.Background(), &proto.GetOBISRequest{
+	stream, err := client.GetOBIS(ctx, &proto.GetOBISRequest{
 		Meter: []*proto.Meter{
-			{
+			meterKeys(&proto.Meter{
+				MeterId:        "meter-0002",
 				Ip:             "2401:4900:833f:2688:0000:0000:0000:0002",
 				Port:           40591,
 				SystemTitle:    "6162636465666768",
@@ -44,7 +92,7 @@ func main() {
 				ClientAddress:  "48",
 				ServerAddress:  "1",
 				Obis:           "1.0.1.8.0.255",
-			},
+			}),
 		},
 		Obis: "1.0.1.8.0.255",
 	})
@@ -98,9 +146,10 @@ func main() {
 	// Now test GetBlockLoadProfile
 	fmt.Println("\n=== Getting Block Load Profile ===")
 
-	profileStream, err := client.GetBlockLoadProfile(context.Background(), &proto.GetBlockLoadProfileRequest{
+	profileStream, err := client.GetBlockLoadProfile(ctx, &proto.GetBlockLoadProfileRequest{
 		Meter: []*proto.Meter{
-			{
+			meterKeys(&proto.Meter{
+				MeterId:        "meter-0002",
 				Ip:             "2401:4900:833f:2688:0000:0000:0000:0002",
 				Port:           4059,
 				SystemTitle:    "6162636465666768",
@@ -110,7 +159,7 @@ func main() {
 				ClientAddress:  "48",
 				ServerAddress:  "1",
 				Obis:           "1.0.1.8.0.255",
-			},
+			}),
 		},
 		Retries:           3,
 		RetryDelay:        1000,
@@ -145,7 +194,7 @@ func main() {
 			log.Fatalf("Block Load Profile response is nil")
 		}
 
-		fmt.Printf("Received Block Load Profile from meter %s:\n", profileResp.MeterIp)
+		fmt.Printf("Received Block Load Profile from meter %s (%s):\n", profileResp.MeterId, profileResp.MeterIp)
 		profile := profileResp.Profile
 		fmt.Printf("  DateTime: %s\n", profile.DateTime)
 		fmt.Printf("  Average Voltage: %.2f V\n", profile.AverageVoltage)
@@ -166,9 +215,10 @@ func main() {
 	// Test GetDailyLoadProfile
 	fmt.Println("\n=== Getting Daily Load Profile ===")
 
-	dailyProfileStream, err := client.GetDailyLoadProfile(context.Background(), &proto.GetDailyLoadProfileRequest{
+	dailyProfileStream, err := client.GetDailyLoadProfile(ctx, &proto.GetDailyLoadProfileRequest{
 		Meter: []*proto.Meter{
-			{
+			meterKeys(&proto.Meter{
+				MeterId:        "meter-0002",
 				Ip:             "2401:4900:833f:2688:0000:0000:0000:0002",
 				Port:           4059,
 				SystemTitle:    "6162636465666768",
@@ -177,7 +227,7 @@ func main() {
 				AuthKey:        "49423031494230324942303349423034",
 				ClientAddress:  "48",
 				ServerAddress:  "1",
-			},
+			}),
 		},
 		Retries:           3,
 		RetryDelay:        1000,
@@ -212,7 +262,7 @@ func main() {
 			log.Fatalf("Daily Load Profile response is nil")
 		}
 
-		fmt.Printf("Received Daily Load Profile from meter %s:\n", dailyResp.MeterIp)
+		fmt.Printf("Received Daily Load Profile from meter %s (%s):\n", dailyResp.MeterId, dailyResp.MeterIp)
 		daily := dailyResp.Profile
 		fmt.Printf("  DateTime: %s\n", daily.DateTime)
 		fmt.Printf("  Cumulative Energy Wh Export: %.2f Wh\n", daily.CumulativeEnergyWhExport)
@@ -230,9 +280,10 @@ func main() {
 	// Test GetBillingDataProfile
 	fmt.Println("\n=== Getting Billing Data Profile ===")
 
-	billingProfileStream, err := client.GetBillingDataProfile(context.Background(), &proto.GetBillingDataProfileRequest{
+	billingProfileStream, err := client.GetBillingDataProfile(ctx, &proto.GetBillingDataProfileRequest{
 		Meter: []*proto.Meter{
-			{
+			meterKeys(&proto.Meter{
+				MeterId:        "meter-0002",
 				Ip:             "2401:4900:833f:2688:0000:0000:0000:0002",
 				Port:           4059,
 				SystemTitle:    "6162636465666768",
@@ -241,7 +292,7 @@ func main() {
 				AuthKey:        "49423031494230324942303349423034",
 				ClientAddress:  "48",
 				ServerAddress:  "1",
-			},
+			}),
 		},
 		Retries:           3,
 		RetryDelay:        1000,
@@ -276,20 +327,16 @@ func main() {
 			log.Fatalf("Billing Data Profile response is nil")
 		}
 
-		fmt.Printf("Received Billing Data Profile from meter %s:\n", billingResp.MeterIp)
+		fmt.Printf("Received Billing Data Profile from meter %s (%s):\n", billingResp.MeterId, billingResp.MeterIp)
 		billing := billingResp.Profile
 		fmt.Printf("  Billing Date: %s\n", billing.BillingDate)
 		fmt.Printf("  Average PF for Billing Period: %.3f\n", billing.AveragePfForBillingPeriod)
 		fmt.Printf("  Cumulative Energy Wh Import: %.2f Wh\n", billing.CumEnergyWhImport)
-		fmt.Printf("  Cumulative Energy Wh TZ1: %.2f Wh\n", billing.CumEnergyWhTz1)
-		fmt.Printf("  Cumulative Energy Wh TZ2: %.2f Wh\n", billing.CumEnergyWhTz2)
-		fmt.Printf("  Cumulative Energy Wh TZ3: %.2f Wh\n", billing.CumEnergyWhTz3)
-		fmt.Printf("  Cumulative Energy Wh TZ4: %.2f Wh\n", billing.CumEnergyWhTz4)
 		fmt.Printf("  Cumulative Energy VAh Import: %.2f VAh\n", billing.CumEnergyVahImport)
-		fmt.Printf("  Cumulative Energy VAh TZ1: %.2f VAh\n", billing.CumEnergyVahTz1)
-		fmt.Printf("  Cumulative Energy VAh TZ2: %.2f VAh\n", billing.CumEnergyVahTz2)
-		fmt.Printf("  Cumulative Energy VAh TZ3: %.2f VAh\n", billing.CumEnergyVahTz3)
-		fmt.Printf("  Cumulative Energy VAh TZ4: %.2f VAh\n", billing.CumEnergyVahTz4)
+		for _, zone := range billing.TariffZones {
+			fmt.Printf("  TZ%d: %.2f Wh, %.2f VAh, MD %.2f W at %s, MD %.2f VA at %s\n", zone.Zone,
+				zone.CumEnergyWh, zone.CumEnergyVah, zone.Mdw, zone.MdwDateTime, zone.Mdva, zone.MdvaDateTime)
+		}
 		fmt.Printf("  MD W: %.2f W\n", billing.Mdw)
 		fmt.Printf("  MD W DateTime: %s\n", billing.MdwDateTime)
 		fmt.Printf("  MD VA: %.2f VA\n", billing.Mdva)
@@ -308,9 +355,10 @@ func main() {
 	// Test GetInstantaneousProfile
 	fmt.Println("\n=== Getting Instantaneous Profile ===")
 
-	instantProfileStream, err := client.GetInstantaneousProfile(context.Background(), &proto.GetInstantaneousProfileRequest{
+	instantProfileStream, err := client.GetInstantaneousProfile(ctx, &proto.GetInstantaneousProfileRequest{
 		Meter: []*proto.Meter{
-			{
+			meterKeys(&proto.Meter{
+				MeterId:        "meter-0002",
 				Ip:             "2401:4900:833f:2688:0000:0000:0000:0002",
 				Port:           4059,
 				SystemTitle:    "6162636465666768",
@@ -319,7 +367,7 @@ func main() {
 				AuthKey:        "49423031494230324942303349423034",
 				ClientAddress:  "48",
 				ServerAddress:  "1",
-			},
+			}),
 		},
 		Retries:           3,
 		RetryDelay:        1000,
@@ -354,7 +402,7 @@ func main() {
 			log.Fatalf("Instantaneous Profile response is nil")
 		}
 
-		fmt.Printf("Received Instantaneous Profile from meter %s:\n", instantResp.MeterIp)
+		fmt.Printf("Received Instantaneous Profile from meter %s (%s):\n", instantResp.MeterId, instantResp.MeterIp)
 		instant := instantResp.Profile
 		fmt.Printf("  DateTime: %s\n", instant.DateTime)
 		fmt.Printf("  Voltage: %.2f V\n", instant.Voltage)
@@ -370,3 +418,79 @@ func main() {
 
 	fmt.Println("Done with Instantaneous Profile")
 }
+
+// transportCredentials builds the connection credentials from the flags,
+// falling back to plaintext unless -tls or a certificate flag is given
+func transportCredentials() (credentials.TransportCredentials, error) {
+	if !*useTLS && *caFile == "" && *certFile == "" {
+		return insecure.NewCredentials(), nil
+	}
+
+	cfg := &tls.Config{MinVersion: tls.VersionTLS12, ServerName: *serverName}
+	if *caFile != "" {
+		pem, err := os.ReadFile(*caFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA file: %w", err)
+		}
+		cfg.RootCAs = x509.NewCertPool()
+		if !cfg.RootCAs.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in %s", *caFile)
+		}
+	}
+	if *certFile != "" || *keyFile != "" {
+		cert, err := tls.LoadX509KeyPair(*certFile, *keyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate: %w", err)
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+	return credentials.NewTLS(cfg), nil
+}
+
+// meterKeys replaces the meter's keys with a reference to its current keys
+// when -key-ref is set
+func meterKeys(m *proto.Meter) *proto.Meter {
+	if *keyRef {
+		m.AuthPassword, m.AuthKey, m.BlockCipherKey = "", "", ""
+		m.KeyRef = &proto.KeyRef{}
+	}
+	return m
+}
+
+// setupTracing exports spans to -otlp-endpoint and propagates the trace
+// context to the processor. The returned function flushes pending spans.
+func setupTracing(ctx context.Context) (func(), error) {
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	if *otlpAddr == "" {
+		return func() {}, nil
+	}
+
+	exporter, err := otlptracegrpc.New(ctx, otlptracegrpc.WithEndpoint(*otlpAddr), otlptracegrpc.WithInsecure())
+	if err != nil {
+		return nil, fmt.Errorf("failed to create OTLP exporter: %w", err)
+	}
+	res := resource.NewSchemaless(attribute.String("service.name", "dlms-consumer"))
+	provider := sdktrace.NewTracerProvider(sdktrace.WithBatcher(exporter), sdktrace.WithResource(res))
+	otel.SetTracerProvider(provider)
+
+	return func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := provider.Shutdown(ctx); err != nil {
+			slog.Error("Failed to flush traces", "error", err)
+		}
+	}, nil
+}
+
+// bearerToken sends a token in the authorization metadata of every call
+type bearerToken string
+
+func (t bearerToken) GetRequestMetadata(ctx context.Context, uri ...string) (map[string]string, error) {
+	return map[string]string{"authorization": "Bearer " + string(t)}, nil
+}
+
+// RequireTransportSecurity is false so tokens also work against a plaintext
+// development server
+func (t bearerToken) RequireTransportSecurity() bool {
+	return false
+}
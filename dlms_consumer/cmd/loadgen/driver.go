@@ -0,0 +1,138 @@
+package main
+
+import (
+	"context"
+	"dlms_consumer/proto"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc/codes"
+)
+
+// maxCredits bounds the credits a stream may hold, far above any
+// processor's window
+const maxCredits = 1 << 16
+
+// dispatch sends the fleet's meters to the processors' queues in turn, at
+// rate meters a minute if it is positive, until every meter is sent, the
+// duration if positive has passed or ctx is done. It closes the queues.
+func dispatch(ctx context.Context, size int, rate float64, duration time.Duration, queues []chan int) {
+	defer func() {
+		for _, q := range queues {
+			close(q)
+		}
+	}()
+
+	var interval time.Duration
+	if rate > 0 {
+		interval = time.Duration(float64(time.Minute) / rate)
+	}
+	start := time.Now()
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+	for i := range size {
+		due := start.Add(time.Duration(i) * interval)
+		if duration > 0 && (due.Sub(start) >= duration || time.Since(start) >= duration) {
+			return
+		}
+		if d := time.Until(due); d > 0 {
+			timer.Reset(d)
+			select {
+			case <-timer.C:
+			case <-ctx.Done():
+				return
+			}
+		}
+		select {
+		case queues[i%len(queues)] <- i:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// drive reads the meters of jobs on a Process stream, sending as many as
+// the processor's credits allow, until jobs is closed and every read has
+// completed
+func drive(ctx context.Context, client proto.DLMSProcessorClient, f *fleet, jobs <-chan int, st *stats) error {
+	stream, err := client.Process(ctx)
+	if err != nil {
+		return err
+	}
+
+	credits := make(chan struct{}, maxCredits)
+	var mu sync.Mutex
+	started := make(map[string]time.Time)
+
+	recvErr := make(chan error, 1)
+	go func() {
+		for {
+			resp, err := stream.Recv()
+			if err != nil {
+				recvErr <- err
+				return
+			}
+			for range min(resp.Credits, maxCredits) {
+				credits <- struct{}{}
+			}
+			mu.Lock()
+			t, ok := started[resp.CorrelationId]
+			delete(started, resp.CorrelationId)
+			mu.Unlock()
+			if ok {
+				st.done(time.Since(t), failure(resp))
+			}
+		}
+	}()
+
+	for {
+		select {
+		case <-credits:
+		case err := <-recvErr:
+			return fmt.Errorf("stream ended early: %w", err)
+		}
+		var i int
+		var ok bool
+		select {
+		case i, ok = <-jobs:
+		case err := <-recvErr:
+			return fmt.Errorf("stream ended early: %w", err)
+		}
+		if !ok {
+			break
+		}
+
+		req := f.request(i)
+		mu.Lock()
+		started[req.CorrelationId] = time.Now()
+		mu.Unlock()
+		st.sending()
+		if err := stream.Send(req); err != nil {
+			return err
+		}
+	}
+
+	// The processor ends the stream once the reads sent have completed
+	if err := stream.CloseSend(); err != nil {
+		return err
+	}
+	if err := <-recvErr; !errors.Is(err, io.EOF) {
+		return err
+	}
+	return nil
+}
+
+// failure returns why a read failed, empty if it did not
+func failure(resp *proto.ProcessResponse) string {
+	e := resp.GetError()
+	if e == nil {
+		return ""
+	}
+	if e.Reason != "" {
+		return e.Reason
+	}
+	return codes.Code(e.Code).String()
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"dlms_consumer/proto"
+	"fmt"
+	"net/netip"
+)
+
+// fleet synthesizes the virtual meters of a load test. Meter i has the id
+// meter-<i>, an address in fd00::/64 derived from i and the same keys as
+// every other; the processor's fake or simulated backend answers for it.
+type fleet struct {
+	size       int
+	port       int32
+	keys       string // block cipher and authentication key, in hex
+	obis       string
+	profile    proto.ProfileType
+	timeout    int32 // ms
+	connection int32 // ms
+}
+
+// meter returns virtual meter i
+func (f *fleet) meter(i int) *proto.Meter {
+	var addr [16]byte
+	addr[0], addr[1] = 0xfd, 0x00
+	for b, v := 15, uint64(i)+1; b >= 8 && v > 0; b, v = b-1, v>>8 {
+		addr[b] = byte(v)
+	}
+	return &proto.Meter{
+		MeterId:        fmt.Sprintf("meter-%07d", i),
+		Ip:             netip.AddrFrom16(addr).String(),
+		Port:           f.port,
+		SystemTitle:    "4c4f414447454e31",
+		BlockCipherKey: f.keys,
+		AuthKey:        f.keys,
+		ClientAddress:  "48",
+		ServerAddress:  "1",
+	}
+}
+
+// request returns the read of virtual meter i
+func (f *fleet) request(i int) *proto.ProcessRequest {
+	read := &proto.ReadOperation{Target: &proto.ReadOperation_Profile{Profile: f.profile}}
+	if f.obis != "" {
+		read.Target = &proto.ReadOperation_Attribute{Attribute: &proto.AttributeReference{Obis: f.obis, ObjectType: 3, AttributeIndex: 2}}
+	}
+	return &proto.ProcessRequest{
+		CorrelationId:     fmt.Sprint(i),
+		Meter:             f.meter(i),
+		Timeout:           f.timeout,
+		ConnectionTimeout: f.connection,
+		Operation:         &proto.ProcessRequest_Read{Read: read},
+	}
+}
+
+// profiles are the profiles -read names
+var profiles = map[string]proto.ProfileType{
+	"block-load":    proto.ProfileType_PROFILE_TYPE_BLOCK_LOAD,
+	"daily-load":    proto.ProfileType_PROFILE_TYPE_DAILY_LOAD,
+	"billing":       proto.ProfileType_PROFILE_TYPE_BILLING_DATA,
+	"instantaneous": proto.ProfileType_PROFILE_TYPE_INSTANTANEOUS,
+	"nameplate":     proto.ProfileType_PROFILE_TYPE_NAMEPLATE,
+}
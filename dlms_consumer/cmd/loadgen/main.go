@@ -0,0 +1,185 @@
+// Command loadgen drives processors with the reads of a fleet of virtual
+// meters and reports whether they keep up, to validate the sizing of
+// 1,000,000 meters in 10 minutes across 4 processors: 25,000 devices a
+// minute each. The processors answer for the virtual meters with their fake
+// or simulated backend (-meter-backend), whose latency and failures the
+// processors' flags set.
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"dlms_consumer/proto"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+var (
+	addrs       = flag.String("addr", "localhost:50051", "processor addresses, comma separated; the meters are spread over them")
+	metricsURLs = flag.String("metrics", "", "processors' /metrics URLs, comma separated, e.g. http://localhost:8080/metrics")
+	meters      = flag.Int("meters", 1000000, "virtual meters to read, each once")
+	rate        = flag.Float64("rate", 100000, "meters sent a minute across all processors, 0 as fast as the processors take them")
+	duration    = flag.Duration("duration", 0, "stop sending after this long, 0 to send every meter")
+	streams     = flag.Int("streams", 4, "Process streams per processor")
+	read        = flag.String("read", "block-load", "profile to read: block-load, daily-load, billing, instantaneous or nameplate")
+	obis        = flag.String("obis", "", "register to read instead of a profile")
+	timeout     = flag.Duration("timeout", 30*time.Second, "timeout of each read")
+	connTimeout = flag.Duration("connection-timeout", 5*time.Second, "timeout of each I/O step with a meter")
+	port        = flag.Int("port", 4059, "port of the virtual meters")
+	keys        = flag.String("keys", "62626262626262626262626262626262", "block cipher and authentication key of the virtual meters, in hex")
+	interval    = flag.Duration("report", 10*time.Second, "how often to report progress")
+	target      = flag.Float64("target", 25000, "devices a minute each processor must read, 0 for no target; loadgen exits with 1 below it")
+	useTLS      = flag.Bool("tls", false, "connect over TLS")
+	caFile      = flag.String("ca-file", "", "CA certificate that signed the server certificates; system roots if empty")
+	token       = flag.String("token", "", "bearer token, for servers with an authorization policy")
+)
+
+func main() {
+	flag.Parse()
+
+	profile, ok := profiles[*read]
+	if !ok && *obis == "" {
+		log.Fatalf("Unknown profile %q", *read)
+	}
+	f := &fleet{
+		size:       *meters,
+		port:       int32(*port),
+		keys:       *keys,
+		obis:       *obis,
+		profile:    profile,
+		timeout:    int32(timeout.Milliseconds()),
+		connection: int32(connTimeout.Milliseconds()),
+	}
+	processors := split(*addrs)
+	scraped := split(*metricsURLs)
+	if len(processors) == 0 || *streams <= 0 || *meters <= 0 {
+		log.Fatal("Need a processor, a stream and a meter")
+	}
+
+	creds, err := transportCredentials()
+	if err != nil {
+		log.Fatalf("Failed to set up TLS: %v", err)
+	}
+	opts := []grpc.DialOption{grpc.WithTransportCredentials(creds)}
+	if *token != "" {
+		opts = append(opts, grpc.WithPerRPCCredentials(bearerToken(*token)))
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	fmt.Printf("Reading %d virtual meters at %.0f/min over %d processors, %d streams each\n", *meters, *rate, len(processors), *streams)
+	st := newStats()
+	queues := make([]chan int, len(processors))
+	var wg sync.WaitGroup
+	for p, addr := range processors {
+		conn, err := grpc.NewClient(addr, opts...)
+		if err != nil {
+			log.Fatalf("Failed to connect to %s: %v", addr, err)
+		}
+		defer conn.Close()
+		client := proto.NewDLMSProcessorClient(conn)
+
+		queues[p] = make(chan int)
+		for range *streams {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if err := drive(ctx, client, f, queues[p], st); err != nil {
+					log.Printf("Stream to %s failed: %v", addr, err)
+				}
+			}()
+		}
+	}
+	go dispatch(ctx, *meters, *rate, *duration, queues)
+
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	peaks := make(map[string]processorStats)
+	ticker := time.NewTicker(*interval)
+	defer ticker.Stop()
+	for running := true; running; {
+		select {
+		case <-ticker.C:
+		case <-done:
+			running = false
+		}
+		fmt.Println(st.interval())
+		fmt.Printf("        loadgen %s\n", runtimeStats())
+		for _, url := range scraped {
+			p, err := scrape(ctx, url)
+			if err != nil {
+				fmt.Printf("        %s: %v\n", url, err)
+				continue
+			}
+			peaks[url] = peaks[url].max(p)
+			fmt.Printf("        %s: %v\n", url, p)
+		}
+	}
+
+	summary, met := st.summary(len(processors), *target, peaks)
+	fmt.Print(summary)
+	if !met {
+		os.Exit(1)
+	}
+}
+
+// split splits a comma separated list, dropping empty entries
+func split(list string) []string {
+	var out []string
+	for _, s := range strings.Split(list, ",") {
+		if s = strings.TrimSpace(s); s != "" {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// transportCredentials builds the connection credentials from the flags,
+// falling back to plaintext unless -tls or -ca-file is given
+func transportCredentials() (credentials.TransportCredentials, error) {
+	if !*useTLS && *caFile == "" {
+		return insecure.NewCredentials(), nil
+	}
+
+	cfg := &tls.Config{MinVersion: tls.VersionTLS12}
+	if *caFile != "" {
+		pem, err := os.ReadFile(*caFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA file: %w", err)
+		}
+		cfg.RootCAs = x509.NewCertPool()
+		if !cfg.RootCAs.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in %s", *caFile)
+		}
+	}
+	return credentials.NewTLS(cfg), nil
+}
+
+// bearerToken sends a token in the authorization metadata of every call
+type bearerToken string
+
+func (t bearerToken) GetRequestMetadata(ctx context.Context, uri ...string) (map[string]string, error) {
+	return map[string]string{"authorization": "Bearer " + string(t)}, nil
+}
+
+// RequireTransportSecurity is false so tokens also work against a plaintext
+// development server
+func (t bearerToken) RequireTransportSecurity() bool {
+	return false
+}
@@ -0,0 +1,218 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"runtime"
+	"runtime/pprof"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// stats collects the outcome of every read of a load test
+type stats struct {
+	start time.Time
+
+	mu        sync.Mutex
+	latencies []time.Duration // of every completed read, in completion order
+	failures  map[string]int  // by reason, or gRPC code when there is none
+	reported  int             // reads completed at the last report
+	lastAt    time.Time
+	sent      int
+}
+
+func newStats() *stats {
+	now := time.Now()
+	return &stats{start: now, lastAt: now, failures: make(map[string]int)}
+}
+
+// sending counts a read sent to a processor
+func (s *stats) sending() {
+	s.mu.Lock()
+	s.sent++
+	s.mu.Unlock()
+}
+
+// done records a read that completed after d, failed for reason unless it
+// is empty
+func (s *stats) done(d time.Duration, reason string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.latencies = append(s.latencies, d)
+	if reason != "" {
+		s.failures[reason]++
+	}
+}
+
+// failed counts failed reads
+func (s *stats) failed() int {
+	n := 0
+	for _, count := range s.failures {
+		n += count
+	}
+	return n
+}
+
+// percentiles formats the 50th, 90th and 99th percentile and the maximum of
+// latencies, which it sorts
+func percentiles(latencies []time.Duration) string {
+	if len(latencies) == 0 {
+		return "p50 - p90 - p99 - max -"
+	}
+	slices.Sort(latencies)
+	at := func(p float64) time.Duration {
+		return latencies[min(len(latencies)-1, int(p*float64(len(latencies))))].Round(time.Millisecond)
+	}
+	return fmt.Sprintf("p50 %v p90 %v p99 %v max %v", at(0.5), at(0.9), at(0.99), latencies[len(latencies)-1].Round(time.Millisecond))
+}
+
+// interval returns a report line of the reads completed since the last one
+func (s *stats) interval() string {
+	s.mu.Lock()
+	now := time.Now()
+	latest := slices.Clone(s.latencies[s.reported:])
+	completed, sent, failed := len(s.latencies), s.sent, s.failed()
+	elapsed := now.Sub(s.lastAt)
+	s.reported, s.lastAt = completed, now
+	s.mu.Unlock()
+
+	return fmt.Sprintf("%6s  done %d (%d failed)  in flight %d  %.0f/min  %s",
+		now.Sub(s.start).Round(time.Second), completed, failed, sent-completed,
+		float64(len(latest))/elapsed.Minutes(), percentiles(latest))
+}
+
+// runtimeStats formats the goroutines, threads and heap of the load
+// generator itself
+func runtimeStats() string {
+	var m runtime.MemStats
+	runtime.ReadMemStats(&m)
+	return fmt.Sprintf("goroutines %d  threads %d  heap %s",
+		runtime.NumGoroutine(), pprof.Lookup("threadcreate").Count(), bytes(m.HeapInuse))
+}
+
+// bytes formats a size in MiB
+func bytes(n uint64) string {
+	return fmt.Sprintf("%.1f MiB", float64(n)/(1<<20))
+}
+
+// processorStats is what a processor reports of itself on /metrics
+type processorStats struct {
+	goroutines, threads float64
+	rss, heap           float64 // bytes
+	inFlight, queued    float64 // meter operations running and waiting for a worker
+}
+
+func (p processorStats) String() string {
+	return fmt.Sprintf("goroutines %.0f  threads %.0f  rss %s  heap %s  operations %.0f  queued %.0f",
+		p.goroutines, p.threads, bytes(uint64(p.rss)), bytes(uint64(p.heap)), p.inFlight, p.queued)
+}
+
+// max returns the larger of each of p's and o's values
+func (p processorStats) max(o processorStats) processorStats {
+	return processorStats{
+		goroutines: max(p.goroutines, o.goroutines),
+		threads:    max(p.threads, o.threads),
+		rss:        max(p.rss, o.rss),
+		heap:       max(p.heap, o.heap),
+		inFlight:   max(p.inFlight, o.inFlight),
+		queued:     max(p.queued, o.queued),
+	}
+}
+
+// scrape reads a processor's /metrics
+func scrape(ctx context.Context, url string) (processorStats, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return processorStats{}, err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return processorStats{}, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return processorStats{}, fmt.Errorf("%s: %s", url, resp.Status)
+	}
+	samples, err := parseMetrics(resp.Body)
+	if err != nil {
+		return processorStats{}, fmt.Errorf("%s: %w", url, err)
+	}
+	return processorStats{
+		goroutines: samples["go_goroutines"],
+		threads:    samples["go_threads"],
+		rss:        samples["process_resident_memory_bytes"],
+		heap:       samples["go_memstats_heap_inuse_bytes"],
+		inFlight:   samples["dlms_meter_operations_in_flight"],
+		queued:     samples["dlms_worker_queue_depth"],
+	}, nil
+}
+
+// parseMetrics sums the samples of each metric in the Prometheus text
+// format, over their labels
+func parseMetrics(r io.Reader) (map[string]float64, error) {
+	samples := make(map[string]float64)
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		name, value, ok := strings.Cut(line, " ")
+		if i := strings.IndexByte(line, '}'); i >= 0 {
+			name, value, ok = line[:i+1], strings.TrimSpace(line[i+1:]), true
+		}
+		if !ok {
+			continue
+		}
+		name, _, _ = strings.Cut(name, "{")
+		value, _, _ = strings.Cut(value, " ") // a timestamp
+		v, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			continue
+		}
+		samples[name] += v
+	}
+	return samples, scanner.Err()
+}
+
+// summary is the final report of a load test
+func (s *stats) summary(processors int, target float64, peaks map[string]processorStats) (string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	elapsed := time.Since(s.start)
+	completed, failed := len(s.latencies), s.failed()
+	rate := float64(completed) / elapsed.Minutes()
+	perProcessor := rate / float64(processors)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Load test: %d reads in %v, %d failed\n", completed, elapsed.Round(time.Second), failed)
+	fmt.Fprintf(&b, "  throughput  %.0f devices/min, %.0f per processor\n", rate, perProcessor)
+	fmt.Fprintf(&b, "  latency     %s\n", percentiles(slices.Clone(s.latencies)))
+	for _, reason := range slices.Sorted(maps.Keys(s.failures)) {
+		fmt.Fprintf(&b, "  failed      %-24s %d\n", reason, s.failures[reason])
+	}
+	for _, url := range slices.Sorted(maps.Keys(peaks)) {
+		fmt.Fprintf(&b, "  peak        %s: %v\n", url, peaks[url])
+	}
+	fmt.Fprintf(&b, "  loadgen     %s\n", runtimeStats())
+
+	met := target <= 0 || perProcessor >= target
+	if target > 0 {
+		verdict := "met"
+		if !met {
+			verdict = "NOT met"
+		}
+		fmt.Fprintf(&b, "  target      %.0f devices/min per processor %s\n", target, verdict)
+	}
+	return b.String(), met
+}
@@ -24,17 +24,44 @@ const (
 	DLMSProcessor_GetDailyLoadProfile_FullMethodName     = "/dlmsprocessor.DLMSProcessor/GetDailyLoadProfile"
 	DLMSProcessor_GetBillingDataProfile_FullMethodName   = "/dlmsprocessor.DLMSProcessor/GetBillingDataProfile"
 	DLMSProcessor_GetInstantaneousProfile_FullMethodName = "/dlmsprocessor.DLMSProcessor/GetInstantaneousProfile"
+	DLMSProcessor_GetNameplate_FullMethodName            = "/dlmsprocessor.DLMSProcessor/GetNameplate"
+	DLMSProcessor_Probe_FullMethodName                   = "/dlmsprocessor.DLMSProcessor/Probe"
+	DLMSProcessor_ProgramTariff_FullMethodName           = "/dlmsprocessor.DLMSProcessor/ProgramTariff"
+	DLMSProcessor_Process_FullMethodName                 = "/dlmsprocessor.DLMSProcessor/Process"
 )
 
 // DLMSProcessorClient is the client API for DLMSProcessor service.
 //
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// The server-streaming calls are also served as HTTP/JSON through their
+// google.api.http bindings. Process is bidirectional and stays gRPC only.
 type DLMSProcessorClient interface {
 	GetOBIS(ctx context.Context, in *GetOBISRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[GetOBISResponse], error)
 	GetBlockLoadProfile(ctx context.Context, in *GetBlockLoadProfileRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[GetBlockLoadProfileResponse], error)
 	GetDailyLoadProfile(ctx context.Context, in *GetDailyLoadProfileRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[GetDailyLoadProfileResponse], error)
 	GetBillingDataProfile(ctx context.Context, in *GetBillingDataProfileRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[GetBillingDataProfileResponse], error)
 	GetInstantaneousProfile(ctx context.Context, in *GetInstantaneousProfileRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[GetInstantaneousProfileResponse], error)
+	// GetNameplate reads each meter's static identity, to check that the
+	// address reaches the expected meter and to keep the inventory in sync
+	GetNameplate(ctx context.Context, in *GetNameplateRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[GetNameplateResponse], error)
+	// Probe checks that each meter is reachable and accepts its keys before it
+	// is scheduled for reads. Every step is timed and reported on its own; a
+	// meter that fails a step is a normal response, not an error.
+	Probe(ctx context.Context, in *ProbeRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ProbeResponse], error)
+	// ProgramTariff writes each meter's passive activity calendar, then
+	// activates it at once or sets when it becomes active, replaces the
+	// special days and reads the tariff back to verify it
+	ProgramTariff(ctx context.Context, in *ProgramTariffRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ProgramTariffResponse], error)
+	// Process runs operations as the orchestrator streams them in and streams
+	// each result back as soon as it completes, in completion order.
+	//
+	// Flow control is credit based. The first response carries only an initial
+	// grant of credits; every operation sent uses one credit and every result
+	// returns one. Operations sent without a credit are rejected with
+	// RESOURCE_EXHAUSTED. Closing the send side lets in-flight operations
+	// finish before the stream ends.
+	Process(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[ProcessRequest, ProcessResponse], error)
 }
 
 type dLMSProcessorClient struct {
@@ -140,15 +167,108 @@ func (c *dLMSProcessorClient) GetInstantaneousProfile(ctx context.Context, in *G
 // This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
 type DLMSProcessor_GetInstantaneousProfileClient = grpc.ServerStreamingClient[GetInstantaneousProfileResponse]
 
+func (c *dLMSProcessorClient) GetNameplate(ctx context.Context, in *GetNameplateRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[GetNameplateResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &DLMSProcessor_ServiceDesc.Streams[5], DLMSProcessor_GetNameplate_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[GetNameplateRequest, GetNameplateResponse]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type DLMSProcessor_GetNameplateClient = grpc.ServerStreamingClient[GetNameplateResponse]
+
+func (c *dLMSProcessorClient) Probe(ctx context.Context, in *ProbeRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ProbeResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &DLMSProcessor_ServiceDesc.Streams[6], DLMSProcessor_Probe_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ProbeRequest, ProbeResponse]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type DLMSProcessor_ProbeClient = grpc.ServerStreamingClient[ProbeResponse]
+
+func (c *dLMSProcessorClient) ProgramTariff(ctx context.Context, in *ProgramTariffRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ProgramTariffResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &DLMSProcessor_ServiceDesc.Streams[7], DLMSProcessor_ProgramTariff_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ProgramTariffRequest, ProgramTariffResponse]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type DLMSProcessor_ProgramTariffClient = grpc.ServerStreamingClient[ProgramTariffResponse]
+
+func (c *dLMSProcessorClient) Process(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[ProcessRequest, ProcessResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &DLMSProcessor_ServiceDesc.Streams[8], DLMSProcessor_Process_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ProcessRequest, ProcessResponse]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type DLMSProcessor_ProcessClient = grpc.BidiStreamingClient[ProcessRequest, ProcessResponse]
+
 // DLMSProcessorServer is the server API for DLMSProcessor service.
 // All implementations must embed UnimplementedDLMSProcessorServer
 // for forward compatibility.
+//
+// The server-streaming calls are also served as HTTP/JSON through their
+// google.api.http bindings. Process is bidirectional and stays gRPC only.
 type DLMSProcessorServer interface {
 	GetOBIS(*GetOBISRequest, grpc.ServerStreamingServer[GetOBISResponse]) error
 	GetBlockLoadProfile(*GetBlockLoadProfileRequest, grpc.ServerStreamingServer[GetBlockLoadProfileResponse]) error
 	GetDailyLoadProfile(*GetDailyLoadProfileRequest, grpc.ServerStreamingServer[GetDailyLoadProfileResponse]) error
 	GetBillingDataProfile(*GetBillingDataProfileRequest, grpc.ServerStreamingServer[GetBillingDataProfileResponse]) error
 	GetInstantaneousProfile(*GetInstantaneousProfileRequest, grpc.ServerStreamingServer[GetInstantaneousProfileResponse]) error
+	// GetNameplate reads each meter's static identity, to check that the
+	// address reaches the expected meter and to keep the inventory in sync
+	GetNameplate(*GetNameplateRequest, grpc.ServerStreamingServer[GetNameplateResponse]) error
+	// Probe checks that each meter is reachable and accepts its keys before it
+	// is scheduled for reads. Every step is timed and reported on its own; a
+	// meter that fails a step is a normal response, not an error.
+	Probe(*ProbeRequest, grpc.ServerStreamingServer[ProbeResponse]) error
+	// ProgramTariff writes each meter's passive activity calendar, then
+	// activates it at once or sets when it becomes active, replaces the
+	// special days and reads the tariff back to verify it
+	ProgramTariff(*ProgramTariffRequest, grpc.ServerStreamingServer[ProgramTariffResponse]) error
+	// Process runs operations as the orchestrator streams them in and streams
+	// each result back as soon as it completes, in completion order.
+	//
+	// Flow control is credit based. The first response carries only an initial
+	// grant of credits; every operation sent uses one credit and every result
+	// returns one. Operations sent without a credit are rejected with
+	// RESOURCE_EXHAUSTED. Closing the send side lets in-flight operations
+	// finish before the stream ends.
+	Process(grpc.BidiStreamingServer[ProcessRequest, ProcessResponse]) error
 	mustEmbedUnimplementedDLMSProcessorServer()
 }
 
@@ -174,6 +294,18 @@ func (UnimplementedDLMSProcessorServer) GetBillingDataProfile(*GetBillingDataPro
 func (UnimplementedDLMSProcessorServer) GetInstantaneousProfile(*GetInstantaneousProfileRequest, grpc.ServerStreamingServer[GetInstantaneousProfileResponse]) error {
 	return status.Errorf(codes.Unimplemented, "method GetInstantaneousProfile not implemented")
 }
+func (UnimplementedDLMSProcessorServer) GetNameplate(*GetNameplateRequest, grpc.ServerStreamingServer[GetNameplateResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method GetNameplate not implemented")
+}
+func (UnimplementedDLMSProcessorServer) Probe(*ProbeRequest, grpc.ServerStreamingServer[ProbeResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method Probe not implemented")
+}
+func (UnimplementedDLMSProcessorServer) ProgramTariff(*ProgramTariffRequest, grpc.ServerStreamingServer[ProgramTariffResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method ProgramTariff not implemented")
+}
+func (UnimplementedDLMSProcessorServer) Process(grpc.BidiStreamingServer[ProcessRequest, ProcessResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method Process not implemented")
+}
 func (UnimplementedDLMSProcessorServer) mustEmbedUnimplementedDLMSProcessorServer() {}
 func (UnimplementedDLMSProcessorServer) testEmbeddedByValue()                       {}
 
@@ -250,6 +382,46 @@ func _DLMSProcessor_GetInstantaneousProfile_Handler(srv interface{}, stream grpc
 // This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
 type DLMSProcessor_GetInstantaneousProfileServer = grpc.ServerStreamingServer[GetInstantaneousProfileResponse]
 
+func _DLMSProcessor_GetNameplate_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(GetNameplateRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(DLMSProcessorServer).GetNameplate(m, &grpc.GenericServerStream[GetNameplateRequest, GetNameplateResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type DLMSProcessor_GetNameplateServer = grpc.ServerStreamingServer[GetNameplateResponse]
+
+func _DLMSProcessor_Probe_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ProbeRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(DLMSProcessorServer).Probe(m, &grpc.GenericServerStream[ProbeRequest, ProbeResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type DLMSProcessor_ProbeServer = grpc.ServerStreamingServer[ProbeResponse]
+
+func _DLMSProcessor_ProgramTariff_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ProgramTariffRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(DLMSProcessorServer).ProgramTariff(m, &grpc.GenericServerStream[ProgramTariffRequest, ProgramTariffResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type DLMSProcessor_ProgramTariffServer = grpc.ServerStreamingServer[ProgramTariffResponse]
+
+func _DLMSProcessor_Process_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(DLMSProcessorServer).Process(&grpc.GenericServerStream[ProcessRequest, ProcessResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type DLMSProcessor_ProcessServer = grpc.BidiStreamingServer[ProcessRequest, ProcessResponse]
+
 // DLMSProcessor_ServiceDesc is the grpc.ServiceDesc for DLMSProcessor service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -283,6 +455,27 @@ var DLMSProcessor_ServiceDesc = grpc.ServiceDesc{
 			Handler:       _DLMSProcessor_GetInstantaneousProfile_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "GetNameplate",
+			Handler:       _DLMSProcessor_GetNameplate_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "Probe",
+			Handler:       _DLMSProcessor_Probe_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "ProgramTariff",
+			Handler:       _DLMSProcessor_ProgramTariff_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "Process",
+			Handler:       _DLMSProcessor_Process_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
 	},
 	Metadata: "dlmsprocessor.proto",
 }
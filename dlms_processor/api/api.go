@@ -1,322 +1,653 @@
 package api
 
 import (
+	"context"
+	"dlmsprocessor/auth"
 	"dlmsprocessor/dlms"
+	"dlmsprocessor/keys"
+	"dlmsprocessor/metrics"
 	"dlmsprocessor/proto"
+	"dlmsprocessor/tracing"
+	"errors"
+	"fmt"
 	"log/slog"
+	"math"
+	"strconv"
 	"sync"
+	"time"
 
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 )
 
+// DefaultProcessWindow is how many operations a Process stream may have in
+// flight unless configured otherwise
+const DefaultProcessWindow = 64
+
+// tracer is resolved from the global provider on each use, so spans follow
+// the provider installed at the time rather than the one at package init
+func tracer() trace.Tracer {
+	return otel.GetTracerProvider().Tracer("dlmsprocessor/api")
+}
+
+// DefaultMaxMeterWorkers is how many meter operations run at once across
+// all calls unless configured otherwise
+const DefaultMaxMeterWorkers = 256
+
+// maxRetries caps the retries a request may ask for
+const maxRetries = 10
+
+// Operation names, as reported in metrics
+const (
+	operationOBIS                 = "obis"
+	operationBlockLoadProfile     = "block_load_profile"
+	operationDailyLoadProfile     = "daily_load_profile"
+	operationBillingDataProfile   = "billing_data_profile"
+	operationInstantaneousProfile = "instantaneous_profile"
+	operationNameplate            = "nameplate"
+	operationDefinedProfile       = "defined_profile"
+	operationReadAttribute        = "read_attribute"
+	operationWriteAttribute       = "write_attribute"
+	operationExecute              = "execute"
+	operationFOTA                 = "fota"
+	operationProbe                = "probe"
+	operationProgramTariff        = "program_tariff"
+)
+
 type DLMSProcessorAPI struct {
 	proto.UnimplementedDLMSProcessorServer
+
+	processWindow     int
+	maxMeterWorkers   int
+	connectionTimeout time.Duration
+	operationTimeout  time.Duration
+	sessionOptions    dlms.SessionOptions
+	audit             *auth.AuditLog
+	keyProvider       keys.Provider
+	allowRawKeys      bool
+	frameTraceDir     string
+	meterFactory      dlms.MeterFactory
+	profiles          *dlms.ProfileDefinitions
+
+	workers chan struct{} // one slot per running meter operation
+
+	drainOnce sync.Once
+	draining  chan struct{}
 }
 
-func NewDLMSProcessorAPI() *DLMSProcessorAPI {
-	return &DLMSProcessorAPI{}
+// Option configures a DLMSProcessorAPI
+type Option func(*DLMSProcessorAPI)
+
+// WithProcessWindow sets the credits granted to each Process stream
+func WithProcessWindow(n int) Option {
+	return func(s *DLMSProcessorAPI) {
+		if n > 0 {
+			s.processWindow = n
+		}
+	}
 }
 
-func (s *DLMSProcessorAPI) GetOBIS(req *proto.GetOBISRequest, stream grpc.ServerStreamingServer[proto.GetOBISResponse]) error {
+// WithMaxMeterWorkers bounds the meter operations running at once across all
+// calls; further operations queue for a free worker
+func WithMaxMeterWorkers(n int) Option {
+	return func(s *DLMSProcessorAPI) {
+		if n > 0 {
+			s.maxMeterWorkers = n
+		}
+	}
+}
 
-	if len(req.Meter) == 0 {
-		return status.Error(codes.InvalidArgument, "no meters provided")
+// WithConnectionTimeout sets the meter I/O timeout used when a request sets none
+func WithConnectionTimeout(d time.Duration) Option {
+	return func(s *DLMSProcessorAPI) {
+		s.connectionTimeout = d
 	}
+}
 
-	var wg sync.WaitGroup
-	errChan := make(chan error, len(req.Meter))
+// WithOperationTimeout bounds each meter operation when a request sets no
+// timeout of its own
+func WithOperationTimeout(d time.Duration) Option {
+	return func(s *DLMSProcessorAPI) {
+		s.operationTimeout = d
+	}
+}
 
-	for _, reqMeter := range req.Meter {
-		wg.Add(1)
-		go func(reqMeter *proto.Meter) {
-			defer wg.Done()
+// WithSessionOptions sets how long Process streams hold meter associations
+func WithSessionOptions(opts dlms.SessionOptions) Option {
+	return func(s *DLMSProcessorAPI) {
+		s.sessionOptions = opts
+	}
+}
 
-			slog.Info("NewFakeMeter", "ip", reqMeter.Ip, "port", reqMeter.Port)
-			var meter dlms.Meter
-			//meter, err := dlms.NewFakeMeter(reqMeter.Ip, int(reqMeter.Port))
-			meter, err := dlms.NewRealMeter(dlms.RealMeter{
-				MeterIP:           reqMeter.Ip,
-				MeterPort:         int(reqMeter.Port),
-				AuthPassword:      reqMeter.AuthPassword,
-				SystemTitle:       reqMeter.SystemTitle,
-				BlockCipherKey:    reqMeter.BlockCipherKey,
-				AuthenticationKey: reqMeter.AuthKey,
-			})
-			if err != nil {
-				slog.Error("NewFakeMeter", "error", err)
-				errChan <- err
-			}
+// WithAuditLog records every state-changing operation in audit
+func WithAuditLog(audit *auth.AuditLog) Option {
+	return func(s *DLMSProcessorAPI) {
+		s.audit = audit
+	}
+}
 
-			slog.Info("Connecting to meter")
-			err1 := meter.Connect()
+// WithKeyProvider resolves the keys of meters sent with a key reference
+func WithKeyProvider(p keys.Provider) Option {
+	return func(s *DLMSProcessorAPI) {
+		s.keyProvider = p
+	}
+}
 
-			if err1 != nil {
-				slog.Error("Connect", "error", err1)
-				errChan <- err1
-			}
-			slog.Info("Connected to meter")
+// WithRawKeys lets requests carry meter keys themselves rather than a key
+// reference. They are refused otherwise.
+func WithRawKeys(allow bool) Option {
+	return func(s *DLMSProcessorAPI) {
+		s.allowRawKeys = allow
+	}
+}
 
-			obis, err := meter.GetOBIS(reqMeter.Obis)
-			if err != nil {
-				errChan <- err
-				return
-			}
+// WithFrameTraceDir lets requests have their frame trace written to a pcap
+// file in dir. They are refused when dir is empty.
+func WithFrameTraceDir(dir string) Option {
+	return func(s *DLMSProcessorAPI) {
+		s.frameTraceDir = dir
+	}
+}
 
-			err = stream.Send(&proto.GetOBISResponse{Value: obis})
-			if err != nil {
-				errChan <- err
-				return
-			}
-		}(reqMeter)
+// WithProfileDefinitions lets Process read the profiles defs describe by
+// name, selected by the meter's model
+func WithProfileDefinitions(defs *dlms.ProfileDefinitions) Option {
+	return func(s *DLMSProcessorAPI) {
+		s.profiles = defs
 	}
+}
 
-	wg.Wait()
+// WithMeterFactory sets what creates the meters requests talk to, real
+// meters by default. Probe always talks to real meters, as it times their
+// network.
+func WithMeterFactory(f dlms.MeterFactory) Option {
+	return func(s *DLMSProcessorAPI) {
+		if f != nil {
+			s.meterFactory = f
+		}
+	}
+}
 
-	// Check for any errors
+// MethodRights is the right each RPC needs. Any caller that may read can
+// open a Process stream; its write and execute operations are checked one
+// by one.
+var MethodRights = map[string]auth.Right{
+	proto.DLMSProcessor_GetOBIS_FullMethodName:                 auth.Read,
+	proto.DLMSProcessor_GetBlockLoadProfile_FullMethodName:     auth.Read,
+	proto.DLMSProcessor_GetDailyLoadProfile_FullMethodName:     auth.Read,
+	proto.DLMSProcessor_GetBillingDataProfile_FullMethodName:   auth.Read,
+	proto.DLMSProcessor_GetInstantaneousProfile_FullMethodName: auth.Read,
+	proto.DLMSProcessor_GetNameplate_FullMethodName:            auth.Read,
+	proto.DLMSProcessor_Probe_FullMethodName:                   auth.Read,
+	proto.DLMSProcessor_ProgramTariff_FullMethodName:           auth.Write,
+	proto.DLMSProcessor_Process_FullMethodName:                 auth.Read,
+}
+
+func NewDLMSProcessorAPI(opts ...Option) *DLMSProcessorAPI {
+	s := &DLMSProcessorAPI{
+		processWindow:   DefaultProcessWindow,
+		maxMeterWorkers: DefaultMaxMeterWorkers,
+		meterFactory:    dlms.RealMeters{},
+		draining:        make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	s.workers = make(chan struct{}, s.maxMeterWorkers)
+	return s
+}
+
+// Drain stops the API from taking new work ahead of a shutdown. New calls and
+// new Process operations are refused with UNAVAILABLE, while work already
+// running completes; Process streams end once their in-flight operations have.
+func (s *DLMSProcessorAPI) Drain() {
+	s.drainOnce.Do(func() { close(s.draining) })
+}
+
+// admit refuses new work while draining
+func (s *DLMSProcessorAPI) admit() error {
 	select {
-	case err := <-errChan:
-		return err
+	case <-s.draining:
+		return status.Error(codes.Unavailable, "server is shutting down")
 	default:
 		return nil
 	}
 }
 
-func (s *DLMSProcessorAPI) GetBlockLoadProfile(req *proto.GetBlockLoadProfileRequest, stream grpc.ServerStreamingServer[proto.GetBlockLoadProfileResponse]) error {
-
-	if len(req.Meter) == 0 {
-		return status.Error(codes.InvalidArgument, "no meters provided")
+// acquireWorker waits for a free meter worker. The returned release must be
+// called once the meter operation is done.
+func (s *DLMSProcessorAPI) acquireWorker(ctx context.Context) (release func(), err error) {
+	metrics.WorkerQueueDepth.Inc()
+	select {
+	case s.workers <- struct{}{}:
+		metrics.WorkerQueueDepth.Dec()
+	case <-ctx.Done():
+		metrics.WorkerQueueDepth.Dec()
+		return nil, ctx.Err()
 	}
 
-	var wg sync.WaitGroup
-	errChan := make(chan error, len(req.Meter))
+	metrics.MeterOperationsInFlight.Inc()
+	return func() {
+		metrics.MeterOperationsInFlight.Dec()
+		<-s.workers
+	}, nil
+}
 
-	for _, reqMeter := range req.Meter {
-		wg.Add(1)
-		go func(reqMeter *proto.Meter) {
-			defer wg.Done()
+// retryPolicy is how often a failed meter operation is attempted again, and
+// how long each attempt may take; 0 leaves it to the operation timeout of s
+type retryPolicy struct {
+	retries int
+	delay   time.Duration
+	timeout time.Duration
+}
 
-			slog.Info("NewRealMeter for BlockLoadProfile", "ip", reqMeter.Ip, "port", reqMeter.Port)
-			var meter dlms.Meter
-			//meter, err := dlms.NewFakeMeter(reqMeter.Ip, int(reqMeter.Port))
-			meter, err := dlms.NewRealMeter(dlms.RealMeter{
-				MeterIP:           reqMeter.Ip,
-				MeterPort:         int(reqMeter.Port),
-				AuthPassword:      reqMeter.AuthPassword,
-				SystemTitle:       reqMeter.SystemTitle,
-				BlockCipherKey:    reqMeter.BlockCipherKey,
-				AuthenticationKey: reqMeter.AuthKey,
-			})
-			if err != nil {
-				slog.Error("NewRealMeter", "error", err)
-				errChan <- err
-				return
-			}
+// requestRetries is the retry policy a request asks for, with the delay and
+// the timeout of each attempt in milliseconds
+func requestRetries(retries, retryDelay, timeout int32) retryPolicy {
+	return retryPolicy{
+		retries: min(max(int(retries), 0), maxRetries),
+		delay:   time.Duration(max(retryDelay, 0)) * time.Millisecond,
+		timeout: time.Duration(max(timeout, 0)) * time.Millisecond,
+	}
+}
 
-			slog.Info("Connecting to meter for BlockLoadProfile")
-			err1 := meter.Connect()
+// retryable reports whether another attempt could succeed where err failed
+func retryable(err error) bool {
+	switch statusFromError(err).Code() {
+	case codes.InvalidArgument, codes.PermissionDenied, codes.Unauthenticated, codes.NotFound, codes.FailedPrecondition, codes.Internal, codes.Unimplemented:
+		return false
+	}
+	return true
+}
 
-			if err1 != nil {
-				slog.Error("Connect", "error", err1)
-				errChan <- err1
-				return
-			}
-			slog.Info("Connected to meter for BlockLoadProfile")
+// withRetries runs fn, each attempt bounded by the timeout of retry or else
+// the operation timeout of s, until it succeeds, fails for good or the
+// retries run out
+func withRetries[T any](s *DLMSProcessorAPI, ctx context.Context, operation string, retry retryPolicy, fn func(ctx context.Context) (*T, error)) (*T, error) {
+	for attempt := 1; ; attempt++ {
+		opCtx, cancel := s.operationContext(ctx, retry.timeout)
+		resp, err := fn(opCtx)
+		cancel()
+		if err == nil || attempt > retry.retries || ctx.Err() != nil || !retryable(err) {
+			return resp, err
+		}
+
+		slog.Warn("Retrying meter operation", "operation", operation, "attempt", attempt, "delay", retry.delay, "error", err)
+		metrics.Retries.WithLabelValues(operation).Inc()
+		trace.SpanFromContext(ctx).AddEvent("retry", trace.WithAttributes(
+			attribute.Int("attempt", attempt),
+			attribute.String("error", err.Error()),
+		))
+		select {
+		case <-time.After(retry.delay):
+		case <-ctx.Done():
+			return nil, err
+		}
+	}
+}
 
-			profile, err := meter.GetBlockLoadProfile()
-			if err != nil {
-				errChan <- err
-				return
-			}
+// startMeterSpan starts the span of one meter's part in a call
+func startMeterSpan(ctx context.Context, operation string, reqMeter *proto.Meter) (context.Context, trace.Span) {
+	attrs := append(tracing.MeterAttributes(reqMeter.MeterId, reqMeter.Ip, int(reqMeter.Port)), attribute.String("dlms.operation", operation))
+	return tracer().Start(ctx, "meter "+operation, trace.WithAttributes(attrs...))
+}
 
-			// Convert from dlms.BlockLoadProfile to proto.BlockLoadProfile
-			protoProfile := &proto.BlockLoadProfile{
-				DateTime:             profile.DateTime,
-				AverageVoltage:       profile.AverageVoltage,
-				BlockEnergyWhImport:  profile.BlockEnergyWhImport,
-				BlockEnergyVahImport: profile.BlockEnergyVAhImport,
-				BlockEnergyWhExport:  profile.BlockEnergyWhExport,
-				BlockEnergyVahExport: profile.BlockEnergyVAhExport,
-				AverageCurrent:       profile.AverageCurrent,
-				MeterHealthIndicator: uint32(profile.MeterHealthIndicator),
-			}
+// meterTimeout is the connection timeout for a request in milliseconds,
+// falling back to the configured default
+func (s *DLMSProcessorAPI) meterTimeout(requested int32) int32 {
+	if requested > 0 {
+		return requested
+	}
+	return int32(s.connectionTimeout.Milliseconds())
+}
 
-			err = stream.Send(&proto.GetBlockLoadProfileResponse{
-				Profile: protoProfile,
-				MeterIp: reqMeter.Ip,
-			})
-			if err != nil {
-				errChan <- err
-				return
-			}
-		}(reqMeter)
+// operationContext bounds one meter operation by timeout, or by the
+// configured default when the request sets none
+func (s *DLMSProcessorAPI) operationContext(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		timeout = s.operationTimeout
+	}
+	if timeout <= 0 {
+		return context.WithCancel(ctx)
 	}
+	return context.WithTimeout(ctx, timeout)
+}
 
-	wg.Wait()
+// meterKeys returns the keys for a requested meter: from the key provider
+// when it names a key reference, or as sent where raw keys are allowed.
+// Errors never mention the keys.
+func (s *DLMSProcessorAPI) meterKeys(ctx context.Context, reqMeter *proto.Meter) (keys.Keys, error) {
+	raw := keys.Keys{
+		AuthPassword:      reqMeter.AuthPassword,
+		AuthenticationKey: reqMeter.AuthKey,
+		BlockCipherKey:    reqMeter.BlockCipherKey,
+	}
 
-	// Check for any errors
-	select {
-	case err := <-errChan:
-		return err
-	default:
-		return nil
+	if reqMeter.KeyRef == nil {
+		if !raw.IsZero() && !s.allowRawKeys {
+			return keys.Keys{}, status.Errorf(codes.InvalidArgument, "meter %s: raw keys are not allowed, send a keyRef", reqMeter.MeterId)
+		}
+		return raw, nil
+	}
+
+	if !raw.IsZero() {
+		return keys.Keys{}, status.Errorf(codes.InvalidArgument, "meter %s: send either a keyRef or keys, not both", reqMeter.MeterId)
+	}
+	if s.keyProvider == nil {
+		return keys.Keys{}, status.Errorf(codes.FailedPrecondition, "meter %s: no key provider is configured", reqMeter.MeterId)
 	}
+	ref := keys.Ref{MeterID: reqMeter.KeyRef.MeterId, Version: reqMeter.KeyRef.Version}
+	if ref.MeterID == "" {
+		ref.MeterID = reqMeter.MeterId
+	}
+	k, err := s.keyProvider.Keys(ctx, ref)
+	switch {
+	case errors.Is(err, keys.ErrNotFound):
+		return keys.Keys{}, status.Errorf(codes.NotFound, "meter %s: no keys for %s", reqMeter.MeterId, ref)
+	case err != nil:
+		return keys.Keys{}, status.Errorf(codes.Unavailable, "meter %s: %v", reqMeter.MeterId, err)
+	}
+	return k, nil
 }
 
-func (s *DLMSProcessorAPI) GetDailyLoadProfile(req *proto.GetDailyLoadProfileRequest, stream grpc.ServerStreamingServer[proto.GetDailyLoadProfileResponse]) error {
+// meterConfig maps a requested meter and its keys onto the dlms connection
+// settings, including the identity the meter is expected to report. The
+// client and server addresses are sent as decimal strings; empty leaves the
+// library's default.
+func meterConfig(reqMeter *proto.Meter, k keys.Keys, connectionTimeout int32) (dlms.RealMeter, error) {
+	clientAddress, err := meterAddress(reqMeter.ClientAddress)
+	if err != nil {
+		return dlms.RealMeter{}, status.Errorf(codes.InvalidArgument, "meter %s: invalid client address: %v", reqMeter.MeterId, err)
+	}
+	serverAddress, err := meterAddress(reqMeter.ServerAddress)
+	if err != nil {
+		return dlms.RealMeter{}, status.Errorf(codes.InvalidArgument, "meter %s: invalid server address: %v", reqMeter.MeterId, err)
+	}
 
-	if len(req.Meter) == 0 {
-		return status.Error(codes.InvalidArgument, "no meters provided")
+	return dlms.RealMeter{
+		MeterID:           reqMeter.MeterId,
+		SerialNumber:      reqMeter.SerialNumber,
+		LogicalDeviceName: reqMeter.LogicalDeviceName,
+		MeterIP:           reqMeter.Ip,
+		MeterPort:         int(reqMeter.Port),
+		ConnectionTimeout: int(connectionTimeout),
+		AuthPassword:      k.AuthPassword,
+		SystemTitle:       reqMeter.SystemTitle,
+		BlockCipherKey:    k.BlockCipherKey,
+		AuthenticationKey: k.AuthenticationKey,
+		ClientAddress:     clientAddress,
+		ServerAddress:     serverAddress,
+	}, nil
+}
+
+// meterAddress parses a client or server address, 0 if unset
+func meterAddress(value string) (int, error) {
+	if value == "" {
+		return 0, nil
+	}
+	address, err := strconv.Atoi(value)
+	if err != nil || address < 0 || address > math.MaxUint16 {
+		return 0, fmt.Errorf("%q is not a number between 0 and %d", value, math.MaxUint16)
 	}
+	return address, nil
+}
 
-	var wg sync.WaitGroup
-	errChan := make(chan error, len(req.Meter))
+// blockLoadProfileToProto converts from dlms.BlockLoadProfile to proto.BlockLoadProfile
+func blockLoadProfileToProto(profile *dlms.BlockLoadProfile) *proto.BlockLoadProfile {
+	return &proto.BlockLoadProfile{
+		DateTime:             profile.DateTime,
+		AverageVoltage:       profile.AverageVoltage,
+		BlockEnergyWhImport:  profile.BlockEnergyWhImport,
+		BlockEnergyVahImport: profile.BlockEnergyVAhImport,
+		BlockEnergyWhExport:  profile.BlockEnergyWhExport,
+		BlockEnergyVahExport: profile.BlockEnergyVAhExport,
+		AverageCurrent:       profile.AverageCurrent,
+		MeterHealthIndicator: uint32(profile.MeterHealthIndicator),
+		ThreePhase:           threePhaseBlockLoadProfileToProto(profile.ThreePhase),
+	}
+}
 
-	for _, reqMeter := range req.Meter {
-		wg.Add(1)
-		go func(reqMeter *proto.Meter) {
-			defer wg.Done()
+// threePhaseBlockLoadProfileToProto converts from dlms.ThreePhaseBlockLoadProfile to proto.ThreePhaseBlockLoadProfile, nil for a single-phase meter
+func threePhaseBlockLoadProfileToProto(profile *dlms.ThreePhaseBlockLoadProfile) *proto.ThreePhaseBlockLoadProfile {
+	if profile == nil {
+		return nil
+	}
+	return &proto.ThreePhaseBlockLoadProfile{
+		DateTime:             profile.DateTime,
+		CurrentR:             profile.CurrentR,
+		CurrentY:             profile.CurrentY,
+		CurrentB:             profile.CurrentB,
+		VoltageR:             profile.VoltageR,
+		VoltageY:             profile.VoltageY,
+		VoltageB:             profile.VoltageB,
+		BlockEnergyWhImport:  profile.BlockEnergyWhImport,
+		BlockEnergyVarhLag:   profile.BlockEnergyVarhLag,
+		BlockEnergyVarhLead:  profile.BlockEnergyVarhLead,
+		BlockEnergyVahImport: profile.BlockEnergyVAhImport,
+		BlockEnergyWhExport:  profile.BlockEnergyWhExport,
+		BlockEnergyVahExport: profile.BlockEnergyVAhExport,
+	}
+}
 
-			slog.Info("NewRealMeter for DailyLoadProfile", "ip", reqMeter.Ip, "port", reqMeter.Port)
-			var meter dlms.Meter
-			//meter, err := dlms.NewFakeMeter(reqMeter.Ip, int(reqMeter.Port))
-			meter, err := dlms.NewRealMeter(dlms.RealMeter{
-				MeterIP:           reqMeter.Ip,
-				MeterPort:         int(reqMeter.Port),
-				AuthPassword:      reqMeter.AuthPassword,
-				SystemTitle:       reqMeter.SystemTitle,
-				BlockCipherKey:    reqMeter.BlockCipherKey,
-				AuthenticationKey: reqMeter.AuthKey,
-			})
-			if err != nil {
-				slog.Error("NewRealMeter", "error", err)
-				errChan <- err
-				return
-			}
+// dailyLoadProfileToProto converts from dlms.DailyLoadProfile to proto.DailyLoadProfile
+func dailyLoadProfileToProto(profile *dlms.DailyLoadProfile) *proto.DailyLoadProfile {
+	return &proto.DailyLoadProfile{
+		DateTime:                  profile.DateTime,
+		CumulativeEnergyWhExport:  profile.CumulativeEnergyWhExport,
+		CumulativeEnergyVahExport: profile.CumulativeEnergyVAhExport,
+		CumulativeEnergyWhImport:  profile.CumulativeEnergyWhImport,
+		CumulativeEnergyVahImport: profile.CumulativeEnergyVAhImport,
+	}
+}
 
-			slog.Info("Connecting to meter for DailyLoadProfile")
-			err1 := meter.Connect()
+// billingDataProfileToProto converts from dlms.BillingDataProfile to proto.BillingDataProfile.
+// The deprecated TZ1 to TZ4 fields are still filled for existing clients.
+func billingDataProfileToProto(profile *dlms.BillingDataProfile) *proto.BillingDataProfile {
+	return &proto.BillingDataProfile{
+		BillingDate:               profile.BillingDate,
+		AveragePfForBillingPeriod: profile.AveragePFForBillingPeriod,
+		CumEnergyWhImport:         profile.CumEnergyWhImport,
+		CumEnergyWhTz1:            profile.CumEnergyWhTZ1,
+		CumEnergyWhTz2:            profile.CumEnergyWhTZ2,
+		CumEnergyWhTz3:            profile.CumEnergyWhTZ3,
+		CumEnergyWhTz4:            profile.CumEnergyWhTZ4,
+		CumEnergyVahImport:        profile.CumEnergyVAhImport,
+		CumEnergyVahTz1:           profile.CumEnergyVAhTZ1,
+		CumEnergyVahTz2:           profile.CumEnergyVAhTZ2,
+		CumEnergyVahTz3:           profile.CumEnergyVAhTZ3,
+		CumEnergyVahTz4:           profile.CumEnergyVAhTZ4,
+		Mdw:                       profile.MDW,
+		MdwDateTime:               profile.MDWDateTime,
+		Mdva:                      profile.MDVA,
+		MdvaDateTime:              profile.MDVADateTime,
+		BillingPowerOnDuration:    profile.BillingPowerOnDuration,
+		CumEnergyWh:               profile.CumEnergyWh,
+		CumEnergyVah:              profile.CumEnergyVAh,
+		TariffZones:               tariffZonesToProto(profile.TariffZones),
+	}
+}
 
-			if err1 != nil {
-				slog.Error("Connect", "error", err1)
-				errChan <- err1
-				return
-			}
-			slog.Info("Connected to meter for DailyLoadProfile")
+// tariffZonesToProto converts from dlms.TariffZone to proto.TariffZone
+func tariffZonesToProto(zones []dlms.TariffZone) []*proto.TariffZone {
+	var result []*proto.TariffZone
+	for _, z := range zones {
+		result = append(result, &proto.TariffZone{
+			Zone:         int32(z.Zone),
+			CumEnergyWh:  z.CumEnergyWh,
+			CumEnergyVah: z.CumEnergyVAh,
+			Mdw:          z.MDW,
+			MdwDateTime:  z.MDWDateTime,
+			Mdva:         z.MDVA,
+			MdvaDateTime: z.MDVADateTime,
+		})
+	}
+	return result
+}
 
-			profile, err := meter.GetDailyLoadProfile()
-			if err != nil {
-				errChan <- err
-				return
-			}
+// instantaneousProfileToProto converts from dlms.InstantaneousProfile to proto.InstantaneousProfile
+func instantaneousProfileToProto(profile *dlms.InstantaneousProfile) *proto.InstantaneousProfile {
+	return &proto.InstantaneousProfile{
+		DateTime:          profile.DateTime,
+		Voltage:           profile.Voltage,
+		PhaseCurrent:      profile.PhaseCurrent,
+		NeutralCurrent:    profile.NeutralCurrent,
+		SignedPowerFactor: profile.SignedPowerFactor,
+		Frequency:         profile.Frequency,
+		ApparentPower:     profile.ApparentPower,
+		ActivePower:       profile.ActivePower,
+		CumEnergyWh:       profile.CumEnergyWh,
+		ThreePhase:        threePhaseInstantaneousProfileToProto(profile.ThreePhase),
+	}
+}
 
-			// Convert from dlms.DailyLoadProfile to proto.DailyLoadProfile
-			protoProfile := &proto.DailyLoadProfile{
-				DateTime:                  profile.DateTime,
-				CumulativeEnergyWhExport:  profile.CumulativeEnergyWhExport,
-				CumulativeEnergyVahExport: profile.CumulativeEnergyVAhExport,
-				CumulativeEnergyWhImport:  profile.CumulativeEnergyWhImport,
-				CumulativeEnergyVahImport: profile.CumulativeEnergyVAhImport,
-			}
+// nameplateToProto converts from dlms.NameplateProfile to proto.NameplateProfile
+func nameplateToProto(nameplate *dlms.NameplateProfile) *proto.NameplateProfile {
+	return &proto.NameplateProfile{
+		SerialNumber:      nameplate.SerialNumber,
+		ManufacturerName:  nameplate.ManufacturerName,
+		FirmwareVersion:   nameplate.FirmwareVersion,
+		MeterType:         uint32(nameplate.MeterType),
+		Category:          nameplate.Category,
+		CurrentRating:     nameplate.CurrentRating,
+		YearOfManufacture: uint32(nameplate.YearOfManufacture),
+	}
+}
 
-			err = stream.Send(&proto.GetDailyLoadProfileResponse{
-				Profile: protoProfile,
-				MeterIp: reqMeter.Ip,
-			})
-			if err != nil {
-				errChan <- err
-				return
+// profileDataToProto converts from dlms.ProfileData to proto.ProfileData
+func profileDataToProto(data *dlms.ProfileData) *proto.ProfileData {
+	def := data.Definition
+	out := &proto.ProfileData{Name: def.Name, Obis: def.OBIS}
+	for i, c := range def.Columns {
+		out.Columns = append(out.Columns, &proto.ProfileColumn{Name: c.Name, Obis: c.OBIS, Attribute: int32(data.Attributes[i]), Unit: c.Unit})
+	}
+	for _, row := range data.Rows {
+		values := make([]*proto.ProfileValue, len(row))
+		for i, v := range row {
+			values[i] = &proto.ProfileValue{}
+			switch v := v.(type) {
+			case string:
+				values[i].Value = &proto.ProfileValue_StringValue{StringValue: v}
+			case float64:
+				values[i].Value = &proto.ProfileValue_NumberValue{NumberValue: v}
 			}
-		}(reqMeter)
+		}
+		out.Rows = append(out.Rows, &proto.ProfileRow{Values: values})
 	}
+	return out
+}
 
-	wg.Wait()
-
-	// Check for any errors
-	select {
-	case err := <-errChan:
-		return err
-	default:
+// threePhaseInstantaneousProfileToProto converts from dlms.ThreePhaseInstantaneousProfile to proto.ThreePhaseInstantaneousProfile, nil for a single-phase meter
+func threePhaseInstantaneousProfileToProto(profile *dlms.ThreePhaseInstantaneousProfile) *proto.ThreePhaseInstantaneousProfile {
+	if profile == nil {
 		return nil
 	}
+	return &proto.ThreePhaseInstantaneousProfile{
+		DateTime:           profile.DateTime,
+		CurrentR:           profile.CurrentR,
+		CurrentY:           profile.CurrentY,
+		CurrentB:           profile.CurrentB,
+		VoltageR:           profile.VoltageR,
+		VoltageY:           profile.VoltageY,
+		VoltageB:           profile.VoltageB,
+		PowerFactorR:       profile.PowerFactorR,
+		PowerFactorY:       profile.PowerFactorY,
+		PowerFactorB:       profile.PowerFactorB,
+		PowerFactor:        profile.PowerFactor,
+		Frequency:          profile.Frequency,
+		ActivePowerR:       profile.ActivePowerR,
+		ActivePowerY:       profile.ActivePowerY,
+		ActivePowerB:       profile.ActivePowerB,
+		ReactivePowerR:     profile.ReactivePowerR,
+		ReactivePowerY:     profile.ReactivePowerY,
+		ReactivePowerB:     profile.ReactivePowerB,
+		ApparentPowerR:     profile.ApparentPowerR,
+		ApparentPowerY:     profile.ApparentPowerY,
+		ApparentPowerB:     profile.ApparentPowerB,
+		ActivePower:        profile.ActivePower,
+		ReactivePower:      profile.ReactivePower,
+		ApparentPower:      profile.ApparentPower,
+		CumEnergyWhImport:  profile.CumEnergyWhImport,
+		CumEnergyWhExport:  profile.CumEnergyWhExport,
+		CumEnergyVarhLag:   profile.CumEnergyVarhLag,
+		CumEnergyVarhLead:  profile.CumEnergyVarhLead,
+		CumEnergyVahImport: profile.CumEnergyVAhImport,
+		CumEnergyVahExport: profile.CumEnergyVAhExport,
+	}
 }
 
-func (s *DLMSProcessorAPI) GetBillingDataProfile(req *proto.GetBillingDataProfileRequest, stream grpc.ServerStreamingServer[proto.GetBillingDataProfileResponse]) error {
+// openMeter creates the meter for a requested meter and prepares it for use
+func (s *DLMSProcessorAPI) openMeter(ctx context.Context, reqMeter *proto.Meter, connectionTimeout int32) (dlms.Meter, error) {
+	k, err := s.meterKeys(ctx, reqMeter)
+	if err != nil {
+		return nil, err
+	}
 
-	if len(req.Meter) == 0 {
-		return status.Error(codes.InvalidArgument, "no meters provided")
+	config, err := meterConfig(reqMeter, k, s.meterTimeout(connectionTimeout))
+	if err != nil {
+		return nil, err
+	}
+
+	meter, err := s.meterFactory.NewMeter(config, nil)
+	if err != nil {
+		slog.Error("NewMeter", "meter_id", reqMeter.MeterId, "error", err)
+		return nil, err
+	}
+
+	if err := meter.Connect(ctx); err != nil {
+		slog.Error("Connect", "meter_id", reqMeter.MeterId, "error", err)
+		return nil, err
 	}
 
+	return meter, nil
+}
+
+// forEachMeter runs read for every requested meter concurrently on the
+// worker pool of s, retrying as the request asks with each attempt bounded by
+// its timeout or the operation timeout, and streams each result with send.
+// Meters that have not started when ctx is done are skipped, and sends are
+// serialized because a gRPC stream is not safe for concurrent use. It returns the first error,
+// or the context's status if the caller went away or its deadline expired.
+func forEachMeter[T any](s *DLMSProcessorAPI, ctx context.Context, operation string, retry retryPolicy, meters []*proto.Meter, read func(ctx context.Context, reqMeter *proto.Meter) (*T, error), send func(*T) error) error {
 	var wg sync.WaitGroup
-	errChan := make(chan error, len(req.Meter))
+	var sendMu sync.Mutex
+	errChan := make(chan error, len(meters))
+
+	for _, reqMeter := range meters {
+		if ctx.Err() != nil {
+			break
+		}
 
-	for _, reqMeter := range req.Meter {
 		wg.Add(1)
 		go func(reqMeter *proto.Meter) {
 			defer wg.Done()
 
-			slog.Info("NewRealMeter for BillingDataProfile", "ip", reqMeter.Ip, "port", reqMeter.Port)
-			var meter dlms.Meter
-			//meter, err := dlms.NewFakeMeter(reqMeter.Ip, int(reqMeter.Port))
-			meter, err := dlms.NewRealMeter(dlms.RealMeter{
-				MeterIP:           reqMeter.Ip,
-				MeterPort:         int(reqMeter.Port),
-				AuthPassword:      reqMeter.AuthPassword,
-				SystemTitle:       reqMeter.SystemTitle,
-				BlockCipherKey:    reqMeter.BlockCipherKey,
-				AuthenticationKey: reqMeter.AuthKey,
-			})
-			if err != nil {
-				slog.Error("NewRealMeter", "error", err)
-				errChan <- err
-				return
-			}
-
-			slog.Info("Connecting to meter for BillingDataProfile")
-			err1 := meter.Connect()
-
-			if err1 != nil {
-				slog.Error("Connect", "error", err1)
-				errChan <- err1
-				return
-			}
-			slog.Info("Connected to meter for BillingDataProfile")
-
-			profile, err := meter.GetBillingDataProfile()
+			ctx, span := startMeterSpan(ctx, operation, reqMeter)
+			resp, err := func() (*T, error) {
+				release, err := s.acquireWorker(ctx)
+				if err != nil {
+					return nil, err
+				}
+				defer release()
+				span.AddEvent("worker acquired")
+
+				return withRetries(s, ctx, operation, retry, func(ctx context.Context) (*T, error) {
+					return read(ctx, reqMeter)
+				})
+			}()
+			metrics.MeterOperations.WithLabelValues(operation, metrics.Outcome(err)).Inc()
+			tracing.End(span, err)
 			if err != nil {
 				errChan <- err
 				return
 			}
 
-			// Convert from dlms.BillingDataProfile to proto.BillingDataProfile
-			protoProfile := &proto.BillingDataProfile{
-				BillingDate:               profile.BillingDate,
-				AveragePfForBillingPeriod: profile.AveragePFForBillingPeriod,
-				CumEnergyWhImport:         profile.CumEnergyWhImport,
-				CumEnergyWhTz1:            profile.CumEnergyWhTZ1,
-				CumEnergyWhTz2:            profile.CumEnergyWhTZ2,
-				CumEnergyWhTz3:            profile.CumEnergyWhTZ3,
-				CumEnergyWhTz4:            profile.CumEnergyWhTZ4,
-				CumEnergyVahImport:        profile.CumEnergyVAhImport,
-				CumEnergyVahTz1:           profile.CumEnergyVAhTZ1,
-				CumEnergyVahTz2:           profile.CumEnergyVAhTZ2,
-				CumEnergyVahTz3:           profile.CumEnergyVAhTZ3,
-				CumEnergyVahTz4:           profile.CumEnergyVAhTZ4,
-				Mdw:                       profile.MDW,
-				MdwDateTime:               profile.MDWDateTime,
-				Mdva:                      profile.MDVA,
-				MdvaDateTime:              profile.MDVADateTime,
-				BillingPowerOnDuration:    profile.BillingPowerOnDuration,
-				CumEnergyWh:               profile.CumEnergyWh,
-				CumEnergyVah:              profile.CumEnergyVAh,
-			}
-
-			err = stream.Send(&proto.GetBillingDataProfileResponse{
-				Profile: protoProfile,
-				MeterIp: reqMeter.Ip,
-			})
-			if err != nil {
+			sendMu.Lock()
+			defer sendMu.Unlock()
+			if err := send(resp); err != nil {
 				errChan <- err
 				return
 			}
@@ -325,93 +656,212 @@ func (s *DLMSProcessorAPI) GetBillingDataProfile(req *proto.GetBillingDataProfil
 
 	wg.Wait()
 
+	if err := ctx.Err(); err != nil {
+		return status.FromContextError(err).Err()
+	}
+
 	// Check for any errors
 	select {
 	case err := <-errChan:
-		return err
+		return statusFromError(err).Err()
 	default:
 		return nil
 	}
 }
 
-func (s *DLMSProcessorAPI) GetInstantaneousProfile(req *proto.GetInstantaneousProfileRequest, stream grpc.ServerStreamingServer[proto.GetInstantaneousProfileResponse]) error {
+func (s *DLMSProcessorAPI) GetOBIS(req *proto.GetOBISRequest, stream grpc.ServerStreamingServer[proto.GetOBISResponse]) error {
+
+	if err := s.admit(); err != nil {
+		return err
+	}
 
 	if len(req.Meter) == 0 {
 		return status.Error(codes.InvalidArgument, "no meters provided")
 	}
 
-	var wg sync.WaitGroup
-	errChan := make(chan error, len(req.Meter))
+	return forEachMeter(s, stream.Context(), operationOBIS, requestRetries(req.Retries, req.RetryDelay, req.Timeout), req.Meter, func(ctx context.Context, reqMeter *proto.Meter) (*proto.GetOBISResponse, error) {
+		slog.Info("Connecting to meter", "meter_id", reqMeter.MeterId, "ip", reqMeter.Ip, "port", reqMeter.Port)
+		meter, err := s.openMeter(ctx, reqMeter, req.ConnectionTimeout)
+		if err != nil {
+			return nil, err
+		}
+		defer meter.Close()
+		slog.Info("Connected to meter", "meter_id", reqMeter.MeterId)
+
+		obis, err := meter.GetOBIS(ctx, reqMeter.Obis)
+		if err != nil {
+			return nil, err
+		}
+
+		return &proto.GetOBISResponse{
+			Value:        obis,
+			MeterId:      reqMeter.MeterId,
+			MeterIp:      reqMeter.Ip,
+			SerialNumber: reqMeter.SerialNumber,
+		}, nil
+	}, stream.Send)
+}
 
-	for _, reqMeter := range req.Meter {
-		wg.Add(1)
-		go func(reqMeter *proto.Meter) {
-			defer wg.Done()
+func (s *DLMSProcessorAPI) GetBlockLoadProfile(req *proto.GetBlockLoadProfileRequest, stream grpc.ServerStreamingServer[proto.GetBlockLoadProfileResponse]) error {
 
-			slog.Info("NewRealMeter for InstantaneousProfile", "ip", reqMeter.Ip, "port", reqMeter.Port)
-			var meter dlms.Meter
-			//meter, err := dlms.NewFakeMeter(reqMeter.Ip, int(reqMeter.Port))
-			meter, err := dlms.NewRealMeter(dlms.RealMeter{
-				MeterIP:           reqMeter.Ip,
-				MeterPort:         int(reqMeter.Port),
-				AuthPassword:      reqMeter.AuthPassword,
-				SystemTitle:       reqMeter.SystemTitle,
-				BlockCipherKey:    reqMeter.BlockCipherKey,
-				AuthenticationKey: reqMeter.AuthKey,
-			})
-			if err != nil {
-				slog.Error("NewRealMeter", "error", err)
-				errChan <- err
-				return
-			}
+	if err := s.admit(); err != nil {
+		return err
+	}
 
-			slog.Info("Connecting to meter for InstantaneousProfile")
-			err1 := meter.Connect()
+	if len(req.Meter) == 0 {
+		return status.Error(codes.InvalidArgument, "no meters provided")
+	}
 
-			if err1 != nil {
-				slog.Error("Connect", "error", err1)
-				errChan <- err1
-				return
-			}
-			slog.Info("Connected to meter for InstantaneousProfile")
+	return forEachMeter(s, stream.Context(), operationBlockLoadProfile, requestRetries(req.Retries, req.RetryDelay, req.Timeout), req.Meter, func(ctx context.Context, reqMeter *proto.Meter) (*proto.GetBlockLoadProfileResponse, error) {
+		slog.Info("Connecting to meter for BlockLoadProfile", "meter_id", reqMeter.MeterId, "ip", reqMeter.Ip, "port", reqMeter.Port)
+		meter, err := s.openMeter(ctx, reqMeter, req.ConnectionTimeout)
+		if err != nil {
+			return nil, err
+		}
+		defer meter.Close()
+		slog.Info("Connected to meter for BlockLoadProfile", "meter_id", reqMeter.MeterId)
+
+		profile, err := meter.GetBlockLoadProfile(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		return &proto.GetBlockLoadProfileResponse{
+			Profile:      blockLoadProfileToProto(profile),
+			MeterIp:      reqMeter.Ip,
+			MeterId:      reqMeter.MeterId,
+			SerialNumber: reqMeter.SerialNumber,
+		}, nil
+	}, stream.Send)
+}
 
-			profile, err := meter.GetInstantaneousProfile()
-			if err != nil {
-				errChan <- err
-				return
-			}
+func (s *DLMSProcessorAPI) GetDailyLoadProfile(req *proto.GetDailyLoadProfileRequest, stream grpc.ServerStreamingServer[proto.GetDailyLoadProfileResponse]) error {
 
-			// Convert from dlms.InstantaneousProfile to proto.InstantaneousProfile
-			protoProfile := &proto.InstantaneousProfile{
-				DateTime:          profile.DateTime,
-				Voltage:           profile.Voltage,
-				PhaseCurrent:      profile.PhaseCurrent,
-				NeutralCurrent:    profile.NeutralCurrent,
-				SignedPowerFactor: profile.SignedPowerFactor,
-				Frequency:         profile.Frequency,
-				ApparentPower:     profile.ApparentPower,
-				ActivePower:       profile.ActivePower,
-				CumEnergyWh:       profile.CumEnergyWh,
-			}
+	if err := s.admit(); err != nil {
+		return err
+	}
 
-			err = stream.Send(&proto.GetInstantaneousProfileResponse{
-				Profile: protoProfile,
-				MeterIp: reqMeter.Ip,
-			})
-			if err != nil {
-				errChan <- err
-				return
-			}
-		}(reqMeter)
+	if len(req.Meter) == 0 {
+		return status.Error(codes.InvalidArgument, "no meters provided")
 	}
 
-	wg.Wait()
+	return forEachMeter(s, stream.Context(), operationDailyLoadProfile, requestRetries(req.Retries, req.RetryDelay, req.Timeout), req.Meter, func(ctx context.Context, reqMeter *proto.Meter) (*proto.GetDailyLoadProfileResponse, error) {
+		slog.Info("Connecting to meter for DailyLoadProfile", "meter_id", reqMeter.MeterId, "ip", reqMeter.Ip, "port", reqMeter.Port)
+		meter, err := s.openMeter(ctx, reqMeter, req.ConnectionTimeout)
+		if err != nil {
+			return nil, err
+		}
+		defer meter.Close()
+		slog.Info("Connected to meter for DailyLoadProfile", "meter_id", reqMeter.MeterId)
+
+		profile, err := meter.GetDailyLoadProfile(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		return &proto.GetDailyLoadProfileResponse{
+			Profile:      dailyLoadProfileToProto(profile),
+			MeterIp:      reqMeter.Ip,
+			MeterId:      reqMeter.MeterId,
+			SerialNumber: reqMeter.SerialNumber,
+		}, nil
+	}, stream.Send)
+}
 
-	// Check for any errors
-	select {
-	case err := <-errChan:
+func (s *DLMSProcessorAPI) GetBillingDataProfile(req *proto.GetBillingDataProfileRequest, stream grpc.ServerStreamingServer[proto.GetBillingDataProfileResponse]) error {
+
+	if err := s.admit(); err != nil {
 		return err
-	default:
-		return nil
 	}
+
+	if len(req.Meter) == 0 {
+		return status.Error(codes.InvalidArgument, "no meters provided")
+	}
+
+	return forEachMeter(s, stream.Context(), operationBillingDataProfile, requestRetries(req.Retries, req.RetryDelay, req.Timeout), req.Meter, func(ctx context.Context, reqMeter *proto.Meter) (*proto.GetBillingDataProfileResponse, error) {
+		slog.Info("Connecting to meter for BillingDataProfile", "meter_id", reqMeter.MeterId, "ip", reqMeter.Ip, "port", reqMeter.Port)
+		meter, err := s.openMeter(ctx, reqMeter, req.ConnectionTimeout)
+		if err != nil {
+			return nil, err
+		}
+		defer meter.Close()
+		slog.Info("Connected to meter for BillingDataProfile", "meter_id", reqMeter.MeterId)
+
+		profile, err := meter.GetBillingDataProfile(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		return &proto.GetBillingDataProfileResponse{
+			Profile:      billingDataProfileToProto(profile),
+			MeterIp:      reqMeter.Ip,
+			MeterId:      reqMeter.MeterId,
+			SerialNumber: reqMeter.SerialNumber,
+		}, nil
+	}, stream.Send)
+}
+
+func (s *DLMSProcessorAPI) GetInstantaneousProfile(req *proto.GetInstantaneousProfileRequest, stream grpc.ServerStreamingServer[proto.GetInstantaneousProfileResponse]) error {
+
+	if err := s.admit(); err != nil {
+		return err
+	}
+
+	if len(req.Meter) == 0 {
+		return status.Error(codes.InvalidArgument, "no meters provided")
+	}
+
+	return forEachMeter(s, stream.Context(), operationInstantaneousProfile, requestRetries(req.Retries, req.RetryDelay, req.Timeout), req.Meter, func(ctx context.Context, reqMeter *proto.Meter) (*proto.GetInstantaneousProfileResponse, error) {
+		slog.Info("Connecting to meter for InstantaneousProfile", "meter_id", reqMeter.MeterId, "ip", reqMeter.Ip, "port", reqMeter.Port)
+		meter, err := s.openMeter(ctx, reqMeter, req.ConnectionTimeout)
+		if err != nil {
+			return nil, err
+		}
+		defer meter.Close()
+		slog.Info("Connected to meter for InstantaneousProfile", "meter_id", reqMeter.MeterId)
+
+		profile, err := meter.GetInstantaneousProfile(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		return &proto.GetInstantaneousProfileResponse{
+			Profile:      instantaneousProfileToProto(profile),
+			MeterIp:      reqMeter.Ip,
+			MeterId:      reqMeter.MeterId,
+			SerialNumber: reqMeter.SerialNumber,
+		}, nil
+	}, stream.Send)
+}
+
+func (s *DLMSProcessorAPI) GetNameplate(req *proto.GetNameplateRequest, stream grpc.ServerStreamingServer[proto.GetNameplateResponse]) error {
+	if err := s.admit(); err != nil {
+		return err
+	}
+
+	if len(req.Meter) == 0 {
+		return status.Error(codes.InvalidArgument, "no meters provided")
+	}
+
+	return forEachMeter(s, stream.Context(), operationNameplate, requestRetries(req.Retries, req.RetryDelay, req.Timeout), req.Meter, func(ctx context.Context, reqMeter *proto.Meter) (*proto.GetNameplateResponse, error) {
+		slog.Info("Connecting to meter for Nameplate", "meter_id", reqMeter.MeterId, "ip", reqMeter.Ip, "port", reqMeter.Port)
+		meter, err := s.openMeter(ctx, reqMeter, req.ConnectionTimeout)
+		if err != nil {
+			return nil, err
+		}
+		defer meter.Close()
+		slog.Info("Connected to meter for Nameplate", "meter_id", reqMeter.MeterId)
+
+		nameplate, err := meter.GetNameplate(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		return &proto.GetNameplateResponse{
+			Nameplate:    nameplateToProto(nameplate),
+			MeterIp:      reqMeter.Ip,
+			MeterId:      reqMeter.MeterId,
+			SerialNumber: reqMeter.SerialNumber,
+		}, nil
+	}, stream.Send)
 }
@@ -2,13 +2,16 @@ package api
 
 import (
 	"context"
+	"dlmsprocessor/dlms"
 	"dlmsprocessor/proto"
 	"io"
 	"net"
 	"testing"
 
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
 	"google.golang.org/grpc/test/bufconn"
 )
 
@@ -17,9 +20,13 @@ const bufSize = 1024 * 1024
 var lis *bufconn.Listener
 
 func init() {
+	meters, err := dlms.NewFakeMeters(dlms.Scenario{})
+	if err != nil {
+		panic(err)
+	}
 	lis = bufconn.Listen(bufSize)
 	s := grpc.NewServer()
-	proto.RegisterDLMSProcessorServer(s, NewDLMSProcessorAPI())
+	proto.RegisterDLMSProcessorServer(s, NewDLMSProcessorAPI(WithRawKeys(true), WithMeterFactory(meters)))
 	go func() {
 		if err := s.Serve(lis); err != nil {
 			panic(err)
@@ -179,22 +186,9 @@ func TestGetOBIS_EmptyMeterList(t *testing.T) {
 		t.Fatalf("GetOBIS failed: %v", err)
 	}
 
-	// Collect responses
-	var responses []*proto.GetOBISResponse
-	for {
-		resp, err := stream.Recv()
-		if err == io.EOF {
-			break
-		}
-		if err != nil {
-			t.Fatalf("Failed to receive response: %v", err)
-		}
-		responses = append(responses, resp)
-	}
-
-	// Should receive no responses for empty meter list
-	if len(responses) != 0 {
-		t.Errorf("Expected 0 responses for empty meter list, got %d", len(responses))
+	// An empty meter list is refused
+	if _, err := stream.Recv(); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("Expected InvalidArgument for empty meter list, got %v", err)
 	}
 }
 
@@ -0,0 +1,109 @@
+package api
+
+import (
+	"context"
+	"dlmsprocessor/dlms"
+	"errors"
+	"strconv"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ErrorDomain is the google.rpc.ErrorInfo domain of meter failures
+const ErrorDomain = "dlmsprocessor"
+
+// Reasons a meter operation failed, reported in the google.rpc.ErrorInfo
+// detail of its status and in OperationError.reason
+const (
+	ReasonTimeout             = "TIMEOUT"
+	ReasonConnectionRefused   = "CONNECTION_REFUSED"
+	ReasonHostUnreachable     = "HOST_UNREACHABLE"
+	ReasonConnectionLost      = "CONNECTION_LOST"
+	ReasonAuthentication      = "AUTHENTICATION_FAILED"
+	ReasonDecryption          = "DECRYPTION_FAILED"
+	ReasonAssociationRejected = "ASSOCIATION_REJECTED"
+	ReasonIdentityMismatch    = "IDENTITY_MISMATCH"
+	ReasonObjectUnavailable   = "OBJECT_UNAVAILABLE"
+	ReasonAccessDenied        = "ACCESS_DENIED"
+	ReasonTypeMismatch        = "TYPE_MISMATCH"
+	ReasonTemporaryFailure    = "TEMPORARY_FAILURE"
+	ReasonHardwareFault       = "HARDWARE_FAULT"
+	ReasonInvalidResponse     = "INVALID_RESPONSE"
+	ReasonTariffMismatch      = "TARIFF_MISMATCH"
+	ReasonNotSupported        = "NOT_SUPPORTED"
+)
+
+// errorReasons maps each kind of meter failure onto its gRPC code and
+// reason. Failures that another attempt cannot fix, such as wrong keys or a
+// missing object, map onto codes that are not retried.
+var errorReasons = []struct {
+	kind   error
+	code   codes.Code
+	reason string
+}{
+	{dlms.ErrTimeout, codes.DeadlineExceeded, ReasonTimeout},
+	{dlms.ErrConnectionRefused, codes.Unavailable, ReasonConnectionRefused},
+	{dlms.ErrHostUnreachable, codes.Unavailable, ReasonHostUnreachable},
+	{dlms.ErrConnectionLost, codes.Unavailable, ReasonConnectionLost},
+	{dlms.ErrAuthentication, codes.FailedPrecondition, ReasonAuthentication},
+	{dlms.ErrDecryption, codes.FailedPrecondition, ReasonDecryption},
+	{dlms.ErrAssociationRejected, codes.FailedPrecondition, ReasonAssociationRejected},
+	{dlms.ErrIdentityMismatch, codes.FailedPrecondition, ReasonIdentityMismatch},
+	{dlms.ErrObjectUnavailable, codes.NotFound, ReasonObjectUnavailable},
+	{dlms.ErrAccessDenied, codes.PermissionDenied, ReasonAccessDenied},
+	{dlms.ErrTypeMismatch, codes.InvalidArgument, ReasonTypeMismatch},
+	{dlms.ErrTemporaryFailure, codes.Unavailable, ReasonTemporaryFailure},
+	{dlms.ErrHardwareFault, codes.Internal, ReasonHardwareFault},
+	{dlms.ErrInvalidResponse, codes.Unavailable, ReasonInvalidResponse},
+	{dlms.ErrTariffMismatch, codes.FailedPrecondition, ReasonTariffMismatch},
+	{dlms.ErrNotSupported, codes.Unimplemented, ReasonNotSupported},
+}
+
+// statusFromError maps an operation error onto a gRPC status. Meter failures
+// carry a google.rpc.ErrorInfo with their reason and DLMS error code.
+func statusFromError(err error) *status.Status {
+	if st, ok := status.FromError(err); ok {
+		return st
+	}
+
+	code, reason := codes.Unknown, ""
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		code, reason = codes.DeadlineExceeded, ReasonTimeout
+	case errors.Is(err, context.Canceled):
+		code = codes.Canceled
+	default:
+		for _, r := range errorReasons {
+			if errors.Is(err, r.kind) {
+				code, reason = r.code, r.reason
+				break
+			}
+		}
+	}
+
+	st := status.New(code, err.Error())
+	if reason == "" {
+		return st
+	}
+	info := &errdetails.ErrorInfo{Reason: reason, Domain: ErrorDomain}
+	var dlmsErr *dlms.Error
+	if errors.As(err, &dlmsErr) {
+		info.Metadata = map[string]string{"dlms_code": strconv.Itoa(dlmsErr.Code)}
+	}
+	if detailed, err := st.WithDetails(info); err == nil {
+		return detailed
+	}
+	return st
+}
+
+// errorReason is the reason in st's ErrorInfo, if it has one
+func errorReason(st *status.Status) string {
+	for _, detail := range st.Details() {
+		if info, ok := detail.(*errdetails.ErrorInfo); ok && info.Domain == ErrorDomain {
+			return info.Reason
+		}
+	}
+	return ""
+}
@@ -0,0 +1,84 @@
+package api
+
+import (
+	"context"
+	"dlmsprocessor/dlms"
+	"dlmsprocessor/proto"
+	"errors"
+	"fmt"
+	"testing"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestStatusFromError(t *testing.T) {
+	refused := fmt.Errorf("failed to connect to meter: %w", &dlms.Error{Code: 0x2000006f, Kind: dlms.ErrConnectionRefused})
+
+	tests := []struct {
+		name   string
+		err    error
+		code   codes.Code
+		reason string
+	}{
+		{"connection refused", refused, codes.Unavailable, ReasonConnectionRefused},
+		{"meter timeout", &dlms.Error{Code: 253, Kind: dlms.ErrTimeout}, codes.DeadlineExceeded, ReasonTimeout},
+		{"deadline", fmt.Errorf("%w: %w", context.DeadlineExceeded, refused), codes.DeadlineExceeded, ReasonTimeout},
+		{"cancelled", fmt.Errorf("%w: %w", context.Canceled, refused), codes.Canceled, ""},
+		{"wrong keys", &dlms.Error{Code: 279, Kind: dlms.ErrAuthentication}, codes.FailedPrecondition, ReasonAuthentication},
+		{"access denied", &dlms.Error{Code: 3, Kind: dlms.ErrAccessDenied}, codes.PermissionDenied, ReasonAccessDenied},
+		{"no object", &dlms.Error{Code: 4, Kind: dlms.ErrObjectUnavailable}, codes.NotFound, ReasonObjectUnavailable},
+		{"identity", fmt.Errorf("%w: wrong meter", dlms.ErrIdentityMismatch), codes.FailedPrecondition, ReasonIdentityMismatch},
+		{"tariff", fmt.Errorf("%w: special days differ", dlms.ErrTariffMismatch), codes.FailedPrecondition, ReasonTariffMismatch},
+		{"not supported", fmt.Errorf("firmware upgrade: %w", dlms.ErrNotSupported), codes.Unimplemented, ReasonNotSupported},
+		{"unclassified", &dlms.Error{Code: 258}, codes.Unknown, ""},
+		{"status", status.Error(codes.InvalidArgument, "bad request"), codes.InvalidArgument, ""},
+		{"other", errors.New("no data found"), codes.Unknown, ""},
+	}
+
+	for _, tt := range tests {
+		st := statusFromError(tt.err)
+		if st.Code() != tt.code {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.code, st.Code())
+		}
+		if got := errorReason(st); got != tt.reason {
+			t.Errorf("%s: expected reason %q, got %q", tt.name, tt.reason, got)
+		}
+	}
+
+	for _, detail := range statusFromError(refused).Details() {
+		if info, ok := detail.(*errdetails.ErrorInfo); ok && info.Metadata["dlms_code"] != "536871023" {
+			t.Errorf("Expected the DLMS error code in the error info, got %v", info.Metadata)
+		}
+	}
+}
+
+func TestMeterFailureReason(t *testing.T) {
+	client := newProcessTestClient(t, NewDLMSProcessorAPI(WithRawKeys(true)))
+	meter := closingMeter(t)
+
+	stream, err := client.GetInstantaneousProfile(context.Background(), &proto.GetInstantaneousProfileRequest{
+		Meter:             []*proto.Meter{meter},
+		ConnectionTimeout: 1000,
+	})
+	if err == nil {
+		_, err = stream.Recv()
+	}
+	if st := status.Convert(err); st.Code() != codes.Unavailable || errorReason(st) != ReasonConnectionLost {
+		t.Errorf("Expected the meter hanging up to be reported as a lost connection, got %v", st.Proto())
+	}
+
+	process, err := client.Process(context.Background())
+	if err != nil {
+		t.Fatalf("Process: %v", err)
+	}
+	if err := process.Send(readInstantaneous("op", meter, 1000)); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	process.CloseSend()
+	resp := receiveAll(t, process)["op"]
+	if e := resp.GetError(); e == nil || codes.Code(e.Code) != codes.Unavailable || e.Reason != ReasonConnectionLost {
+		t.Errorf("Expected the operation to fail with %s, got %v", ReasonConnectionLost, resp)
+	}
+}
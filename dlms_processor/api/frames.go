@@ -0,0 +1,111 @@
+package api
+
+import (
+	"context"
+	"dlmsprocessor/auth"
+	"dlmsprocessor/dlms"
+	"dlmsprocessor/proto"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// validateFrameTrace checks that the server can trace frames as mode asks
+func (s *DLMSProcessorAPI) validateFrameTrace(mode proto.FrameTraceMode) error {
+	switch mode {
+	case proto.FrameTraceMode_FRAME_TRACE_MODE_NONE, proto.FrameTraceMode_FRAME_TRACE_MODE_RESPONSE:
+		return nil
+	case proto.FrameTraceMode_FRAME_TRACE_MODE_FILE:
+		if s.frameTraceDir == "" {
+			return status.Error(codes.FailedPrecondition, "frame trace files are not enabled on this server")
+		}
+		return nil
+	}
+	return status.Errorf(codes.InvalidArgument, "unknown frame trace mode %v", mode)
+}
+
+// startFrameTrace attaches a frame trace to ctx unless mode is NONE
+func startFrameTrace(ctx context.Context, mode proto.FrameTraceMode) (context.Context, *dlms.FrameTrace) {
+	if mode == proto.FrameTraceMode_FRAME_TRACE_MODE_NONE {
+		return ctx, nil
+	}
+	trace := dlms.NewFrameTrace()
+	return dlms.WithFrameTrace(ctx, trace), trace
+}
+
+// frameTraceResult returns the frames of trace, or the file it wrote them
+// to, as mode asks; nil if nothing was traced. The deciphered APDUs are only
+// returned to callers that may write to meters, as they hold what the keys
+// protect and a read-only caller may have sent a keyRef rather than keys.
+func (s *DLMSProcessorAPI) frameTraceResult(ctx context.Context, trace *dlms.FrameTrace, mode proto.FrameTraceMode, meterID string) *proto.FrameTrace {
+	if trace == nil {
+		return nil
+	}
+
+	result := &proto.FrameTrace{Truncated: trace.Truncated()}
+	if mode == proto.FrameTraceMode_FRAME_TRACE_MODE_FILE {
+		file, err := s.writeFrameTrace(trace, meterID)
+		if err != nil {
+			slog.Error("Failed to write frame trace", "meter_id", meterID, "error", err)
+		}
+		result.File = file
+		return result
+	}
+
+	deciphered := auth.Check(ctx, auth.Write) == nil
+	for _, f := range trace.Frames() {
+		direction := proto.FrameDirection_FRAME_DIRECTION_SENT
+		if f.Direction == dlms.Received {
+			direction = proto.FrameDirection_FRAME_DIRECTION_RECEIVED
+		}
+		frame := &proto.Frame{
+			TimestampUs: f.Time.UnixMicro(),
+			Direction:   direction,
+			Data:        f.Data,
+		}
+		if deciphered {
+			frame.Apdu = f.APDU
+		}
+		result.Frames = append(result.Frames, frame)
+	}
+	return result
+}
+
+// writeFrameTrace writes trace as a pcap file in frameTraceDir and returns
+// its name
+func (s *DLMSProcessorAPI) writeFrameTrace(trace *dlms.FrameTrace, meterID string) (string, error) {
+	pattern := fmt.Sprintf("%s-%s-*.pcap", fileSafe(meterID), time.Now().UTC().Format("20060102T150405Z"))
+	f, err := os.CreateTemp(s.frameTraceDir, pattern)
+	if err != nil {
+		return "", fmt.Errorf("creating frame trace file: %w", err)
+	}
+	if err := trace.WritePcap(f); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return "", err
+	}
+	if err := f.Close(); err != nil {
+		return "", fmt.Errorf("closing frame trace file: %w", err)
+	}
+	return filepath.Base(f.Name()), nil
+}
+
+// fileSafe replaces the characters of s that do not belong in a file name
+func fileSafe(s string) string {
+	if s == "" {
+		return "meter"
+	}
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_', r == '.':
+			return r
+		}
+		return '_'
+	}, s)
+}
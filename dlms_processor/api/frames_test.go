@@ -0,0 +1,136 @@
+package api
+
+import (
+	"context"
+	"crypto/sha256"
+	"dlmsprocessor/auth"
+	"dlmsprocessor/proto"
+	"encoding/binary"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestTraceFrames(t *testing.T) {
+	dir := t.TempDir()
+	client := newProcessTestClient(t, NewDLMSProcessorAPI(WithRawKeys(true), WithFrameTraceDir(dir)))
+	meter := closingMeter(t)
+
+	process, err := client.Process(context.Background())
+	if err != nil {
+		t.Fatalf("Process: %v", err)
+	}
+	traced := readInstantaneous("traced", meter, 1000)
+	traced.TraceFrames = proto.FrameTraceMode_FRAME_TRACE_MODE_RESPONSE
+	for _, req := range []*proto.ProcessRequest{traced, readInstantaneous("untraced", meter, 1000)} {
+		if err := process.Send(req); err != nil {
+			t.Fatalf("Send: %v", err)
+		}
+	}
+	process.CloseSend()
+	responses := receiveAll(t, process)
+
+	// The meter hangs up on the AARQ, which is still returned with the failure
+	frames := responses["traced"].GetFrames().GetFrames()
+	if responses["traced"].GetError() == nil || len(frames) == 0 {
+		t.Fatalf("Expected a failed read with its frames, got %v", responses["traced"])
+	}
+	if f := frames[0]; f.Direction != proto.FrameDirection_FRAME_DIRECTION_SENT || len(f.Apdu) == 0 || f.TimestampUs == 0 {
+		t.Errorf("Expected the AARQ with its deciphered InitiateRequest, got %v", f)
+	}
+	if responses["untraced"].GetFrames() != nil {
+		t.Errorf("Expected no frames unless asked for, got %v", responses["untraced"].GetFrames())
+	}
+
+	stream, err := client.Probe(context.Background(), &proto.ProbeRequest{
+		Meter:             []*proto.Meter{meter},
+		ConnectionTimeout: 1000,
+		TraceFrames:       proto.FrameTraceMode_FRAME_TRACE_MODE_FILE,
+	})
+	if err != nil {
+		t.Fatalf("Probe: %v", err)
+	}
+	resp, err := stream.Recv()
+	if err != nil {
+		t.Fatalf("Probe: %v", err)
+	}
+	file := resp.GetFrames().GetFile()
+	if file == "" || len(resp.GetFrames().GetFrames()) != 0 {
+		t.Fatalf("Expected the frames in a file, got %v", resp.GetFrames())
+	}
+	pcap, err := os.ReadFile(filepath.Join(dir, file))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pcap) <= 24 || binary.LittleEndian.Uint32(pcap) != 0xa1b2c3d4 {
+		t.Errorf("Expected a pcap file with packets, got %d bytes", len(pcap))
+	}
+}
+
+func TestTraceFramesToFileNeedsDir(t *testing.T) {
+	client := newProcessTestClient(t, NewDLMSProcessorAPI(WithRawKeys(true)))
+	meter := closingMeter(t)
+
+	process, err := client.Process(context.Background())
+	if err != nil {
+		t.Fatalf("Process: %v", err)
+	}
+	req := readInstantaneous("op", meter, 1000)
+	req.TraceFrames = proto.FrameTraceMode_FRAME_TRACE_MODE_FILE
+	if err := process.Send(req); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	process.CloseSend()
+	if e := receiveAll(t, process)["op"].GetError(); e == nil || codes.Code(e.Code) != codes.FailedPrecondition {
+		t.Errorf("Expected frame trace files to be refused, got %v", e)
+	}
+}
+
+func TestTraceFramesDecipheredForWriters(t *testing.T) {
+	hash := func(token string) string {
+		sum := sha256.Sum256([]byte(token))
+		return hex.EncodeToString(sum[:])
+	}
+	policy := &auth.Policy{Callers: []auth.CallerPolicy{
+		{Name: "billing", Role: "read-only", TokenSHA256: hash("billing-token")},
+		{Name: "scada", Role: "operator", TokenSHA256: hash("scada-token")},
+	}}
+	authorizer := auth.NewAuthorizer(policy, MethodRights, nil)
+	client := newProcessTestClient(t, NewDLMSProcessorAPI(WithRawKeys(true)),
+		grpc.StreamInterceptor(authorizer.StreamInterceptor()))
+	meter := closingMeter(t)
+
+	trace := func(token string) []*proto.Frame {
+		t.Helper()
+		ctx := metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer "+token)
+		process, err := client.Process(ctx)
+		if err != nil {
+			t.Fatalf("Process: %v", err)
+		}
+		req := readInstantaneous("traced", meter, 1000)
+		req.TraceFrames = proto.FrameTraceMode_FRAME_TRACE_MODE_RESPONSE
+		if err := process.Send(req); err != nil {
+			t.Fatalf("Send: %v", err)
+		}
+		process.CloseSend()
+		frames := receiveAll(t, process)["traced"].GetFrames().GetFrames()
+		if len(frames) == 0 {
+			t.Fatalf("Expected the AARQ to be traced")
+		}
+		return frames
+	}
+
+	for _, f := range trace("billing-token") {
+		if len(f.Apdu) != 0 {
+			t.Errorf("Expected no deciphered APDU for a read-only caller, got %v", f)
+		}
+	}
+	if f := trace("scada-token")[0]; len(f.Apdu) == 0 {
+		t.Errorf("Expected the deciphered InitiateRequest for an operator, got %v", f)
+	}
+}
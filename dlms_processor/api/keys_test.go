@@ -0,0 +1,69 @@
+package api
+
+import (
+	"context"
+	"dlmsprocessor/keys"
+	"dlmsprocessor/proto"
+	"strings"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	protobuf "google.golang.org/protobuf/proto"
+)
+
+func TestMeterKeys(t *testing.T) {
+	store, err := keys.NewFileStore([]byte(`{"keys": [
+		{"meterId": "meter-closing", "version": 1, "authPassword": "wwwwwwwwwwwwwwww", "authKey": "62626262626262626262626262626262", "blockCipherKey": "62626262626262626262626262626262"}
+	]}`))
+	if err != nil {
+		t.Fatalf("NewFileStore: %v", err)
+	}
+	meter := closingMeter(t)
+	referenced := protobuf.Clone(meter).(*proto.Meter)
+	referenced.AuthPassword, referenced.AuthKey, referenced.BlockCipherKey = "", "", ""
+	referenced.KeyRef = &proto.KeyRef{Version: 1}
+
+	withRef := func(ref *proto.KeyRef) *proto.Meter {
+		m := protobuf.Clone(referenced).(*proto.Meter)
+		m.KeyRef = ref
+		return m
+	}
+	both := protobuf.Clone(meter).(*proto.Meter)
+	both.KeyRef = &proto.KeyRef{}
+
+	testCases := []struct {
+		name      string
+		processor *DLMSProcessorAPI
+		meter     *proto.Meter
+		code      codes.Code // of the meter's failure; the meter always hangs up
+	}{
+		{"raw keys refused", NewDLMSProcessorAPI(WithKeyProvider(store)), meter, codes.InvalidArgument},
+		{"raw keys allowed", NewDLMSProcessorAPI(WithRawKeys(true)), meter, codes.Unavailable},
+		{"key reference", NewDLMSProcessorAPI(WithKeyProvider(store)), referenced, codes.Unavailable},
+		{"named meter", NewDLMSProcessorAPI(WithKeyProvider(store)), withRef(&proto.KeyRef{MeterId: "meter-closing"}), codes.Unavailable},
+		{"unknown version", NewDLMSProcessorAPI(WithKeyProvider(store)), withRef(&proto.KeyRef{Version: 2}), codes.NotFound},
+		{"unknown meter", NewDLMSProcessorAPI(WithKeyProvider(store)), withRef(&proto.KeyRef{MeterId: "meter-other"}), codes.NotFound},
+		{"no provider", NewDLMSProcessorAPI(), referenced, codes.FailedPrecondition},
+		{"reference and keys", NewDLMSProcessorAPI(WithKeyProvider(store), WithRawKeys(true)), both, codes.InvalidArgument},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			client := newProcessTestClient(t, tc.processor)
+			stream, err := client.GetInstantaneousProfile(context.Background(), &proto.GetInstantaneousProfileRequest{
+				Meter:             []*proto.Meter{tc.meter},
+				ConnectionTimeout: 1000,
+			})
+			if err == nil {
+				_, err = stream.Recv()
+			}
+			if code := status.Code(err); code != tc.code {
+				t.Errorf("Expected %v, got %v", tc.code, err)
+			}
+			if msg := status.Convert(err).Message(); strings.Contains(msg, "wwww") || strings.Contains(msg, "6262") {
+				t.Errorf("Error reveals a key: %s", msg)
+			}
+		})
+	}
+}
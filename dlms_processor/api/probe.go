@@ -0,0 +1,83 @@
+package api
+
+import (
+	"context"
+	"dlmsprocessor/dlms"
+	"dlmsprocessor/proto"
+	"log/slog"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Probe runs the connectivity and credential check on every requested meter
+// and streams one response per meter. Probes are never retried: a failed step
+// is the answer.
+func (s *DLMSProcessorAPI) Probe(req *proto.ProbeRequest, stream grpc.ServerStreamingServer[proto.ProbeResponse]) error {
+	if err := s.admit(); err != nil {
+		return err
+	}
+
+	if len(req.Meter) == 0 {
+		return status.Error(codes.InvalidArgument, "no meters provided")
+	}
+	if err := s.validateFrameTrace(req.TraceFrames); err != nil {
+		return err
+	}
+
+	return forEachMeter(s, stream.Context(), operationProbe, requestRetries(0, 0, req.Timeout), req.Meter, func(ctx context.Context, reqMeter *proto.Meter) (*proto.ProbeResponse, error) {
+		k, err := s.meterKeys(ctx, reqMeter)
+		if err != nil {
+			return nil, err
+		}
+
+		config, err := meterConfig(reqMeter, k, s.meterTimeout(req.ConnectionTimeout))
+		if err != nil {
+			return nil, err
+		}
+
+		ctx, trace := startFrameTrace(ctx, req.TraceFrames)
+		result := dlms.Probe(ctx, config)
+		resp := probeResultToProto(result)
+		resp.Frames = s.frameTraceResult(ctx, trace, req.TraceFrames, reqMeter.MeterId)
+		resp.MeterId = reqMeter.MeterId
+		resp.MeterIp = reqMeter.Ip
+		resp.SerialNumber = reqMeter.SerialNumber
+		if !resp.Ok {
+			slog.Warn("Meter probe failed", "meter_id", reqMeter.MeterId, "ip", reqMeter.Ip)
+		}
+		return resp, nil
+	}, stream.Send)
+}
+
+// probeResultToProto converts from dlms.ProbeResult to proto.ProbeResponse
+func probeResultToProto(result dlms.ProbeResult) *proto.ProbeResponse {
+	resp := &proto.ProbeResponse{Ok: result.OK()}
+	for _, step := range result.Steps {
+		p := &proto.ProbeStep{
+			Name:       step.Name,
+			Status:     proto.ProbeStepStatus_PROBE_STEP_STATUS_OK,
+			DurationMs: float64(step.Duration.Microseconds()) / 1000,
+			Reason:     step.Reason,
+			Detail:     step.Detail,
+		}
+		switch {
+		case step.Skipped:
+			p.Status = proto.ProbeStepStatus_PROBE_STEP_STATUS_SKIPPED
+		case step.Err != nil:
+			p.Status = proto.ProbeStepStatus_PROBE_STEP_STATUS_FAILED
+			p.Error = step.Err.Error()
+		}
+		if step.Err == nil {
+			switch step.Name {
+			case dlms.ProbeStepLogicalDeviceName:
+				resp.LogicalDeviceName = step.Detail
+			case dlms.ProbeStepFirmwareVersion:
+				resp.FirmwareVersion = step.Detail
+			}
+		}
+		resp.Steps = append(resp.Steps, p)
+	}
+	return resp
+}
@@ -0,0 +1,65 @@
+package api
+
+import (
+	"context"
+	"dlmsprocessor/dlms"
+	"dlmsprocessor/proto"
+	"io"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestProbe(t *testing.T) {
+	client := newProcessTestClient(t, NewDLMSProcessorAPI(WithRawKeys(true)))
+	meter := closingMeter(t)
+
+	stream, err := client.Probe(context.Background(), &proto.ProbeRequest{
+		Meter:             []*proto.Meter{meter},
+		ConnectionTimeout: 1000,
+	})
+	if err != nil {
+		t.Fatalf("Probe: %v", err)
+	}
+	resp, err := stream.Recv()
+	if err != nil {
+		t.Fatalf("Expected a failed probe to be a response, got %v", err)
+	}
+	if _, err := stream.Recv(); err != io.EOF {
+		t.Errorf("Expected one response per meter, got %v", err)
+	}
+
+	if resp.Ok || resp.MeterId != meter.MeterId {
+		t.Errorf("Expected a failed probe of %s, got %v", meter.MeterId, resp)
+	}
+	want := map[string]proto.ProbeStepStatus{
+		dlms.ProbeStepTCPConnect:        proto.ProbeStepStatus_PROBE_STEP_STATUS_OK,
+		dlms.ProbeStepLinkSetup:         proto.ProbeStepStatus_PROBE_STEP_STATUS_OK,
+		dlms.ProbeStepAssociation:       proto.ProbeStepStatus_PROBE_STEP_STATUS_FAILED,
+		dlms.ProbeStepAuthentication:    proto.ProbeStepStatus_PROBE_STEP_STATUS_SKIPPED,
+		dlms.ProbeStepLogicalDeviceName: proto.ProbeStepStatus_PROBE_STEP_STATUS_SKIPPED,
+		dlms.ProbeStepFirmwareVersion:   proto.ProbeStepStatus_PROBE_STEP_STATUS_SKIPPED,
+		dlms.ProbeStepRelease:           proto.ProbeStepStatus_PROBE_STEP_STATUS_SKIPPED,
+	}
+	if len(resp.Steps) != len(want) {
+		t.Fatalf("Expected %d steps, got %v", len(want), resp.Steps)
+	}
+	for _, step := range resp.Steps {
+		if step.Status != want[step.Name] {
+			t.Errorf("%s: expected %v, got %v", step.Name, want[step.Name], step.Status)
+		}
+		if step.Status == proto.ProbeStepStatus_PROBE_STEP_STATUS_FAILED && (step.Reason == "" || step.Error == "") {
+			t.Errorf("%s: failed without a reason", step.Name)
+		}
+	}
+
+	// Meters whose keys cannot be resolved fail the call, as other reads do
+	stream, err = newProcessTestClient(t, NewDLMSProcessorAPI()).Probe(context.Background(), &proto.ProbeRequest{Meter: []*proto.Meter{meter}})
+	if err == nil {
+		_, err = stream.Recv()
+	}
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("Expected raw keys to be refused, got %v", err)
+	}
+}
@@ -0,0 +1,523 @@
+package api
+
+import (
+	"context"
+	"dlmsprocessor/auth"
+	"dlmsprocessor/dlms"
+	"dlmsprocessor/metrics"
+	"dlmsprocessor/proto"
+	"dlmsprocessor/tracing"
+	"errors"
+	"io"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Process runs operations as the orchestrator streams them in and streams the
+// results back as they complete. Each stream is granted processWindow credits
+// up front and gets one back with every result; see the proto for details.
+func (s *DLMSProcessorAPI) Process(stream grpc.BidiStreamingServer[proto.ProcessRequest, proto.ProcessResponse]) error {
+	if err := s.admit(); err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithCancelCause(stream.Context())
+	defer cancel(nil)
+
+	// Operations on the same meter within a stream share one association
+	sessions := dlms.NewSessionPool(s.sessionOptions)
+	defer sessions.Close()
+
+	var sendMu sync.Mutex
+	send := func(resp *proto.ProcessResponse) error {
+		sendMu.Lock()
+		defer sendMu.Unlock()
+		return stream.Send(resp)
+	}
+
+	if err := send(&proto.ProcessResponse{Credits: int32(s.processWindow)}); err != nil {
+		return err
+	}
+
+	// Receive on a separate goroutine so that a drain is noticed while the
+	// orchestrator is idle
+	requests := make(chan *proto.ProcessRequest)
+	recvErr := make(chan error, 1)
+	go func() {
+		for {
+			req, err := stream.Recv()
+			if err != nil {
+				recvErr <- err
+				return
+			}
+			select {
+			case requests <- req:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	var wg sync.WaitGroup
+	var inFlight atomic.Int32
+
+	for {
+		var req *proto.ProcessRequest
+		select {
+		case req = <-requests:
+		case err := <-recvErr:
+			wg.Wait()
+			if err == io.EOF {
+				return streamError(ctx)
+			}
+			return err
+		case <-s.draining:
+			slog.Info("Draining Process stream", "in_flight", inFlight.Load())
+			wg.Wait()
+			if err := streamError(ctx); err != nil {
+				return err
+			}
+			return status.Error(codes.Unavailable, "server is shutting down")
+		case <-ctx.Done():
+			wg.Wait()
+			return streamError(ctx)
+		}
+
+		if int(inFlight.Load()) >= s.processWindow {
+			slog.Warn("Process operation sent without credit", "correlation_id", req.CorrelationId)
+			err := send(&proto.ProcessResponse{
+				CorrelationId: req.CorrelationId,
+				Result:        errorResult(status.Error(codes.ResourceExhausted, "operation sent without credit")),
+			})
+			if err != nil {
+				cancel(err)
+				wg.Wait()
+				return err
+			}
+			continue
+		}
+
+		inFlight.Add(1)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			resp := s.runOperation(ctx, sessions, req)
+
+			// Release the slot before granting the credit, so an operation
+			// sent in response to this result is never rejected
+			inFlight.Add(-1)
+			resp.Credits = 1
+			if err := send(resp); err != nil {
+				cancel(err)
+			}
+		}()
+	}
+}
+
+// streamError is what a Process stream returns once ctx is done: the send
+// error that cancelled it, the status of the caller's own cancellation, or
+// nil if ctx is still live
+func streamError(ctx context.Context) error {
+	err := context.Cause(ctx)
+	if err == nil {
+		return nil
+	}
+	if ctxErr := ctx.Err(); errors.Is(err, ctxErr) {
+		return status.FromContextError(ctxErr).Err()
+	}
+	return err
+}
+
+// runOperation runs a single Process operation and builds its response
+func (s *DLMSProcessorAPI) runOperation(ctx context.Context, sessions *dlms.SessionPool, req *proto.ProcessRequest) *proto.ProcessResponse {
+	resp := &proto.ProcessResponse{CorrelationId: req.CorrelationId}
+
+	if req.Meter == nil {
+		resp.Result = errorResult(status.Error(codes.InvalidArgument, "no meter provided"))
+		return resp
+	}
+	resp.MeterId = req.Meter.MeterId
+	resp.MeterIp = req.Meter.Ip
+	resp.SerialNumber = req.Meter.SerialNumber
+
+	if req.Operation == nil {
+		resp.Result = errorResult(status.Error(codes.InvalidArgument, "no operation provided"))
+		return resp
+	}
+
+	operation := operationName(req)
+	ctx, span := startMeterSpan(ctx, operation, req.Meter)
+	span.SetAttributes(attribute.String("dlms.correlation_id", req.CorrelationId))
+	defer func() { tracing.End(span, resultError(resp)) }()
+
+	right, params := operationRight(req)
+	if err := auth.Check(ctx, right); err != nil {
+		slog.Warn("Process operation refused", "correlation_id", req.CorrelationId, "meter_id", req.Meter.MeterId, "right", right)
+		s.auditOperation(ctx, req, right, params, auth.OutcomeDenied, err)
+		metrics.MeterOperations.WithLabelValues(operation, metrics.Outcome(err)).Inc()
+		resp.Result = errorResult(err)
+		return resp
+	}
+
+	release, err := s.acquireWorker(ctx)
+	if err != nil {
+		resp.Result = errorResult(err)
+		return resp
+	}
+	defer release()
+
+	if err := s.validateFrameTrace(req.TraceFrames); err != nil {
+		resp.Result = errorResult(err)
+		return resp
+	}
+
+	ctx, cancel := s.operationContext(ctx, time.Duration(req.Timeout)*time.Millisecond)
+	defer cancel()
+	ctx, trace := startFrameTrace(ctx, req.TraceFrames)
+
+	result, err := func() (any, error) {
+		k, err := s.meterKeys(ctx, req.Meter)
+		if err != nil {
+			return nil, err
+		}
+		config, err := meterConfig(req.Meter, k, s.meterTimeout(req.ConnectionTimeout))
+		if err != nil {
+			return nil, err
+		}
+		meter, err := s.meterFactory.NewMeter(config, sessions)
+		if err != nil {
+			return nil, err
+		}
+		if err := meter.Connect(ctx); err != nil {
+			return nil, err
+		}
+		defer meter.Close()
+
+		switch op := req.Operation.(type) {
+		case *proto.ProcessRequest_Read:
+			return s.runRead(ctx, meter, req.Meter.Model, op.Read)
+		case *proto.ProcessRequest_Write:
+			return nil, runWrite(ctx, meter, op.Write)
+		case *proto.ProcessRequest_Execute:
+			return runExecute(ctx, meter, op.Execute)
+		}
+		return nil, status.Error(codes.InvalidArgument, "unknown operation")
+	}()
+	resp.Frames = s.frameTraceResult(ctx, trace, req.TraceFrames, req.Meter.MeterId)
+	outcome := auth.OutcomeSucceeded
+	if err != nil {
+		outcome = auth.OutcomeFailed
+	}
+	s.auditOperation(ctx, req, right, params, outcome, err)
+	metrics.MeterOperations.WithLabelValues(operation, metrics.Outcome(err)).Inc()
+	if err != nil {
+		slog.Error("Process operation failed", "correlation_id", req.CorrelationId, "meter_id", req.Meter.MeterId, "error", err)
+		resp.Result = errorResult(err)
+		return resp
+	}
+
+	switch r := result.(type) {
+	case string:
+		resp.Result = &proto.ProcessResponse_Value{Value: r}
+	case *proto.BlockLoadProfile:
+		resp.Result = &proto.ProcessResponse_BlockLoadProfile{BlockLoadProfile: r}
+	case *proto.DailyLoadProfile:
+		resp.Result = &proto.ProcessResponse_DailyLoadProfile{DailyLoadProfile: r}
+	case *proto.BillingDataProfile:
+		resp.Result = &proto.ProcessResponse_BillingDataProfile{BillingDataProfile: r}
+	case *proto.InstantaneousProfile:
+		resp.Result = &proto.ProcessResponse_InstantaneousProfile{InstantaneousProfile: r}
+	case *proto.NameplateProfile:
+		resp.Result = &proto.ProcessResponse_Nameplate{Nameplate: r}
+	case *proto.ProfileData:
+		resp.Result = &proto.ProcessResponse_ProfileData{ProfileData: r}
+	}
+
+	return resp
+}
+
+func (s *DLMSProcessorAPI) runRead(ctx context.Context, meter dlms.Meter, model string, op *proto.ReadOperation) (any, error) {
+	switch target := op.GetTarget().(type) {
+	case *proto.ReadOperation_Attribute:
+		a := target.Attribute
+		if err := validateAttribute(a); err != nil {
+			return nil, err
+		}
+		return meter.ReadAttribute(ctx, a.Obis, int(a.ObjectType), int(a.AttributeIndex))
+
+	case *proto.ReadOperation_Profile:
+		switch target.Profile {
+		case proto.ProfileType_PROFILE_TYPE_BLOCK_LOAD:
+			profile, err := meter.GetBlockLoadProfile(ctx)
+			if err != nil {
+				return nil, err
+			}
+			return blockLoadProfileToProto(profile), nil
+		case proto.ProfileType_PROFILE_TYPE_DAILY_LOAD:
+			profile, err := meter.GetDailyLoadProfile(ctx)
+			if err != nil {
+				return nil, err
+			}
+			return dailyLoadProfileToProto(profile), nil
+		case proto.ProfileType_PROFILE_TYPE_BILLING_DATA:
+			profile, err := meter.GetBillingDataProfile(ctx)
+			if err != nil {
+				return nil, err
+			}
+			return billingDataProfileToProto(profile), nil
+		case proto.ProfileType_PROFILE_TYPE_INSTANTANEOUS:
+			profile, err := meter.GetInstantaneousProfile(ctx)
+			if err != nil {
+				return nil, err
+			}
+			return instantaneousProfileToProto(profile), nil
+		case proto.ProfileType_PROFILE_TYPE_NAMEPLATE:
+			nameplate, err := meter.GetNameplate(ctx)
+			if err != nil {
+				return nil, err
+			}
+			return nameplateToProto(nameplate), nil
+		}
+		return nil, status.Errorf(codes.InvalidArgument, "unsupported profile %v", target.Profile)
+
+	case *proto.ReadOperation_DefinedProfile:
+		if s.profiles == nil {
+			return nil, status.Error(codes.FailedPrecondition, "no profile definitions configured")
+		}
+		def, ok := s.profiles.Select(target.DefinedProfile, model)
+		if !ok {
+			return nil, status.Errorf(codes.NotFound, "no definition of profile %q for model %q", target.DefinedProfile, model)
+		}
+		data, err := meter.ReadProfile(ctx, *def)
+		if err != nil {
+			return nil, err
+		}
+		return profileDataToProto(data), nil
+	}
+
+	return nil, status.Error(codes.InvalidArgument, "no read target provided")
+}
+
+func runWrite(ctx context.Context, meter dlms.Meter, op *proto.WriteOperation) error {
+	a := op.GetAttribute()
+	if err := validateAttribute(a); err != nil {
+		return err
+	}
+
+	value, err := writeValue(op)
+	if err != nil {
+		return err
+	}
+	return meter.WriteAttribute(ctx, a.Obis, int(a.ObjectType), int(a.AttributeIndex), value)
+}
+
+// writeValue is the Go value to write for op
+func writeValue(op *proto.WriteOperation) (any, error) {
+	switch v := op.Value.(type) {
+	case *proto.WriteOperation_Int32Value:
+		return v.Int32Value, nil
+	case *proto.WriteOperation_Uint32Value:
+		return v.Uint32Value, nil
+	case *proto.WriteOperation_Float64Value:
+		return v.Float64Value, nil
+	case *proto.WriteOperation_BoolValue:
+		return v.BoolValue, nil
+	case *proto.WriteOperation_StringValue:
+		return v.StringValue, nil
+	case *proto.WriteOperation_OctetStringValue:
+		return v.OctetStringValue, nil
+	case *proto.WriteOperation_DateTimeValue:
+		t, err := time.Parse(time.RFC3339, v.DateTimeValue)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid date time %q: %v", v.DateTimeValue, err)
+		}
+		return t, nil
+	}
+	return nil, status.Error(codes.InvalidArgument, "no value provided")
+}
+
+// writeValueShape is the type of the value op writes and, for strings and
+// octet strings, its length in bytes; -1 otherwise
+func writeValueShape(op *proto.WriteOperation) (string, int) {
+	switch v := op.Value.(type) {
+	case *proto.WriteOperation_Int32Value:
+		return "int32", -1
+	case *proto.WriteOperation_Uint32Value:
+		return "uint32", -1
+	case *proto.WriteOperation_Float64Value:
+		return "float64", -1
+	case *proto.WriteOperation_BoolValue:
+		return "bool", -1
+	case *proto.WriteOperation_StringValue:
+		return "string", len(v.StringValue)
+	case *proto.WriteOperation_OctetStringValue:
+		return "octet_string", len(v.OctetStringValue)
+	case *proto.WriteOperation_DateTimeValue:
+		return "date_time", -1
+	}
+	return "", -1
+}
+
+// FOTAFunction is the execute function that starts a firmware upgrade
+const FOTAFunction = "fota"
+
+func runExecute(ctx context.Context, meter dlms.Meter, op *proto.ExecuteOperation) (any, error) {
+	switch op.Function {
+	case "":
+	case FOTAFunction:
+		return nil, meter.FOTA(ctx)
+	default:
+		return nil, status.Errorf(codes.InvalidArgument, "unknown function %q", op.Function)
+	}
+
+	m := op.GetMethod()
+	if err := validateMethod(m); err != nil {
+		return nil, err
+	}
+	return nil, meter.Invoke(ctx, m.Obis, int(m.ObjectType), int(m.MethodIndex), op.Data)
+}
+
+// operationName names a Process operation in metrics
+func operationName(req *proto.ProcessRequest) string {
+	switch op := req.Operation.(type) {
+	case *proto.ProcessRequest_Read:
+		if op.Read.GetDefinedProfile() != "" {
+			return operationDefinedProfile
+		}
+		switch op.Read.GetProfile() {
+		case proto.ProfileType_PROFILE_TYPE_BLOCK_LOAD:
+			return operationBlockLoadProfile
+		case proto.ProfileType_PROFILE_TYPE_DAILY_LOAD:
+			return operationDailyLoadProfile
+		case proto.ProfileType_PROFILE_TYPE_BILLING_DATA:
+			return operationBillingDataProfile
+		case proto.ProfileType_PROFILE_TYPE_INSTANTANEOUS:
+			return operationInstantaneousProfile
+		case proto.ProfileType_PROFILE_TYPE_NAMEPLATE:
+			return operationNameplate
+		}
+		return operationReadAttribute
+	case *proto.ProcessRequest_Write:
+		return operationWriteAttribute
+	case *proto.ProcessRequest_Execute:
+		if op.Execute.Function == FOTAFunction {
+			return operationFOTA
+		}
+	}
+	return operationExecute
+}
+
+// operationRight is the right a Process operation needs, with the
+// parameters recorded when auditing it
+func operationRight(req *proto.ProcessRequest) (auth.Right, map[string]any) {
+	switch op := req.Operation.(type) {
+	case *proto.ProcessRequest_Write:
+		a := op.Write.GetAttribute()
+		params := map[string]any{
+			"obis":            a.GetObis(),
+			"object_type":     a.GetObjectType(),
+			"attribute_index": a.GetAttributeIndex(),
+		}
+		// The value may be a secret, such as an LLS password or a key, so
+		// only its type and size are audited
+		if kind, size := writeValueShape(op.Write); kind != "" {
+			params["value_type"] = kind
+			if size >= 0 {
+				params["value_length"] = size
+			}
+		}
+		return auth.Write, params
+	case *proto.ProcessRequest_Execute:
+		if op.Execute.Function != "" {
+			params := map[string]any{"function": op.Execute.Function}
+			if op.Execute.Function == FOTAFunction {
+				return auth.FOTA, params
+			}
+			return auth.Execute, params
+		}
+		// Like a written value, the parameter may be a secret, e.g. a key
+		// being transferred
+		m := op.Execute.GetMethod()
+		return auth.Execute, map[string]any{
+			"obis":         m.GetObis(),
+			"object_type":  m.GetObjectType(),
+			"method_index": m.GetMethodIndex(),
+			"data_length":  len(op.Execute.Data),
+		}
+	}
+	return auth.Read, nil
+}
+
+// auditOperation records a Process operation if it changes state
+func (s *DLMSProcessorAPI) auditOperation(ctx context.Context, req *proto.ProcessRequest, right auth.Right, params map[string]any, outcome string, err error) {
+	if right == auth.Read {
+		return
+	}
+	method, _ := grpc.Method(ctx)
+	rec := auth.AuditRecord{
+		Method:        method,
+		CorrelationID: req.CorrelationId,
+		Right:         right,
+		Meters:        []string{req.Meter.MeterId},
+		Parameters:    params,
+		Outcome:       outcome,
+	}
+	if err != nil {
+		rec.Error = err.Error()
+	}
+	s.audit.Audit(ctx, rec)
+}
+
+func validateAttribute(a *proto.AttributeReference) error {
+	switch {
+	case a == nil:
+		return status.Error(codes.InvalidArgument, "no attribute provided")
+	case a.Obis == "":
+		return status.Error(codes.InvalidArgument, "no OBIS code provided")
+	case a.ObjectType <= 0:
+		return status.Error(codes.InvalidArgument, "object type must be positive")
+	case a.AttributeIndex <= 0:
+		return status.Error(codes.InvalidArgument, "attribute index must be positive")
+	}
+	return nil
+}
+
+func validateMethod(m *proto.MethodReference) error {
+	switch {
+	case m == nil:
+		return status.Error(codes.InvalidArgument, "no method provided")
+	case m.Obis == "":
+		return status.Error(codes.InvalidArgument, "no OBIS code provided")
+	case m.ObjectType <= 0:
+		return status.Error(codes.InvalidArgument, "object type must be positive")
+	case m.MethodIndex <= 0:
+		return status.Error(codes.InvalidArgument, "method index must be positive")
+	}
+	return nil
+}
+
+// resultError is the error a response reports, if any
+func resultError(resp *proto.ProcessResponse) error {
+	if e := resp.GetError(); e != nil {
+		return status.Error(codes.Code(e.Code), e.Message)
+	}
+	return nil
+}
+
+// errorResult reports a failed operation in its ProcessResponse
+func errorResult(err error) *proto.ProcessResponse_Error {
+	st := statusFromError(err)
+	return &proto.ProcessResponse_Error{Error: &proto.OperationError{
+		Code:    int32(st.Code()),
+		Message: st.Message(),
+		Reason:  errorReason(st),
+	}}
+}
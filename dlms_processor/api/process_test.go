@@ -0,0 +1,402 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"dlmsprocessor/auth"
+	"dlmsprocessor/proto"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// newProcessTestClient serves processor over bufconn
+func newProcessTestClient(t *testing.T, processor *DLMSProcessorAPI, opts ...grpc.ServerOption) proto.DLMSProcessorClient {
+	t.Helper()
+
+	lis := bufconn.Listen(bufSize)
+	s := grpc.NewServer(opts...)
+	proto.RegisterDLMSProcessorServer(s, processor)
+	go s.Serve(lis)
+	t.Cleanup(s.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) { return lis.Dial() }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	return proto.NewDLMSProcessorClient(conn)
+}
+
+// silentMeter accepts connections and never answers
+func silentMeter(t *testing.T) *proto.Meter {
+	t.Helper()
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	t.Cleanup(func() { lis.Close() })
+
+	go func() {
+		for {
+			conn, err := lis.Accept()
+			if err != nil {
+				return
+			}
+			t.Cleanup(func() { conn.Close() })
+		}
+	}()
+
+	addr := lis.Addr().(*net.TCPAddr)
+	return &proto.Meter{
+		Ip:             addr.IP.String(),
+		Port:           int32(addr.Port),
+		MeterId:        "meter-silent",
+		AuthPassword:   "wwwwwwwwwwwwwwww",
+		SystemTitle:    "4142434445464748",
+		BlockCipherKey: "62626262626262626262626262626262",
+		AuthKey:        "62626262626262626262626262626262",
+	}
+}
+
+// readInstantaneous is an instantaneous profile read with a timeout in milliseconds
+func readInstantaneous(id string, meter *proto.Meter, timeout int32) *proto.ProcessRequest {
+	return &proto.ProcessRequest{
+		CorrelationId: id,
+		Meter:         meter,
+		Timeout:       timeout,
+		Operation:     &proto.ProcessRequest_Read{Read: &proto.ReadOperation{Target: &proto.ReadOperation_Profile{Profile: proto.ProfileType_PROFILE_TYPE_INSTANTANEOUS}}},
+	}
+}
+
+// receiveAll reads responses until EOF, keyed by correlation id
+func receiveAll(t *testing.T, stream grpc.BidiStreamingClient[proto.ProcessRequest, proto.ProcessResponse]) map[string]*proto.ProcessResponse {
+	t.Helper()
+
+	responses := make(map[string]*proto.ProcessResponse)
+	for {
+		resp, err := stream.Recv()
+		if err == io.EOF {
+			return responses
+		}
+		if err != nil {
+			t.Fatalf("Failed to receive response: %v", err)
+		}
+		responses[resp.CorrelationId] = resp
+	}
+}
+
+func TestProcess_InitialCreditsAndValidation(t *testing.T) {
+	client := newProcessTestClient(t, NewDLMSProcessorAPI(WithRawKeys(true)))
+
+	stream, err := client.Process(context.Background())
+	if err != nil {
+		t.Fatalf("Process failed: %v", err)
+	}
+
+	grant, err := stream.Recv()
+	if err != nil {
+		t.Fatalf("Failed to receive credit grant: %v", err)
+	}
+	if grant.Credits != DefaultProcessWindow {
+		t.Errorf("Expected %d initial credits, got %d", DefaultProcessWindow, grant.Credits)
+	}
+
+	requests := []*proto.ProcessRequest{
+		{CorrelationId: "no-meter"},
+		{CorrelationId: "no-operation", Meter: &proto.Meter{Ip: "127.0.0.1", Port: 4059, MeterId: "meter-0001"}},
+		{
+			CorrelationId: "no-obis",
+			Meter:         &proto.Meter{Ip: "127.0.0.1", Port: 4059, MeterId: "meter-0001"},
+			Operation:     &proto.ProcessRequest_Read{Read: &proto.ReadOperation{Target: &proto.ReadOperation_Attribute{Attribute: &proto.AttributeReference{ObjectType: 3, AttributeIndex: 2}}}},
+		},
+	}
+	for _, req := range requests {
+		if err := stream.Send(req); err != nil {
+			t.Fatalf("Failed to send %s: %v", req.CorrelationId, err)
+		}
+	}
+	stream.CloseSend()
+
+	responses := receiveAll(t, stream)
+	if len(responses) != len(requests) {
+		t.Fatalf("Expected %d responses, got %d", len(requests), len(responses))
+	}
+
+	for _, req := range requests {
+		resp, ok := responses[req.CorrelationId]
+		if !ok {
+			t.Errorf("No response for %s", req.CorrelationId)
+			continue
+		}
+		if code := codes.Code(resp.GetError().GetCode()); code != codes.InvalidArgument {
+			t.Errorf("%s: expected InvalidArgument, got %v", req.CorrelationId, code)
+		}
+		if resp.Credits != 1 {
+			t.Errorf("%s: expected the credit back, got %d", req.CorrelationId, resp.Credits)
+		}
+		if req.Meter != nil && resp.MeterId != req.Meter.MeterId {
+			t.Errorf("%s: expected meter id %q, got %q", req.CorrelationId, req.Meter.MeterId, resp.MeterId)
+		}
+	}
+}
+
+func TestProcess_CreditBackpressure(t *testing.T) {
+	client := newProcessTestClient(t, NewDLMSProcessorAPI(WithRawKeys(true), WithProcessWindow(2)))
+	meter := silentMeter(t)
+
+	stream, err := client.Process(context.Background())
+	if err != nil {
+		t.Fatalf("Process failed: %v", err)
+	}
+
+	grant, err := stream.Recv()
+	if err != nil {
+		t.Fatalf("Failed to receive credit grant: %v", err)
+	}
+	if grant.Credits != 2 {
+		t.Fatalf("Expected 2 initial credits, got %d", grant.Credits)
+	}
+
+	start := time.Now()
+	for _, id := range []string{"first", "second", "over-window"} {
+		if err := stream.Send(readInstantaneous(id, meter, 300)); err != nil {
+			t.Fatalf("Failed to send %s: %v", id, err)
+		}
+	}
+
+	// The operation sent without credit is rejected straight away
+	resp, err := stream.Recv()
+	if err != nil {
+		t.Fatalf("Failed to receive response: %v", err)
+	}
+	if resp.CorrelationId != "over-window" || codes.Code(resp.GetError().GetCode()) != codes.ResourceExhausted {
+		t.Fatalf("Expected over-window to be rejected with ResourceExhausted, got %v", resp)
+	}
+	if resp.Credits != 0 {
+		t.Errorf("Expected no credit for a rejected operation, got %d", resp.Credits)
+	}
+	if elapsed := time.Since(start); elapsed > 250*time.Millisecond {
+		t.Errorf("Rejection took %v", elapsed)
+	}
+
+	// Both admitted operations time out against the silent meter and return their credit
+	credits := 0
+	for range 2 {
+		resp, err := stream.Recv()
+		if err != nil {
+			t.Fatalf("Failed to receive response: %v", err)
+		}
+		if code := codes.Code(resp.GetError().GetCode()); code != codes.DeadlineExceeded {
+			t.Errorf("%s: expected DeadlineExceeded, got %v (%s)", resp.CorrelationId, code, resp.GetError().GetMessage())
+		}
+		credits += int(resp.Credits)
+	}
+	if credits != 2 {
+		t.Fatalf("Expected 2 credits back, got %d", credits)
+	}
+
+	// With credit available again the next operation is admitted
+	if err := stream.Send(&proto.ProcessRequest{CorrelationId: "after-credit"}); err != nil {
+		t.Fatalf("Failed to send: %v", err)
+	}
+	stream.CloseSend()
+
+	responses := receiveAll(t, stream)
+	if code := codes.Code(responses["after-credit"].GetError().GetCode()); code != codes.InvalidArgument {
+		t.Errorf("Expected after-credit to be admitted and fail validation, got %v", code)
+	}
+}
+
+func TestProcess_DrainFinishesInFlightWork(t *testing.T) {
+	processor := NewDLMSProcessorAPI(WithRawKeys(true))
+	client := newProcessTestClient(t, processor)
+	meter := silentMeter(t)
+
+	stream, err := client.Process(context.Background())
+	if err != nil {
+		t.Fatalf("Process failed: %v", err)
+	}
+	if _, err := stream.Recv(); err != nil {
+		t.Fatalf("Failed to receive credit grant: %v", err)
+	}
+
+	if err := stream.Send(readInstantaneous("in-flight", meter, 300)); err != nil {
+		t.Fatalf("Failed to send: %v", err)
+	}
+	// Let the operation get under way before draining
+	time.Sleep(50 * time.Millisecond)
+	processor.Drain()
+
+	resp, err := stream.Recv()
+	if err != nil {
+		t.Fatalf("Expected the in-flight result before the stream ends, got %v", err)
+	}
+	if resp.CorrelationId != "in-flight" {
+		t.Errorf("Expected the in-flight result, got %v", resp)
+	}
+
+	if _, err := stream.Recv(); status.Code(err) != codes.Unavailable {
+		t.Errorf("Expected the stream to end with Unavailable, got %v", err)
+	}
+
+	obis, err := client.GetOBIS(context.Background(), &proto.GetOBISRequest{Meter: []*proto.Meter{meter}})
+	if err == nil {
+		_, err = obis.Recv()
+	}
+	if status.Code(err) != codes.Unavailable {
+		t.Errorf("Expected new calls to be refused with Unavailable, got %v", err)
+	}
+}
+
+func TestProcess_AuthorizesEachOperation(t *testing.T) {
+	hash := func(token string) string {
+		sum := sha256.Sum256([]byte(token))
+		return hex.EncodeToString(sum[:])
+	}
+	policy := &auth.Policy{Callers: []auth.CallerPolicy{
+		{Name: "billing", Role: "read-only", TokenSHA256: hash("billing-token")},
+		{Name: "scada", Role: "operator", TokenSHA256: hash("scada-token")},
+	}}
+	var audit bytes.Buffer
+	auditLog := auth.NewAuditLog(&audit)
+	authorizer := auth.NewAuthorizer(policy, MethodRights, auditLog)
+
+	client := newProcessTestClient(t, NewDLMSProcessorAPI(WithRawKeys(true), WithAuditLog(auditLog)),
+		grpc.UnaryInterceptor(authorizer.UnaryInterceptor()),
+		grpc.StreamInterceptor(authorizer.StreamInterceptor()))
+	meter := silentMeter(t)
+
+	setClock := func(id string) *proto.ProcessRequest {
+		return &proto.ProcessRequest{
+			CorrelationId: id,
+			Meter:         meter,
+			Timeout:       300,
+			Operation: &proto.ProcessRequest_Write{Write: &proto.WriteOperation{
+				Attribute: &proto.AttributeReference{Obis: "0.0.1.0.0.255", ObjectType: 8, AttributeIndex: 2},
+				Value:     &proto.WriteOperation_DateTimeValue{DateTimeValue: "2026-01-01T00:00:00Z"},
+			}},
+		}
+	}
+	fota := &proto.ProcessRequest{
+		CorrelationId: "fota",
+		Meter:         meter,
+		Operation:     &proto.ProcessRequest_Execute{Execute: &proto.ExecuteOperation{Function: FOTAFunction}},
+	}
+
+	run := func(token string, requests ...*proto.ProcessRequest) map[string]*proto.ProcessResponse {
+		t.Helper()
+		ctx := metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer "+token)
+		stream, err := client.Process(ctx)
+		if err != nil {
+			t.Fatalf("Process failed: %v", err)
+		}
+		for _, req := range requests {
+			if err := stream.Send(req); err != nil {
+				t.Fatalf("Failed to send %s: %v", req.CorrelationId, err)
+			}
+		}
+		stream.CloseSend()
+		return receiveAll(t, stream)
+	}
+
+	responses := run("billing-token", setClock("billing-write"))
+	if code := codes.Code(responses["billing-write"].GetError().GetCode()); code != codes.PermissionDenied {
+		t.Errorf("Expected a read-only write to be refused, got %v", code)
+	}
+
+	responses = run("scada-token", setClock("scada-write"), fota)
+	if code := codes.Code(responses["scada-write"].GetError().GetCode()); code != codes.DeadlineExceeded {
+		t.Errorf("Expected an operator write to reach the meter, got %v", code)
+	}
+	if code := codes.Code(responses["fota"].GetError().GetCode()); code != codes.PermissionDenied {
+		t.Errorf("Expected an operator FOTA to be refused, got %v", code)
+	}
+
+	stream, err := client.Process(context.Background())
+	if err == nil {
+		_, err = stream.Recv()
+	}
+	if status.Code(err) != codes.Unauthenticated {
+		t.Errorf("Expected a stream without credentials to be refused, got %v", err)
+	}
+
+	outcomes := make(map[string]string)
+	for _, line := range strings.Split(strings.TrimSpace(audit.String()), "\n") {
+		var rec auth.AuditRecord
+		if err := json.Unmarshal([]byte(line), &rec); err != nil {
+			t.Fatalf("Bad audit record %q: %v", line, err)
+		}
+		if len(rec.Meters) != 1 || rec.Meters[0] != meter.MeterId || rec.Method != proto.DLMSProcessor_Process_FullMethodName {
+			t.Errorf("Unexpected audit record %+v", rec)
+		}
+		outcomes[rec.CorrelationID] = rec.Caller + " " + rec.Outcome
+	}
+	expected := map[string]string{
+		"billing-write": "billing denied",
+		"scada-write":   "scada failed",
+		"fota":          "scada denied",
+	}
+	for id, want := range expected {
+		if outcomes[id] != want {
+			t.Errorf("%s: expected audit %q, got %q", id, want, outcomes[id])
+		}
+	}
+	if len(outcomes) != len(expected) {
+		t.Errorf("Expected %d audit records, got %v", len(expected), outcomes)
+	}
+}
+
+func TestProcess_AuditsWriteValueShape(t *testing.T) {
+	var audit bytes.Buffer
+	client := newProcessTestClient(t, NewDLMSProcessorAPI(WithRawKeys(true), WithAuditLog(auth.NewAuditLog(&audit))))
+	meter := silentMeter(t)
+
+	stream, err := client.Process(context.Background())
+	if err != nil {
+		t.Fatalf("Process failed: %v", err)
+	}
+	// The LLS secret of the association
+	secret := []byte("new-lls-secret")
+	if err := stream.Send(&proto.ProcessRequest{
+		CorrelationId: "secret",
+		Meter:         meter,
+		Timeout:       300,
+		Operation: &proto.ProcessRequest_Write{Write: &proto.WriteOperation{
+			Attribute: &proto.AttributeReference{Obis: "0.0.40.0.0.255", ObjectType: 15, AttributeIndex: 7},
+			Value:     &proto.WriteOperation_OctetStringValue{OctetStringValue: secret},
+		}},
+	}); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	stream.CloseSend()
+	receiveAll(t, stream)
+
+	var rec auth.AuditRecord
+	if err := json.Unmarshal(bytes.TrimSpace(audit.Bytes()), &rec); err != nil {
+		t.Fatalf("Bad audit record %q: %v", audit.String(), err)
+	}
+	if rec.Parameters["value_type"] != "octet_string" || rec.Parameters["value_length"] != float64(len(secret)) {
+		t.Errorf("Expected the value's type and length audited, got %v", rec.Parameters)
+	}
+	if _, ok := rec.Parameters["value"]; ok || strings.Contains(audit.String(), base64.StdEncoding.EncodeToString(secret)) {
+		t.Errorf("Expected the written value kept out of the audit log, got %s", audit.String())
+	}
+}
@@ -0,0 +1,391 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"dlmsprocessor/auth"
+	"dlmsprocessor/dlms"
+	"dlmsprocessor/proto"
+	"dlmsprocessor/simulator"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	protobuf "google.golang.org/protobuf/proto"
+)
+
+// simulatedMeter starts a simulated meter with the default model
+func simulatedMeter(t *testing.T) *proto.Meter {
+	t.Helper()
+
+	m, err := simulator.Start(simulator.DefaultConfig(time.Now()), "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to start simulator: %v", err)
+	}
+	t.Cleanup(func() { m.Close() })
+
+	config := m.RealMeter()
+	return &proto.Meter{
+		MeterId:           "meter-simulated",
+		Ip:                config.MeterIP,
+		Port:              int32(config.MeterPort),
+		LogicalDeviceName: config.LogicalDeviceName,
+		SystemTitle:       config.SystemTitle,
+		BlockCipherKey:    config.BlockCipherKey,
+		AuthKey:           config.AuthenticationKey,
+	}
+}
+
+func TestSimulatorProcess(t *testing.T) {
+	client := newProcessTestClient(t, NewDLMSProcessorAPI(WithRawKeys(true)))
+	meter := simulatedMeter(t)
+
+	process, err := client.Process(context.Background())
+	if err != nil {
+		t.Fatalf("Process: %v", err)
+	}
+	if err := process.Send(readInstantaneous("instantaneous", meter, 5000)); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	process.CloseSend()
+
+	resp := receiveAll(t, process)["instantaneous"]
+	if resp.GetError() != nil {
+		t.Fatalf("Expected the read to succeed, got %v", resp.GetError())
+	}
+	if p := resp.GetInstantaneousProfile(); p.GetVoltage() != 230.1 || p.GetFrequency() != 50 {
+		t.Errorf("Expected the simulated instantaneous values, got %v", p)
+	}
+}
+
+func TestSimulatorProcessServerAddresses(t *testing.T) {
+	client := newProcessTestClient(t, NewDLMSProcessorAPI(WithRawKeys(true)))
+	meter := simulatedMeter(t)
+	meter.ClientAddress, meter.ServerAddress = "48", "1"
+
+	process, err := client.Process(context.Background())
+	if err != nil {
+		t.Fatalf("Process: %v", err)
+	}
+	if err := process.Send(readInstantaneous("server-1", meter, 5000)); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	for {
+		resp, err := process.Recv()
+		if err != nil {
+			t.Fatalf("Recv: %v", err)
+		}
+		if resp.CorrelationId != "server-1" {
+			continue
+		}
+		if resp.GetError() != nil {
+			t.Fatalf("Expected the read of server 1 to succeed, got %v", resp.GetError())
+		}
+		break
+	}
+
+	// Another logical device behind the same endpoint needs its own
+	// association, which the simulated meter refuses; it must not be served
+	// from the one held open for server 1
+	other := protobuf.Clone(meter).(*proto.Meter)
+	other.MeterId, other.ServerAddress = "meter-server-2", "2"
+	if err := process.Send(readInstantaneous("server-2", other, 1000)); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	invalid := protobuf.Clone(meter).(*proto.Meter)
+	invalid.MeterId, invalid.ServerAddress = "meter-invalid", "one"
+	if err := process.Send(readInstantaneous("invalid", invalid, 5000)); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	process.CloseSend()
+
+	responses := receiveAll(t, process)
+	if resp := responses["server-2"]; resp.GetError() == nil {
+		t.Errorf("Expected the read of server 2 to fail, got %v", resp)
+	}
+	if e := responses["invalid"].GetError(); e.GetCode() != int32(codes.InvalidArgument) {
+		t.Errorf("Expected a non-numeric server address to be invalid, got %v", responses["invalid"])
+	}
+}
+
+func TestSimulatorProbe(t *testing.T) {
+	client := newProcessTestClient(t, NewDLMSProcessorAPI(WithRawKeys(true)))
+
+	stream, err := client.Probe(context.Background(), &proto.ProbeRequest{
+		Meter:             []*proto.Meter{simulatedMeter(t)},
+		ConnectionTimeout: 5000,
+	})
+	if err != nil {
+		t.Fatalf("Probe: %v", err)
+	}
+	resp, err := stream.Recv()
+	if err != nil {
+		t.Fatalf("Probe: %v", err)
+	}
+	if !resp.GetOk() || resp.GetLogicalDeviceName() == "" || resp.GetFirmwareVersion() == "" {
+		t.Errorf("Expected the simulated meter to answer the probe, got %v", resp)
+	}
+}
+
+func TestSimulatorNameplate(t *testing.T) {
+	client := newProcessTestClient(t, NewDLMSProcessorAPI(WithRawKeys(true)))
+	meter := simulatedMeter(t)
+	meter.SerialNumber = "SIM00001"
+
+	stream, err := client.GetNameplate(context.Background(), &proto.GetNameplateRequest{
+		Meter:             []*proto.Meter{meter},
+		ConnectionTimeout: 5000,
+	})
+	if err != nil {
+		t.Fatalf("GetNameplate: %v", err)
+	}
+	resp, err := stream.Recv()
+	if err != nil {
+		t.Fatalf("GetNameplate: %v", err)
+	}
+	n := resp.GetNameplate()
+	if n.GetSerialNumber() != resp.GetSerialNumber() || n.GetManufacturerName() != "SIMULATED" || n.GetMeterType() != 5 || n.GetYearOfManufacture() != 2024 {
+		t.Errorf("Expected the simulated nameplate, got %v", resp)
+	}
+}
+
+func TestSimulatorProgramTariff(t *testing.T) {
+	var audit bytes.Buffer
+	client := newProcessTestClient(t, NewDLMSProcessorAPI(WithRawKeys(true), WithAuditLog(auth.NewAuditLog(&audit))))
+	req := &proto.ProgramTariffRequest{
+		Meter:             []*proto.Meter{simulatedMeter(t)},
+		ConnectionTimeout: 5000,
+		Calendar: &proto.TariffCalendar{
+			Name:         "TOU-A",
+			Seasons:      []*proto.Season{{Name: "ALL", Start: &proto.CalendarDate{Month: 1, Day: 1}, WeekProfile: "WEEK"}},
+			WeekProfiles: []*proto.WeekProfile{{Name: "WEEK", DayIds: []uint32{1, 1, 1, 1, 1, 1, 1}}},
+			DayProfiles: []*proto.DayProfile{{Id: 1, Actions: []*proto.DayAction{
+				{StartTime: "00:00", Script: dlms.TariffScriptTableOBIS, Selector: 1},
+				{StartTime: "18:30", Script: dlms.TariffScriptTableOBIS, Selector: 2},
+			}}},
+		},
+		ActivationTime: "2027-04-01T05:30:00+05:30",
+		SpecialDays: &proto.SpecialDaysTable{Entries: []*proto.SpecialDay{
+			{Index: 1, Date: &proto.CalendarDate{Month: 1, Day: 26}, DayId: 1},
+		}},
+	}
+
+	stream, err := client.ProgramTariff(context.Background(), req)
+	if err != nil {
+		t.Fatalf("ProgramTariff: %v", err)
+	}
+	resp, err := stream.Recv()
+	if err != nil {
+		t.Fatalf("ProgramTariff: %v", err)
+	}
+	tariff := resp.GetTariff()
+	if tariff.GetPassive().GetName() != "TOU-A" || tariff.GetActivationTime() != "2027-04-01T00:00:00Z" || len(tariff.GetSpecialDays()) != 1 {
+		t.Errorf("Expected the programmed tariff read back, got %v", tariff)
+	}
+	if start := tariff.GetPassive().GetDayProfiles()[0].GetActions()[1].GetStartTime(); start != "18:30:00" {
+		t.Errorf("Expected the second action at 18:30:00, got %s", start)
+	}
+	if !strings.Contains(audit.String(), `"outcome":"succeeded"`) || !strings.Contains(audit.String(), `"calendar":"TOU-A"`) {
+		t.Errorf("Expected the programming audited, got %s", audit.String())
+	}
+
+	// A week profile using a day profile the calendar does not have
+	req.Calendar.WeekProfiles[0].DayIds[6] = 2
+	stream, err = client.ProgramTariff(context.Background(), req)
+	if err == nil {
+		_, err = stream.Recv()
+	}
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("Expected an invalid calendar to be refused, got %v", err)
+	}
+}
+
+func TestSimulatorExecute(t *testing.T) {
+	var audit bytes.Buffer
+	client := newProcessTestClient(t, NewDLMSProcessorAPI(WithRawKeys(true), WithAuditLog(auth.NewAuditLog(&audit))))
+	m, err := simulator.Start(simulator.DefaultConfig(time.Now()), "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to start simulator: %v", err)
+	}
+	t.Cleanup(func() { m.Close() })
+	config := m.RealMeter()
+	meter := &proto.Meter{
+		MeterId:        "meter-simulated",
+		Ip:             config.MeterIP,
+		Port:           int32(config.MeterPort),
+		SystemTitle:    config.SystemTitle,
+		BlockCipherKey: config.BlockCipherKey,
+		AuthKey:        config.AuthenticationKey,
+	}
+
+	execute := func(id string, op *proto.ExecuteOperation) *proto.ProcessRequest {
+		return &proto.ProcessRequest{
+			CorrelationId: id,
+			Meter:         meter,
+			Timeout:       5000,
+			Operation:     &proto.ProcessRequest_Execute{Execute: op},
+		}
+	}
+	requests := []*proto.ProcessRequest{
+		// remote_disconnect of the disconnect control, with integer 0
+		execute("disconnect", &proto.ExecuteOperation{
+			Method: &proto.MethodReference{Obis: "0.0.96.3.10.255", ObjectType: 70, MethodIndex: 1},
+			Data:   []byte{0x0F, 0x00},
+		}),
+		execute("unknown", &proto.ExecuteOperation{Function: "reset"}),
+		execute(FOTAFunction, &proto.ExecuteOperation{Function: FOTAFunction}),
+	}
+
+	process, err := client.Process(context.Background())
+	if err != nil {
+		t.Fatalf("Process: %v", err)
+	}
+	for _, req := range requests {
+		if err := process.Send(req); err != nil {
+			t.Fatalf("Send: %v", err)
+		}
+	}
+	process.CloseSend()
+	responses := receiveAll(t, process)
+
+	if e := responses["disconnect"].GetError(); e != nil {
+		t.Fatalf("Expected the disconnect to succeed, got %v", e)
+	}
+	if connected, state, err := m.DisconnectState(); err != nil || connected || state != 0 {
+		t.Errorf("Expected the meter disconnected, got %v in state %d (%v)", connected, state, err)
+	}
+	if e := responses["unknown"].GetError(); codes.Code(e.GetCode()) != codes.InvalidArgument {
+		t.Errorf("Expected an unknown function to be invalid, got %v", responses["unknown"])
+	}
+	// Firmware upgrades do not reach the meter, so they may not report success
+	if e := responses[FOTAFunction].GetError(); codes.Code(e.GetCode()) != codes.Unimplemented || e.GetReason() != ReasonNotSupported {
+		t.Errorf("Expected FOTA to be unimplemented, got %v", responses[FOTAFunction])
+	}
+	if n := strings.Count(audit.String(), `"outcome":"succeeded"`); n != 1 || !strings.Contains(audit.String(), `"method_index":1`) {
+		t.Errorf("Expected the disconnect audited as succeeded, got %s", audit.String())
+	}
+	if n := strings.Count(audit.String(), `"outcome":"failed"`); n != 2 {
+		t.Errorf("Expected the unknown function and FOTA audited as failed, got %s", audit.String())
+	}
+}
+
+func TestSimulatorDefinedProfile(t *testing.T) {
+	profiles, err := dlms.LoadProfileDefinitions("../profiles.example.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := newProcessTestClient(t, NewDLMSProcessorAPI(WithRawKeys(true), WithProfileDefinitions(profiles)))
+	meter := simulatedMeter(t)
+	vendor := simulatedMeter(t)
+	vendor.Model = "vendor-x-1p"
+
+	process, err := client.Process(context.Background())
+	if err != nil {
+		t.Fatalf("Process: %v", err)
+	}
+	read := func(id string, meter *proto.Meter, profile string) *proto.ProcessRequest {
+		return &proto.ProcessRequest{
+			CorrelationId: id,
+			Meter:         meter,
+			Timeout:       5000,
+			Operation:     &proto.ProcessRequest_Read{Read: &proto.ReadOperation{Target: &proto.ReadOperation_DefinedProfile{DefinedProfile: profile}}},
+		}
+	}
+	for _, req := range []*proto.ProcessRequest{
+		read("billing", meter, "billing"),
+		read("vendor", vendor, "block-load"),
+		read("unknown", meter, "unknown"),
+	} {
+		if err := process.Send(req); err != nil {
+			t.Fatalf("Send: %v", err)
+		}
+	}
+	process.CloseSend()
+	responses := receiveAll(t, process)
+
+	// The simulated meter captures four of the eight tariff zones
+	billing := responses["billing"].GetProfileData()
+	values := make(map[string]*proto.ProfileValue)
+	attributes := make(map[string]int32)
+	for i, c := range billing.GetColumns() {
+		values[c.Name] = billing.GetRows()[0].GetValues()[i]
+		attributes[c.Name] = c.Attribute
+	}
+	if attributes["md_w"] != 2 || attributes["md_w_date_time"] != 5 || !strings.HasSuffix(values["md_w_date_time"].GetStringValue(), "19:00:00 UTC+00:00") {
+		t.Errorf("Expected maximum demand and its capture time from attributes 2 and 5, got %v", responses["billing"])
+	}
+	if values["cum_energy_wh_tz1"].GetNumberValue() != 2750 || values["cum_energy_wh_tz5"].GetValue() != nil || values["md_w"].GetNumberValue() != 2400 {
+		t.Errorf("Unexpected billing profile %v", responses["billing"])
+	}
+
+	if columns := responses["vendor"].GetProfileData().GetColumns(); len(columns) != 5 || columns[1].Unit != "V" {
+		t.Errorf("Expected the vendor's block load definition, got %v", responses["vendor"])
+	}
+	if e := responses["unknown"].GetError(); e.GetCode() != int32(codes.NotFound) {
+		t.Errorf("Expected an unknown profile not to be found, got %v", responses["unknown"])
+	}
+}
+
+func TestSimulatorMeterFactory(t *testing.T) {
+	meters := simulator.NewMeters(nil)
+	t.Cleanup(func() { meters.Close() })
+	client := newProcessTestClient(t, NewDLMSProcessorAPI(WithRawKeys(true), WithMeterFactory(meters)))
+
+	// Any address reaches a simulation of the meter
+	stream, err := client.GetInstantaneousProfile(context.Background(), &proto.GetInstantaneousProfileRequest{
+		Meter:             []*proto.Meter{{MeterId: "meter-anywhere", Ip: "fd00::1", Port: 4059}},
+		ConnectionTimeout: 5000,
+	})
+	if err != nil {
+		t.Fatalf("GetInstantaneousProfile: %v", err)
+	}
+	resp, err := stream.Recv()
+	if err != nil {
+		t.Fatalf("GetInstantaneousProfile: %v", err)
+	}
+	if resp.GetProfile().GetVoltage() != 230.1 {
+		t.Errorf("Expected the simulated instantaneous values, got %v", resp)
+	}
+}
+
+func TestFakeMeterFactory(t *testing.T) {
+	meters, err := dlms.NewFakeMeters(dlms.Scenario{Seed: 1, ReadFailureRate: 0.5})
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := newProcessTestClient(t, NewDLMSProcessorAPI(WithRawKeys(true), WithMeterFactory(meters)))
+
+	process, err := client.Process(context.Background())
+	if err != nil {
+		t.Fatalf("Process: %v", err)
+	}
+	for i := range 20 {
+		meter := &proto.Meter{MeterId: fmt.Sprintf("meter-%d", i), Ip: "fd00::1", Port: 4059}
+		if err := process.Send(readInstantaneous(meter.MeterId, meter, 1000)); err != nil {
+			t.Fatalf("Send: %v", err)
+		}
+	}
+	process.CloseSend()
+
+	// Half the reads fail, as temporary failures, and the others read
+	var failed int
+	for id, resp := range receiveAll(t, process) {
+		if id == "" {
+			continue // the initial credits
+		}
+		if e := resp.GetError(); e != nil {
+			if e.Reason != ReasonTemporaryFailure {
+				t.Errorf("Expected %s to fail temporarily, got %v", id, e)
+			}
+			failed++
+		} else if v := resp.GetInstantaneousProfile().GetVoltage(); v < 224 || v > 236 {
+			t.Errorf("Expected a fake voltage for %s, got %v", id, v)
+		}
+	}
+	if failed == 0 || failed == 20 {
+		t.Errorf("Expected about half the reads to fail, %d of 20 did", failed)
+	}
+}
@@ -0,0 +1,204 @@
+package api
+
+import (
+	"context"
+	"dlmsprocessor/auth"
+	"dlmsprocessor/dlms"
+	"dlmsprocessor/proto"
+	"fmt"
+	"log/slog"
+	"math"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ProgramTariff programs the tariff of every requested meter and streams the
+// tariff each reads back. Every attempt on a meter is audited, as each one
+// writes to it.
+func (s *DLMSProcessorAPI) ProgramTariff(req *proto.ProgramTariffRequest, stream grpc.ServerStreamingServer[proto.ProgramTariffResponse]) error {
+	if err := s.admit(); err != nil {
+		return err
+	}
+
+	if len(req.Meter) == 0 {
+		return status.Error(codes.InvalidArgument, "no meters provided")
+	}
+	program, err := tariffProgramFromProto(req)
+	if err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	params := map[string]any{
+		"calendar":        program.Calendar.Name,
+		"activation_time": req.ActivationTime,
+	}
+	if program.SpecialDays != nil {
+		params["special_days"] = len(program.SpecialDays)
+	}
+	method, _ := grpc.Method(stream.Context())
+
+	return forEachMeter(s, stream.Context(), operationProgramTariff, requestRetries(req.Retries, req.RetryDelay, req.Timeout), req.Meter, func(ctx context.Context, reqMeter *proto.Meter) (*proto.ProgramTariffResponse, error) {
+		tariff, err := func() (*dlms.Tariff, error) {
+			meter, err := s.openMeter(ctx, reqMeter, req.ConnectionTimeout)
+			if err != nil {
+				return nil, err
+			}
+			defer meter.Close()
+			return meter.ProgramTariff(ctx, program)
+		}()
+
+		rec := auth.AuditRecord{
+			Method:     method,
+			Right:      auth.Write,
+			Meters:     []string{reqMeter.MeterId},
+			Parameters: params,
+			Outcome:    auth.OutcomeSucceeded,
+		}
+		if err != nil {
+			rec.Outcome, rec.Error = auth.OutcomeFailed, err.Error()
+		}
+		s.audit.Audit(ctx, rec)
+		if err != nil {
+			slog.Warn("Failed to program tariff", "meter_id", reqMeter.MeterId, "ip", reqMeter.Ip, "error", err)
+			return nil, err
+		}
+
+		return &proto.ProgramTariffResponse{
+			Tariff:       tariffToProto(tariff),
+			MeterIp:      reqMeter.Ip,
+			MeterId:      reqMeter.MeterId,
+			SerialNumber: reqMeter.SerialNumber,
+		}, nil
+	}, stream.Send)
+}
+
+// tariffProgramFromProto converts a request into the program it writes and
+// validates it
+func tariffProgramFromProto(req *proto.ProgramTariffRequest) (dlms.TariffProgram, error) {
+	var program dlms.TariffProgram
+	if req.Calendar == nil {
+		return program, fmt.Errorf("no calendar provided")
+	}
+	c := req.Calendar
+	program.Calendar.Name = c.Name
+
+	for _, season := range c.Seasons {
+		program.Calendar.Seasons = append(program.Calendar.Seasons, dlms.Season{
+			Name:        season.Name,
+			Start:       calendarDateFromProto(season.Start),
+			WeekProfile: season.WeekProfile,
+		})
+	}
+	for _, week := range c.WeekProfiles {
+		if len(week.DayIds) != 7 {
+			return program, fmt.Errorf("week profile %q needs 7 day IDs, got %d", week.Name, len(week.DayIds))
+		}
+		w := dlms.WeekProfile{Name: week.Name}
+		for i, id := range week.DayIds {
+			if id > math.MaxUint8 {
+				return program, fmt.Errorf("week profile %q: day ID %d out of range", week.Name, id)
+			}
+			w.Days[i] = uint8(id)
+		}
+		program.Calendar.WeekProfiles = append(program.Calendar.WeekProfiles, w)
+	}
+	for _, day := range c.DayProfiles {
+		if day.Id > math.MaxUint8 {
+			return program, fmt.Errorf("day ID %d out of range", day.Id)
+		}
+		d := dlms.DayProfile{ID: uint8(day.Id)}
+		for _, action := range day.Actions {
+			start, err := parseTimeOfDay(action.StartTime)
+			if err != nil {
+				return program, fmt.Errorf("day profile %d: %w", day.Id, err)
+			}
+			if action.Selector > math.MaxUint16 {
+				return program, fmt.Errorf("day profile %d: selector %d out of range", day.Id, action.Selector)
+			}
+			d.Actions = append(d.Actions, dlms.DayAction{Start: start, Script: action.Script, Selector: uint16(action.Selector)})
+		}
+		program.Calendar.DayProfiles = append(program.Calendar.DayProfiles, d)
+	}
+
+	if req.ActivationTime != "" {
+		t, err := time.Parse(time.RFC3339, req.ActivationTime)
+		if err != nil {
+			return program, fmt.Errorf("invalid activation time %q: %w", req.ActivationTime, err)
+		}
+		program.ActivationTime = t
+	}
+
+	if req.SpecialDays != nil {
+		program.SpecialDays = []dlms.SpecialDay{}
+		for _, day := range req.SpecialDays.Entries {
+			if day.Index > math.MaxUint16 || day.DayId > math.MaxUint8 {
+				return program, fmt.Errorf("special day %d: index or day ID out of range", day.Index)
+			}
+			program.SpecialDays = append(program.SpecialDays, dlms.SpecialDay{
+				Index: uint16(day.Index),
+				Date:  calendarDateFromProto(day.Date),
+				DayID: uint8(day.DayId),
+			})
+		}
+	}
+
+	return program, program.Validate()
+}
+
+func calendarDateFromProto(d *proto.CalendarDate) dlms.CalendarDate {
+	return dlms.CalendarDate{Year: int(d.GetYear()), Month: int(d.GetMonth()), Day: int(d.GetDay())}
+}
+
+// parseTimeOfDay parses HH:MM or HH:MM:SS as the time since midnight
+func parseTimeOfDay(value string) (time.Duration, error) {
+	for _, layout := range []string{"15:04", "15:04:05"} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute + time.Duration(t.Second())*time.Second, nil
+		}
+	}
+	return 0, fmt.Errorf("invalid start time %q", value)
+}
+
+// tariffToProto converts from dlms.Tariff to proto.Tariff
+func tariffToProto(t *dlms.Tariff) *proto.Tariff {
+	p := &proto.Tariff{
+		Active:  calendarToProto(t.Active),
+		Passive: calendarToProto(t.Passive),
+	}
+	if !t.ActivationTime.IsZero() {
+		p.ActivationTime = t.ActivationTime.Format(time.RFC3339)
+	}
+	for _, d := range t.SpecialDays {
+		p.SpecialDays = append(p.SpecialDays, &proto.SpecialDay{Index: uint32(d.Index), Date: calendarDateToProto(d.Date), DayId: uint32(d.DayID)})
+	}
+	return p
+}
+
+func calendarToProto(c dlms.Calendar) *proto.TariffCalendar {
+	p := &proto.TariffCalendar{Name: c.Name}
+	for _, s := range c.Seasons {
+		p.Seasons = append(p.Seasons, &proto.Season{Name: s.Name, Start: calendarDateToProto(s.Start), WeekProfile: s.WeekProfile})
+	}
+	for _, w := range c.WeekProfiles {
+		week := &proto.WeekProfile{Name: w.Name}
+		for _, id := range w.Days {
+			week.DayIds = append(week.DayIds, uint32(id))
+		}
+		p.WeekProfiles = append(p.WeekProfiles, week)
+	}
+	for _, d := range c.DayProfiles {
+		day := &proto.DayProfile{Id: uint32(d.ID)}
+		for _, a := range d.Actions {
+			start := time.Time{}.Add(a.Start).Format("15:04:05")
+			day.Actions = append(day.Actions, &proto.DayAction{StartTime: start, Script: a.Script, Selector: uint32(a.Selector)})
+		}
+		p.DayProfiles = append(p.DayProfiles, day)
+	}
+	return p
+}
+
+func calendarDateToProto(d dlms.CalendarDate) *proto.CalendarDate {
+	return &proto.CalendarDate{Year: int32(d.Year), Month: int32(d.Month), Day: int32(d.Day)}
+}
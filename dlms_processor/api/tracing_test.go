@@ -0,0 +1,100 @@
+package api
+
+import (
+	"context"
+	"dlmsprocessor/proto"
+	"dlmsprocessor/tracing"
+	"net"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func TestTracePropagatesToMeterSpans(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(provider)
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+	if _, err := tracing.Setup(context.Background(), tracing.Options{}); err != nil {
+		t.Fatalf("Setup: %v", err)
+	}
+
+	lis := bufconn.Listen(bufSize)
+	s := grpc.NewServer(grpc.StatsHandler(tracing.ServerHandler()))
+	proto.RegisterDLMSProcessorServer(s, NewDLMSProcessorAPI(WithRawKeys(true)))
+	go s.Serve(lis)
+	t.Cleanup(s.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) { return lis.Dial() }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithStatsHandler(otelgrpc.NewClientHandler()))
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	// The meter accepts the TCP connection and hangs up before answering the AARQ
+	meter := closingMeter(t)
+	ctx, root := provider.Tracer("consumer").Start(context.Background(), "bulk read")
+	stream, err := proto.NewDLMSProcessorClient(conn).GetInstantaneousProfile(ctx, &proto.GetInstantaneousProfileRequest{
+		Meter:             []*proto.Meter{meter},
+		ConnectionTimeout: 1000,
+	})
+	if err == nil {
+		_, err = stream.Recv()
+	}
+	if err == nil {
+		t.Fatal("Expected the meter to fail")
+	}
+	s.Stop()
+	root.End()
+
+	spans := make(map[string]sdktrace.ReadOnlySpan)
+	for _, span := range recorder.Ended() {
+		if span.SpanContext().TraceID() != root.SpanContext().TraceID() {
+			t.Errorf("Span %s is not part of the consumer's trace", span.Name())
+		}
+		name := span.Name()
+		if span.SpanKind() == trace.SpanKindClient && strings.HasPrefix(name, "dlmsprocessor.") {
+			name = "client " + name
+		}
+		spans[name] = span
+	}
+
+	// Each span's parent, from the consumer down to the AARQ
+	parents := map[string]string{
+		"client dlmsprocessor.DLMSProcessor/GetInstantaneousProfile": "bulk read",
+		"dlmsprocessor.DLMSProcessor/GetInstantaneousProfile":        "client dlmsprocessor.DLMSProcessor/GetInstantaneousProfile",
+		"meter " + operationInstantaneousProfile:                     "dlmsprocessor.DLMSProcessor/GetInstantaneousProfile",
+		"dlms.associate":                                             "meter " + operationInstantaneousProfile,
+		"tcp.connect":                                                "dlms.associate",
+		"dlms.aarq":                                                  "dlms.associate",
+	}
+	for name, parent := range parents {
+		span, ok := spans[name]
+		if !ok {
+			t.Errorf("No %s span", name)
+			continue
+		}
+		if p, ok := spans[parent]; !ok || span.Parent().SpanID() != p.SpanContext().SpanID() {
+			t.Errorf("Expected %s to be a child of %s", name, parent)
+		}
+	}
+
+	if span, ok := spans["dlms.aarq"]; ok && span.Status().Code.String() != "Error" {
+		t.Errorf("Expected the AARQ span to record the failure, got %v", span.Status())
+	}
+	if span, ok := spans["tcp.connect"]; ok && span.Status().Code.String() == "Error" {
+		t.Errorf("Expected the TCP connect to succeed, got %v", span.Status())
+	}
+}
@@ -0,0 +1,161 @@
+package api
+
+import (
+	"context"
+	"dlmsprocessor/metrics"
+	"dlmsprocessor/proto"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// closingMeter accepts connections and hangs up straight away
+func closingMeter(t *testing.T) *proto.Meter {
+	t.Helper()
+
+	meter := silentMeter(t)
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	t.Cleanup(func() { lis.Close() })
+
+	go func() {
+		for {
+			conn, err := lis.Accept()
+			if err != nil {
+				return
+			}
+			conn.Close()
+		}
+	}()
+
+	meter.MeterId = "meter-closing"
+	meter.Port = int32(lis.Addr().(*net.TCPAddr).Port)
+	return meter
+}
+
+func TestWorkerPoolQueues(t *testing.T) {
+	s := NewDLMSProcessorAPI(WithMaxMeterWorkers(1))
+
+	release, err := s.acquireWorker(context.Background())
+	if err != nil {
+		t.Fatalf("acquireWorker: %v", err)
+	}
+	if got := testutil.ToFloat64(metrics.MeterOperationsInFlight); got != 1 {
+		t.Errorf("Expected 1 operation in flight, got %v", got)
+	}
+
+	acquired := make(chan func())
+	go func() {
+		release, err := s.acquireWorker(context.Background())
+		if err == nil {
+			acquired <- release
+		}
+	}()
+
+	time.Sleep(20 * time.Millisecond)
+	if got := testutil.ToFloat64(metrics.WorkerQueueDepth); got != 1 {
+		t.Errorf("Expected 1 queued operation, got %v", got)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := s.acquireWorker(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected a queued operation to give up at its deadline, got %v", err)
+	}
+
+	release()
+	select {
+	case release := <-acquired:
+		release()
+	case <-time.After(time.Second):
+		t.Fatal("Queued operation did not get the free worker")
+	}
+
+	if got := testutil.ToFloat64(metrics.WorkerQueueDepth); got != 0 {
+		t.Errorf("Expected an empty queue, got %v", got)
+	}
+	if got := testutil.ToFloat64(metrics.MeterOperationsInFlight); got != 0 {
+		t.Errorf("Expected no operations in flight, got %v", got)
+	}
+}
+
+func TestRequestRetries(t *testing.T) {
+	client := newProcessTestClient(t, NewDLMSProcessorAPI(WithRawKeys(true)))
+	meter := closingMeter(t)
+
+	retries := metrics.Retries.WithLabelValues(operationInstantaneousProfile)
+	failures := metrics.MeterOperations.WithLabelValues(operationInstantaneousProfile, "error")
+	retriesBefore, failuresBefore := testutil.ToFloat64(retries), testutil.ToFloat64(failures)
+
+	start := time.Now()
+	stream, err := client.GetInstantaneousProfile(context.Background(), &proto.GetInstantaneousProfileRequest{
+		Meter:             []*proto.Meter{meter},
+		Retries:           2,
+		RetryDelay:        50,
+		ConnectionTimeout: 1000,
+	})
+	if err == nil {
+		_, err = stream.Recv()
+	}
+	if err == nil || status.Code(err) == codes.DeadlineExceeded {
+		t.Fatalf("Expected the meter to fail, got %v", err)
+	}
+
+	if got := testutil.ToFloat64(retries) - retriesBefore; got != 2 {
+		t.Errorf("Expected 2 retries, got %v", got)
+	}
+	if got := testutil.ToFloat64(failures) - failuresBefore; got != 1 {
+		t.Errorf("Expected the meter to be counted once as failed, got %v", got)
+	}
+	if elapsed := time.Since(start); elapsed < 100*time.Millisecond {
+		t.Errorf("Expected two retry delays, finished in %v", elapsed)
+	}
+}
+
+func TestRequestTimeoutBoundsEachAttempt(t *testing.T) {
+	client := newProcessTestClient(t, NewDLMSProcessorAPI(WithRawKeys(true)))
+	meter := silentMeter(t)
+
+	retries := metrics.Retries.WithLabelValues(operationInstantaneousProfile)
+	retriesBefore := testutil.ToFloat64(retries)
+
+	start := time.Now()
+	stream, err := client.GetInstantaneousProfile(context.Background(), &proto.GetInstantaneousProfileRequest{
+		Meter:             []*proto.Meter{meter},
+		Retries:           1,
+		ConnectionTimeout: 10000,
+		Timeout:           200,
+	})
+	if err == nil {
+		_, err = stream.Recv()
+	}
+	if err == nil {
+		t.Fatal("Expected the silent meter to fail")
+	}
+
+	if got := testutil.ToFloat64(retries) - retriesBefore; got != 1 {
+		t.Errorf("Expected 1 retry, got %v", got)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("Expected each attempt to end at the request's timeout, finished in %v", elapsed)
+	}
+}
+
+func TestRequestRetriesBounds(t *testing.T) {
+	if r := requestRetries(-1, -5, -10); r.retries != 0 || r.delay != 0 || r.timeout != 0 {
+		t.Errorf("Expected negative settings to mean no retries, got %+v", r)
+	}
+	if r := requestRetries(1000, 20, 300); r.retries != maxRetries || r.delay != 20*time.Millisecond || r.timeout != 300*time.Millisecond {
+		t.Errorf("Expected retries capped at %d, got %+v", maxRetries, r)
+	}
+	if retryable(status.Error(codes.InvalidArgument, "bad request")) {
+		t.Errorf("Expected invalid requests not to be retried")
+	}
+}
@@ -0,0 +1,108 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+)
+
+// Outcomes of an audited command
+const (
+	OutcomeDenied    = "denied"
+	OutcomeSucceeded = "succeeded"
+	OutcomeFailed    = "failed"
+)
+
+// AuditRecord describes one state-changing command. Meter credentials are
+// never recorded.
+type AuditRecord struct {
+	Time          time.Time      `json:"time"`
+	Caller        string         `json:"caller"`
+	Role          string         `json:"role,omitempty"`
+	Method        string         `json:"method"`
+	CorrelationID string         `json:"correlation_id,omitempty"`
+	Right         Right          `json:"right"`
+	Meters        []string       `json:"meters,omitempty"`
+	Parameters    map[string]any `json:"parameters,omitempty"`
+	Outcome       string         `json:"outcome"`
+	Error         string         `json:"error,omitempty"`
+}
+
+// AuditLog appends records as JSON lines
+type AuditLog struct {
+	mu   sync.Mutex
+	w    io.Writer
+	file *os.File // set when the log owns the file
+}
+
+// NewAuditLog writes records to w
+func NewAuditLog(w io.Writer) *AuditLog {
+	return &AuditLog{w: w}
+}
+
+// OpenAuditLog appends records to the file at path, creating it if needed
+func OpenAuditLog(path string) (*AuditLog, error) {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit log: %w", err)
+	}
+	return &AuditLog{w: f, file: f}, nil
+}
+
+// Record writes rec, synced to disk when the log is a file. A record that
+// cannot be written is logged as an error.
+func (l *AuditLog) Record(rec AuditRecord) {
+	if l == nil {
+		return
+	}
+	if rec.Time.IsZero() {
+		rec.Time = time.Now().UTC()
+	}
+
+	line, err := json.Marshal(rec)
+	if err != nil {
+		slog.Error("Failed to encode audit record", "method", rec.Method, "caller", rec.Caller, "error", err)
+		return
+	}
+	line = append(line, '\n')
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if _, err := l.w.Write(line); err != nil {
+		slog.Error("Failed to write audit record", "method", rec.Method, "caller", rec.Caller, "error", err)
+		return
+	}
+	if l.file != nil {
+		if err := l.file.Sync(); err != nil {
+			slog.Error("Failed to sync audit log", "error", err)
+		}
+	}
+}
+
+// Close closes the file opened by OpenAuditLog
+func (l *AuditLog) Close() error {
+	if l == nil || l.file == nil {
+		return nil
+	}
+	return l.file.Close()
+}
+
+// Audit records a command for the caller in ctx. Callers that were not
+// authenticated are recorded as "anonymous".
+func (l *AuditLog) Audit(ctx context.Context, rec AuditRecord) {
+	if l == nil {
+		return
+	}
+	if caller := FromContext(ctx); caller != nil {
+		rec.Caller, rec.Role = caller.Name, caller.Role
+	} else if rec.Caller == "" {
+		rec.Caller = "anonymous"
+	}
+	l.Record(rec)
+}
@@ -0,0 +1,168 @@
+package auth
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+func tokenHash(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func testPolicy() *Policy {
+	return &Policy{
+		Callers: []CallerPolicy{
+			{Name: "billing", Role: "read-only", TokenSHA256: tokenHash("billing-token")},
+			{Name: "scada", Role: "operator", TokenSHA256: tokenHash("scada-token")},
+			{Name: "ops-console", Role: "admin", Certificate: "ops-console"},
+		},
+	}
+}
+
+var testMethods = map[string]Right{
+	"/svc.Meters/Read":        Read,
+	"/svc.Meters/Disconnect":  Execute,
+	"/grpc.health.v1.Health/": Public,
+}
+
+func withToken(token string) context.Context {
+	return metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Bearer "+token))
+}
+
+func withCertificate(commonName string) context.Context {
+	cert := &x509.Certificate{Subject: pkix.Name{CommonName: commonName}}
+	return peer.NewContext(context.Background(), &peer.Peer{
+		AuthInfo: credentials.TLSInfo{State: tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{cert}}}},
+	})
+}
+
+func TestInterceptorAuthorizesByRole(t *testing.T) {
+	var audit bytes.Buffer
+	a := NewAuthorizer(testPolicy(), testMethods, NewAuditLog(&audit))
+	interceptor := a.UnaryInterceptor()
+
+	testCases := []struct {
+		name   string
+		ctx    context.Context
+		method string
+		code   codes.Code
+		caller string
+	}{
+		{name: "read-only may read", ctx: withToken("billing-token"), method: "/svc.Meters/Read", caller: "billing"},
+		{name: "read-only may not disconnect", ctx: withToken("billing-token"), method: "/svc.Meters/Disconnect", code: codes.PermissionDenied},
+		{name: "operator may disconnect", ctx: withToken("scada-token"), method: "/svc.Meters/Disconnect", caller: "scada"},
+		{name: "certificate identifies the caller", ctx: withCertificate("ops-console"), method: "/svc.Meters/Disconnect", caller: "ops-console"},
+		{name: "unknown certificate", ctx: withCertificate("intruder"), method: "/svc.Meters/Read", code: codes.Unauthenticated},
+		{name: "invalid token", ctx: withToken("guess"), method: "/svc.Meters/Read", code: codes.Unauthenticated},
+		{name: "no credentials", ctx: context.Background(), method: "/svc.Meters/Read", code: codes.Unauthenticated},
+		{name: "public method", ctx: context.Background(), method: "/grpc.health.v1.Health/Check"},
+		{name: "method not in policy", ctx: withToken("scada-token"), method: "/svc.Meters/Format", code: codes.PermissionDenied},
+		{
+			name:   "malformed authorization",
+			ctx:    metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Basic c2NhZGE=")),
+			method: "/svc.Meters/Read",
+			code:   codes.Unauthenticated,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var caller *Caller
+			_, err := interceptor(tc.ctx, nil, &grpc.UnaryServerInfo{FullMethod: tc.method}, func(ctx context.Context, req any) (any, error) {
+				caller = FromContext(ctx)
+				return nil, nil
+			})
+			if code := status.Code(err); code != tc.code {
+				t.Fatalf("Expected %v, got %v", tc.code, err)
+			}
+			if tc.caller != "" && (caller == nil || caller.Name != tc.caller) {
+				t.Errorf("Expected caller %s in the handler context, got %+v", tc.caller, caller)
+			}
+		})
+	}
+
+	var rec AuditRecord
+	if err := json.Unmarshal(audit.Bytes(), &rec); err != nil {
+		t.Fatalf("Expected exactly one audit record, got %q: %v", audit.String(), err)
+	}
+	if rec.Caller != "billing" || rec.Method != "/svc.Meters/Disconnect" || rec.Outcome != OutcomeDenied {
+		t.Errorf("Unexpected audit record %+v", rec)
+	}
+}
+
+func TestCheck(t *testing.T) {
+	if err := Check(context.Background(), FOTA); err != nil {
+		t.Errorf("Expected everything to be allowed without authorization, got %v", err)
+	}
+
+	p := testPolicy()
+	ctx := NewContext(context.Background(), p.byToken("scada-token"))
+	if err := Check(ctx, Write); err != nil {
+		t.Errorf("Expected an operator to write, got %v", err)
+	}
+	if err := Check(ctx, FOTA); status.Code(err) != codes.PermissionDenied {
+		t.Errorf("Expected an operator to be refused FOTA, got %v", err)
+	}
+}
+
+func TestLoadPolicy(t *testing.T) {
+	write := func(content string) string {
+		path := filepath.Join(t.TempDir(), "policy.yaml")
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	p, err := LoadPolicy(write(`
+roles:
+  field-engineer: [read, execute]
+callers:
+  - name: engineer
+    role: field-engineer
+    certificate: engineer-01
+  - name: scada
+    role: operator
+    token_sha256: ` + tokenHash("scada-token") + `
+`))
+	if err != nil {
+		t.Fatalf("LoadPolicy: %v", err)
+	}
+	engineer := p.byCertificate("engineer-01")
+	if engineer == nil || !engineer.Can(Execute) || engineer.Can(Write) {
+		t.Errorf("Expected the custom role to grant read and execute only, got %+v", engineer)
+	}
+	if scada := p.byToken("scada-token"); scada == nil || !scada.Can(Write) {
+		t.Errorf("Expected the default operator role, got %+v", scada)
+	}
+
+	for name, content := range map[string]string{
+		"unknown role":  "callers: [{name: a, role: root, certificate: a}]",
+		"unknown right": "roles: {x: [format]}",
+		"no identity":   "callers: [{name: a, role: admin}]",
+		"bad hash":      "callers: [{name: a, role: admin, token_sha256: secret}]",
+		"duplicate":     "callers: [{name: a, role: admin, certificate: a}, {name: a, role: admin, certificate: b}]",
+		"unknown key":   "caller: []",
+	} {
+		if _, err := LoadPolicy(write(content)); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
@@ -0,0 +1,163 @@
+package auth
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// Public marks methods that need no authentication, such as health checks
+const Public Right = "public"
+
+// Authorizer authenticates callers and checks that their role grants the
+// right each method needs
+type Authorizer struct {
+	policy *Policy
+	// methods maps full method names, or service prefixes ending in "/", to
+	// the right they need. Methods not listed are refused.
+	methods map[string]Right
+	audit   *AuditLog
+}
+
+// NewAuthorizer enforces policy on methods. Refused calls that would have
+// changed state are recorded in audit, which may be nil.
+func NewAuthorizer(policy *Policy, methods map[string]Right, audit *AuditLog) *Authorizer {
+	return &Authorizer{policy: policy, methods: methods, audit: audit}
+}
+
+// UnaryInterceptor enforces the policy on unary calls
+func (a *Authorizer) UnaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		ctx, err := a.authorize(ctx, info.FullMethod)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamInterceptor enforces the policy on streaming calls
+func (a *Authorizer) StreamInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := a.authorize(ss.Context(), info.FullMethod)
+		if err != nil {
+			return err
+		}
+		return handler(srv, &callerStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+// callerStream carries the authenticated caller in its context
+type callerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *callerStream) Context() context.Context {
+	return s.ctx
+}
+
+// authorize returns ctx with the caller attached, or the status refusing the call
+func (a *Authorizer) authorize(ctx context.Context, method string) (context.Context, error) {
+	right, ok := a.methodRight(method)
+	if !ok {
+		slog.Warn("Refused call to a method the policy does not cover", "method", method)
+		return nil, status.Errorf(codes.PermissionDenied, "%s is not permitted", method)
+	}
+	if right == Public {
+		return ctx, nil
+	}
+
+	caller, err := a.authenticate(ctx)
+	if err != nil {
+		slog.Warn("Refused unauthenticated call", "method", method, "error", err)
+		return nil, err
+	}
+	if !caller.Can(right) {
+		slog.Warn("Refused call", "method", method, "caller", caller.Name, "role", caller.Role, "right", right)
+		if right != Read {
+			a.audit.Record(AuditRecord{
+				Caller:  caller.Name,
+				Role:    caller.Role,
+				Method:  method,
+				Right:   right,
+				Outcome: OutcomeDenied,
+			})
+		}
+		return nil, status.Errorf(codes.PermissionDenied, "role %s may not %s", caller.Role, right)
+	}
+	return NewContext(ctx, caller), nil
+}
+
+func (a *Authorizer) methodRight(method string) (Right, bool) {
+	if right, ok := a.methods[method]; ok {
+		return right, true
+	}
+	if i := strings.LastIndex(method, "/"); i > 0 {
+		right, ok := a.methods[method[:i+1]]
+		return right, ok
+	}
+	return "", false
+}
+
+// authenticate identifies the caller by bearer token or, failing that, by
+// verified client certificate. A token that is presented must be valid.
+func (a *Authorizer) authenticate(ctx context.Context) (*Caller, error) {
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get("authorization"); len(values) > 0 {
+			scheme, token, ok := strings.Cut(values[0], " ")
+			if !ok || !strings.EqualFold(scheme, "bearer") || token == "" {
+				return nil, status.Error(codes.Unauthenticated, "authorization must be a bearer token")
+			}
+			if caller := a.policy.byToken(token); caller != nil {
+				return caller, nil
+			}
+			return nil, status.Error(codes.Unauthenticated, "invalid token")
+		}
+	}
+
+	if p, ok := peer.FromContext(ctx); ok {
+		if info, ok := p.AuthInfo.(credentials.TLSInfo); ok && len(info.State.VerifiedChains) > 0 {
+			commonName := info.State.VerifiedChains[0][0].Subject.CommonName
+			if caller := a.policy.byCertificate(commonName); caller != nil {
+				return caller, nil
+			}
+			return nil, status.Errorf(codes.Unauthenticated, "client certificate %q is not in the policy", commonName)
+		}
+	}
+
+	return nil, status.Error(codes.Unauthenticated, "no credentials provided")
+}
+
+type callerKey struct{}
+
+// NewContext returns ctx carrying caller
+func NewContext(ctx context.Context, caller *Caller) context.Context {
+	return context.WithValue(ctx, callerKey{}, caller)
+}
+
+// FromContext returns the authenticated caller, or nil when authorization
+// is not enabled
+func FromContext(ctx context.Context) *Caller {
+	caller, _ := ctx.Value(callerKey{}).(*Caller)
+	return caller
+}
+
+// Check refuses an operation the caller's role does not grant. Handlers use
+// it for operations that need more than their method does, such as writes
+// within a Process stream. Without an authenticated caller, i.e. when
+// authorization is not enabled, everything is allowed.
+func Check(ctx context.Context, right Right) error {
+	caller := FromContext(ctx)
+	if caller == nil || caller.Can(right) {
+		return nil
+	}
+	return status.Errorf(codes.PermissionDenied, "role %s may not %s", caller.Role, right)
+}
@@ -0,0 +1,159 @@
+// Package auth authenticates gRPC callers by bearer token or client
+// certificate, authorizes them against a role policy and keeps an audit
+// trail of the commands that change meter state.
+package auth
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Right is a class of operation a role may be granted
+type Right string
+
+const (
+	Read    Right = "read"    // read attributes and profiles
+	Write   Right = "write"   // set attributes, e.g. the clock
+	Execute Right = "execute" // invoke methods, including relay disconnect and reconnect
+	FOTA    Right = "fota"    // firmware upgrades
+)
+
+// DefaultRoles are the roles available when a policy defines none of its own
+var DefaultRoles = map[string][]Right{
+	"read-only": {Read},
+	"operator":  {Read, Write, Execute},
+	"admin":     {Read, Write, Execute, FOTA},
+}
+
+// Policy maps callers to roles and roles to rights
+type Policy struct {
+	// Roles overrides and extends DefaultRoles
+	Roles   map[string][]Right `yaml:"roles"`
+	Callers []CallerPolicy     `yaml:"callers"`
+}
+
+// CallerPolicy identifies one caller, by token, by client certificate or by both
+type CallerPolicy struct {
+	Name string `yaml:"name"`
+	Role string `yaml:"role"`
+	// TokenSHA256 is the hex SHA-256 of the bearer token, so the policy file
+	// holds no secrets
+	TokenSHA256 string `yaml:"token_sha256"`
+	// Certificate is the subject common name of a verified client certificate
+	Certificate string `yaml:"certificate"`
+}
+
+// Caller is an authenticated client
+type Caller struct {
+	Name   string
+	Role   string
+	rights []Right
+}
+
+// Can reports whether the caller's role grants right
+func (c *Caller) Can(right Right) bool {
+	return slices.Contains(c.rights, right)
+}
+
+// LoadPolicy reads a YAML policy file
+func LoadPolicy(path string) (*Policy, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read policy file: %w", err)
+	}
+
+	var p Policy
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&p); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("failed to parse policy file %s: %w", path, err)
+	}
+	if err := p.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid policy file %s: %w", path, err)
+	}
+	return &p, nil
+}
+
+// Validate checks that every caller has a usable identity and a known role
+func (p *Policy) Validate() error {
+	for role, rights := range p.Roles {
+		for _, right := range rights {
+			switch right {
+			case Read, Write, Execute, FOTA:
+			default:
+				return fmt.Errorf("role %s: unknown right %q", role, right)
+			}
+		}
+	}
+
+	names := make(map[string]bool)
+	for i, c := range p.Callers {
+		if c.Name == "" {
+			return fmt.Errorf("caller %d has no name", i)
+		}
+		if names[c.Name] {
+			return fmt.Errorf("caller %s is defined twice", c.Name)
+		}
+		names[c.Name] = true
+
+		if _, ok := p.rights(c.Role); !ok {
+			return fmt.Errorf("caller %s: unknown role %q", c.Name, c.Role)
+		}
+		if c.TokenSHA256 == "" && c.Certificate == "" {
+			return fmt.Errorf("caller %s needs token_sha256 or certificate", c.Name)
+		}
+		if c.TokenSHA256 != "" {
+			if sum, err := hex.DecodeString(c.TokenSHA256); err != nil || len(sum) != sha256.Size {
+				return fmt.Errorf("caller %s: token_sha256 must be 64 hex digits", c.Name)
+			}
+		}
+	}
+	return nil
+}
+
+func (p *Policy) rights(role string) ([]Right, bool) {
+	if rights, ok := p.Roles[role]; ok {
+		return rights, true
+	}
+	rights, ok := DefaultRoles[role]
+	return rights, ok
+}
+
+func (p *Policy) caller(c CallerPolicy) *Caller {
+	rights, _ := p.rights(c.Role)
+	return &Caller{Name: c.Name, Role: c.Role, rights: rights}
+}
+
+// byToken finds the caller holding token
+func (p *Policy) byToken(token string) *Caller {
+	sum := sha256.Sum256([]byte(token))
+	for _, c := range p.Callers {
+		want, err := hex.DecodeString(c.TokenSHA256)
+		if err != nil || len(want) != sha256.Size {
+			continue
+		}
+		if subtle.ConstantTimeCompare(sum[:], want) == 1 {
+			return p.caller(c)
+		}
+	}
+	return nil
+}
+
+// byCertificate finds the caller with the given certificate common name
+func (p *Policy) byCertificate(commonName string) *Caller {
+	for _, c := range p.Callers {
+		if c.Certificate != "" && c.Certificate == commonName {
+			return p.caller(c)
+		}
+	}
+	return nil
+}
@@ -4,9 +4,14 @@ package main
 // This generates the go code from .proto files
 // For simple cases like this it avoids the need to have a Makefile
 
-//go:generate protoc --go_opt=module=dlmsprocessor --go-grpc_opt=module=dlmsprocessor --go_out=./ --go-grpc_out=./ --proto_path=./ dlmsprocessor.proto
+//go:generate protoc --go_opt=module=dlmsprocessor --go-grpc_opt=module=dlmsprocessor --go_out=./ --go-grpc_out=./ --proto_path=./ --proto_path=./third_party/googleapis dlmsprocessor.proto
 
-//go:generate protoc --go_opt=module=dlmsprocessor --go-grpc_opt=module=dlmsprocessor --go_out=../dlms_consumer/ --go-grpc_out=../dlms_consumer/ --proto_path=./ dlmsprocessor.proto
+//go:generate protoc --go_opt=module=dlmsprocessor --go-grpc_opt=module=dlmsprocessor --go_out=../dlms_consumer/ --go-grpc_out=../dlms_consumer/ --proto_path=./ --proto_path=./third_party/googleapis dlmsprocessor.proto
+
+// The HTTP/JSON gateway and its OpenAPI document, from the google.api.http
+// annotations. Needs protoc-gen-grpc-gateway and protoc-gen-openapiv2:
+// go install github.com/grpc-ecosystem/grpc-gateway/v2/protoc-gen-grpc-gateway@v2.27.2 github.com/grpc-ecosystem/grpc-gateway/v2/protoc-gen-openapiv2@v2.27.2
+//go:generate protoc --grpc-gateway_opt=module=dlmsprocessor --grpc-gateway_out=./ --openapiv2_out=./gateway/ --proto_path=./ --proto_path=./third_party/googleapis dlmsprocessor.proto
 
 // This generates JS code from .proto files
 // //go:generate protoc --ts_opt=no_namespace --ts_opt=unary_rpc_promise=true --ts_opt=target=web --ts_out=../../frontend/proto/ --proto_path=../../proto chatservice.proto
@@ -0,0 +1,81 @@
+// Command keystore creates and inspects the encrypted keystore files the
+// processor resolves meter key references from.
+//
+//	keystore genkey > master.key
+//	keystore -key-file master.key encrypt < keys.json > keystore.enc
+//	keystore -key-file master.key decrypt < keystore.enc
+//
+// keys.json lists every version of every meter's keys:
+//
+//	{"keys": [{"meterId": "meter-0002", "version": 1, "authPassword": "...", "authKey": "...", "blockCipherKey": "..."}]}
+package main
+
+import (
+	"dlmsprocessor/keys"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+)
+
+func main() {
+	keyFile := flag.String("key-file", "", "file holding the hex master key")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: keystore genkey | keystore -key-file FILE encrypt|decrypt < in > out\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	if flag.Arg(0) == "genkey" {
+		key, err := keys.GenerateMasterKey()
+		if err != nil {
+			log.Fatalf("failed to generate a master key: %v", err)
+		}
+		fmt.Println(key)
+		return
+	}
+
+	if *keyFile == "" {
+		log.Fatal("-key-file is required")
+	}
+	masterKey, err := keys.LoadMasterKey(*keyFile)
+	if err != nil {
+		log.Fatal(err)
+	}
+	in, err := io.ReadAll(os.Stdin)
+	if err != nil {
+		log.Fatalf("failed to read input: %v", err)
+	}
+
+	var out []byte
+	switch flag.Arg(0) {
+	case "encrypt":
+		// Refuse documents the processor would refuse
+		store, err := keys.NewFileStore(in)
+		if err != nil {
+			log.Fatal(err)
+		}
+		out, err = keys.Encrypt(in, masterKey)
+		if err != nil {
+			log.Fatal(err)
+		}
+		fmt.Fprintf(os.Stderr, "encrypted %d key versions\n", store.Len())
+	case "decrypt":
+		out, err = keys.Decrypt(in, masterKey)
+		if err != nil {
+			log.Fatal(err)
+		}
+	default:
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	if _, err := os.Stdout.Write(out); err != nil {
+		log.Fatalf("failed to write output: %v", err)
+	}
+}
@@ -1,23 +1,379 @@
 package main
 
 import (
+	"context"
+	"crypto/tls"
 	"dlmsprocessor/api"
+	"dlmsprocessor/auth"
+	"dlmsprocessor/config"
+	"dlmsprocessor/dlms"
+	"dlmsprocessor/gateway"
+	"dlmsprocessor/keys"
+	"dlmsprocessor/metrics"
 	"dlmsprocessor/proto"
+	"dlmsprocessor/simulator"
+	"dlmsprocessor/tlsconfig"
+	"dlmsprocessor/tracing"
+	"errors"
+	"flag"
 	"fmt"
+	"io"
 	"log"
+	"log/slog"
+	"maps"
 	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
 
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
 )
 
 func main() {
+	cfg, err := config.Load(os.Args[1:])
+	if errors.Is(err, flag.ErrHelp) {
+		return
+	}
+	if err != nil {
+		log.Fatalf("failed to load config: %v", err)
+	}
+
+	level, _ := cfg.SlogLevel()
+	slog.SetDefault(slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: level})))
 
-	lis, err := net.Listen("tcp", ":50051")
+	lis, err := net.Listen("tcp", cfg.ListenAddr)
 	if err != nil {
 		log.Fatalf("failed to listen: %v", err)
 	}
-	grpcServer := grpc.NewServer()
-	proto.RegisterDLMSProcessorServer(grpcServer, api.NewDLMSProcessorAPI())
-	fmt.Println("Server is running on port 50051")
-	grpcServer.Serve(lis)
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGTERM, os.Interrupt)
+	defer stop()
+
+	shutdownTracing, err := tracing.Setup(ctx, tracing.Options{
+		Endpoint:    cfg.OTLPEndpoint,
+		Insecure:    cfg.OTLPInsecure,
+		SampleRatio: cfg.TraceSampleRatio,
+	})
+	if err != nil {
+		log.Fatalf("failed to set up tracing: %v", err)
+	}
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := shutdownTracing(ctx); err != nil {
+			slog.Warn("Failed to flush traces", "error", err)
+		}
+	}()
+	if cfg.OTLPEndpoint != "" {
+		slog.Info("Exporting traces", "endpoint", cfg.OTLPEndpoint, "sample_ratio", cfg.TraceSampleRatio)
+	}
+
+	serverOpts := []grpc.ServerOption{grpc.StatsHandler(tracing.ServerHandler())}
+	if cfg.MaxConcurrentStreams > 0 {
+		serverOpts = append(serverOpts, grpc.MaxConcurrentStreams(uint32(cfg.MaxConcurrentStreams)))
+	}
+	var tlsConfig, gatewayTLSConfig *tls.Config
+	if cfg.TLSCertFile != "" {
+		reloader, clientAuth, err := tlsReloader(ctx, cfg)
+		if err != nil {
+			log.Fatalf("failed to set up TLS: %v", err)
+		}
+		tlsConfig = reloader.ServerConfig(clientAuth)
+		gatewayTLSConfig = reloader.ServerConfig(clientAuth, "h2", "http/1.1")
+	} else {
+		slog.Warn("TLS is not configured, serving plaintext")
+	}
+
+	var audit *auth.AuditLog
+	if cfg.AuditLogFile != "" {
+		audit, err = auth.OpenAuditLog(cfg.AuditLogFile)
+		if err != nil {
+			log.Fatalf("failed to set up auditing: %v", err)
+		}
+		defer audit.Close()
+	}
+	if cfg.AuthPolicyFile != "" {
+		policy, err := auth.LoadPolicy(cfg.AuthPolicyFile)
+		if err != nil {
+			log.Fatalf("failed to set up authorization: %v", err)
+		}
+		if cfg.TLSCertFile == "" {
+			slog.Warn("Authorization is enabled without TLS, bearer tokens are sent in plaintext")
+		}
+		if audit == nil {
+			slog.Warn("Authorization is enabled without an audit log")
+		}
+		authorizer := auth.NewAuthorizer(policy, methodRights(), audit)
+		serverOpts = append(serverOpts,
+			grpc.ChainUnaryInterceptor(authorizer.UnaryInterceptor()),
+			grpc.ChainStreamInterceptor(authorizer.StreamInterceptor()))
+		slog.Info("Authorization enabled", "policy_file", cfg.AuthPolicyFile, "callers", len(policy.Callers))
+	} else {
+		slog.Warn("Authorization is not configured, every caller may read, write and execute")
+	}
+	// The gateway reaches the API in memory, through a server sharing the
+	// interceptors but not the TLS credentials of the network one
+	var gatewayLis *gateway.Listener
+	var gatewayGRPC *grpc.Server
+	if cfg.GatewayAddr != "" {
+		gatewayLis = gateway.NewListener()
+		gatewayGRPC = grpc.NewServer(serverOpts...)
+	}
+	if tlsConfig != nil {
+		serverOpts = append(serverOpts, grpc.Creds(credentials.NewTLS(tlsConfig)))
+	}
+	grpcServer := grpc.NewServer(serverOpts...)
+
+	keyProvider, err := newKeyProvider(cfg)
+	if err != nil {
+		log.Fatalf("failed to set up the key provider: %v", err)
+	}
+	if cfg.AllowRawKeys {
+		slog.Warn("Raw meter keys are allowed in requests")
+	}
+	meterFactory, err := newMeterFactory(cfg)
+	if err != nil {
+		log.Fatalf("failed to set up the meters: %v", err)
+	}
+	if closer, ok := meterFactory.(io.Closer); ok {
+		defer closer.Close()
+	}
+
+	var profiles *dlms.ProfileDefinitions
+	if cfg.ProfileDefinitionsFile != "" {
+		profiles, err = dlms.LoadProfileDefinitions(cfg.ProfileDefinitionsFile)
+		if err != nil {
+			log.Fatalf("failed to load profile definitions: %v", err)
+		}
+		slog.Info("Profile definitions loaded", "file", cfg.ProfileDefinitionsFile, "profiles", len(profiles.Profiles))
+	}
+
+	processor := api.NewDLMSProcessorAPI(
+		api.WithAuditLog(audit),
+		api.WithKeyProvider(keyProvider),
+		api.WithRawKeys(cfg.AllowRawKeys),
+		api.WithFrameTraceDir(cfg.FrameTraceDir),
+		api.WithMeterFactory(meterFactory),
+		api.WithProfileDefinitions(profiles),
+		api.WithProcessWindow(cfg.ProcessWindow),
+		api.WithMaxMeterWorkers(cfg.MaxMeterWorkers),
+		api.WithConnectionTimeout(cfg.ConnectionTimeout),
+		api.WithOperationTimeout(cfg.OperationTimeout),
+		api.WithSessionOptions(dlms.SessionOptions{
+			IdleTimeout:       cfg.SessionIdleTimeout,
+			KeepAliveInterval: cfg.KeepAliveInterval,
+		}),
+	)
+	proto.RegisterDLMSProcessorServer(grpcServer, processor)
+	if gatewayGRPC != nil {
+		proto.RegisterDLMSProcessorServer(gatewayGRPC, processor)
+	}
+
+	healthServer := health.NewServer()
+	healthServer.SetServingStatus(proto.DLMSProcessor_ServiceDesc.ServiceName, healthpb.HealthCheckResponse_SERVING)
+	healthpb.RegisterHealthServer(grpcServer, healthServer)
+	reflection.Register(grpcServer)
+
+	serveErr := make(chan error, 2)
+	go func() {
+		serveErr <- grpcServer.Serve(lis)
+	}()
+	slog.Info("Server is running", "addr", lis.Addr().String())
+
+	var metricsServer *http.Server
+	if cfg.MetricsAddr != "" {
+		mux := http.NewServeMux()
+		mux.Handle("/metrics", metrics.Handler())
+		metricsServer = &http.Server{Addr: cfg.MetricsAddr, Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+		go func() {
+			if err := metricsServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				serveErr <- fmt.Errorf("metrics server: %w", err)
+			}
+		}()
+		slog.Info("Serving metrics", "addr", cfg.MetricsAddr)
+	}
+
+	var gatewayServer *http.Server
+	if gatewayGRPC != nil {
+		go gatewayGRPC.Serve(gatewayLis)
+		conn, err := gatewayLis.Dial()
+		if err != nil {
+			log.Fatalf("failed to set up the gateway: %v", err)
+		}
+		handler, err := gateway.NewHandler(ctx, conn)
+		if err != nil {
+			log.Fatalf("failed to set up the gateway: %v", err)
+		}
+		gatewayServer = &http.Server{Addr: cfg.GatewayAddr, Handler: handler, TLSConfig: gatewayTLSConfig, ReadHeaderTimeout: 10 * time.Second}
+		go func() {
+			var err error
+			if gatewayTLSConfig != nil {
+				err = gatewayServer.ListenAndServeTLS("", "")
+			} else {
+				err = gatewayServer.ListenAndServe()
+			}
+			if err != nil && !errors.Is(err, http.ErrServerClosed) {
+				serveErr <- fmt.Errorf("gateway: %w", err)
+			}
+		}()
+		slog.Info("Serving HTTP/JSON gateway", "addr", cfg.GatewayAddr, "openapi", gateway.OpenAPIPath)
+	}
+
+	select {
+	case err := <-serveErr:
+		log.Fatalf("server stopped: %v", err)
+	case <-ctx.Done():
+	}
+
+	// Report not serving so no new work is routed here, refuse new jobs and
+	// give in-flight meter sessions time to finish before cutting them off
+	slog.Info("Shutting down, draining in-flight work", "timeout", cfg.ShutdownTimeout)
+	healthServer.Shutdown()
+	processor.Drain()
+
+	drainCtx, cancelDrain := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+	defer cancelDrain()
+	stopped := make(chan struct{})
+	go func() {
+		if gatewayServer != nil {
+			// HTTP streams end once their calls finish; then nothing uses the
+			// in-memory server
+			gatewayServer.Shutdown(drainCtx)
+			gatewayGRPC.GracefulStop()
+		}
+		grpcServer.GracefulStop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+		slog.Info("Drained, exiting")
+	case <-drainCtx.Done():
+		slog.Warn("Drain timed out, aborting remaining work")
+		if gatewayServer != nil {
+			gatewayServer.Close()
+			gatewayGRPC.Stop()
+		}
+		grpcServer.Stop()
+	}
+
+	// Metrics stay up during the drain so it can be watched
+	if metricsServer != nil {
+		metricsServer.Close()
+	}
+}
+
+// methodRights is the right needed for every method served. Health checks
+// stay open to load balancers; reflection needs read access.
+func methodRights() map[string]auth.Right {
+	rights := map[string]auth.Right{
+		"/grpc.health.v1.Health/":                    auth.Public,
+		"/grpc.reflection.v1.ServerReflection/":      auth.Read,
+		"/grpc.reflection.v1alpha.ServerReflection/": auth.Read,
+	}
+	maps.Copy(rights, api.MethodRights)
+	return rights
+}
+
+// newKeyProvider sets up where key references are resolved, if anywhere
+func newKeyProvider(cfg config.Config) (keys.Provider, error) {
+	switch {
+	case cfg.KeystoreFile != "":
+		masterKey, err := keys.LoadMasterKey(cfg.KeystoreKeyFile)
+		if err != nil {
+			return nil, err
+		}
+		store, err := keys.OpenFileStore(cfg.KeystoreFile, masterKey)
+		if err != nil {
+			return nil, err
+		}
+		slog.Info("Resolving meter keys from keystore", "file", cfg.KeystoreFile, "versions", store.Len())
+		return store, nil
+
+	case cfg.VaultAddr != "":
+		token := os.Getenv("VAULT_TOKEN")
+		if cfg.VaultTokenFile != "" {
+			data, err := os.ReadFile(cfg.VaultTokenFile)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read Vault token: %w", err)
+			}
+			token = strings.TrimSpace(string(data))
+		}
+		slog.Info("Resolving meter keys from Vault", "addr", cfg.VaultAddr, "mount", cfg.VaultMount, "path", cfg.VaultPath)
+		return keys.NewVault(keys.VaultOptions{
+			Address: cfg.VaultAddr,
+			Mount:   cfg.VaultMount,
+			Path:    cfg.VaultPath,
+			Token:   token,
+		}), nil
+	}
+	return nil, nil
+}
+
+// newMeterFactory sets up the meters requests talk to
+func newMeterFactory(cfg config.Config) (dlms.MeterFactory, error) {
+	switch cfg.MeterBackend {
+	case "fake":
+		slog.Warn("Talking to fake meters", "seed", cfg.FakeSeed, "latency", cfg.FakeLatency, "latency_mean", cfg.FakeLatencyMean,
+			"connect_failure_rate", cfg.FakeConnectFailureRate, "read_failure_rate", cfg.FakeReadFailureRate)
+		return dlms.NewFakeMeters(dlms.Scenario{
+			Seed: cfg.FakeSeed,
+			Latency: dlms.Latency{
+				Distribution: dlms.LatencyDistribution(cfg.FakeLatency),
+				Mean:         cfg.FakeLatencyMean,
+				Spread:       cfg.FakeLatencySpread,
+			},
+			ConnectFailureRate: cfg.FakeConnectFailureRate,
+			ReadFailureRate:    cfg.FakeReadFailureRate,
+			Interval:           cfg.FakeInterval,
+		})
+
+	case "simulator":
+		slog.Warn("Talking to simulated meters")
+		return simulator.NewMeters(nil), nil
+	}
+	return dlms.RealMeters{}, nil
+}
+
+// tlsReloader sets up TLS from cfg. Certificates are reloaded when the files
+// change and on SIGHUP, until ctx is done.
+func tlsReloader(ctx context.Context, cfg config.Config) (*tlsconfig.Reloader, tls.ClientAuthType, error) {
+	clientAuth, err := tlsconfig.ParseClientAuth(cfg.TLSClientAuth, cfg.TLSClientCAFile != "")
+	if err != nil {
+		return nil, 0, err
+	}
+
+	reloader, err := tlsconfig.NewReloader(cfg.TLSCertFile, cfg.TLSKeyFile, cfg.TLSClientCAFile)
+	if err != nil {
+		return nil, 0, err
+	}
+	go reloader.Watch(ctx, cfg.TLSReloadInterval)
+
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	go func() {
+		defer signal.Stop(hup)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-hup:
+				if err := reloader.Reload(); err != nil {
+					slog.Error("Failed to reload TLS certificates", "error", err)
+				} else {
+					slog.Info("Reloaded TLS certificates on SIGHUP")
+				}
+			}
+		}
+	}()
+
+	slog.Info("TLS enabled", "cert_file", cfg.TLSCertFile, "client_auth", clientAuth.String())
+	return reloader, clientAuth, nil
 }
@@ -0,0 +1,67 @@
+// Command simulator serves simulated DLMS meters on local TCP ports, for
+// testing the processor without meters on the network.
+//
+//	simulator -listen 127.0.0.1:4059 -meters 3
+//
+// Each meter serves the default object model of package simulator on its
+// own port, counting up from the listen port, with the logical device name
+// SIM followed by its number.
+package main
+
+import (
+	"dlmsprocessor/simulator"
+	"flag"
+	"fmt"
+	"log"
+	"net"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+func main() {
+	listen := flag.String("listen", "127.0.0.1:4059", "address of the first meter")
+	meters := flag.Int("meters", 1, "number of meters")
+	key := flag.String("key", "", "hex block cipher and authentication key, the default model's if empty")
+	threePhase := flag.Bool("three-phase", false, "simulate three-phase meters")
+	tariffZones := flag.Int("tariff-zones", 0, "number of tariff zones in the billing profile, the model's four if 0")
+	flag.Parse()
+
+	model := simulator.DefaultConfig
+	if *threePhase {
+		model = simulator.ThreePhaseConfig
+	}
+
+	host, portText, err := net.SplitHostPort(*listen)
+	if err != nil {
+		log.Fatalf("invalid -listen: %v", err)
+	}
+	port, err := strconv.Atoi(portText)
+	if err != nil {
+		log.Fatalf("invalid -listen port %q", portText)
+	}
+
+	for i := range *meters {
+		config := model(time.Now())
+		if *tariffZones > 0 {
+			config = simulator.WithTariffZones(config, time.Now(), *tariffZones)
+		}
+		config.LogicalDeviceName = fmt.Sprintf("SIM%013d", i+1)
+		if *key != "" {
+			config.BlockCipherKey, config.AuthenticationKey = *key, *key
+		}
+
+		m, err := simulator.Start(config, net.JoinHostPort(host, strconv.Itoa(port+i)))
+		if err != nil {
+			log.Fatalf("failed to start meter %d: %v", i+1, err)
+		}
+		defer m.Close()
+		fmt.Printf("meter %s on %s\n", config.LogicalDeviceName, m.Addr())
+	}
+
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, syscall.SIGTERM, os.Interrupt)
+	<-stop
+}
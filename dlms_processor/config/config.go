@@ -0,0 +1,297 @@
+// Package config loads the processor's server settings. Values come from
+// defaults, then an optional YAML file, then DLMS_* environment variables,
+// then command line flags, each overriding the one before.
+package config
+
+import (
+	"bytes"
+	"dlmsprocessor/tlsconfig"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ConfigEnv names the environment variable holding the config file path,
+// used when -config is not given
+const ConfigEnv = "DLMS_CONFIG"
+
+type Config struct {
+	ListenAddr  string `yaml:"listen_addr"`
+	MetricsAddr string `yaml:"metrics_addr"` // HTTP address serving /metrics, empty to disable
+	GatewayAddr string `yaml:"gateway_addr"` // HTTP address serving the JSON gateway, empty to disable
+	LogLevel    string `yaml:"log_level"`    // debug, info, warn or error
+
+	// Concurrency limits
+	MaxConcurrentStreams uint `yaml:"max_concurrent_streams"` // per client connection, 0 for the gRPC default
+	ProcessWindow        int  `yaml:"process_window"`         // credits granted to each Process stream
+	MaxMeterWorkers      int  `yaml:"max_meter_workers"`      // meter operations running at once across all calls
+
+	// Timeouts
+	ConnectionTimeout time.Duration `yaml:"connection_timeout"` // meter I/O step, when a request sets none
+	OperationTimeout  time.Duration `yaml:"operation_timeout"`  // whole meter operation, when a request sets none; 0 for no limit
+	ShutdownTimeout   time.Duration `yaml:"shutdown_timeout"`   // how long in-flight work may drain on SIGTERM
+
+	// Meter sessions
+	SessionIdleTimeout time.Duration `yaml:"session_idle_timeout"`
+	KeepAliveInterval  time.Duration `yaml:"keep_alive_interval"`
+
+	// TLS, served in plaintext when no certificate is set
+	TLSCertFile       string        `yaml:"tls_cert_file"`
+	TLSKeyFile        string        `yaml:"tls_key_file"`
+	TLSClientCAFile   string        `yaml:"tls_client_ca_file"`  // CAs that client certificates must chain to
+	TLSClientAuth     string        `yaml:"tls_client_auth"`     // none, optional or require; require when a client CA is set
+	TLSReloadInterval time.Duration `yaml:"tls_reload_interval"` // how often the files are checked for changes
+
+	// Authorization, open to every caller when no policy is set
+	AuthPolicyFile string `yaml:"auth_policy_file"`
+	AuditLogFile   string `yaml:"audit_log_file"` // state-changing commands, as JSON lines
+
+	// Meter keys, resolved from a keystore file or a Vault KV v2 API when
+	// requests send a key reference
+	AllowRawKeys    bool   `yaml:"allow_raw_keys"`    // accept keys sent in requests
+	KeystoreFile    string `yaml:"keystore_file"`     // encrypted keystore, see cmd/keystore
+	KeystoreKeyFile string `yaml:"keystore_key_file"` // hex master key that decrypts it
+	VaultAddr       string `yaml:"vault_addr"`
+	VaultMount      string `yaml:"vault_mount"`      // KV v2 mount
+	VaultPath       string `yaml:"vault_path"`       // prefix under the mount, one secret per meter id
+	VaultTokenFile  string `yaml:"vault_token_file"` // VAULT_TOKEN is used when empty
+
+	// Directory requests may have their frame trace written to as pcap
+	// files, empty to refuse them
+	FrameTraceDir string `yaml:"frame_trace_dir"`
+
+	// Profile layouts Process reads by name, selected by meter model
+	ProfileDefinitionsFile string `yaml:"profile_definitions_file"`
+
+	// Tracing, exported over OTLP/gRPC when an endpoint is set
+	OTLPEndpoint     string  `yaml:"otlp_endpoint"`      // collector address, e.g. localhost:4317
+	OTLPInsecure     bool    `yaml:"otlp_insecure"`      // talk to the collector without TLS
+	TraceSampleRatio float64 `yaml:"trace_sample_ratio"` // fraction of new traces recorded
+
+	// Meters the processor talks to: real meters on the network, fake meters
+	// answering from a scenario, or simulated meters over local TCP
+	MeterBackend           string        `yaml:"meter_backend"`             // real, fake or simulator
+	FakeSeed               int64         `yaml:"fake_seed"`                 // seed of the fake readings
+	FakeLatency            string        `yaml:"fake_latency"`              // fixed, uniform, normal or exponential
+	FakeLatencyMean        time.Duration `yaml:"fake_latency_mean"`         // of every fake meter operation
+	FakeLatencySpread      time.Duration `yaml:"fake_latency_spread"`       // half width, or standard deviation if normal
+	FakeConnectFailureRate float64       `yaml:"fake_connect_failure_rate"` // fraction of fake connects failing
+	FakeReadFailureRate    float64       `yaml:"fake_read_failure_rate"`    // fraction of other fake operations failing
+	FakeInterval           time.Duration `yaml:"fake_interval"`             // block load capture period of fake meters
+}
+
+// Default returns the settings used when nothing else is configured
+func Default() Config {
+	return Config{
+		ListenAddr:         ":50051",
+		MetricsAddr:        ":8080",
+		GatewayAddr:        ":8081",
+		LogLevel:           "info",
+		ProcessWindow:      64,
+		MaxMeterWorkers:    256,
+		ConnectionTimeout:  5 * time.Second,
+		ShutdownTimeout:    30 * time.Second,
+		SessionIdleTimeout: 2 * time.Minute,
+		KeepAliveInterval:  30 * time.Second,
+		TLSReloadInterval:  time.Minute,
+		TraceSampleRatio:   1,
+		VaultMount:         "secret",
+		VaultPath:          "dlms/meters",
+		MeterBackend:       "real",
+		FakeLatency:        "fixed",
+		FakeInterval:       30 * time.Minute,
+	}
+}
+
+// bind defines one flag per setting, stored in cfg. The flag names are also
+// the source of the environment variable names.
+func bind(fs *flag.FlagSet, cfg *Config) {
+	fs.StringVar(&cfg.ListenAddr, "listen-addr", cfg.ListenAddr, "address to serve gRPC on")
+	fs.StringVar(&cfg.MetricsAddr, "metrics-addr", cfg.MetricsAddr, "HTTP address to serve Prometheus /metrics on, empty to disable")
+	fs.StringVar(&cfg.GatewayAddr, "gateway-addr", cfg.GatewayAddr, "HTTP address to serve the HTTP/JSON gateway on, empty to disable")
+	fs.StringVar(&cfg.LogLevel, "log-level", cfg.LogLevel, "log level: debug, info, warn or error")
+	fs.UintVar(&cfg.MaxConcurrentStreams, "max-concurrent-streams", cfg.MaxConcurrentStreams, "concurrent streams per client connection, 0 for the gRPC default")
+	fs.IntVar(&cfg.ProcessWindow, "process-window", cfg.ProcessWindow, "credits granted to each Process stream")
+	fs.IntVar(&cfg.MaxMeterWorkers, "max-meter-workers", cfg.MaxMeterWorkers, "meter operations running at once across all calls")
+	fs.DurationVar(&cfg.ConnectionTimeout, "connection-timeout", cfg.ConnectionTimeout, "meter I/O timeout when a request sets none")
+	fs.DurationVar(&cfg.OperationTimeout, "operation-timeout", cfg.OperationTimeout, "meter operation timeout when a request sets none, 0 for no limit")
+	fs.DurationVar(&cfg.ShutdownTimeout, "shutdown-timeout", cfg.ShutdownTimeout, "how long in-flight work may drain on shutdown")
+	fs.DurationVar(&cfg.SessionIdleTimeout, "session-idle-timeout", cfg.SessionIdleTimeout, "release a meter association after this long unused")
+	fs.DurationVar(&cfg.KeepAliveInterval, "keep-alive-interval", cfg.KeepAliveInterval, "keep-alive interval for held meter associations")
+	fs.StringVar(&cfg.TLSCertFile, "tls-cert-file", cfg.TLSCertFile, "server certificate, enables TLS")
+	fs.StringVar(&cfg.TLSKeyFile, "tls-key-file", cfg.TLSKeyFile, "server private key")
+	fs.StringVar(&cfg.TLSClientCAFile, "tls-client-ca-file", cfg.TLSClientCAFile, "CA bundle for verifying client certificates")
+	fs.StringVar(&cfg.TLSClientAuth, "tls-client-auth", cfg.TLSClientAuth, "client certificates: none, optional or require")
+	fs.DurationVar(&cfg.TLSReloadInterval, "tls-reload-interval", cfg.TLSReloadInterval, "how often to check the TLS files for changes")
+	fs.StringVar(&cfg.AuthPolicyFile, "auth-policy-file", cfg.AuthPolicyFile, "caller roles policy, enables authorization")
+	fs.StringVar(&cfg.AuditLogFile, "audit-log-file", cfg.AuditLogFile, "file to append the audit log of state-changing commands to")
+	fs.BoolVar(&cfg.AllowRawKeys, "allow-raw-keys", cfg.AllowRawKeys, "accept meter keys sent in requests instead of a key reference")
+	fs.StringVar(&cfg.KeystoreFile, "keystore-file", cfg.KeystoreFile, "encrypted keystore to resolve key references from")
+	fs.StringVar(&cfg.KeystoreKeyFile, "keystore-key-file", cfg.KeystoreKeyFile, "file holding the hex master key of the keystore")
+	fs.StringVar(&cfg.VaultAddr, "vault-addr", cfg.VaultAddr, "Vault KV v2 compatible API to resolve key references from")
+	fs.StringVar(&cfg.VaultMount, "vault-mount", cfg.VaultMount, "KV v2 mount holding the meter keys")
+	fs.StringVar(&cfg.VaultPath, "vault-path", cfg.VaultPath, "path under the mount with one secret per meter id")
+	fs.StringVar(&cfg.VaultTokenFile, "vault-token-file", cfg.VaultTokenFile, "file holding the Vault token, VAULT_TOKEN if empty")
+	fs.StringVar(&cfg.FrameTraceDir, "frame-trace-dir", cfg.FrameTraceDir, "directory to write requested frame traces to as pcap files, empty to refuse them")
+	fs.StringVar(&cfg.ProfileDefinitionsFile, "profile-definitions-file", cfg.ProfileDefinitionsFile, "YAML or JSON profile definitions read by name")
+	fs.StringVar(&cfg.OTLPEndpoint, "otlp-endpoint", cfg.OTLPEndpoint, "OTLP/gRPC collector to export traces to, empty to disable")
+	fs.BoolVar(&cfg.OTLPInsecure, "otlp-insecure", cfg.OTLPInsecure, "connect to the OTLP collector without TLS")
+	fs.Float64Var(&cfg.TraceSampleRatio, "trace-sample-ratio", cfg.TraceSampleRatio, "fraction of new traces recorded, 0 to 1")
+	fs.StringVar(&cfg.MeterBackend, "meter-backend", cfg.MeterBackend, "meters to talk to: real, fake or simulator")
+	fs.Int64Var(&cfg.FakeSeed, "fake-seed", cfg.FakeSeed, "seed of the fake meters' readings")
+	fs.StringVar(&cfg.FakeLatency, "fake-latency", cfg.FakeLatency, "latency distribution of fake meters: fixed, uniform, normal or exponential")
+	fs.DurationVar(&cfg.FakeLatencyMean, "fake-latency-mean", cfg.FakeLatencyMean, "mean latency of a fake meter operation")
+	fs.DurationVar(&cfg.FakeLatencySpread, "fake-latency-spread", cfg.FakeLatencySpread, "spread of fake meter latencies around the mean")
+	fs.Float64Var(&cfg.FakeConnectFailureRate, "fake-connect-failure-rate", cfg.FakeConnectFailureRate, "fraction of fake meter connects that fail, 0 to 1")
+	fs.Float64Var(&cfg.FakeReadFailureRate, "fake-read-failure-rate", cfg.FakeReadFailureRate, "fraction of other fake meter operations that fail, 0 to 1")
+	fs.DurationVar(&cfg.FakeInterval, "fake-interval", cfg.FakeInterval, "block load capture period of fake meters")
+}
+
+// envName is the environment variable for a flag, e.g. DLMS_LISTEN_ADDR
+func envName(flagName string) string {
+	return "DLMS_" + strings.ToUpper(strings.ReplaceAll(flagName, "-", "_"))
+}
+
+// Load builds the configuration from args (without the program name), the
+// environment and the config file they point to
+func Load(args []string) (Config, error) {
+	// First pass: find the config file and which flags were given
+	var scratch Config
+	var path string
+	fs := flag.NewFlagSet("dlmsprocessor", flag.ContinueOnError)
+	fs.StringVar(&path, "config", os.Getenv(ConfigEnv), "path to a YAML config file (env "+ConfigEnv+")")
+	bind(fs, &scratch)
+	if err := fs.Parse(args); err != nil {
+		return Config{}, err
+	}
+	if fs.NArg() > 0 {
+		return Config{}, fmt.Errorf("unexpected arguments: %v", fs.Args())
+	}
+	given := make(map[string]string)
+	fs.Visit(func(f *flag.Flag) { given[f.Name] = f.Value.String() })
+
+	cfg := Default()
+	if path != "" {
+		if err := loadFile(path, &cfg); err != nil {
+			return Config{}, err
+		}
+	}
+
+	// Second pass: environment, then the flags given on the command line
+	layer := flag.NewFlagSet("dlmsprocessor", flag.ContinueOnError)
+	bind(layer, &cfg)
+	var err error
+	layer.VisitAll(func(f *flag.Flag) {
+		if v, ok := os.LookupEnv(envName(f.Name)); ok && err == nil {
+			if setErr := layer.Set(f.Name, v); setErr != nil {
+				err = fmt.Errorf("invalid %s: %w", envName(f.Name), setErr)
+			}
+		}
+	})
+	if err != nil {
+		return Config{}, err
+	}
+	for name, v := range given {
+		if name == "config" {
+			continue
+		}
+		if err := layer.Set(name, v); err != nil {
+			return Config{}, fmt.Errorf("invalid -%s: %w", name, err)
+		}
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return Config{}, err
+	}
+	return cfg, nil
+}
+
+func loadFile(path string, cfg *Config) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(cfg); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+	return nil
+}
+
+// Validate checks that the settings are usable
+func (c Config) Validate() error {
+	if c.ListenAddr == "" {
+		return errors.New("listen_addr is required")
+	}
+	if _, err := c.SlogLevel(); err != nil {
+		return err
+	}
+	if c.ProcessWindow <= 0 {
+		return errors.New("process_window must be positive")
+	}
+	if c.MaxMeterWorkers <= 0 {
+		return errors.New("max_meter_workers must be positive")
+	}
+	if c.ConnectionTimeout <= 0 {
+		return errors.New("connection_timeout must be positive")
+	}
+	if c.OperationTimeout < 0 || c.ShutdownTimeout < 0 || c.SessionIdleTimeout < 0 || c.KeepAliveInterval < 0 {
+		return errors.New("timeouts cannot be negative")
+	}
+	if (c.TLSCertFile == "") != (c.TLSKeyFile == "") {
+		return errors.New("tls_cert_file and tls_key_file must be set together")
+	}
+	if c.TLSCertFile == "" && (c.TLSClientCAFile != "" || c.TLSClientAuth != "") {
+		return errors.New("client certificate settings need tls_cert_file")
+	}
+	if _, err := tlsconfig.ParseClientAuth(c.TLSClientAuth, c.TLSClientCAFile != ""); err != nil {
+		return err
+	}
+	if c.TLSReloadInterval <= 0 {
+		return errors.New("tls_reload_interval must be positive")
+	}
+	if c.TraceSampleRatio < 0 || c.TraceSampleRatio > 1 {
+		return errors.New("trace_sample_ratio must be between 0 and 1")
+	}
+	if (c.KeystoreFile == "") != (c.KeystoreKeyFile == "") {
+		return errors.New("keystore_file and keystore_key_file must be set together")
+	}
+	if c.KeystoreFile != "" && c.VaultAddr != "" {
+		return errors.New("set either keystore_file or vault_addr, not both")
+	}
+	switch c.MeterBackend {
+	case "real", "fake", "simulator":
+	default:
+		return fmt.Errorf("unknown meter_backend %q", c.MeterBackend)
+	}
+	switch c.FakeLatency {
+	case "fixed", "uniform", "normal", "exponential":
+	default:
+		return fmt.Errorf("unknown fake_latency %q", c.FakeLatency)
+	}
+	if c.FakeLatencyMean < 0 || c.FakeLatencySpread < 0 || c.FakeInterval <= 0 {
+		return errors.New("fake latencies cannot be negative and fake_interval must be positive")
+	}
+	if c.FakeConnectFailureRate < 0 || c.FakeConnectFailureRate > 1 || c.FakeReadFailureRate < 0 || c.FakeReadFailureRate > 1 {
+		return errors.New("fake failure rates must be between 0 and 1")
+	}
+	return nil
+}
+
+// SlogLevel parses LogLevel
+func (c Config) SlogLevel() (slog.Level, error) {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(c.LogLevel)); err != nil {
+		return 0, fmt.Errorf("invalid log_level %q", c.LogLevel)
+	}
+	return level, nil
+}
@@ -0,0 +1,134 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func writeConfig(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+	return path
+}
+
+func TestLoadPrecedence(t *testing.T) {
+	path := writeConfig(t, `
+listen_addr: ":6000"
+log_level: debug
+process_window: 16
+shutdown_timeout: 45s
+`)
+
+	testCases := []struct {
+		name string
+		env  map[string]string
+		args []string
+		want func(c *Config)
+	}{
+		{
+			name: "defaults",
+			want: func(c *Config) {},
+		},
+		{
+			name: "file over defaults",
+			args: []string{"-config", path},
+			want: func(c *Config) {
+				c.ListenAddr = ":6000"
+				c.LogLevel = "debug"
+				c.ProcessWindow = 16
+				c.ShutdownTimeout = 45 * time.Second
+			},
+		},
+		{
+			name: "file from environment",
+			env:  map[string]string{ConfigEnv: path},
+			want: func(c *Config) {
+				c.ListenAddr = ":6000"
+				c.LogLevel = "debug"
+				c.ProcessWindow = 16
+				c.ShutdownTimeout = 45 * time.Second
+			},
+		},
+		{
+			name: "environment over file",
+			env:  map[string]string{"DLMS_LISTEN_ADDR": ":7000", "DLMS_CONNECTION_TIMEOUT": "2s"},
+			args: []string{"-config", path},
+			want: func(c *Config) {
+				c.ListenAddr = ":7000"
+				c.LogLevel = "debug"
+				c.ProcessWindow = 16
+				c.ShutdownTimeout = 45 * time.Second
+				c.ConnectionTimeout = 2 * time.Second
+			},
+		},
+		{
+			name: "flags over environment",
+			env:  map[string]string{"DLMS_LISTEN_ADDR": ":7000", "DLMS_PROCESS_WINDOW": "8"},
+			args: []string{"-config", path, "-listen-addr", ":8000", "-max-concurrent-streams", "100"},
+			want: func(c *Config) {
+				c.ListenAddr = ":8000"
+				c.LogLevel = "debug"
+				c.ProcessWindow = 8
+				c.ShutdownTimeout = 45 * time.Second
+				c.MaxConcurrentStreams = 100
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			for k, v := range tc.env {
+				t.Setenv(k, v)
+			}
+
+			got, err := Load(tc.args)
+			if err != nil {
+				t.Fatalf("Load: %v", err)
+			}
+
+			want := Default()
+			tc.want(&want)
+			if got != want {
+				t.Errorf("Expected %+v, got %+v", want, got)
+			}
+		})
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	testCases := []struct {
+		name string
+		env  map[string]string
+		args []string
+	}{
+		{name: "unknown file key", args: []string{"-config", writeConfig(t, "listen_adr: \":6000\"\n")}},
+		{name: "missing file", args: []string{"-config", filepath.Join(t.TempDir(), "missing.yaml")}},
+		{name: "bad environment value", env: map[string]string{"DLMS_SHUTDOWN_TIMEOUT": "soon"}},
+		{name: "bad log level", args: []string{"-log-level", "loud"}},
+		{name: "zero window", args: []string{"-process-window", "0"}},
+		{name: "stray argument", args: []string{"serve"}},
+		{name: "certificate without key", args: []string{"-tls-cert-file", "server.crt"}},
+		{name: "client CA without certificate", args: []string{"-tls-client-ca-file", "ca.crt"}},
+		{name: "client auth without CA", args: []string{"-tls-cert-file", "server.crt", "-tls-key-file", "server.key", "-tls-client-auth", "require"}},
+		{name: "keystore without master key", args: []string{"-keystore-file", "keys.enc"}},
+		{name: "two key providers", args: []string{"-keystore-file", "keys.enc", "-keystore-key-file", "master.key", "-vault-addr", "http://localhost:8200"}},
+		{name: "unknown meter backend", args: []string{"-meter-backend", "mock"}},
+		{name: "fake failure rate above one", args: []string{"-fake-read-failure-rate", "1.5"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			for k, v := range tc.env {
+				t.Setenv(k, v)
+			}
+			if _, err := Load(tc.args); err == nil {
+				t.Errorf("Expected an error")
+			}
+		})
+	}
+}
@@ -0,0 +1,136 @@
+package dlms
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Attribute names the attribute of a COSEM object a profile column captures:
+// an index such as "5", or a name resolved against the object's interface
+// class, such as "capture_time". Empty is the value attribute, 2.
+type Attribute string
+
+// attributeNames are the attribute names of the register classes. A
+// Register's value and an Extended Register's value and capture time are
+// attributes 2 and 5; a Demand Register keeps its current and last average
+// in 2 and 3 and its capture time in 6.
+var attributeNames = map[int]map[string]int{
+	ObjectTypeRegister: {"value": 2, "scaler_unit": 3},
+	ObjectTypeExtendedRegister: {
+		"value": 2, "scaler_unit": 3, "status": 4, "capture_time": 5,
+	},
+	ObjectTypeDemandRegister: {
+		"value": 2, "current_average": 2, "last_average": 3, "scaler_unit": 4,
+		"status": 5, "capture_time": 6, "start_time_current": 7, "period": 8, "number_of_periods": 9,
+	},
+}
+
+// Index resolves the attribute for an object of classID. Names take their
+// Extended Register meaning when the class is unknown (0), as meters capture
+// maximum demand in Extended Registers.
+func (a Attribute) Index(classID int) (int, error) {
+	if a == "" {
+		return 2, nil
+	}
+	if i, err := strconv.ParseUint(string(a), 10, 8); err == nil {
+		if i == 0 {
+			return 0, fmt.Errorf("invalid attribute %q", a)
+		}
+		return int(i), nil
+	}
+
+	if classID == 0 {
+		classID = ObjectTypeExtendedRegister
+	}
+	names, ok := attributeNames[classID]
+	if !ok {
+		names = map[string]int{"value": 2}
+	}
+	if i, ok := names[string(a)]; ok {
+		return i, nil
+	}
+	return 0, fmt.Errorf("class %d has no attribute %q", classID, a)
+}
+
+// validate checks that the attribute is an index or a name of some class
+func (a Attribute) validate() error {
+	if _, err := a.Index(0); err == nil {
+		return nil
+	}
+	for classID := range attributeNames {
+		if _, err := a.Index(classID); err == nil {
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown attribute %q", a)
+}
+
+// column finds the column capturing attribute of obis. Columns whose
+// attribute the meter did not report match whatever attribute is wanted.
+func (r *DLMSResult) column(obis string, attribute Attribute) (int, bool) {
+	unknown := -1
+	for i, name := range r.ColumnNames {
+		if name != obis {
+			continue
+		}
+		captured := 0
+		if i < len(r.ColumnAttributes) {
+			captured = r.ColumnAttributes[i]
+		}
+		if captured == 0 {
+			if unknown < 0 {
+				unknown = i
+			}
+			continue
+		}
+		classID := 0
+		if i < len(r.ColumnClasses) {
+			classID = r.ColumnClasses[i]
+		}
+		if want, err := attribute.Index(classID); err == nil && want == captured {
+			return i, true
+		}
+	}
+	return unknown, unknown >= 0
+}
+
+// dateTimeLayout is how the shim formats date-times, e.g. the clock
+const dateTimeLayout = "01/02/2006 15:04:05 UTC-07:00"
+
+// parseDateTime formats a COSEM date-time that reached Go as a 12 byte
+// octet string, like an Extended Register's capture time in a profile, the
+// way the shim formats date-times it knows the type of. Other values are
+// returned as they are.
+func parseDateTime(value string) string {
+	raw, err := hex.DecodeString(strings.TrimPrefix(value, "Hex:"))
+	if !strings.HasPrefix(value, "Hex:") || err != nil {
+		return value
+	}
+	t, ok := decodeDateTime(raw)
+	if !ok {
+		return value
+	}
+	return t.Format(dateTimeLayout)
+}
+
+// decodeDateTime decodes a 12 byte COSEM date-time, if it is a complete one
+func decodeDateTime(raw []byte) (time.Time, bool) {
+	if len(raw) != dateTimeSize {
+		return time.Time{}, false
+	}
+	year := int(binary.BigEndian.Uint16(raw[0:2]))
+	month, day, hour, minute, second := int(raw[2]), int(raw[3]), int(raw[5]), int(raw[6]), int(raw[7])
+	if year == 0xFFFF || month < 1 || month > 12 || day < 1 || day > 31 || hour > 23 || minute > 59 || second > 59 {
+		return time.Time{}, false
+	}
+	// The deviation is UTC minus local time in minutes, 0x8000 if unspecified
+	offset := 0
+	if deviation := int16(binary.BigEndian.Uint16(raw[9:11])); deviation != -0x8000 {
+		offset = -int(deviation) * 60
+	}
+	return time.Date(year, time.Month(month), day, hour, minute, second, 0, time.FixedZone("", offset)), true
+}
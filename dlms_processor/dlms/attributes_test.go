@@ -0,0 +1,92 @@
+package dlms
+
+import "testing"
+
+func TestAttributeIndex(t *testing.T) {
+	testCases := []struct {
+		attribute Attribute
+		classID   int
+		want      int
+		wantErr   bool
+	}{
+		{attribute: "", classID: ObjectTypeRegister, want: 2},
+		{attribute: "5", classID: ObjectTypeData, want: 5},
+		{attribute: "capture_time", classID: ObjectTypeExtendedRegister, want: 5},
+		{attribute: "capture_time", classID: ObjectTypeDemandRegister, want: 6},
+		{attribute: "capture_time", classID: 0, want: 5},
+		{attribute: "last_average", classID: ObjectTypeDemandRegister, want: 3},
+		{attribute: "current_average", classID: ObjectTypeDemandRegister, want: 2},
+		{attribute: "value", classID: ObjectTypeClock, want: 2},
+		{attribute: "last_average", classID: ObjectTypeExtendedRegister, wantErr: true},
+		{attribute: "0", classID: ObjectTypeRegister, wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		got, err := tc.attribute.Index(tc.classID)
+		if tc.wantErr {
+			if err == nil {
+				t.Errorf("Expected %q of class %d to be refused, got %d", tc.attribute, tc.classID, got)
+			}
+			continue
+		}
+		if err != nil || got != tc.want {
+			t.Errorf("Expected %q of class %d to be %d, got %d, %v", tc.attribute, tc.classID, tc.want, got, err)
+		}
+	}
+
+	if err := Attribute("last_average").validate(); err != nil {
+		t.Errorf("Expected a Demand Register name to be valid: %v", err)
+	}
+	if err := Attribute("peak").validate(); err == nil {
+		t.Error("Expected an unknown name to be invalid")
+	}
+}
+
+func TestResultColumn(t *testing.T) {
+	result := &DLMSResult{
+		ColumnNames:      []string{"1.0.1.6.0.255", "1.0.1.6.0.255", "1.0.1.4.0.255", "1.0.1.4.0.255", "1.0.1.8.0.255"},
+		ColumnClasses:    []int{ObjectTypeExtendedRegister, ObjectTypeExtendedRegister, ObjectTypeDemandRegister, ObjectTypeDemandRegister, 0},
+		ColumnAttributes: []int{2, 5, 3, 6, 0},
+	}
+	testCases := []struct {
+		obis      string
+		attribute Attribute
+		want      int
+		found     bool
+	}{
+		{obis: "1.0.1.6.0.255", attribute: "", want: 0, found: true},
+		{obis: "1.0.1.6.0.255", attribute: "capture_time", want: 1, found: true},
+		{obis: "1.0.1.4.0.255", attribute: "last_average", want: 2, found: true},
+		{obis: "1.0.1.4.0.255", attribute: "capture_time", want: 3, found: true},
+		{obis: "1.0.1.4.0.255", attribute: "current_average", want: -1, found: false},
+		{obis: "1.0.1.8.0.255", attribute: "capture_time", want: 4, found: true}, // attribute not reported
+		{obis: "1.0.2.8.0.255", attribute: "", want: -1, found: false},
+	}
+
+	for _, tc := range testCases {
+		got, found := result.column(tc.obis, tc.attribute)
+		if got != tc.want || found != tc.found {
+			t.Errorf("column(%s, %q) = %d, %v, want %d, %v", tc.obis, tc.attribute, got, found, tc.want, tc.found)
+		}
+	}
+}
+
+func TestParseDateTime(t *testing.T) {
+	testCases := []struct {
+		value string
+		want  string
+	}{
+		{value: "Hex:07EA091C0113000000000000", want: "09/28/2026 19:00:00 UTC+00:00"},
+		{value: "Hex:07EA091C0113000000FEB600", want: "09/28/2026 19:00:00 UTC+05:30"}, // deviation -330 minutes
+		{value: "Hex:07EA091C01130000FF800000", want: "09/28/2026 19:00:00 UTC+00:00"}, // deviation unspecified
+		{value: "Hex:FFFF091C0113000000000000", want: "Hex:FFFF091C0113000000000000"},
+		{value: "Hex:4142", want: "Hex:4142"},
+		{value: "10/19/2026 10:00:00 UTC+00:00", want: "10/19/2026 10:00:00 UTC+00:00"},
+	}
+
+	for _, tc := range testCases {
+		if got := parseDateTime(tc.value); got != tc.want {
+			t.Errorf("parseDateTime(%q) = %q, want %q", tc.value, got, tc.want)
+		}
+	}
+}
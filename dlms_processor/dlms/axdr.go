@@ -0,0 +1,222 @@
+package dlms
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// A-XDR data type tags of the values the calendar objects hold
+const (
+	tagArray        = 0x01
+	tagStructure    = 0x02
+	tagOctetString  = 0x09
+	tagInteger      = 0x0F
+	tagUnsigned     = 0x11
+	tagLongUnsigned = 0x12
+	tagDateTime     = 0x19
+	tagDate         = 0x1A
+	tagTime         = 0x1B
+)
+
+// Sizes of the COSEM date and time encodings
+const (
+	dateTimeSize = 12
+	dateSize     = 5
+	timeSize     = 4
+)
+
+// Limits on what decodeAXDR accepts from a meter
+const (
+	maxAXDRNesting   = 8
+	maxAXDRItemCount = 4096
+)
+
+// axdr is a DLMS data value: the items of an array or structure, the bytes
+// of an octet string, date, time or date-time, or a number
+type axdr struct {
+	tag    byte
+	items  []axdr
+	bytes  []byte
+	number int64
+}
+
+func axdrArray(items ...axdr) axdr     { return axdr{tag: tagArray, items: items} }
+func axdrStructure(items ...axdr) axdr { return axdr{tag: tagStructure, items: items} }
+func axdrOctets(b []byte) axdr         { return axdr{tag: tagOctetString, bytes: b} }
+func axdrUnsigned(v uint8) axdr        { return axdr{tag: tagUnsigned, number: int64(v)} }
+func axdrLongUnsigned(v uint16) axdr   { return axdr{tag: tagLongUnsigned, number: int64(v)} }
+func axdrInteger(v int8) axdr          { return axdr{tag: tagInteger, number: int64(v)} }
+
+// encode returns the A-XDR encoding of v
+func (v axdr) encode() Encoded {
+	return v.appendTo(nil)
+}
+
+func (v axdr) appendTo(b []byte) []byte {
+	b = append(b, v.tag)
+	switch v.tag {
+	case tagArray, tagStructure:
+		b = appendLength(b, len(v.items))
+		for _, item := range v.items {
+			b = item.appendTo(b)
+		}
+	case tagOctetString:
+		b = appendLength(b, len(v.bytes))
+		b = append(b, v.bytes...)
+	case tagDateTime, tagDate, tagTime:
+		b = append(b, v.bytes...)
+	case tagInteger, tagUnsigned:
+		b = append(b, byte(v.number))
+	case tagLongUnsigned:
+		b = binary.BigEndian.AppendUint16(b, uint16(v.number))
+	}
+	return b
+}
+
+// appendLength appends a length in the variable length form of A-XDR
+func appendLength(b []byte, n int) []byte {
+	switch {
+	case n < 0x80:
+		return append(b, byte(n))
+	case n <= 0xFF:
+		return append(b, 0x81, byte(n))
+	default:
+		return binary.BigEndian.AppendUint16(append(b, 0x82), uint16(n))
+	}
+}
+
+// decodeAXDR decodes a single A-XDR value that fills data
+func decodeAXDR(data []byte) (axdr, error) {
+	v, rest, err := decodeValue(data, 0)
+	if err != nil {
+		return axdr{}, err
+	}
+	if len(rest) != 0 {
+		return axdr{}, fmt.Errorf("%d bytes after the value", len(rest))
+	}
+	return v, nil
+}
+
+func decodeValue(data []byte, depth int) (axdr, []byte, error) {
+	if len(data) == 0 {
+		return axdr{}, nil, fmt.Errorf("truncated value")
+	}
+	if depth > maxAXDRNesting {
+		return axdr{}, nil, fmt.Errorf("values nested too deep")
+	}
+	v := axdr{tag: data[0]}
+	data = data[1:]
+
+	fixed := func(size int) error {
+		if len(data) < size {
+			return fmt.Errorf("truncated value of type %d", v.tag)
+		}
+		v.bytes, data = data[:size], data[size:]
+		return nil
+	}
+	var err error
+	switch v.tag {
+	case tagArray, tagStructure:
+		var n int
+		if n, data, err = decodeLength(data); err != nil {
+			return axdr{}, nil, err
+		}
+		if n > maxAXDRItemCount {
+			return axdr{}, nil, fmt.Errorf("%d items is too many", n)
+		}
+		for range n {
+			var item axdr
+			if item, data, err = decodeValue(data, depth+1); err != nil {
+				return axdr{}, nil, err
+			}
+			v.items = append(v.items, item)
+		}
+	case tagOctetString:
+		var n int
+		if n, data, err = decodeLength(data); err != nil {
+			return axdr{}, nil, err
+		}
+		err = fixed(n)
+	case tagDateTime:
+		err = fixed(dateTimeSize)
+	case tagDate:
+		err = fixed(dateSize)
+	case tagTime:
+		err = fixed(timeSize)
+	case tagInteger:
+		if err = fixed(1); err == nil {
+			v.number = int64(int8(v.bytes[0]))
+		}
+	case tagUnsigned:
+		if err = fixed(1); err == nil {
+			v.number = int64(v.bytes[0])
+		}
+	case tagLongUnsigned:
+		if err = fixed(2); err == nil {
+			v.number = int64(binary.BigEndian.Uint16(v.bytes))
+		}
+	default:
+		return axdr{}, nil, fmt.Errorf("unsupported data type %d", v.tag)
+	}
+	if err != nil {
+		return axdr{}, nil, err
+	}
+	if v.tag != tagOctetString && v.tag != tagDateTime && v.tag != tagDate && v.tag != tagTime {
+		v.bytes = nil
+	}
+	return v, data, nil
+}
+
+// decodeLength decodes a length in the variable length form of A-XDR
+func decodeLength(data []byte) (int, []byte, error) {
+	if len(data) == 0 {
+		return 0, nil, fmt.Errorf("truncated length")
+	}
+	first := data[0]
+	if first < 0x80 {
+		return int(first), data[1:], nil
+	}
+	size := int(first & 0x7F)
+	if size == 0 || size > 2 || len(data) < 1+size {
+		return 0, nil, fmt.Errorf("invalid length")
+	}
+	n := 0
+	for _, b := range data[1 : 1+size] {
+		n = n<<8 | int(b)
+	}
+	return n, data[1+size:], nil
+}
+
+// octets returns the bytes of an octet string, or of a date, time or
+// date-time sent as its own type
+func (v axdr) octets() ([]byte, error) {
+	switch v.tag {
+	case tagOctetString, tagDateTime, tagDate, tagTime:
+		return v.bytes, nil
+	}
+	return nil, fmt.Errorf("expected an octet string, got type %d", v.tag)
+}
+
+// unsigned returns an unsigned number of at most max
+func (v axdr) unsigned(max int64) (int64, error) {
+	switch v.tag {
+	case tagUnsigned, tagLongUnsigned, tagInteger:
+		if v.number < 0 || v.number > max {
+			return 0, fmt.Errorf("%d out of range", v.number)
+		}
+		return v.number, nil
+	}
+	return 0, fmt.Errorf("expected a number, got type %d", v.tag)
+}
+
+// list returns the items of an array or structure, which must have count
+// items unless count is negative
+func (v axdr) list(tag byte, count int) ([]axdr, error) {
+	if v.tag != tag {
+		return nil, fmt.Errorf("expected type %d, got type %d", tag, v.tag)
+	}
+	if count >= 0 && len(v.items) != count {
+		return nil, fmt.Errorf("expected %d items, got %d", count, len(v.items))
+	}
+	return v.items, nil
+}
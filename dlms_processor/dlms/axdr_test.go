@@ -0,0 +1,57 @@
+package dlms
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestAXDRRoundTrip(t *testing.T) {
+	v := axdrArray(
+		axdrStructure(axdrLongUnsigned(300), axdrOctets(bytes.Repeat([]byte{0xAB}, 200)), axdrUnsigned(7)),
+		axdrStructure(axdrInteger(-1)),
+	)
+	data := v.encode()
+	// The octet string's length of 200 takes the two byte form
+	if !bytes.Contains(data, []byte{tagOctetString, 0x81, 200}) {
+		t.Errorf("Expected a long form length in % X", data)
+	}
+
+	got, err := decodeAXDR(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got.encode(), data) {
+		t.Errorf("Expected % X, got % X", data, got.encode())
+	}
+	items, err := got.list(tagArray, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fields, err := items[0].list(tagStructure, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n, err := fields[0].unsigned(0xFFFF); err != nil || n != 300 {
+		t.Errorf("Expected 300, got %d, %v", n, err)
+	}
+	if _, err := fields[2].unsigned(5); err == nil {
+		t.Errorf("Expected 7 to be out of range")
+	}
+}
+
+func TestDecodeAXDRRejectsMalformed(t *testing.T) {
+	for _, data := range [][]byte{
+		{},
+		{tagArray, 2, tagUnsigned, 1},         // too few items
+		{tagOctetString, 5, 1, 2},             // truncated
+		{tagUnsigned, 1, 2},                   // trailing bytes
+		{tagArray, 0x82, 0xFF, 0xFF},          // too many items
+		{tagOctetString, 0x83, 0, 0, 1, 0},    // length too long
+		{0x20},                                // unsupported type
+		bytes.Repeat([]byte{tagArray, 1}, 20), // nested too deep
+	} {
+		if _, err := decodeAXDR(data); err == nil {
+			t.Errorf("Expected % X to be rejected", data)
+		}
+	}
+}
@@ -0,0 +1,668 @@
+package dlms
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ActivityCalendarOBIS is the activity calendar (IC 20) switching tariffs,
+// SpecialDaysTableOBIS the special days table (IC 11) it consults and
+// TariffScriptTableOBIS the tariffication script table day actions run
+const (
+	ActivityCalendarOBIS  = "0.0.13.0.0.255"
+	SpecialDaysTableOBIS  = "0.0.11.0.0.255"
+	TariffScriptTableOBIS = "0.0.10.0.100.255"
+)
+
+// Activity calendar attributes, the passive ones at passiveCalendar plus
+// the same offsets, and its method activating the passive calendar
+const (
+	activeCalendar              = 2
+	passiveCalendar             = 6
+	activatePassiveCalendarTime = 10
+	activatePassiveCalendar     = 1
+)
+
+// Special days table attribute and methods
+const (
+	specialDaysEntries = 2
+	specialDaysInsert  = 1
+	specialDaysDelete  = 2
+)
+
+// ErrTariffMismatch is returned when a meter reads back a tariff other than
+// the one programmed
+var ErrTariffMismatch = errors.New("tariff read back differs")
+
+// Tariff is a meter's activity calendar and special days
+type Tariff struct {
+	Active         Calendar     `json:"active"`
+	Passive        Calendar     `json:"passive"`
+	ActivationTime time.Time    `json:"activation_time"` // when the passive calendar becomes active, zero if not set
+	SpecialDays    []SpecialDay `json:"special_days"`
+}
+
+// Calendar is the seasons of an activity calendar, the week profiles they
+// use and the day profiles those switch tariffs by
+type Calendar struct {
+	Name         string        `json:"name"`
+	Seasons      []Season      `json:"seasons"`
+	WeekProfiles []WeekProfile `json:"week_profiles"`
+	DayProfiles  []DayProfile  `json:"day_profiles"`
+}
+
+// Season is in force from its start until the next season's
+type Season struct {
+	Name        string       `json:"name"`
+	Start       CalendarDate `json:"start"`
+	WeekProfile string       `json:"week_profile"`
+}
+
+// WeekProfile gives the day profile of each day of the week
+type WeekProfile struct {
+	Name string   `json:"name"`
+	Days [7]uint8 `json:"days"` // day profile IDs, Monday to Sunday
+}
+
+// DayProfile is the actions a day runs, in order of their start
+type DayProfile struct {
+	ID      uint8       `json:"id"`
+	Actions []DayAction `json:"actions"`
+}
+
+// DayAction runs a script of a script table from its start, for tariffs
+// the tariffication script table's script of the zone
+type DayAction struct {
+	Start    time.Duration `json:"start"`  // since midnight
+	Script   string        `json:"script"` // OBIS code of the script table
+	Selector uint16        `json:"selector"`
+}
+
+// CalendarDate is a date whose fields are 0 when not specified, such as a
+// year of 0 for a date repeating every year
+type CalendarDate struct {
+	Year  int `json:"year"`
+	Month int `json:"month"`
+	Day   int `json:"day"`
+}
+
+// SpecialDay runs a day profile on a date instead of the week profile's
+type SpecialDay struct {
+	Index uint16       `json:"index"`
+	Date  CalendarDate `json:"date"`
+	DayID uint8        `json:"day_id"`
+}
+
+// TariffProgram is what ProgramTariff writes
+type TariffProgram struct {
+	Calendar       Calendar     // written as the passive calendar
+	ActivationTime time.Time    // when it becomes active; zero activates it at once
+	SpecialDays    []SpecialDay // replace the table's entries, unless nil
+}
+
+// Validate checks that the program is complete and its references resolve
+func (p TariffProgram) Validate() error {
+	c := p.Calendar
+	if c.Name == "" {
+		return fmt.Errorf("calendar name is required")
+	}
+	if len(c.Seasons) == 0 || len(c.WeekProfiles) == 0 || len(c.DayProfiles) == 0 {
+		return fmt.Errorf("calendar needs seasons, week profiles and day profiles")
+	}
+
+	days := make(map[uint8]bool)
+	for _, d := range c.DayProfiles {
+		if days[d.ID] {
+			return fmt.Errorf("day profile %d is defined twice", d.ID)
+		}
+		days[d.ID] = true
+		if len(d.Actions) == 0 {
+			return fmt.Errorf("day profile %d has no actions", d.ID)
+		}
+		for i, a := range d.Actions {
+			if a.Start < 0 || a.Start >= 24*time.Hour || a.Start%time.Second != 0 {
+				return fmt.Errorf("day profile %d: invalid start %s", d.ID, a.Start)
+			}
+			if i > 0 && a.Start <= d.Actions[i-1].Start {
+				return fmt.Errorf("day profile %d: actions must start in order", d.ID)
+			}
+			if err := validOBIS(a.Script); err != nil {
+				return fmt.Errorf("day profile %d: %w", d.ID, err)
+			}
+		}
+	}
+
+	weeks := make(map[string]bool)
+	for _, w := range c.WeekProfiles {
+		if w.Name == "" || weeks[w.Name] {
+			return fmt.Errorf("week profile %q is unnamed or defined twice", w.Name)
+		}
+		weeks[w.Name] = true
+		for _, id := range w.Days {
+			if !days[id] {
+				return fmt.Errorf("week profile %q uses undefined day profile %d", w.Name, id)
+			}
+		}
+	}
+
+	seasons := make(map[string]bool)
+	for _, s := range c.Seasons {
+		if s.Name == "" || seasons[s.Name] {
+			return fmt.Errorf("season %q is unnamed or defined twice", s.Name)
+		}
+		seasons[s.Name] = true
+		if !weeks[s.WeekProfile] {
+			return fmt.Errorf("season %q uses undefined week profile %q", s.Name, s.WeekProfile)
+		}
+		if err := s.Start.validate(); err != nil {
+			return fmt.Errorf("season %q: %w", s.Name, err)
+		}
+	}
+
+	indexes := make(map[uint16]bool)
+	for _, d := range p.SpecialDays {
+		if indexes[d.Index] {
+			return fmt.Errorf("special day %d is defined twice", d.Index)
+		}
+		indexes[d.Index] = true
+		if !days[d.DayID] {
+			return fmt.Errorf("special day %d uses undefined day profile %d", d.Index, d.DayID)
+		}
+		if err := d.Date.validate(); err != nil {
+			return fmt.Errorf("special day %d: %w", d.Index, err)
+		}
+	}
+	return nil
+}
+
+func (d CalendarDate) validate() error {
+	if d.Year < 0 || d.Year >= 0xFFFF || d.Month < 0 || d.Month > 12 || d.Day < 0 || d.Day > 31 {
+		return fmt.Errorf("invalid date %d-%d-%d", d.Year, d.Month, d.Day)
+	}
+	return nil
+}
+
+// Equal reports whether two calendars have the same contents
+func (c Calendar) Equal(other Calendar) bool {
+	return c.Name == other.Name &&
+		slices.Equal(c.Seasons, other.Seasons) &&
+		slices.Equal(c.WeekProfiles, other.WeekProfiles) &&
+		slices.EqualFunc(c.DayProfiles, other.DayProfiles, func(a, b DayProfile) bool {
+			return a.ID == b.ID && slices.Equal(a.Actions, b.Actions)
+		})
+}
+
+// cosemObjects is what reading and programming a tariff needs of a session
+type cosemObjects interface {
+	ReadEncoded(ctx context.Context, obisCode string, objectType, attributeIndex int) (Encoded, error)
+	WriteAttribute(ctx context.Context, obisCode string, objectType, attributeIndex int, value any) error
+	Invoke(ctx context.Context, obisCode string, objectType, methodIndex int, data Encoded) error
+}
+
+// readTariff reads both calendars, the activation time and the special days
+func readTariff(ctx context.Context, objects cosemObjects) (*Tariff, error) {
+	var t Tariff
+	var err error
+	if t.Active, err = readCalendar(ctx, objects, activeCalendar); err != nil {
+		return nil, fmt.Errorf("failed to read active calendar: %w", err)
+	}
+	if t.Passive, err = readCalendar(ctx, objects, passiveCalendar); err != nil {
+		return nil, fmt.Errorf("failed to read passive calendar: %w", err)
+	}
+
+	value, err := readCOSEM(ctx, objects, ActivityCalendarOBIS, ObjectTypeActivityCalendar, activatePassiveCalendarTime)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read activation time: %w", err)
+	}
+	raw, err := value.octets()
+	if err != nil {
+		return nil, fmt.Errorf("invalid activation time: %w", err)
+	}
+	t.ActivationTime, _ = decodeDateTime(raw)
+
+	if t.SpecialDays, err = readSpecialDays(ctx, objects); err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// readCalendar reads the four attributes of a calendar from first on
+func readCalendar(ctx context.Context, objects cosemObjects, first int) (Calendar, error) {
+	var values [4]axdr
+	for i := range values {
+		var err error
+		if values[i], err = readCOSEM(ctx, objects, ActivityCalendarOBIS, ObjectTypeActivityCalendar, first+i); err != nil {
+			return Calendar{}, err
+		}
+	}
+
+	var c Calendar
+	name, err := values[0].octets()
+	if err != nil {
+		return Calendar{}, fmt.Errorf("invalid calendar name: %w", err)
+	}
+	c.Name = decodeName(name)
+	if c.Seasons, err = decodeSeasons(values[1]); err != nil {
+		return Calendar{}, fmt.Errorf("invalid season profile: %w", err)
+	}
+	if c.WeekProfiles, err = decodeWeekProfiles(values[2]); err != nil {
+		return Calendar{}, fmt.Errorf("invalid week profile table: %w", err)
+	}
+	if c.DayProfiles, err = decodeDayProfiles(values[3]); err != nil {
+		return Calendar{}, fmt.Errorf("invalid day profile table: %w", err)
+	}
+	return c, nil
+}
+
+func readSpecialDays(ctx context.Context, objects cosemObjects) ([]SpecialDay, error) {
+	value, err := readCOSEM(ctx, objects, SpecialDaysTableOBIS, ObjectTypeSpecialDaysTable, specialDaysEntries)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read special days: %w", err)
+	}
+	days, err := decodeSpecialDays(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid special days: %w", err)
+	}
+	return days, nil
+}
+
+func readCOSEM(ctx context.Context, objects cosemObjects, obis string, objectType, attributeIndex int) (axdr, error) {
+	data, err := objects.ReadEncoded(ctx, obis, objectType, attributeIndex)
+	if err != nil {
+		return axdr{}, err
+	}
+	value, err := decodeAXDR(data)
+	if err != nil {
+		return axdr{}, fmt.Errorf("invalid %s attribute %d: %w", obis, attributeIndex, err)
+	}
+	return value, nil
+}
+
+// programTariff writes the passive calendar, then activates it or sets its
+// activation time, replaces the special days and reads everything back.
+// On ErrTariffMismatch the tariff read back is returned with the error.
+func programTariff(ctx context.Context, objects cosemObjects, p TariffProgram) (*Tariff, error) {
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+
+	c := p.Calendar
+	writes := []axdr{
+		axdrOctets(encodeName(c.Name)),
+		encodeSeasons(c.Seasons),
+		encodeWeekProfiles(c.WeekProfiles),
+		encodeDayProfiles(c.DayProfiles),
+	}
+	for i, value := range writes {
+		if err := objects.WriteAttribute(ctx, ActivityCalendarOBIS, ObjectTypeActivityCalendar, passiveCalendar+i, value.encode()); err != nil {
+			return nil, fmt.Errorf("failed to write passive calendar attribute %d: %w", passiveCalendar+i, err)
+		}
+	}
+
+	if p.ActivationTime.IsZero() {
+		if err := objects.Invoke(ctx, ActivityCalendarOBIS, ObjectTypeActivityCalendar, activatePassiveCalendar, axdrInteger(0).encode()); err != nil {
+			return nil, fmt.Errorf("failed to activate passive calendar: %w", err)
+		}
+	} else {
+		value := axdrOctets(encodeDateTime(p.ActivationTime))
+		if err := objects.WriteAttribute(ctx, ActivityCalendarOBIS, ObjectTypeActivityCalendar, activatePassiveCalendarTime, value.encode()); err != nil {
+			return nil, fmt.Errorf("failed to write activation time: %w", err)
+		}
+	}
+
+	if p.SpecialDays != nil {
+		if err := replaceSpecialDays(ctx, objects, p.SpecialDays); err != nil {
+			return nil, err
+		}
+	}
+
+	t, err := readTariff(ctx, objects)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read back tariff: %w", err)
+	}
+	if err := verifyTariff(t, p); err != nil {
+		return t, err
+	}
+	return t, nil
+}
+
+// replaceSpecialDays deletes the entries the program does not have and
+// inserts its own, which replace those with the same index
+func replaceSpecialDays(ctx context.Context, objects cosemObjects, days []SpecialDay) error {
+	current, err := readSpecialDays(ctx, objects)
+	if err != nil {
+		return err
+	}
+	for _, d := range current {
+		if slices.ContainsFunc(days, func(n SpecialDay) bool { return n.Index == d.Index }) {
+			continue
+		}
+		if err := objects.Invoke(ctx, SpecialDaysTableOBIS, ObjectTypeSpecialDaysTable, specialDaysDelete, axdrLongUnsigned(d.Index).encode()); err != nil {
+			return fmt.Errorf("failed to delete special day %d: %w", d.Index, err)
+		}
+	}
+	for _, d := range days {
+		if err := objects.Invoke(ctx, SpecialDaysTableOBIS, ObjectTypeSpecialDaysTable, specialDaysInsert, encodeSpecialDay(d).encode()); err != nil {
+			return fmt.Errorf("failed to insert special day %d: %w", d.Index, err)
+		}
+	}
+	return nil
+}
+
+// verifyTariff checks that a tariff read back holds the program
+func verifyTariff(t *Tariff, p TariffProgram) error {
+	if p.ActivationTime.IsZero() {
+		if !t.Active.Equal(p.Calendar) {
+			return fmt.Errorf("%w: active calendar is %q", ErrTariffMismatch, t.Active.Name)
+		}
+	} else {
+		if !t.Passive.Equal(p.Calendar) {
+			return fmt.Errorf("%w: passive calendar is %q", ErrTariffMismatch, t.Passive.Name)
+		}
+		if !t.ActivationTime.Equal(p.ActivationTime.Truncate(time.Second)) {
+			return fmt.Errorf("%w: activation time is %s", ErrTariffMismatch, t.ActivationTime.Format(time.RFC3339))
+		}
+	}
+	if p.SpecialDays != nil {
+		sortSpecialDays := func(days []SpecialDay) []SpecialDay {
+			return slices.SortedFunc(slices.Values(days), func(a, b SpecialDay) int { return int(a.Index) - int(b.Index) })
+		}
+		if !slices.Equal(sortSpecialDays(t.SpecialDays), sortSpecialDays(p.SpecialDays)) {
+			return fmt.Errorf("%w: special days differ", ErrTariffMismatch)
+		}
+	}
+	return nil
+}
+
+// encodeName encodes a name as its bytes, or as the bytes of its "Hex:"
+// form for names that are not printable
+func encodeName(name string) []byte {
+	if hexValue, ok := strings.CutPrefix(name, "Hex:"); ok {
+		if raw, err := hex.DecodeString(hexValue); err == nil {
+			return raw
+		}
+	}
+	return []byte(name)
+}
+
+func decodeName(raw []byte) string {
+	if len(raw) == 0 {
+		return ""
+	}
+	return decodeOctetString("Hex:" + strings.ToUpper(hex.EncodeToString(raw)))
+}
+
+func encodeSeasons(seasons []Season) axdr {
+	items := make([]axdr, len(seasons))
+	for i, s := range seasons {
+		start := append(encodeDate(s.Start), 0, 0, 0, 0, 0x80, 0x00, 0xFF)
+		items[i] = axdrStructure(axdrOctets(encodeName(s.Name)), axdrOctets(start), axdrOctets(encodeName(s.WeekProfile)))
+	}
+	return axdrArray(items...)
+}
+
+func decodeSeasons(v axdr) ([]Season, error) {
+	items, err := v.list(tagArray, -1)
+	if err != nil {
+		return nil, err
+	}
+	var seasons []Season
+	for _, item := range items {
+		fields, err := item.list(tagStructure, 3)
+		if err != nil {
+			return nil, err
+		}
+		name, err := fields[0].octets()
+		if err != nil {
+			return nil, err
+		}
+		start, err := fields[1].octets()
+		if err != nil {
+			return nil, err
+		}
+		week, err := fields[2].octets()
+		if err != nil {
+			return nil, err
+		}
+		date, err := decodeDate(start)
+		if err != nil {
+			return nil, err
+		}
+		seasons = append(seasons, Season{Name: decodeName(name), Start: date, WeekProfile: decodeName(week)})
+	}
+	return seasons, nil
+}
+
+func encodeWeekProfiles(weeks []WeekProfile) axdr {
+	items := make([]axdr, len(weeks))
+	for i, w := range weeks {
+		fields := []axdr{axdrOctets(encodeName(w.Name))}
+		for _, id := range w.Days {
+			fields = append(fields, axdrUnsigned(id))
+		}
+		items[i] = axdrStructure(fields...)
+	}
+	return axdrArray(items...)
+}
+
+func decodeWeekProfiles(v axdr) ([]WeekProfile, error) {
+	items, err := v.list(tagArray, -1)
+	if err != nil {
+		return nil, err
+	}
+	var weeks []WeekProfile
+	for _, item := range items {
+		fields, err := item.list(tagStructure, 8)
+		if err != nil {
+			return nil, err
+		}
+		name, err := fields[0].octets()
+		if err != nil {
+			return nil, err
+		}
+		w := WeekProfile{Name: decodeName(name)}
+		for i := range w.Days {
+			id, err := fields[1+i].unsigned(0xFF)
+			if err != nil {
+				return nil, err
+			}
+			w.Days[i] = uint8(id)
+		}
+		weeks = append(weeks, w)
+	}
+	return weeks, nil
+}
+
+func encodeDayProfiles(days []DayProfile) axdr {
+	items := make([]axdr, len(days))
+	for i, d := range days {
+		actions := make([]axdr, len(d.Actions))
+		for j, a := range d.Actions {
+			actions[j] = axdrStructure(axdrOctets(encodeTime(a.Start)), axdrOctets(obisBytes(a.Script)), axdrLongUnsigned(a.Selector))
+		}
+		items[i] = axdrStructure(axdrUnsigned(d.ID), axdrArray(actions...))
+	}
+	return axdrArray(items...)
+}
+
+func decodeDayProfiles(v axdr) ([]DayProfile, error) {
+	items, err := v.list(tagArray, -1)
+	if err != nil {
+		return nil, err
+	}
+	var days []DayProfile
+	for _, item := range items {
+		fields, err := item.list(tagStructure, 2)
+		if err != nil {
+			return nil, err
+		}
+		id, err := fields[0].unsigned(0xFF)
+		if err != nil {
+			return nil, err
+		}
+		actions, err := fields[1].list(tagArray, -1)
+		if err != nil {
+			return nil, err
+		}
+		d := DayProfile{ID: uint8(id)}
+		for _, action := range actions {
+			fields, err := action.list(tagStructure, 3)
+			if err != nil {
+				return nil, err
+			}
+			start, err := fields[0].octets()
+			if err != nil {
+				return nil, err
+			}
+			script, err := fields[1].octets()
+			if err != nil {
+				return nil, err
+			}
+			selector, err := fields[2].unsigned(0xFFFF)
+			if err != nil {
+				return nil, err
+			}
+			a := DayAction{Selector: uint16(selector)}
+			if a.Start, err = decodeTime(start); err != nil {
+				return nil, err
+			}
+			if a.Script, err = obisString(script); err != nil {
+				return nil, err
+			}
+			d.Actions = append(d.Actions, a)
+		}
+		days = append(days, d)
+	}
+	return days, nil
+}
+
+func encodeSpecialDay(d SpecialDay) axdr {
+	return axdrStructure(axdrLongUnsigned(d.Index), axdrOctets(encodeDate(d.Date)), axdrUnsigned(d.DayID))
+}
+
+func decodeSpecialDays(v axdr) ([]SpecialDay, error) {
+	items, err := v.list(tagArray, -1)
+	if err != nil {
+		return nil, err
+	}
+	var days []SpecialDay
+	for _, item := range items {
+		fields, err := item.list(tagStructure, 3)
+		if err != nil {
+			return nil, err
+		}
+		index, err := fields[0].unsigned(0xFFFF)
+		if err != nil {
+			return nil, err
+		}
+		raw, err := fields[1].octets()
+		if err != nil {
+			return nil, err
+		}
+		date, err := decodeDate(raw)
+		if err != nil {
+			return nil, err
+		}
+		id, err := fields[2].unsigned(0xFF)
+		if err != nil {
+			return nil, err
+		}
+		days = append(days, SpecialDay{Index: uint16(index), Date: date, DayID: uint8(id)})
+	}
+	return days, nil
+}
+
+// encodeDate encodes a COSEM date, with 0xFF(FF) for the fields not
+// specified and the day of the week
+func encodeDate(d CalendarDate) []byte {
+	b := binary.BigEndian.AppendUint16(nil, 0xFFFF)
+	if d.Year != 0 {
+		b = binary.BigEndian.AppendUint16(nil, uint16(d.Year))
+	}
+	for _, field := range []int{d.Month, d.Day} {
+		if field == 0 {
+			field = 0xFF
+		}
+		b = append(b, byte(field))
+	}
+	return append(b, 0xFF)
+}
+
+// decodeDate decodes the date of a COSEM date or date-time
+func decodeDate(raw []byte) (CalendarDate, error) {
+	if len(raw) != dateSize && len(raw) != dateTimeSize {
+		return CalendarDate{}, fmt.Errorf("invalid date of %d bytes", len(raw))
+	}
+	var d CalendarDate
+	if year := binary.BigEndian.Uint16(raw[0:2]); year != 0xFFFF {
+		d.Year = int(year)
+	}
+	if raw[2] >= 1 && raw[2] <= 12 {
+		d.Month = int(raw[2])
+	}
+	if raw[3] >= 1 && raw[3] <= 31 {
+		d.Day = int(raw[3])
+	}
+	return d, nil
+}
+
+// encodeTime encodes a time of day as a COSEM time
+func encodeTime(d time.Duration) []byte {
+	return []byte{byte(d / time.Hour), byte(d % time.Hour / time.Minute), byte(d % time.Minute / time.Second), 0}
+}
+
+// decodeTime decodes a COSEM time, taking the fields not specified as 0
+func decodeTime(raw []byte) (time.Duration, error) {
+	if len(raw) != timeSize {
+		return 0, fmt.Errorf("invalid time of %d bytes", len(raw))
+	}
+	var d time.Duration
+	for i, unit := range []time.Duration{time.Hour, time.Minute, time.Second} {
+		if raw[i] != 0xFF {
+			d += time.Duration(raw[i]) * unit
+		}
+	}
+	if d >= 24*time.Hour {
+		return 0, fmt.Errorf("invalid time %s", d)
+	}
+	return d, nil
+}
+
+// encodeDateTime encodes t as a COSEM date-time in UTC, or with every
+// field not specified if t is zero
+func encodeDateTime(t time.Time) []byte {
+	if t.IsZero() {
+		return []byte{0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0x80, 0x00, 0xFF}
+	}
+	t = t.UTC()
+	b := binary.BigEndian.AppendUint16(nil, uint16(t.Year()))
+	weekday := int(t.Weekday())
+	if weekday == 0 {
+		weekday = 7
+	}
+	return append(b, byte(t.Month()), byte(t.Day()), byte(weekday), byte(t.Hour()), byte(t.Minute()), byte(t.Second()), 0, 0, 0, 0)
+}
+
+// obisBytes encodes an OBIS code as its six byte logical name
+func obisBytes(obis string) []byte {
+	b := make([]byte, 0, 6)
+	for _, group := range strings.Split(obis, ".") {
+		n, _ := strconv.ParseUint(group, 10, 8)
+		b = append(b, byte(n))
+	}
+	return b
+}
+
+func obisString(raw []byte) (string, error) {
+	if len(raw) != 6 {
+		return "", fmt.Errorf("invalid logical name of %d bytes", len(raw))
+	}
+	return fmt.Sprintf("%d.%d.%d.%d.%d.%d", raw[0], raw[1], raw[2], raw[3], raw[4], raw[5]), nil
+}
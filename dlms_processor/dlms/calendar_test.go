@@ -0,0 +1,214 @@
+package dlms
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+)
+
+// calendarObjects holds the activity calendar and special days table's
+// attributes encoded, and runs their methods as a meter would
+type calendarObjects struct {
+	attributes map[string]Encoded
+	invoked    []string
+	corrupt    bool // stores the calendar name it is sent reversed
+}
+
+func newCalendarObjects() *calendarObjects {
+	o := &calendarObjects{attributes: make(map[string]Encoded)}
+	for i := range 4 {
+		o.attributes[calendarKey(activeCalendar+i)] = encodeCalendar(Calendar{})[i].encode()
+		o.attributes[calendarKey(passiveCalendar+i)] = encodeCalendar(Calendar{})[i].encode()
+	}
+	o.attributes[calendarKey(activatePassiveCalendarTime)] = axdrOctets(encodeDateTime(time.Time{})).encode()
+	o.attributes[specialDaysKey()] = axdrArray(
+		encodeSpecialDay(SpecialDay{Index: 1, Date: CalendarDate{Month: 1, Day: 26}, DayID: 1}),
+		encodeSpecialDay(SpecialDay{Index: 2, Date: CalendarDate{Month: 8, Day: 15}, DayID: 1}),
+	).encode()
+	return o
+}
+
+func calendarKey(attribute int) string {
+	return fmt.Sprintf("%s/%d", ActivityCalendarOBIS, attribute)
+}
+
+func specialDaysKey() string {
+	return fmt.Sprintf("%s/%d", SpecialDaysTableOBIS, specialDaysEntries)
+}
+
+func encodeCalendar(c Calendar) []axdr {
+	return []axdr{axdrOctets(encodeName(c.Name)), encodeSeasons(c.Seasons), encodeWeekProfiles(c.WeekProfiles), encodeDayProfiles(c.DayProfiles)}
+}
+
+func (o *calendarObjects) ReadEncoded(ctx context.Context, obis string, objectType, attributeIndex int) (Encoded, error) {
+	value, ok := o.attributes[fmt.Sprintf("%s/%d", obis, attributeIndex)]
+	if !ok {
+		return nil, ErrObjectUnavailable
+	}
+	return value, nil
+}
+
+func (o *calendarObjects) WriteAttribute(ctx context.Context, obis string, objectType, attributeIndex int, value any) error {
+	data := value.(Encoded)
+	if o.corrupt && attributeIndex == passiveCalendar {
+		name, _ := decodeAXDR(data)
+		slices.Reverse(name.bytes)
+		data = name.encode()
+	}
+	o.attributes[fmt.Sprintf("%s/%d", obis, attributeIndex)] = data
+	return nil
+}
+
+func (o *calendarObjects) Invoke(ctx context.Context, obis string, objectType, methodIndex int, data Encoded) error {
+	o.invoked = append(o.invoked, fmt.Sprintf("%s/%d", obis, methodIndex))
+	switch {
+	case obis == ActivityCalendarOBIS && methodIndex == activatePassiveCalendar:
+		for i := range 4 {
+			o.attributes[calendarKey(activeCalendar+i)] = o.attributes[calendarKey(passiveCalendar+i)]
+		}
+	case obis == SpecialDaysTableOBIS:
+		value, _ := decodeAXDR(o.attributes[specialDaysKey()])
+		days, _ := decodeSpecialDays(value)
+		arg, _ := decodeAXDR(data)
+		if methodIndex == specialDaysInsert {
+			day, _ := decodeSpecialDays(axdrArray(arg))
+			days = append(slices.DeleteFunc(days, func(d SpecialDay) bool { return d.Index == day[0].Index }), day[0])
+		} else {
+			days = slices.DeleteFunc(days, func(d SpecialDay) bool { return int64(d.Index) == arg.number })
+		}
+		items := make([]axdr, len(days))
+		for i, d := range days {
+			items[i] = encodeSpecialDay(d)
+		}
+		o.attributes[specialDaysKey()] = axdrArray(items...).encode()
+	}
+	return nil
+}
+
+func testProgram() TariffProgram {
+	return TariffProgram{
+		Calendar: Calendar{
+			Name: "TOU2026",
+			Seasons: []Season{
+				{Name: "SUMMER", Start: CalendarDate{Month: 4, Day: 1}, WeekProfile: "WORK"},
+				{Name: "WINTER", Start: CalendarDate{Year: 2026, Month: 10, Day: 1}, WeekProfile: "WORK"},
+			},
+			WeekProfiles: []WeekProfile{{Name: "WORK", Days: [7]uint8{1, 1, 1, 1, 1, 2, 2}}},
+			DayProfiles: []DayProfile{
+				{ID: 1, Actions: []DayAction{
+					{Start: 0, Script: TariffScriptTableOBIS, Selector: 1},
+					{Start: 17*time.Hour + 30*time.Minute, Script: TariffScriptTableOBIS, Selector: 2},
+				}},
+				{ID: 2, Actions: []DayAction{{Start: 0, Script: TariffScriptTableOBIS, Selector: 1}}},
+			},
+		},
+		SpecialDays: []SpecialDay{
+			{Index: 2, Date: CalendarDate{Month: 8, Day: 15}, DayID: 2},
+			{Index: 3, Date: CalendarDate{Year: 2026, Month: 11, Day: 8}, DayID: 2},
+		},
+	}
+}
+
+func TestProgramTariffActivatesAtOnce(t *testing.T) {
+	objects := newCalendarObjects()
+	program := testProgram()
+
+	tariff, err := programTariff(context.Background(), objects, program)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !tariff.Active.Equal(program.Calendar) || !tariff.Passive.Equal(program.Calendar) {
+		t.Errorf("Expected the program in both calendars, got %+v", tariff)
+	}
+	if !tariff.ActivationTime.IsZero() {
+		t.Errorf("Expected no activation time, got %s", tariff.ActivationTime)
+	}
+	// Special day 1 is deleted, 2 replaced and 3 added
+	if !slices.Equal(tariff.SpecialDays, program.SpecialDays) {
+		t.Errorf("Expected special days %+v, got %+v", program.SpecialDays, tariff.SpecialDays)
+	}
+	expected := []string{"0.0.13.0.0.255/1", "0.0.11.0.0.255/2", "0.0.11.0.0.255/1", "0.0.11.0.0.255/1"}
+	if !slices.Equal(objects.invoked, expected) {
+		t.Errorf("Expected methods %v, got %v", expected, objects.invoked)
+	}
+}
+
+func TestProgramTariffSchedulesActivation(t *testing.T) {
+	objects := newCalendarObjects()
+	program := testProgram()
+	program.ActivationTime = time.Date(2026, 11, 1, 5, 30, 0, 0, time.FixedZone("IST", 5*3600+1800))
+	program.SpecialDays = nil
+
+	tariff, err := programTariff(context.Background(), objects, program)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tariff.Active.Name != "" || !tariff.Passive.Equal(program.Calendar) {
+		t.Errorf("Expected only the passive calendar written, got %+v", tariff)
+	}
+	if !tariff.ActivationTime.Equal(time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected activation at midnight UTC, got %s", tariff.ActivationTime)
+	}
+	if len(tariff.SpecialDays) != 2 || len(objects.invoked) != 0 {
+		t.Errorf("Expected the special days left as they are, got %+v after %v", tariff.SpecialDays, objects.invoked)
+	}
+}
+
+func TestProgramTariffVerifiesReadBack(t *testing.T) {
+	objects := newCalendarObjects()
+	objects.corrupt = true
+
+	tariff, err := programTariff(context.Background(), objects, testProgram())
+	if !errors.Is(err, ErrTariffMismatch) {
+		t.Fatalf("Expected a mismatch, got %v", err)
+	}
+	if tariff == nil || tariff.Active.Name != "6202UOT" {
+		t.Errorf("Expected the tariff read back, got %+v", tariff)
+	}
+}
+
+func TestTariffProgramValidate(t *testing.T) {
+	for name, change := range map[string]func(*TariffProgram){
+		"unnamed calendar":       func(p *TariffProgram) { p.Calendar.Name = "" },
+		"no seasons":             func(p *TariffProgram) { p.Calendar.Seasons = nil },
+		"undefined week profile": func(p *TariffProgram) { p.Calendar.Seasons[0].WeekProfile = "HOLIDAY" },
+		"undefined day profile":  func(p *TariffProgram) { p.Calendar.WeekProfiles[0].Days[6] = 9 },
+		"duplicate day profile":  func(p *TariffProgram) { p.Calendar.DayProfiles[1].ID = 1 },
+		"actions out of order":   func(p *TariffProgram) { p.Calendar.DayProfiles[0].Actions[1].Start = 0 },
+		"start past midnight":    func(p *TariffProgram) { p.Calendar.DayProfiles[0].Actions[1].Start = 24 * time.Hour },
+		"invalid script":         func(p *TariffProgram) { p.Calendar.DayProfiles[0].Actions[0].Script = "0.0.10.0.100" },
+		"invalid season start":   func(p *TariffProgram) { p.Calendar.Seasons[0].Start.Month = 13 },
+		"duplicate special day":  func(p *TariffProgram) { p.SpecialDays[1].Index = 2 },
+		"special day profile":    func(p *TariffProgram) { p.SpecialDays[0].DayID = 3 },
+	} {
+		program := testProgram()
+		change(&program)
+		if err := program.Validate(); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+	if err := testProgram().Validate(); err != nil {
+		t.Errorf("Expected the test program to be valid, got %v", err)
+	}
+}
+
+func TestDecodeDayProfilesAcceptsTypedTime(t *testing.T) {
+	// Meters may send times as the time type rather than octet strings
+	value := axdrArray(axdrStructure(axdrUnsigned(1), axdrArray(axdrStructure(
+		axdr{tag: tagTime, bytes: []byte{6, 30, 0xFF, 0xFF}}, axdrOctets(obisBytes(TariffScriptTableOBIS)), axdrLongUnsigned(3)))))
+	decoded, err := decodeAXDR(value.encode())
+	if err != nil {
+		t.Fatal(err)
+	}
+	days, err := decodeDayProfiles(decoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := DayAction{Start: 6*time.Hour + 30*time.Minute, Script: TariffScriptTableOBIS, Selector: 3}
+	if len(days) != 1 || len(days[0].Actions) != 1 || days[0].Actions[0] != expected {
+		t.Errorf("Expected %+v, got %+v", expected, days)
+	}
+}
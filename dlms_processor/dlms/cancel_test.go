@@ -0,0 +1,133 @@
+package dlms
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+)
+
+// silentListener accepts connections and never answers, like a meter that
+// has stopped responding mid-association.
+func silentListener(t *testing.T) (string, int) {
+	t.Helper()
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	t.Cleanup(func() { lis.Close() })
+
+	go func() {
+		for {
+			conn, err := lis.Accept()
+			if err != nil {
+				return
+			}
+			t.Cleanup(func() { conn.Close() })
+		}
+	}()
+
+	addr := lis.Addr().(*net.TCPAddr)
+	return addr.IP.String(), addr.Port
+}
+
+// testMeterConfig is a meter with the default test credentials at ip:port
+func testMeterConfig(ip string, port int) RealMeter {
+	return RealMeter{
+		MeterIP:           ip,
+		MeterPort:         port,
+		ConnectionTimeout: 30000,
+		AuthPassword:      "wwwwwwwwwwwwwwww",
+		SystemTitle:       "4142434445464748",
+		BlockCipherKey:    "62626262626262626262626262626262",
+		AuthenticationKey: "62626262626262626262626262626262",
+		ClientAddress:     48,
+		ServerAddress:     1,
+	}
+}
+
+func TestGetOBISHonoursContext(t *testing.T) {
+	ip, port := silentListener(t)
+
+	testCases := []struct {
+		name    string
+		ctx     func() (context.Context, context.CancelFunc)
+		wantErr error
+	}{
+		{
+			name: "cancelled",
+			ctx: func() (context.Context, context.CancelFunc) {
+				ctx, cancel := context.WithCancel(context.Background())
+				time.AfterFunc(200*time.Millisecond, cancel)
+				return ctx, cancel
+			},
+			wantErr: context.Canceled,
+		},
+		{
+			name: "deadline",
+			ctx: func() (context.Context, context.CancelFunc) {
+				return context.WithTimeout(context.Background(), 200*time.Millisecond)
+			},
+			wantErr: context.DeadlineExceeded,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			meter, err := NewRealMeter(testMeterConfig(ip, port))
+			if err != nil {
+				t.Fatalf("NewRealMeter: %v", err)
+			}
+
+			ctx, cancel := tc.ctx()
+			defer cancel()
+
+			if err := meter.Connect(ctx); err != nil {
+				t.Fatalf("Connect: %v", err)
+			}
+			defer meter.Close()
+
+			start := time.Now()
+			_, err = meter.GetOBIS(ctx, "1.0.1.8.0.255")
+			if !errors.Is(err, tc.wantErr) {
+				t.Errorf("Expected %v, got %v", tc.wantErr, err)
+			}
+			if elapsed := time.Since(start); elapsed > 5*time.Second {
+				t.Errorf("GetOBIS took %v after the context was done", elapsed)
+			}
+		})
+	}
+}
+
+func TestCommandKeepsAcceptedResult(t *testing.T) {
+	client := NewMeterClient()
+	defer client.Close()
+	config := testMeterConfig("127.0.0.1", 4059)
+	if err := client.Configure(&config); err != nil {
+		t.Fatal(err)
+	}
+	client.mu.Lock()
+	defer client.mu.Unlock()
+
+	// The meter accepts the write, then the context ends before the call
+	// returns: the write happened and must not be reported as failed
+	ctx, cancel := context.WithCancel(context.Background())
+	ret, err := client.command(ctx, func() int {
+		cancel()
+		return 0
+	})
+	if ret != 0 || err != nil {
+		t.Errorf("Expected the accepted command to succeed, got %d, %v", ret, err)
+	}
+
+	// A context done beforehand stops the command from running at all
+	ran := false
+	_, err = client.command(ctx, func() int {
+		ran = true
+		return 0
+	})
+	if ran || !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected the command not to run, ran %v, got %v", ran, err)
+	}
+}
@@ -0,0 +1,227 @@
+package dlms
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ProfileDefinitions describe profile layouts in configuration rather than
+// in Go struct tags, so a vendor's columns or an extra tariff zone need no
+// code change
+type ProfileDefinitions struct {
+	Profiles []ProfileDefinition `yaml:"profiles" json:"profiles"`
+}
+
+// ProfileDefinition is the layout of a profile generic object on some meter
+// models
+type ProfileDefinition struct {
+	Name    string             `yaml:"name" json:"name"`
+	OBIS    string             `yaml:"obis" json:"obis"`
+	Models  []string           `yaml:"models" json:"models"` // meter models it applies to, every model if empty
+	Columns []ColumnDefinition `yaml:"columns" json:"columns"`
+}
+
+// ColumnDefinition is one captured attribute of a profile
+type ColumnDefinition struct {
+	Name      string    `yaml:"name" json:"name"`
+	OBIS      string    `yaml:"obis" json:"obis"`
+	Attribute Attribute `yaml:"attribute" json:"attribute"` // captured attribute, by index or name; the value if empty
+	Type      string    `yaml:"type" json:"type"`           // string, datetime, float64, int, uint8, uint16 or uint32
+	Unit      string    `yaml:"unit" json:"unit"`
+	Scaler    *int      `yaml:"scaler" json:"scaler"` // numbers are multiplied by 10^scaler when set
+}
+
+// ProfileData is a profile read through a definition: each row holds one
+// value per column of the definition, a string or a float64, or nil where
+// the meter does not capture the column
+type ProfileData struct {
+	Definition ProfileDefinition
+	Attributes []int // attribute index each column was read from, 0 where the meter does not capture it
+	Rows       [][]any
+}
+
+// LoadProfileDefinitions reads a YAML or JSON profile definitions file
+func LoadProfileDefinitions(path string) (*ProfileDefinitions, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read profile definitions: %w", err)
+	}
+
+	// JSON is YAML, so one decoder reads both
+	var d ProfileDefinitions
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&d); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("failed to parse profile definitions %s: %w", path, err)
+	}
+	if err := d.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid profile definitions %s: %w", path, err)
+	}
+	return &d, nil
+}
+
+// Validate checks every definition, and that no two definitions of a name
+// apply to the same model
+func (d *ProfileDefinitions) Validate() error {
+	seen := make(map[[2]string]bool)
+	for i, p := range d.Profiles {
+		if p.Name == "" {
+			return fmt.Errorf("profile %d has no name", i)
+		}
+		if err := validOBIS(p.OBIS); err != nil {
+			return fmt.Errorf("profile %s: %w", p.Name, err)
+		}
+		if len(p.Columns) == 0 {
+			return fmt.Errorf("profile %s has no columns", p.Name)
+		}
+		models := p.Models
+		if len(models) == 0 {
+			models = []string{""}
+		}
+		for _, model := range models {
+			if seen[[2]string{p.Name, model}] {
+				if model == "" {
+					return fmt.Errorf("profile %s is defined twice for every model", p.Name)
+				}
+				return fmt.Errorf("profile %s is defined twice for model %s", p.Name, model)
+			}
+			seen[[2]string{p.Name, model}] = true
+		}
+
+		for j, c := range p.Columns {
+			if c.Name == "" {
+				return fmt.Errorf("profile %s: column %d has no name", p.Name, j)
+			}
+			if err := validOBIS(c.OBIS); err != nil {
+				return fmt.Errorf("profile %s: column %s: %w", p.Name, c.Name, err)
+			}
+			if err := c.Attribute.validate(); err != nil {
+				return fmt.Errorf("profile %s: column %s: %w", p.Name, c.Name, err)
+			}
+			switch c.Type {
+			case "string", "datetime", "float64", "int", "uint8", "uint16", "uint32":
+			default:
+				return fmt.Errorf("profile %s: column %s: unknown type %q", p.Name, c.Name, c.Type)
+			}
+			if c.Scaler != nil && (c.Type == "string" || c.Type == "datetime") {
+				return fmt.Errorf("profile %s: column %s: a %s has no scaler", p.Name, c.Name, c.Type)
+			}
+		}
+	}
+	return nil
+}
+
+// Select returns the definition of the named profile for a meter model: the
+// one listing the model, else the one for every model
+func (d *ProfileDefinitions) Select(name, model string) (*ProfileDefinition, bool) {
+	var fallback *ProfileDefinition
+	for i, p := range d.Profiles {
+		if p.Name != name {
+			continue
+		}
+		if model != "" && slices.Contains(p.Models, model) {
+			return &d.Profiles[i], true
+		}
+		if len(p.Models) == 0 {
+			fallback = &d.Profiles[i]
+		}
+	}
+	return fallback, fallback != nil
+}
+
+// validOBIS checks that obis is six dot separated groups of 0 to 255
+func validOBIS(obis string) error {
+	groups := strings.Split(obis, ".")
+	if len(groups) != 6 {
+		return fmt.Errorf("invalid OBIS code %q", obis)
+	}
+	for _, g := range groups {
+		if _, err := strconv.ParseUint(g, 10, 8); err != nil {
+			return fmt.Errorf("invalid OBIS code %q", obis)
+		}
+	}
+	return nil
+}
+
+// mapDefinedProfile maps the rows read from a profile to the columns of
+// its definition, matching capture objects by OBIS code and attribute.
+// Cells that do not parse as their column's type are left nil.
+func mapDefinedProfile(result *DLMSResult, def ProfileDefinition) *ProfileData {
+	data := &ProfileData{Definition: def, Attributes: make([]int, len(def.Columns)), Rows: make([][]any, 0, result.NumRows)}
+	columns := make([]int, len(def.Columns))
+	for i, column := range def.Columns {
+		colIdx, ok := result.column(column.OBIS, column.Attribute)
+		if !ok {
+			colIdx = -1
+		} else if colIdx < len(result.ColumnAttributes) && result.ColumnAttributes[colIdx] != 0 {
+			data.Attributes[i] = result.ColumnAttributes[colIdx]
+		} else {
+			data.Attributes[i], _ = column.Attribute.Index(0)
+		}
+		columns[i] = colIdx
+	}
+
+	for _, row := range result.Data {
+		values := make([]any, len(def.Columns))
+		for i, column := range def.Columns {
+			colIdx := columns[i]
+			if colIdx < 0 || colIdx >= len(row) {
+				continue
+			}
+			value, err := definedValue(row[colIdx], column)
+			if err != nil {
+				continue // Skip if parsing fails
+			}
+			values[i] = value
+		}
+		data.Rows = append(data.Rows, values)
+	}
+	return data
+}
+
+// definedValue parses a cell as its column's type: text for strings and
+// date-times, a scaled float64 for numbers
+func definedValue(cell string, column ColumnDefinition) (any, error) {
+	switch column.Type {
+	case "string":
+		return decodeOctetString(cell), nil
+	case "datetime":
+		return parseDateTime(cell), nil
+	}
+
+	parsed, err := parseValueByType(cell, column.Type)
+	if err != nil {
+		return nil, err
+	}
+	var value float64
+	switch v := parsed.(type) {
+	case float64:
+		value = v
+	case int:
+		value = float64(v)
+	case uint8:
+		value = float64(v)
+	case uint16:
+		value = float64(v)
+	case uint32:
+		value = float64(v)
+	}
+	switch {
+	case column.Scaler == nil:
+	case *column.Scaler < 0:
+		// Dividing keeps 2304 with a scaler of -1 at exactly 230.4
+		value /= math.Pow10(-*column.Scaler)
+	default:
+		value *= math.Pow10(*column.Scaler)
+	}
+	return value, nil
+}
@@ -0,0 +1,95 @@
+package dlms
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadProfileDefinitions(t *testing.T) {
+	defs, err := LoadProfileDefinitions("../profiles.example.yaml")
+	if err != nil {
+		t.Fatalf("Failed to load the example definitions: %v", err)
+	}
+
+	def, ok := defs.Select("block-load", "vendor-x-1p")
+	if !ok || len(def.Models) == 0 {
+		t.Errorf("Expected the vendor's block load definition, got %+v", def)
+	}
+	def, ok = defs.Select("block-load", "other")
+	if !ok || len(def.Models) != 0 {
+		t.Errorf("Expected the block load definition for every model, got %+v", def)
+	}
+	if _, ok := defs.Select("unknown", ""); ok {
+		t.Error("Expected no definition of an unknown profile")
+	}
+
+	// JSON works as well
+	path := filepath.Join(t.TempDir(), "profiles.json")
+	json := `{"profiles": [{"name": "p", "obis": "1.0.99.1.0.255", "columns": [{"name": "v", "obis": "1.0.12.27.0.255", "type": "float64"}]}]}`
+	if err := os.WriteFile(path, []byte(json), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadProfileDefinitions(path); err != nil {
+		t.Errorf("Failed to load JSON definitions: %v", err)
+	}
+}
+
+func TestProfileDefinitionsValidate(t *testing.T) {
+	column := ColumnDefinition{Name: "v", OBIS: "1.0.12.27.0.255", Type: "float64"}
+	scaler := -1
+	testCases := []struct {
+		name    string
+		profile ProfileDefinition
+		wantErr string
+	}{
+		{name: "no name", profile: ProfileDefinition{OBIS: "1.0.99.1.0.255", Columns: []ColumnDefinition{column}}, wantErr: "no name"},
+		{name: "bad OBIS", profile: ProfileDefinition{Name: "p", OBIS: "1.0.99.1.0", Columns: []ColumnDefinition{column}}, wantErr: "invalid OBIS"},
+		{name: "no columns", profile: ProfileDefinition{Name: "p", OBIS: "1.0.99.1.0.255"}, wantErr: "no columns"},
+		{name: "unknown type", profile: ProfileDefinition{Name: "p", OBIS: "1.0.99.1.0.255", Columns: []ColumnDefinition{{Name: "v", OBIS: "1.0.12.27.0.255", Type: "double"}}}, wantErr: "unknown type"},
+		{name: "scaled string", profile: ProfileDefinition{Name: "p", OBIS: "1.0.99.1.0.255", Columns: []ColumnDefinition{{Name: "v", OBIS: "0.0.96.1.0.255", Type: "string", Scaler: &scaler}}}, wantErr: "no scaler"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			defs := ProfileDefinitions{Profiles: []ProfileDefinition{tc.profile}}
+			if err := defs.Validate(); err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Errorf("Expected an error containing %q, got %v", tc.wantErr, err)
+			}
+		})
+	}
+
+	twice := ProfileDefinitions{Profiles: []ProfileDefinition{
+		{Name: "p", OBIS: "1.0.99.1.0.255", Models: []string{"a", "b"}, Columns: []ColumnDefinition{column}},
+		{Name: "p", OBIS: "1.0.99.1.0.255", Models: []string{"b"}, Columns: []ColumnDefinition{column}},
+	}}
+	if err := twice.Validate(); err == nil || !strings.Contains(err.Error(), "twice for model b") {
+		t.Errorf("Expected a definition twice for a model to be refused, got %v", err)
+	}
+}
+
+func TestMapDefinedProfile(t *testing.T) {
+	scaler := -1
+	def := ProfileDefinition{Name: "p", OBIS: "1.0.99.1.0.255", Columns: []ColumnDefinition{
+		{Name: "voltage", OBIS: "1.0.12.27.0.255", Type: "uint16", Scaler: &scaler},
+		{Name: "clock", OBIS: "0.0.1.0.0.255", Type: "string"},
+		{Name: "serial", OBIS: "0.0.96.1.0.255", Type: "string"},
+		{Name: "missing", OBIS: "1.0.1.29.0.255", Type: "float64"},
+		{Name: "unparsable", OBIS: "1.0.11.27.0.255", Type: "float64"},
+	}}
+	result := &DLMSResult{
+		NumRows:     1,
+		ColumnNames: []string{"0.0.1.0.0.255", "1.0.12.27.0.255", "0.0.96.1.0.255", "1.0.11.27.0.255"},
+		Data:        [][]string{{"10/19/2026 10:00:00 UTC+00:00", "2304", "Hex:53494D", "[error]"}},
+	}
+
+	data := mapDefinedProfile(result, def)
+	if len(data.Rows) != 1 {
+		t.Fatalf("Expected one row, got %d", len(data.Rows))
+	}
+	row := data.Rows[0]
+	if row[0] != 230.4 || row[1] != "10/19/2026 10:00:00 UTC+00:00" || row[2] != "SIM" || row[3] != nil || row[4] != nil {
+		t.Errorf("Unexpected row %v", row)
+	}
+}
@@ -10,12 +10,18 @@ package dlms
 */
 import "C"
 import (
+	"context"
+	"dlmsprocessor/tracing"
+	"encoding/hex"
 	"fmt"
 	"log/slog"
 	"reflect"
 	"runtime"
+	"runtime/cgo"
 	"strconv"
 	"strings"
+	"sync"
+	"time"
 	"unsafe"
 )
 
@@ -29,6 +35,8 @@ type BlockLoadProfile struct {
 	BlockEnergyVAhExport float64 `obis:"1.0.10.29.0.255" type:"float64" json:"block_energy_vah_export"` // Block energy VAh-export
 	AverageCurrent       float64 `obis:"1.0.11.27.0.255" type:"float64" json:"average_current"`         // Average Current
 	MeterHealthIndicator uint8   `obis:"0.0.96.10.1.255" type:"uint8" json:"meter_health_indicator"`    // Meter Health Indicator
+
+	ThreePhase *ThreePhaseBlockLoadProfile `json:"three_phase,omitempty"` // the per-phase entry, for three-phase meters
 }
 
 // DailyLoadProfile represents a single daily load profile entry with structured data
@@ -41,34 +49,28 @@ type DailyLoadProfile struct {
 }
 
 type BillingDataProfile struct {
-	BillingDate               string  `obis:"0.0.0.1.2.255" type:"string" json:"billing_date"`                    // Billing Date
-	AveragePFForBillingPeriod float64 `obis:"1.0.13.0.0.255" type:"float64" json:"average_pf_for_billing_period"` // Average PF for Billing Period
-	CumEnergyWhImport         float64 `obis:"1.0.1.8.0.255" type:"float64" json:"cum_energy_wh_import"`           // Cumulative Energy - Wh(Import)
-	CumEnergyWhTZ1            float64 `obis:"1.0.1.8.1.255" type:"float64" json:"cum_energy_wh_tz1"`              // Cumulative Energy - Wh - TZ1
-	CumEnergyWhTZ2            float64 `obis:"1.0.1.8.2.255" type:"float64" json:"cum_energy_wh_tz2"`              // Cumulative Energy - Wh - TZ2
-	CumEnergyWhTZ3            float64 `obis:"1.0.1.8.3.255" type:"float64" json:"cum_energy_wh_tz3"`              // Cumulative Energy - Wh - TZ3
-	CumEnergyWhTZ4            float64 `obis:"1.0.1.8.4.255" type:"float64" json:"cum_energy_wh_tz4"`              // Cumulative Energy - Wh - TZ4
-	// CumEnergyWhTZ5            float64 `obis:"1.0.1.8.5.255" type:"float64"`  // Cumulative Energy - Wh - TZ5
-	// CumEnergyWhTZ6            float64 `obis:"1.0.1.8.6.255" type:"float64"`  // Cumulative Energy - Wh - TZ6
-	// CumEnergyWhTZ7            float64 `obis:"1.0.1.8.7.255" type:"float64"`  // Cumulative Energy - Wh - TZ7
-	// CumEnergyWhTZ8     float64 `obis:"1.0.1.8.8.255" type:"float64"` // Cumulative Energy - Wh - TZ8
-	CumEnergyVAhImport float64 `obis:"1.0.9.8.0.255" type:"float64" json:"cum_energy_vah_import"` // Cumulative Energy - VAh(Import)
-	CumEnergyVAhTZ1    float64 `obis:"1.0.9.8.1.255" type:"float64" json:"cum_energy_vah_tz1"`    // Cumulative Energy - VAh - TZ1
-	CumEnergyVAhTZ2    float64 `obis:"1.0.9.8.2.255" type:"float64" json:"cum_energy_vah_tz2"`    // Cumulative Energy - VAh - TZ2
-	CumEnergyVAhTZ3    float64 `obis:"1.0.9.8.3.255" type:"float64" json:"cum_energy_vah_tz3"`    // Cumulative Energy - VAh - TZ3
-	CumEnergyVAhTZ4    float64 `obis:"1.0.9.8.4.255" type:"float64" json:"cum_energy_vah_tz4"`    // Cumulative Energy - VAh - TZ4
-	// CumEnergyVAhTZ5    float64 `obis:"1.0.9.8.5.255" type:"float64"` // Cumulative Energy - VAh - TZ5
-	// CumEnergyVAhTZ6    float64 `obis:"1.0.9.8.6.255" type:"float64"` // Cumulative Energy - VAh - TZ6
-	// CumEnergyVAhTZ7    float64 `obis:"1.0.9.8.7.255" type:"float64"` // Cumulative Energy - VAh - TZ7
-	// CumEnergyVAhTZ8    float64 `obis:"1.0.9.8.8.255" type:"float64"` // Cumulative Energy - VAh - TZ8
-	MDW                    float64 `obis:"1.0.1.6.0.255" type:"float64" json:"md_w"`                          // MD W
-	MDWDateTime            string  `obis:"1.0.1.6.0.255" type:"string" json:"md_w_date_time"`                 // MD W - Date & Time
-	MDVA                   float64 `obis:"1.0.9.6.0.255" type:"float64" json:"md_va"`                         // MD VA
-	MDVADateTime           string  `obis:"1.0.9.6.0.255" type:"string" json:"md_va_date_time"`                // MD VA - Date & Time
-	BillingPowerOnDuration float64 `obis:"0.0.94.91.13.255 " type:"float64" json:"billing_power_on_duration"` // Billing Power On Duration
-	CumEnergyWh            float64 `obis:"1.0.2.8.0.255" type:"float64" json:"cum_energy_wh"`                 // Billing Power On Duration
-	CumEnergyVAh           float64 `obis:"1.0.10.8.0.255" type:"float64" json:"cum_energy_vah"`               // Billing Power On Duration
+	BillingDate               string  `obis:"0.0.0.1.2.255" type:"string" json:"billing_date"`                               // Billing Date
+	AveragePFForBillingPeriod float64 `obis:"1.0.13.0.0.255" type:"float64" json:"average_pf_for_billing_period"`            // Average PF for Billing Period
+	CumEnergyWhImport         float64 `obis:"1.0.1.8.0.255" type:"float64" json:"cum_energy_wh_import"`                      // Cumulative Energy - Wh(Import)
+	CumEnergyWhTZ1            float64 `obis:"1.0.1.8.1.255" type:"float64" json:"cum_energy_wh_tz1"`                         // Cumulative Energy - Wh - TZ1
+	CumEnergyWhTZ2            float64 `obis:"1.0.1.8.2.255" type:"float64" json:"cum_energy_wh_tz2"`                         // Cumulative Energy - Wh - TZ2
+	CumEnergyWhTZ3            float64 `obis:"1.0.1.8.3.255" type:"float64" json:"cum_energy_wh_tz3"`                         // Cumulative Energy - Wh - TZ3
+	CumEnergyWhTZ4            float64 `obis:"1.0.1.8.4.255" type:"float64" json:"cum_energy_wh_tz4"`                         // Cumulative Energy - Wh - TZ4
+	CumEnergyVAhImport        float64 `obis:"1.0.9.8.0.255" type:"float64" json:"cum_energy_vah_import"`                     // Cumulative Energy - VAh(Import)
+	CumEnergyVAhTZ1           float64 `obis:"1.0.9.8.1.255" type:"float64" json:"cum_energy_vah_tz1"`                        // Cumulative Energy - VAh - TZ1
+	CumEnergyVAhTZ2           float64 `obis:"1.0.9.8.2.255" type:"float64" json:"cum_energy_vah_tz2"`                        // Cumulative Energy - VAh - TZ2
+	CumEnergyVAhTZ3           float64 `obis:"1.0.9.8.3.255" type:"float64" json:"cum_energy_vah_tz3"`                        // Cumulative Energy - VAh - TZ3
+	CumEnergyVAhTZ4           float64 `obis:"1.0.9.8.4.255" type:"float64" json:"cum_energy_vah_tz4"`                        // Cumulative Energy - VAh - TZ4
+	MDW                       float64 `obis:"1.0.1.6.0.255" type:"float64" json:"md_w"`                                      // MD W
+	MDWDateTime               string  `obis:"1.0.1.6.0.255" attribute:"capture_time" type:"datetime" json:"md_w_date_time"`  // MD W - Date & Time
+	MDVA                      float64 `obis:"1.0.9.6.0.255" type:"float64" json:"md_va"`                                     // MD VA
+	MDVADateTime              string  `obis:"1.0.9.6.0.255" attribute:"capture_time" type:"datetime" json:"md_va_date_time"` // MD VA - Date & Time
+	BillingPowerOnDuration    float64 `obis:"0.0.94.91.13.255 " type:"float64" json:"billing_power_on_duration"`             // Billing Power On Duration
+	CumEnergyWh               float64 `obis:"1.0.2.8.0.255" type:"float64" json:"cum_energy_wh"`                             // Billing Power On Duration
+	CumEnergyVAh              float64 `obis:"1.0.10.8.0.255" type:"float64" json:"cum_energy_vah"`                           // Billing Power On Duration
 	// MDVADateTime              string  `obis:"1.0.1.6.0.255" type:"string"`   // MD VA - Date & Time
+
+	TariffZones []TariffZone `json:"tariff_zones"` // every tariff zone the profile captures, TZ1 to TZ4 included
 }
 
 // InstantaneousProfile represents instantaneous values from the meter
@@ -83,11 +85,40 @@ type InstantaneousProfile struct {
 	ActivePower       float64 `obis:"1.0.1.7.0.255" type:"float64"`  // Active Power - W (instantaneous)
 	CumEnergyWh       float64 `obis:"1.0.1.8.0.255" type:"float64"`  // Cumulative Energy - Wh
 	CumEnergyVAh      float64 `obis:"1.0.9.8.0.255" type:"float64"`  // Cumulative Energy - VAh
+
+	ThreePhase *ThreePhaseInstantaneousProfile // the per-phase entry, for three-phase meters
 }
 
-// MeterClient provides a high-level interface for connecting to DLMS meters
+// MeterClient provides a high-level interface for connecting to DLMS meters.
+//
+// A MeterClient is safe for concurrent use: its methods are serialized, as a
+// DLMS association carries one request at a time. Calls on different clients
+// run in parallel; the Gurux library itself is guarded by a process-wide lock
+// that is released while a client waits on its socket, so only message
+// encoding and decoding is serialized across clients.
 type MeterClient struct {
-	meter *C.meter_t
+	mu     sync.Mutex
+	meter  *C.meter_t
+	replay cgo.Handle // of the Replay the client talks to instead of a meter, 0 if none
+	name   string     // the meter configured, as its String without keys
+}
+
+// errNotInitialized reports the client was used after Close, naming the
+// meter it was configured for. Must be called with c.mu held.
+func (c *MeterClient) errNotInitialized() error {
+	if c.name == "" {
+		return fmt.Errorf("client not initialized")
+	}
+	return fmt.Errorf("%s: client not initialized", c.name)
+}
+
+// WithLibraryLock runs f under the process-wide Gurux lock. Other packages
+// that call into the Gurux library, like the meter simulator, must do so
+// from f.
+func WithLibraryLock(f func()) {
+	C.gurux_enter()
+	defer C.gurux_leave()
+	f()
 }
 
 // NewMeterClient creates a new DLMS meter client with default configuration
@@ -107,10 +138,20 @@ func NewMeterClient() *MeterClient {
 
 // cleanup ensures the C resources are freed
 func (c *MeterClient) cleanup() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
 	if c.meter != nil {
+		// Freeing releases an open association, which goes through Gurux
+		C.gurux_enter()
 		C.meter_free(c.meter)
+		C.gurux_leave()
 		c.meter = nil
 	}
+	if c.replay != 0 {
+		c.replay.Delete()
+		c.replay = 0
+	}
 }
 
 // Close explicitly frees the client resources
@@ -121,6 +162,10 @@ func (c *MeterClient) Close() {
 
 // Configure sets multiple configuration parameters at once
 func (c *MeterClient) Configure(meter *RealMeter) error {
+	c.mu.Lock()
+	c.name = meter.String()
+	c.mu.Unlock()
+
 	if err := c.SetMeterIP(meter.MeterIP); err != nil {
 		return fmt.Errorf("setting meter IP: %w", err)
 	}
@@ -190,8 +235,11 @@ func (c *MeterClient) Configure(meter *RealMeter) error {
 
 // SetMeterIP sets the meter IP address
 func (c *MeterClient) SetMeterIP(ip string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
 	if c.meter == nil {
-		return fmt.Errorf("client not initialized")
+		return c.errNotInitialized()
 	}
 
 	cIP := C.CString(ip)
@@ -207,8 +255,11 @@ func (c *MeterClient) SetMeterIP(ip string) error {
 
 // SetMeterPort sets the meter port
 func (c *MeterClient) SetMeterPort(port int) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
 	if c.meter == nil {
-		return fmt.Errorf("client not initialized")
+		return c.errNotInitialized()
 	}
 
 	ret := C.meter_set_port(c.meter, C.int(port))
@@ -221,8 +272,11 @@ func (c *MeterClient) SetMeterPort(port int) error {
 
 // SetConnectionTimeout sets the connection timeout in milliseconds
 func (c *MeterClient) SetConnectionTimeout(timeout int) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
 	if c.meter == nil {
-		return fmt.Errorf("client not initialized")
+		return c.errNotInitialized()
 	}
 
 	ret := C.meter_set_connection_timeout(c.meter, C.int(timeout))
@@ -235,8 +289,11 @@ func (c *MeterClient) SetConnectionTimeout(timeout int) error {
 
 // SetAuthPassword sets the authentication password
 func (c *MeterClient) SetAuthPassword(password string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
 	if c.meter == nil {
-		return fmt.Errorf("client not initialized")
+		return c.errNotInitialized()
 	}
 
 	cPassword := C.CString(password)
@@ -252,8 +309,11 @@ func (c *MeterClient) SetAuthPassword(password string) error {
 
 // SetSystemTitle sets the system title (8 bytes as hex string)
 func (c *MeterClient) SetSystemTitle(title string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
 	if c.meter == nil {
-		return fmt.Errorf("client not initialized")
+		return c.errNotInitialized()
 	}
 
 	if len(title) != 16 {
@@ -273,8 +333,11 @@ func (c *MeterClient) SetSystemTitle(title string) error {
 
 // SetBlockCipherKey sets the block cipher key (16 bytes as hex string)
 func (c *MeterClient) SetBlockCipherKey(key string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
 	if c.meter == nil {
-		return fmt.Errorf("client not initialized")
+		return c.errNotInitialized()
 	}
 
 	if len(key) != 32 {
@@ -294,8 +357,11 @@ func (c *MeterClient) SetBlockCipherKey(key string) error {
 
 // SetAuthenticationKey sets the authentication key (16 bytes as hex string)
 func (c *MeterClient) SetAuthenticationKey(key string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
 	if c.meter == nil {
-		return fmt.Errorf("client not initialized")
+		return c.errNotInitialized()
 	}
 
 	if len(key) != 32 {
@@ -315,8 +381,11 @@ func (c *MeterClient) SetAuthenticationKey(key string) error {
 
 // SetClientAddress sets the client address
 func (c *MeterClient) SetClientAddress(address int) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
 	if c.meter == nil {
-		return fmt.Errorf("client not initialized")
+		return c.errNotInitialized()
 	}
 
 	ret := C.meter_set_client_address(c.meter, C.int(address))
@@ -329,8 +398,11 @@ func (c *MeterClient) SetClientAddress(address int) error {
 
 // SetServerAddress sets the server address
 func (c *MeterClient) SetServerAddress(address int) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
 	if c.meter == nil {
-		return fmt.Errorf("client not initialized")
+		return c.errNotInitialized()
 	}
 
 	ret := C.meter_set_server_address(c.meter, C.int(address))
@@ -343,8 +415,11 @@ func (c *MeterClient) SetServerAddress(address int) error {
 
 // SetAttributeIndex sets the attribute index
 func (c *MeterClient) SetAttributeIndex(index int) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
 	if c.meter == nil {
-		return fmt.Errorf("client not initialized")
+		return c.errNotInitialized()
 	}
 
 	ret := C.meter_set_attribute_index(c.meter, C.int(index))
@@ -357,8 +432,11 @@ func (c *MeterClient) SetAttributeIndex(index int) error {
 
 // SetMaxEntries sets the maximum number of entries to read
 func (c *MeterClient) SetMaxEntries(maxEntries int) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
 	if c.meter == nil {
-		return fmt.Errorf("client not initialized")
+		return c.errNotInitialized()
 	}
 
 	ret := C.meter_set_max_entries(c.meter, C.int(maxEntries))
@@ -369,25 +447,156 @@ func (c *MeterClient) SetMaxEntries(maxEntries int) error {
 	return nil
 }
 
+// interruptible runs a blocking shim call under ctx, holding the Gurux lock;
+// the caller must hold c.mu. The call is given the context deadline and, if
+// ctx is cancelled while it is in progress, its socket is shut down from
+// this goroutine so the call returns promptly. Its frames are recorded in
+// the FrameTrace of ctx, if any.
+func (c *MeterClient) interruptible(ctx context.Context, call func()) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	var deadline int64
+	if d, ok := ctx.Deadline(); ok {
+		deadline = d.UnixMilli()
+	}
+	C.meter_set_deadline(c.meter, C.int64_t(deadline))
+	C.meter_clear_abort(c.meter)
+
+	aborted := make(chan struct{})
+	stop := context.AfterFunc(ctx, func() {
+		defer close(aborted)
+		C.meter_abort(c.meter)
+	})
+
+	trace := frameTraceFrom(ctx)
+	if trace != nil {
+		handle := cgo.NewHandle(trace)
+		defer handle.Delete()
+		C.meter_set_frame_trace(c.meter, C.uintptr_t(handle))
+	}
+
+	C.gurux_enter()
+	call()
+	if trace != nil {
+		C.meter_set_frame_trace(c.meter, 0)
+		trace.observe(c.meter)
+	}
+	C.gurux_leave()
+
+	// Wait for a running abort so it never outlives the call and races a Close
+	if !stop() {
+		<-aborted
+	}
+
+	return ctx.Err()
+}
+
+// command runs a shim call that changes the meter's state as interruptible
+// does, returning its code. The context's error is only returned if the call
+// never ran: once it has, a code of 0 means the meter carried out the
+// request, even if ctx ended just after, and a failure shows in the code.
+func (c *MeterClient) command(ctx context.Context, call func() int) (int, error) {
+	var ret int
+	ran := false
+	err := c.interruptible(ctx, func() {
+		ran = true
+		ret = call()
+	})
+	if !ran {
+		return 0, err
+	}
+	return ret, nil
+}
+
+// contextError prefers the context's error when a call failed because ctx
+// was cancelled or expired, so callers can test it with errors.Is
+func contextError(ctx context.Context, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	ctxErr := ctx.Err()
+	if ctxErr == nil {
+		// The shim gets the deadline truncated to milliseconds and gives up at
+		// it, which can be just before ctx itself notices
+		if d, ok := ctx.Deadline(); ok && time.Until(d) < time.Millisecond {
+			ctxErr = context.DeadlineExceeded
+		}
+	}
+	if ctxErr != nil {
+		return fmt.Errorf("%w: %w", ctxErr, err)
+	}
+	return err
+}
+
 // Connect establishes a connection to the DLMS meter
-func (c *MeterClient) Connect() error {
+func (c *MeterClient) Connect(ctx context.Context) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
 	if c.meter == nil {
-		return fmt.Errorf("client not initialized")
+		return c.errNotInitialized()
 	}
 
-	ret := C.meter_connect(c.meter)
+	start := time.Now()
+	var ret C.int
+	err := c.interruptible(ctx, func() { ret = C.meter_connect(c.meter) })
 	if ret != 0 {
-		return fmt.Errorf("failed to connect to meter: error code %d", ret)
+		err = codeError(ctx, int(ret), "", "failed to connect to meter")
+	}
+	var socketOpenedMs int64
+	if C.meter_connect_failed_step(c.meter) != C.METER_STEP_SOCKET {
+		socketOpenedMs = int64(C.meter_connect_step_ms(c.meter, C.METER_STEP_SOCKET))
 	}
+	traceConnect(ctx, start, socketOpenedMs, err)
 
-	return nil
+	return err
+}
+
+// connectSteps returns when each step of the last connect finished or
+// failed, the zero time for steps it did not reach, and the index of the
+// step it failed at, -1 if none
+func (c *MeterClient) connectSteps() ([]time.Time, int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.meter == nil {
+		return nil, -1
+	}
+	ends := make([]time.Time, C.METER_STEP_COUNT)
+	for i := range ends {
+		if ms := int64(C.meter_connect_step_ms(c.meter, C.int(i))); ms != 0 {
+			ends[i] = time.UnixMilli(ms)
+		}
+	}
+	return ends, int(C.meter_connect_failed_step(c.meter))
+}
+
+// release sends the release request, failing if the meter does not
+// acknowledge it, and closes the socket either way
+func (c *MeterClient) release(ctx context.Context) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.meter == nil {
+		return c.errNotInitialized()
+	}
+
+	var ret C.int
+	err := c.interruptible(ctx, func() { ret = C.meter_release(c.meter) })
+	if ret != 0 {
+		err = codeError(ctx, int(ret), "", "failed to release the association")
+	}
+	return err
 }
 
-func ReadProfileDataTyped[T any](c *MeterClient, obisCode string, index, count int) ([]T, error) {
+func ReadProfileDataTyped[T any](ctx context.Context, c *MeterClient, obisCode string, index, count int) ([]T, error) {
 	var zero T
 	structType := reflect.TypeOf(zero)
 
-	genericResults, err := c.ReadProfileData(obisCode, index, count, structType)
+	genericResults, err := c.ReadProfileData(ctx, obisCode, index, count, structType)
 	if err != nil {
 		return nil, err
 	}
@@ -402,9 +611,9 @@ func ReadProfileDataTyped[T any](c *MeterClient, obisCode string, index, count i
 }
 
 // ReadProfileData is a generic function that reads profile data and maps it to any struct type with OBIS tags
-func (c *MeterClient) ReadProfileData(obisCode string, index, count int, structType reflect.Type) ([]interface{}, error) {
+func (c *MeterClient) ReadProfileData(ctx context.Context, obisCode string, index, count int, structType reflect.Type) ([]interface{}, error) {
 	// First get the raw data using the existing method
-	result, err := c.ProfileGenericReadRows(obisCode, index, count)
+	result, err := c.ProfileGenericReadRows(ctx, obisCode, index, count)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read profile data: %w", err)
 	}
@@ -416,18 +625,42 @@ func (c *MeterClient) ReadProfileData(obisCode string, index, count int, structT
 	// slog trace level print result
 	slog.Info("result", "result", result)
 
-	// Use the generic mapping function
-	return mapDLMSDataToStruct(result, structType)
+	return mapProfileData(ctx, result, structType)
+}
+
+// mapProfileData maps the rows of a profile read to structType
+func mapProfileData(ctx context.Context, result *DLMSResult, structType reflect.Type) ([]interface{}, error) {
+	_, span := tracer().Start(ctx, "dlms.map_profile")
+	rows, err := mapDLMSDataToStruct(result, structType)
+	tracing.End(span, err)
+	return rows, err
+}
+
+// mapProfileDataTyped maps the rows of a profile read to T
+func mapProfileDataTyped[T any](ctx context.Context, result *DLMSResult) ([]T, error) {
+	var zero T
+	genericResults, err := mapProfileData(ctx, result, reflect.TypeOf(zero))
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]T, len(genericResults))
+	for i, genericResult := range genericResults {
+		results[i] = genericResult.(T)
+	}
+	return results, nil
 }
 
 // DLMSResult represents the result of reading profile data from a DLMS meter
 type DLMSResult struct {
-	ErrorCode    int
-	ErrorMessage string
-	NumRows      int
-	NumColumns   int
-	ColumnNames  []string
-	Data         [][]string
+	ErrorCode        int
+	ErrorMessage     string
+	NumRows          int
+	NumColumns       int
+	ColumnNames      []string
+	ColumnClasses    []int // interface class of each captured object, 0 if unknown
+	ColumnAttributes []int // captured attribute of each column, 0 if unknown
+	Data             [][]string
 }
 
 // mapDLMSDataToStruct uses reflection to map DLMS data to any struct with OBIS tags
@@ -473,10 +706,12 @@ func mapDLMSDataToStruct(result *DLMSResult, structType reflect.Type) ([]interfa
 			colIdx := -1
 			exists := false
 
-			// First try exact OBIS code match
-			if idx, found := columnMap[obisCode]; found {
+			// First try the column capturing the tagged attribute of the OBIS code
+			if idx, found := result.column(obisCode, Attribute(field.Tag.Get("attribute"))); found {
 				colIdx = idx
 				exists = true
+			} else if _, captured := columnMap[obisCode]; captured {
+				// The meter captures other attributes of the object, not this one
 			} else {
 				// Try to find by field name as fallback
 				for j, columnName := range result.ColumnNames {
@@ -526,7 +761,7 @@ func mapDLMSDataToStruct(result *DLMSResult, structType reflect.Type) ([]interfa
 
 			// Set the field value using reflection
 			switch dataType {
-			case "string":
+			case "string", "datetime":
 				if fieldValue.Kind() == reflect.String {
 					fieldValue.SetString(parsedValue.(string))
 				}
@@ -564,6 +799,8 @@ func parseValueByType(value string, targetType string) (interface{}, error) {
 	switch targetType {
 	case "string":
 		return value, nil
+	case "datetime":
+		return parseDateTime(value), nil
 	case "float64":
 		return parseFloat64(value)
 	case "uint8":
@@ -625,9 +862,15 @@ func parseUint8(s string) (uint8, error) {
 }
 
 // ProfileGenericReadRows reads rows from a profile generic object using OBIS code
-func (c *MeterClient) ProfileGenericReadRows(obisCode string, index, count int) (*DLMSResult, error) {
+func (c *MeterClient) ProfileGenericReadRows(ctx context.Context, obisCode string, index, count int) (_ *DLMSResult, err error) {
+	ctx, span := startCOSEM(ctx, "cosem.get", obisCode, ObjectTypeProfileGeneric, 2)
+	defer func() { tracing.End(span, err) }()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
 	if c.meter == nil {
-		return nil, fmt.Errorf("client not initialized")
+		return nil, c.errNotInitialized()
 	}
 
 	if obisCode == "" {
@@ -639,7 +882,10 @@ func (c *MeterClient) ProfileGenericReadRows(obisCode string, index, count int)
 	defer C.free(unsafe.Pointer(cObisCode))
 
 	// Call the C function to read profile generic data
-	cResult := C.meter_read_profile_generic(c.meter, cObisCode)
+	var cResult *C.dlms_result_t
+	if err := c.interruptible(ctx, func() { cResult = C.meter_read_profile_generic(c.meter, cObisCode) }); err != nil && cResult == nil {
+		return nil, err
+	}
 	if cResult == nil {
 		return nil, fmt.Errorf("failed to read profile generic rows: C function returned NULL")
 	}
@@ -657,7 +903,7 @@ func (c *MeterClient) ProfileGenericReadRows(obisCode string, index, count int)
 
 	// Check for errors
 	if result.ErrorCode != 0 {
-		return result, fmt.Errorf("DLMS error %d: %s", result.ErrorCode, result.ErrorMessage)
+		return result, codeError(ctx, result.ErrorCode, result.ErrorMessage, "failed to read profile "+obisCode)
 	}
 
 	// Extract column names
@@ -671,6 +917,12 @@ func (c *MeterClient) ProfileGenericReadRows(obisCode string, index, count int)
 				result.ColumnNames[i] = fmt.Sprintf("Column_%d", i)
 			}
 		}
+		result.ColumnClasses = make([]int, result.NumColumns)
+		result.ColumnAttributes = make([]int, result.NumColumns)
+		for i := range result.NumColumns {
+			result.ColumnClasses[i] = int(C.dlms_result_get_column_class(cResult, C.int(i)))
+			result.ColumnAttributes[i] = int(C.dlms_result_get_column_attribute(cResult, C.int(i)))
+		}
 	}
 
 	// Extract data
@@ -691,3 +943,282 @@ func (c *MeterClient) ProfileGenericReadRows(obisCode string, index, count int)
 
 	return result, nil
 }
+
+// ReadAttribute reads a single attribute of a COSEM object and returns its
+// value in the same string form used for profile cells
+func (c *MeterClient) ReadAttribute(ctx context.Context, obisCode string, objectType, attributeIndex int) (_ string, err error) {
+	ctx, span := startCOSEM(ctx, "cosem.get", obisCode, objectType, attributeIndex)
+	defer func() { tracing.End(span, err) }()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.meter == nil {
+		return "", c.errNotInitialized()
+	}
+
+	if obisCode == "" {
+		return "", fmt.Errorf("OBIS code cannot be empty")
+	}
+
+	cObisCode := C.CString(obisCode)
+	defer C.free(unsafe.Pointer(cObisCode))
+
+	var cResult *C.dlms_result_t
+	if err := c.interruptible(ctx, func() {
+		cResult = C.meter_read_attribute(c.meter, cObisCode, C.int(objectType), C.int(attributeIndex))
+	}); err != nil && cResult == nil {
+		return "", err
+	}
+	if cResult == nil {
+		return "", fmt.Errorf("failed to read attribute: C function returned NULL")
+	}
+	defer C.dlms_result_free(cResult)
+
+	if cResult.error_code != 0 {
+		return "", codeError(ctx, int(cResult.error_code), C.GoString(cResult.error_message), fmt.Sprintf("failed to read %s attribute %d", obisCode, attributeIndex))
+	}
+
+	cellData := C.dlms_result_get_data(cResult, 0, 0)
+	if cellData == nil {
+		return "", fmt.Errorf("no value returned for %s attribute %d", obisCode, attributeIndex)
+	}
+
+	return C.GoString(cellData), nil
+}
+
+// ReadEncoded reads a single attribute of a COSEM object as its A-XDR
+// encoding, for values like arrays and structures
+func (c *MeterClient) ReadEncoded(ctx context.Context, obisCode string, objectType, attributeIndex int) (_ Encoded, err error) {
+	ctx, span := startCOSEM(ctx, "cosem.get", obisCode, objectType, attributeIndex)
+	defer func() { tracing.End(span, err) }()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.meter == nil {
+		return nil, c.errNotInitialized()
+	}
+
+	if obisCode == "" {
+		return nil, fmt.Errorf("OBIS code cannot be empty")
+	}
+
+	cObisCode := C.CString(obisCode)
+	defer C.free(unsafe.Pointer(cObisCode))
+
+	var cResult *C.dlms_result_t
+	if err := c.interruptible(ctx, func() {
+		cResult = C.meter_read_attribute_encoded(c.meter, cObisCode, C.int(objectType), C.int(attributeIndex))
+	}); err != nil && cResult == nil {
+		return nil, err
+	}
+	if cResult == nil {
+		return nil, fmt.Errorf("failed to read attribute: C function returned NULL")
+	}
+	defer C.dlms_result_free(cResult)
+
+	if cResult.error_code != 0 {
+		return nil, codeError(ctx, int(cResult.error_code), C.GoString(cResult.error_message), fmt.Sprintf("failed to read %s attribute %d", obisCode, attributeIndex))
+	}
+
+	cellData := C.dlms_result_get_data(cResult, 0, 0)
+	if cellData == nil {
+		return nil, fmt.Errorf("no value returned for %s attribute %d", obisCode, attributeIndex)
+	}
+	data, err := hex.DecodeString(strings.TrimPrefix(C.GoString(cellData), "Hex:"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid encoding of %s attribute %d: %w", obisCode, attributeIndex, err)
+	}
+	return data, nil
+}
+
+// Disconnect releases the association and closes the socket but keeps the
+// client configured, so Connect can open a new association later
+func (c *MeterClient) Disconnect() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.meter == nil {
+		return
+	}
+	C.gurux_enter()
+	C.meter_disconnect(c.meter)
+	C.gurux_leave()
+}
+
+// IsConnected reports whether an association is currently open
+func (c *MeterClient) IsConnected() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.meter != nil && C.meter_is_connected(c.meter) != 0
+}
+
+// KeepAlive tells the meter that the association is still in use
+func (c *MeterClient) KeepAlive(ctx context.Context) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.meter == nil {
+		return c.errNotInitialized()
+	}
+
+	ret, err := c.command(ctx, func() int { return int(C.meter_keep_alive(c.meter)) })
+	if err != nil {
+		return err
+	}
+	if ret != 0 {
+		return codeError(ctx, ret, "", "keep-alive failed")
+	}
+
+	return nil
+}
+
+// Encoded is a value in its A-XDR encoding: WriteAttribute sends it as it
+// is, with its own data type
+type Encoded []byte
+
+// WriteAttribute writes value to an attribute of a COSEM object. The DLMS
+// data type is chosen from the Go type of value.
+func (c *MeterClient) WriteAttribute(ctx context.Context, obisCode string, objectType, attributeIndex int, value any) (err error) {
+	ctx, span := startCOSEM(ctx, "cosem.set", obisCode, objectType, attributeIndex)
+	defer func() { tracing.End(span, err) }()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.meter == nil {
+		return c.errNotInitialized()
+	}
+
+	if obisCode == "" {
+		return fmt.Errorf("OBIS code cannot be empty")
+	}
+
+	cObisCode := C.CString(obisCode)
+	defer C.free(unsafe.Pointer(cObisCode))
+	ot, ai := C.int(objectType), C.int(attributeIndex)
+
+	var write func() C.int
+	switch v := value.(type) {
+	case int8:
+		write = func() C.int { return C.meter_write_obis_int8(c.meter, cObisCode, C.int8_t(v), ot, ai) }
+	case int16:
+		write = func() C.int { return C.meter_write_obis_int16(c.meter, cObisCode, C.int16_t(v), ot, ai) }
+	case int32:
+		write = func() C.int { return C.meter_write_obis_int32(c.meter, cObisCode, C.int32_t(v), ot, ai) }
+	case uint8:
+		write = func() C.int { return C.meter_write_obis_uint8(c.meter, cObisCode, C.uint8_t(v), ot, ai) }
+	case uint16:
+		write = func() C.int { return C.meter_write_obis_uint16(c.meter, cObisCode, C.uint16_t(v), ot, ai) }
+	case uint32:
+		write = func() C.int { return C.meter_write_obis_uint32(c.meter, cObisCode, C.uint32_t(v), ot, ai) }
+	case float32:
+		write = func() C.int { return C.meter_write_obis_float32(c.meter, cObisCode, C.float(v), ot, ai) }
+	case float64:
+		write = func() C.int { return C.meter_write_obis_float64(c.meter, cObisCode, C.double(v), ot, ai) }
+	case bool:
+		var b C.uchar
+		if v {
+			b = 1
+		}
+		write = func() C.int { return C.meter_write_obis_boolean(c.meter, cObisCode, b, ot, ai) }
+	case string:
+		cValue := C.CString(v)
+		defer C.free(unsafe.Pointer(cValue))
+		write = func() C.int { return C.meter_write_obis_string(c.meter, cObisCode, cValue, ot, ai) }
+	case []byte:
+		if len(v) == 0 {
+			return fmt.Errorf("octet string cannot be empty")
+		}
+		cData := C.CBytes(v)
+		defer C.free(cData)
+		write = func() C.int {
+			return C.meter_write_obis_octet_string(c.meter, cObisCode, (*C.uchar)(cData), C.int(len(v)), ot, ai)
+		}
+	case time.Time:
+		write = func() C.int { return C.meter_write_obis_datetime(c.meter, cObisCode, C.time_t(v.Unix()), ot, ai) }
+	case Encoded:
+		if len(v) == 0 {
+			return fmt.Errorf("encoded value cannot be empty")
+		}
+		cData := C.CBytes(v)
+		defer C.free(cData)
+		write = func() C.int {
+			return C.meter_write_obis_encoded(c.meter, cObisCode, (*C.uchar)(cData), C.int(len(v)), ot, ai)
+		}
+	default:
+		return fmt.Errorf("unsupported value type %T", value)
+	}
+
+	ret, err := c.command(ctx, func() int { return int(write()) })
+	if err != nil {
+		return err
+	}
+	if ret != 0 {
+		return codeError(ctx, ret, "", fmt.Sprintf("failed to write %s attribute %d", obisCode, attributeIndex))
+	}
+
+	return nil
+}
+
+// Invoke calls a method of a COSEM object, with its parameter A-XDR
+// encoded, or none if data is empty
+func (c *MeterClient) Invoke(ctx context.Context, obisCode string, objectType, methodIndex int, data Encoded) (err error) {
+	ctx, span := startCOSEM(ctx, "cosem.action", obisCode, objectType, methodIndex)
+	defer func() { tracing.End(span, err) }()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.meter == nil {
+		return c.errNotInitialized()
+	}
+
+	if obisCode == "" {
+		return fmt.Errorf("OBIS code cannot be empty")
+	}
+
+	cObisCode := C.CString(obisCode)
+	defer C.free(unsafe.Pointer(cObisCode))
+	var cData unsafe.Pointer
+	if len(data) > 0 {
+		cData = C.CBytes(data)
+		defer C.free(cData)
+	}
+
+	ret, err := c.command(ctx, func() int {
+		return int(C.meter_call_method_with_data(c.meter, cObisCode, C.int(objectType), C.int(methodIndex), (*C.uchar)(cData), C.int(len(data))))
+	})
+	if err != nil {
+		return err
+	}
+	if ret != 0 {
+		return codeError(ctx, ret, "", fmt.Sprintf("failed to invoke %s method %d", obisCode, methodIndex))
+	}
+
+	return nil
+}
+
+// SetClock sets the meter clock through the clock object's adjust method
+func (c *MeterClient) SetClock(ctx context.Context, t time.Time) (err error) {
+	ctx, span := startCOSEM(ctx, "cosem.action", ClockOBIS, ObjectTypeClock, clockAdjustMethod)
+	defer func() { tracing.End(span, err) }()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.meter == nil {
+		return c.errNotInitialized()
+	}
+
+	ret, err := c.command(ctx, func() int { return int(C.meter_call_set_time(c.meter, C.time_t(t.Unix()))) })
+	if err != nil {
+		return err
+	}
+	if ret != 0 {
+		return codeError(ctx, ret, "", "failed to set clock")
+	}
+
+	return nil
+}
@@ -18,6 +18,12 @@
 #include <strings.h>
 #include <sys/time.h>
 #include <time.h>
+#include <errno.h>
+#include <fcntl.h>
+#include <netdb.h>
+#include <poll.h>
+#include <semaphore.h>
+#include <sys/socket.h>
 #endif
 
 #include "./helpers/include/communication.h"
@@ -37,20 +43,59 @@
 #define DEFAULT_SECURITY_LEVEL DLMS_SECURITY_AUTHENTICATION_ENCRYPTION
 #define DEFAULT_INTERFACE_TYPE DLMS_INTERFACE_TYPE_WRAPPER
 
-// Stub functions required by the framework
-void svr_preGet(dlmsSettings* settings, gxValueEventCollection* args) {}
-void svr_postGet(dlmsSettings* settings, gxValueEventCollection* args) {}
-void svr_preRead(dlmsSettings* settings, gxValueEventCollection* args) {}
-void svr_preWrite(dlmsSettings* settings, gxValueEventCollection* args) {}
-void svr_preAction(dlmsSettings* settings, gxValueEventCollection* args) {}
-void svr_postRead(dlmsSettings* settings, gxValueEventCollection* args) {}
-void svr_postWrite(dlmsSettings* settings, gxValueEventCollection* args) {}
-void svr_postAction(dlmsSettings* settings, gxValueEventCollection* args) {}
-unsigned char svr_isTarget(dlmsSettings* settings, unsigned long serverAddress, unsigned long clientAddress) { return 0; }
-int svr_connected(dlmsServerSettings* settings) { return 0; }
-DLMS_ACCESS_MODE svr_getAttributeAccess(dlmsSettings* settings, gxObject* obj, unsigned char index) { return DLMS_ACCESS_MODE_READ_WRITE; }
-DLMS_METHOD_ACCESS_MODE svr_getMethodAccess(dlmsSettings* settings, gxObject* obj, unsigned char index) { return DLMS_METHOD_ACCESS_MODE_ACCESS; }
-void svr_trace(const char* str, const char* data) {}
+// Stub functions required by the framework. They are weak so that the
+// simulator package, which runs a Gurux server, can define its own.
+#define SVR_STUB __attribute__((weak))
+SVR_STUB void svr_preGet(dlmsSettings* settings, gxValueEventCollection* args) {}
+SVR_STUB void svr_postGet(dlmsSettings* settings, gxValueEventCollection* args) {}
+SVR_STUB void svr_preRead(dlmsSettings* settings, gxValueEventCollection* args) {}
+SVR_STUB void svr_preWrite(dlmsSettings* settings, gxValueEventCollection* args) {}
+SVR_STUB void svr_preAction(dlmsSettings* settings, gxValueEventCollection* args) {}
+SVR_STUB void svr_postRead(dlmsSettings* settings, gxValueEventCollection* args) {}
+SVR_STUB void svr_postWrite(dlmsSettings* settings, gxValueEventCollection* args) {}
+SVR_STUB void svr_postAction(dlmsSettings* settings, gxValueEventCollection* args) {}
+SVR_STUB unsigned char svr_isTarget(dlmsSettings* settings, unsigned long serverAddress, unsigned long clientAddress) { return 0; }
+SVR_STUB int svr_connected(dlmsServerSettings* settings) { return 0; }
+SVR_STUB DLMS_ACCESS_MODE svr_getAttributeAccess(dlmsSettings* settings, gxObject* obj, unsigned char index) { return DLMS_ACCESS_MODE_READ_WRITE; }
+SVR_STUB DLMS_METHOD_ACCESS_MODE svr_getMethodAccess(dlmsSettings* settings, gxObject* obj, unsigned char index) { return DLMS_METHOD_ACCESS_MODE_ACCESS; }
+SVR_STUB void svr_trace(const char* str, const char* data) {}
+
+// Process-wide Gurux lock, see dlms_shim.h
+static sem_t gurux_sem;
+static pthread_once_t gurux_once = PTHREAD_ONCE_INIT;
+
+static void gurux_init(void) {
+    sem_init(&gurux_sem, 0, 1);
+}
+
+void gurux_enter(void) {
+    pthread_once(&gurux_once, gurux_init);
+    while (sem_wait(&gurux_sem) == -1 && errno == EINTR) {
+    }
+}
+
+void gurux_leave(void) {
+    sem_post(&gurux_sem);
+}
+
+// send/recv with the Gurux lock released while blocked
+static ssize_t unlocked_send(int fd, const void* buf, size_t len) {
+    gurux_leave();
+    ssize_t ret = send(fd, buf, len, 0);
+    int err = errno;
+    gurux_enter();
+    errno = err;
+    return ret;
+}
+
+static ssize_t unlocked_recv(int fd, void* buf, size_t len) {
+    gurux_leave();
+    ssize_t ret = recv(fd, buf, len, 0);
+    int err = errno;
+    gurux_enter();
+    errno = err;
+    return ret;
+}
 
 // Helper function to safely copy strings
 static char* safe_strdup(const char* str) {
@@ -80,38 +125,6 @@ static int hex_to_bytes(const char* hex_str, unsigned char* bytes, int max_bytes
     return len / 2;
 }
 
-// Helper function to print raw packet data in hex format
-static void print_packet_hex(const char* direction, const unsigned char* data, int length) {
-    printf("\n=== %s PACKET ===\n", direction);
-    printf("Length: %d bytes\n", length);
-    printf("Raw data:\n");
-    
-    for (int i = 0; i < length; i++) {
-        if (i % 16 == 0) {
-            printf("%04X: ", i);
-        }
-        printf("%02X ", data[i]);
-        if ((i + 1) % 16 == 0 || i == length - 1) {
-            // Print ASCII representation
-            int ascii_start = (i / 16) * 16;
-            int ascii_count = (i % 16) + 1;
-            if (i == length - 1 && i % 16 != 15) {
-                // Pad spaces for last incomplete line
-                for (int j = ascii_count; j < 16; j++) {
-                    printf("   ");
-                }
-            }
-            printf(" |");
-            for (int j = 0; j < ascii_count; j++) {
-                unsigned char c = data[ascii_start + j];
-                printf("%c", (c >= 32 && c <= 126) ? c : '.');
-            }
-            printf("|\n");
-        }
-    }
-    printf("=== END PACKET ===\n\n");
-}
-
 // Helper function to convert variant to string
 static char* variant_to_string(dlmsVARIANT* value) {
     if (!value) return safe_strdup("[NULL]");
@@ -217,13 +230,22 @@ meter_t* meter_create(void) {
     meter->server_address = DEFAULT_SERVER_ADDRESS;
     meter->attribute_index = DEFAULT_ATTRIBUTE_INDEX;
     meter->max_entries = DEFAULT_MAX_ENTRIES;
-    
-    // Initialize debug settings
-    meter->debug_packets = 0;  // Debug off by default
+    meter->trace_handle = 0;
+    meter->replay_handle = 0;
     
     // Initialize connection state
     meter->connection = NULL;
     meter->is_connected = 0;
+
+    // Initialize cancellation state
+    meter->deadline_ms = 0;
+    meter->aborted = 0;
+    meter->socket_fd = -1;
+    pthread_mutex_init(&meter->io_lock, NULL);
+
+    // Initialize tracing state
+    memset(meter->step_end_ms, 0, sizeof(meter->step_end_ms));
+    meter->failed_step = -1;
     
     return meter;
 }
@@ -241,6 +263,7 @@ void meter_free(meter_t* meter) {
     free(meter->system_title);
     free(meter->block_cipher_key);
     free(meter->authentication_key);
+    pthread_mutex_destroy(&meter->io_lock);
     free(meter);
 }
 
@@ -315,12 +338,288 @@ int meter_set_max_entries(meter_t* meter, int max_entries) {
     return 0;
 }
 
-int meter_set_debug_packets(meter_t* meter, int enable) {
+int meter_set_frame_trace(meter_t* meter, uintptr_t handle) {
     if (!meter) return -1;
-    meter->debug_packets = enable ? 1 : 0;
+    meter->trace_handle = handle;
     return 0;
 }
 
+int meter_set_replay(meter_t* meter, uintptr_t handle) {
+    if (!meter) return -1;
+    meter->replay_handle = handle;
+    return 0;
+}
+
+int meter_server_system_title(meter_t* meter, unsigned char* title) {
+    if (!meter || !meter->is_connected || !meter->connection) return -1;
+    connection* con = (connection*)meter->connection;
+    static const unsigned char none[8] = {0};
+    if (memcmp(con->settings.sourceSystemTitle, none, 8) == 0) return -1;
+    memcpy(title, con->settings.sourceSystemTitle, 8);
+    return 0;
+}
+
+// Exported by frames.go
+extern void goTraceFrame(uintptr_t handle, unsigned char sent, unsigned char* data, uint32_t size);
+
+// Passes the data the connection sent or received to the meter's frame trace
+static void trace_frame(void* context, unsigned char sent, const unsigned char* data, uint32_t size) {
+    meter_t* meter = (meter_t*)context;
+    if (meter->trace_handle != 0 && size > 0) {
+        goTraceFrame(meter->trace_handle, sent, (unsigned char*)data, size);
+    }
+}
+
+// Exported by replay.go
+extern int goReplaySend(uintptr_t handle, unsigned char* data, uint32_t size);
+extern int goReplayReceive(uintptr_t handle, unsigned char* data, uint32_t size);
+
+static int replay_send(void* context, const unsigned char* data, uint32_t size) {
+    return goReplaySend(((meter_t*)context)->replay_handle, (unsigned char*)data, size);
+}
+
+static int replay_receive(void* context, unsigned char* data, uint32_t size) {
+    return goReplayReceive(((meter_t*)context)->replay_handle, data, size);
+}
+
+// send/recv through the connection's transport if it has one, else its socket
+static ssize_t conn_send(connection* con, const void* buf, size_t len) {
+    if (con->onSend) {
+        return con->onSend(con->transportContext, buf, len);
+    }
+    return unlocked_send(con->socket, buf, len);
+}
+
+static ssize_t conn_recv(connection* con, void* buf, size_t len) {
+    if (con->onReceive) {
+        return con->onReceive(con->transportContext, buf, len);
+    }
+    return unlocked_recv(con->socket, buf, len);
+}
+
+// Milliseconds since the Unix epoch
+static int64_t now_ms(void) {
+    struct timeval tv;
+    gettimeofday(&tv, NULL);
+    return (int64_t)tv.tv_sec * 1000 + tv.tv_usec / 1000;
+}
+
+// Time budget for the next I/O step: timeout_ms capped by the meter deadline.
+// Returns a value <= 0 when there is no time left.
+static int remaining_ms(meter_t* meter, int timeout_ms) {
+    if (meter->deadline_ms <= 0) return timeout_ms;
+    int64_t left = meter->deadline_ms - now_ms();
+    if (left <= 0) return 0;
+    return left < timeout_ms ? (int)left : timeout_ms;
+}
+
+static void set_socket_timeouts(int fd, int timeout_ms) {
+    struct timeval tv;
+    tv.tv_sec = timeout_ms / 1000;
+    tv.tv_usec = (timeout_ms % 1000) * 1000;
+    setsockopt(fd, SOL_SOCKET, SO_RCVTIMEO, (const char*)&tv, sizeof(tv));
+    setsockopt(fd, SOL_SOCKET, SO_SNDTIMEO, (const char*)&tv, sizeof(tv));
+}
+
+static void set_socket_fd(meter_t* meter, int fd) {
+    pthread_mutex_lock(&meter->io_lock);
+    meter->socket_fd = fd;
+    pthread_mutex_unlock(&meter->io_lock);
+}
+
+// Checks cancellation and the deadline before an operation and bounds the
+// socket timeouts so a blocked send/recv never outlives the deadline
+static int meter_begin_io(meter_t* meter) {
+    if (meter->aborted) {
+        return DLMS_ERROR_TYPE_COMMUNICATION_ERROR | ECANCELED;
+    }
+    int timeout = remaining_ms(meter, meter->connection_timeout);
+    if (timeout <= 0) {
+        return DLMS_ERROR_TYPE_COMMUNICATION_ERROR | ETIMEDOUT;
+    }
+    pthread_mutex_lock(&meter->io_lock);
+    if (meter->socket_fd != -1) {
+        set_socket_timeouts(meter->socket_fd, timeout);
+    }
+    pthread_mutex_unlock(&meter->io_lock);
+    return DLMS_ERROR_CODE_OK;
+}
+
+// Opens the TCP connection with a non-blocking connect so that it never waits
+// past the connection timeout or deadline and gives up as soon as
+// meter_abort() is called. Errors use the same encoding as com_makeConnect.
+static int open_socket(meter_t* meter, connection* con) {
+    char port[16];
+    snprintf(port, sizeof(port), "%d", meter->meter_port);
+
+    struct addrinfo hints;
+    struct addrinfo* addrs = NULL;
+    memset(&hints, 0, sizeof(hints));
+    hints.ai_family = AF_UNSPEC;
+    hints.ai_socktype = SOCK_STREAM;
+    gurux_leave();
+    int gai = getaddrinfo(meter->meter_ip, port, &hints, &addrs);
+    gurux_enter();
+    if (gai != 0 || !addrs) {
+        // Name resolution failed
+        return DLMS_ERROR_TYPE_COMMUNICATION_ERROR | EHOSTUNREACH;
+    }
+
+    int fd = socket(addrs->ai_family, addrs->ai_socktype, addrs->ai_protocol);
+    if (fd == -1) {
+        int err = errno;
+        freeaddrinfo(addrs);
+        return DLMS_ERROR_TYPE_COMMUNICATION_ERROR | err;
+    }
+    set_socket_fd(meter, fd);
+
+    int err = 0;
+    int flags = fcntl(fd, F_GETFL, 0);
+    fcntl(fd, F_SETFL, flags | O_NONBLOCK);
+    int ret = connect(fd, addrs->ai_addr, addrs->ai_addrlen);
+    if (ret == -1) {
+        err = errno;
+    }
+    freeaddrinfo(addrs);
+
+    if (ret == -1 && err == EINPROGRESS) {
+        int64_t started = now_ms();
+        err = 0;
+        for (;;) {
+            if (meter->aborted) {
+                err = ECANCELED;
+                break;
+            }
+            int left = remaining_ms(meter, meter->connection_timeout - (int)(now_ms() - started));
+            if (left <= 0) {
+                err = ETIMEDOUT;
+                break;
+            }
+            // Poll in short slices so an abort is noticed promptly
+            struct pollfd pfd = { .fd = fd, .events = POLLOUT };
+            gurux_leave();
+            int n = poll(&pfd, 1, left < 100 ? left : 100);
+            int poll_err = errno;
+            gurux_enter();
+            if (n < 0 && poll_err != EINTR) {
+                err = poll_err;
+                break;
+            }
+            if (n > 0) {
+                socklen_t len = sizeof(err);
+                getsockopt(fd, SOL_SOCKET, SO_ERROR, &err, &len);
+                break;
+            }
+        }
+    }
+    if (err == 0 && meter->aborted) {
+        err = ECANCELED;
+    }
+    if (err != 0) {
+        set_socket_fd(meter, -1);
+        close(fd);
+        return DLMS_ERROR_TYPE_COMMUNICATION_ERROR | err;
+    }
+
+    fcntl(fd, F_SETFL, flags);
+    con->socket = fd;
+    return DLMS_ERROR_CODE_OK;
+}
+
+int meter_set_deadline(meter_t* meter, int64_t deadline_ms) {
+    if (!meter) return -1;
+    meter->deadline_ms = deadline_ms;
+    return 0;
+}
+
+int meter_abort(meter_t* meter) {
+    if (!meter) return -1;
+    meter->aborted = 1;
+    // Shutting the socket down wakes up a connect/send/recv blocked in another thread
+    pthread_mutex_lock(&meter->io_lock);
+    if (meter->socket_fd != -1) {
+        shutdown(meter->socket_fd, SHUT_RDWR);
+    }
+    pthread_mutex_unlock(&meter->io_lock);
+    return 0;
+}
+
+int meter_clear_abort(meter_t* meter) {
+    if (!meter) return -1;
+    meter->aborted = 0;
+    return 0;
+}
+
+int64_t meter_connect_step_ms(meter_t* meter, int step) {
+    if (!meter || step < 0 || step >= METER_STEP_COUNT) return 0;
+    return meter->step_end_ms[step];
+}
+
+int meter_connect_failed_step(meter_t* meter) {
+    return meter ? meter->failed_step : -1;
+}
+
+// Sends one request and parses its reply, recording when the step finished
+// or that it failed
+static int connect_step(meter_t* meter, connection* con, int step,
+    int (*request)(dlmsSettings*, message*),
+    int (*parse)(dlmsSettings*, gxByteBuffer*)) {
+    message messages;
+    gxReplyData reply;
+    mes_init(&messages);
+    reply_init(&reply);
+    int ret = meter_begin_io(meter);
+    if (ret == DLMS_ERROR_CODE_OK &&
+        (ret = request(&con->settings, &messages)) == 0 &&
+        (ret = com_readDataBlock(con, &messages, &reply)) == 0) {
+        ret = parse(&con->settings, &reply.data);
+    }
+    mes_clear(&messages);
+    reply_clear(&reply);
+    meter->step_end_ms[step] = now_ms();
+    if (ret != DLMS_ERROR_CODE_OK) {
+        meter->failed_step = step;
+    }
+    return ret;
+}
+
+// com_initializeConnection for a socket, split into timed steps
+static int initialize_connection(meter_t* meter, connection* con) {
+    int ret = connect_step(meter, con, METER_STEP_LINK, cl_snrmRequest, cl_parseUAResponse);
+    if (ret != DLMS_ERROR_CODE_OK) return ret;
+
+    ret = connect_step(meter, con, METER_STEP_ASSOCIATION, cl_aarqRequest, cl_parseAAREResponse);
+    if (ret != DLMS_ERROR_CODE_OK) return ret;
+    if (con->settings.maxPduSize == 0xFFFF) {
+        con_initializeBuffers(con, con->settings.maxPduSize);
+    } else {
+        // Allocate 50 bytes more because some meters count this wrong and send few bytes too many
+        con_initializeBuffers(con, 50 + con->settings.maxPduSize);
+    }
+
+    if (con->settings.authentication > DLMS_AUTHENTICATION_LOW) {
+        return connect_step(meter, con, METER_STEP_AUTHENTICATION,
+            cl_getApplicationAssociationRequest, cl_parseApplicationAssociationResponse);
+    }
+    meter->step_end_ms[METER_STEP_AUTHENTICATION] = meter->step_end_ms[METER_STEP_ASSOCIATION];
+    return DLMS_ERROR_CODE_OK;
+}
+
+// Closes the socket and frees the connection without a release request
+static void close_connection(meter_t* meter) {
+    connection* con = (connection*)meter->connection;
+    set_socket_fd(meter, -1);
+    if (con->socket != -1) {
+        close(con->socket);
+        con->socket = -1;
+    }
+    con_close(con);
+    cl_clear(&con->settings);
+    free(con);
+    meter->connection = NULL;
+    meter->is_connected = 0;
+}
+
 int meter_connect(meter_t* meter) {
     if (!meter || !meter->meter_ip) {
         return -1; // Invalid meter configuration
@@ -329,6 +628,8 @@ int meter_connect(meter_t* meter) {
     if (meter->is_connected) {
         return 0; // Already connected
     }
+    memset(meter->step_end_ms, 0, sizeof(meter->step_end_ms));
+    meter->failed_step = -1;
     
     // Allocate connection structure
     connection* con = malloc(sizeof(connection));
@@ -338,6 +639,8 @@ int meter_connect(meter_t* meter) {
     
     // Initialize connection
     con_init(con, GX_TRACE_LEVEL_ERROR);
+    con->onFrame = trace_frame;
+    con->frameContext = meter;
     
     // Initialize client settings
     cl_init(&con->settings, 1, meter->client_address, meter->server_address,
@@ -378,9 +681,23 @@ int meter_connect(meter_t* meter) {
         }
     }
     
-    // Connect to meter
-    ret = com_makeConnect(con, meter->meter_ip, meter->meter_port, meter->connection_timeout);
+    // Connect to meter, or to its replay
+    if (meter->replay_handle != 0) {
+        con->onSend = replay_send;
+        con->onReceive = replay_receive;
+        con->transportContext = meter;
+        ret = DLMS_ERROR_CODE_OK;
+    } else {
+        ret = open_socket(meter, con);
+    }
+    if (ret == DLMS_ERROR_CODE_OK) {
+        meter->step_end_ms[METER_STEP_SOCKET] = now_ms();
+        ret = meter_begin_io(meter);
+    }
     if (ret != DLMS_ERROR_CODE_OK) {
+        meter->failed_step = meter->step_end_ms[METER_STEP_SOCKET] ? METER_STEP_LINK : METER_STEP_SOCKET;
+        meter->step_end_ms[meter->failed_step] = now_ms();
+        set_socket_fd(meter, -1);
         com_close(con);
         con_close(con);
         cl_clear(&con->settings);
@@ -389,8 +706,9 @@ int meter_connect(meter_t* meter) {
     }
     
     // Initialize connection
-    ret = com_initializeConnection(con);
+    ret = initialize_connection(meter, con);
     if (ret != 0) {
+        set_socket_fd(meter, -1);
         com_close(con);
         con_close(con);
         cl_clear(&con->settings);
@@ -412,6 +730,14 @@ int meter_disconnect(meter_t* meter) {
     
     connection* con = (connection*)meter->connection;
     
+    // Stop abort from touching the socket once it is being closed. After an
+    // abort the socket is already shut down, so skip the release request.
+    set_socket_fd(meter, -1);
+    if (meter->aborted && con->socket != -1) {
+        close(con->socket);
+        con->socket = -1;
+    }
+
     // Close connection
     com_close(con);
     con_close(con);
@@ -425,11 +751,52 @@ int meter_disconnect(meter_t* meter) {
     return 0;
 }
 
+int meter_release(meter_t* meter) {
+    if (!meter || !meter->is_connected || !meter->connection) {
+        return DLMS_ERROR_CODE_NOT_INITIALIZED;
+    }
+
+    connection* con = (connection*)meter->connection;
+    message msg;
+    gxReplyData reply;
+    mes_init(&msg);
+    reply_init(&reply);
+    int ret = meter_begin_io(meter);
+    if (ret == DLMS_ERROR_CODE_OK &&
+        (ret = cl_releaseRequest2(&con->settings, &msg,
+            con->settings.cipher.security != DLMS_SECURITY_NONE)) == 0 &&
+        (ret = com_readDataBlock(con, &msg, &reply)) == 0) {
+        mes_clear(&msg);
+        reply_clear(&reply);
+        if ((ret = cl_disconnectRequest(&con->settings, &msg)) == 0) {
+            ret = com_readDataBlock(con, &msg, &reply);
+        }
+    }
+    mes_clear(&msg);
+    reply_clear(&reply);
+
+    close_connection(meter);
+    return ret;
+}
+
 int meter_is_connected(meter_t* meter) {
     if (!meter) return 0;
     return meter->is_connected;
 }
 
+int meter_keep_alive(meter_t* meter) {
+    if (!meter || !meter->is_connected || !meter->connection) {
+        return DLMS_ERROR_CODE_NOT_INITIALIZED;
+    }
+
+    int ret = meter_begin_io(meter);
+    if (ret != DLMS_ERROR_CODE_OK) {
+        return ret;
+    }
+
+    return com_getKeepAlive((connection*)meter->connection);
+}
+
 dlms_result_t* meter_read_profile_generic(meter_t* meter, const char* obis_code) {
     if (!meter || !obis_code) {
         dlms_result_t* result = calloc(1, sizeof(dlms_result_t));
@@ -453,7 +820,12 @@ dlms_result_t* meter_read_profile_generic(meter_t* meter, const char* obis_code)
     if (!result) return NULL;
     
     connection* con = (connection*)meter->connection;
-    int ret;
+    int ret = meter_begin_io(meter);
+    if (ret != 0) {
+        result->error_code = ret;
+        result->error_message = safe_strdup(hlp_getErrorMessage(ret));
+        return result;
+    }
     
     // Create profile generic object
     gxProfileGeneric pg;
@@ -495,7 +867,15 @@ dlms_result_t* meter_read_profile_generic(meter_t* meter, const char* obis_code)
             goto cleanup_pg;
         }
         
-        // Extract column names from capture objects
+        result->column_classes = calloc(result->num_columns, sizeof(int));
+        result->column_attributes = calloc(result->num_columns, sizeof(int));
+        if (!result->column_classes || !result->column_attributes) {
+            result->error_code = -1;
+            result->error_message = safe_strdup("Memory allocation failed");
+            goto cleanup_pg;
+        }
+
+        // Extract column names, classes and attributes from capture objects
         for (int col = 0; col < result->num_columns; col++) {
             void* value = NULL;
             if (arr_getByIndex(&pg.captureObjects, col, &value) == 0 && value) {
@@ -504,6 +884,10 @@ dlms_result_t* meter_read_profile_generic(meter_t* meter, const char* obis_code)
                 char ln[25];
                 hlp_getLogicalNameToString(obj->logicalName, ln);
                 result->column_names[col] = safe_strdup(ln);
+                result->column_classes[col] = obj->objectType;
+                if (kv->value) {
+                    result->column_attributes[col] = ((gxTarget*)kv->value)->attributeIndex;
+                }
             } else {
                 char temp[32];
                 snprintf(temp, sizeof(temp), "Column_%d", col);
@@ -560,7 +944,10 @@ profile_generic_t* meter_read_profile_generic_object(meter_t* meter, const char*
     }
     
     connection* con = (connection*)meter->connection;
-    int ret;
+    int ret = meter_begin_io(meter);
+    if (ret != 0) {
+        return NULL;
+    }
     
     // Allocate and initialize profile generic object
     gxProfileGeneric* pg = malloc(sizeof(gxProfileGeneric));
@@ -692,6 +1079,13 @@ dlms_result_t* profile_generic_read_rows(meter_t* meter, profile_generic_t* pg_w
     dlms_result_t* result = calloc(1, sizeof(dlms_result_t));
     if (!result) return NULL;
     
+    ret = meter_begin_io(meter);
+    if (ret != 0) {
+        result->error_code = ret;
+        result->error_message = safe_strdup(hlp_getErrorMessage(ret));
+        return result;
+    }
+    
     // Clear any existing buffer data
     arr_clear(&pg->buffer);
     
@@ -797,6 +1191,8 @@ void dlms_result_free(dlms_result_t* result) {
         }
         free(result->column_names);
     }
+    free(result->column_classes);
+    free(result->column_attributes);
     
     if (result->data) {
         int total_cells = result->num_rows * result->num_columns;
@@ -826,6 +1222,22 @@ const char* dlms_result_get_column_name(dlms_result_t* result, int col) {
     return result->column_names[col];
 }
 
+int dlms_result_get_column_class(dlms_result_t* result, int col) {
+    if (!result || !result->column_classes || col < 0 || col >= result->num_columns) {
+        return 0;
+    }
+
+    return result->column_classes[col];
+}
+
+int dlms_result_get_column_attribute(dlms_result_t* result, int col) {
+    if (!result || !result->column_attributes || col < 0 || col >= result->num_columns) {
+        return 0;
+    }
+
+    return result->column_attributes[col];
+}
+
 // Helper function to convert access mode to string
 static char* access_mode_to_string(DLMS_ACCESS_MODE mode) {
     switch (mode) {
@@ -899,6 +1311,13 @@ association_view_t* meter_get_association_view(meter_t* meter) {
     association_view_t* result = calloc(1, sizeof(association_view_t));
     if (!result) return NULL;
     
+    ret = meter_begin_io(meter);
+    if (ret != 0) {
+        result->error_code = ret;
+        result->error_message = safe_strdup(hlp_getErrorMessage(ret));
+        return result;
+    }
+    
     // Get association view using the communication helper
     ret = com_getAssociationView(con, NULL);
     if (ret != 0) {
@@ -1033,59 +1452,39 @@ static int send_write_messages(meter_t* meter, message* messages) {
         return DLMS_ERROR_CODE_INVALID_PARAMETER;
     }
     
+    int ret = meter_begin_io(meter);
+    if (ret != DLMS_ERROR_CODE_OK) {
+        return ret;
+    }
+    
     connection* conn = (connection*)meter->connection;
     
     for (uint16_t pos = 0; pos < messages->size; pos++) {
         gxByteBuffer* bb = messages->data[pos];
         
-        // Print outgoing packet if debug is enabled
-        if (meter->debug_packets) {
-            printf("\n[DLMS WRITE DEBUG] Sending message %d/%d\n", pos + 1, messages->size);
-            print_packet_hex("OUTGOING", bb->data, bb->size);
-        }
-        
-        if (send(conn->socket, bb->data, bb->size, 0) < 0) {
-            if (meter->debug_packets) {
-                printf("[DLMS WRITE DEBUG] ERROR: Failed to send packet\n");
-            }
+        if (conn_send(conn, bb->data, bb->size) < 0) {
             return DLMS_ERROR_CODE_SEND_FAILED;
         }
-        
-        if (meter->debug_packets) {
-            printf("[DLMS WRITE DEBUG] Packet sent successfully, waiting for response...\n");
-        }
+        trace_frame(meter, 1, bb->data, bb->size);
         
         // Wait for response
         gxByteBuffer reply;
         bb_init(&reply);
         bb_capacity(&reply, 1024);
         
-        int bytes = recv(conn->socket, reply.data, reply.capacity, 0);
+        int bytes = conn_recv(conn, reply.data, reply.capacity);
         if (bytes <= 0) {
-            if (meter->debug_packets) {
-                printf("[DLMS WRITE DEBUG] ERROR: Failed to receive response (bytes=%d)\n", bytes);
-            }
             bb_clear(&reply);
             return DLMS_ERROR_CODE_RECEIVE_FAILED;
         }
         
         reply.size = bytes;
-        
-        // Print incoming packet if debug is enabled
-        if (meter->debug_packets) {
-            printf("[DLMS WRITE DEBUG] Received response:\n");
-            print_packet_hex("INCOMING", reply.data, reply.size);
-        }
+        trace_frame(meter, 0, reply.data, reply.size);
         
         gxReplyData replyData;
         reply_init(&replyData);
         
-        int ret = cl_getData(&conn->settings, &reply, &replyData);
-        
-        if (meter->debug_packets) {
-            printf("[DLMS WRITE DEBUG] cl_getData returned: %d (%s)\n", 
-                   ret, ret == DLMS_ERROR_CODE_OK ? "SUCCESS" : "ERROR");
-        }
+        ret = cl_getData(&conn->settings, &reply, &replyData);
         
         bb_clear(&reply);
         reply_clear(&replyData);
@@ -1095,10 +1494,6 @@ static int send_write_messages(meter_t* meter, message* messages) {
         }
     }
     
-    if (meter->debug_packets) {
-        printf("[DLMS WRITE DEBUG] All messages processed successfully\n\n");
-    }
-    
     return DLMS_ERROR_CODE_OK;
 }
 
@@ -1549,6 +1944,44 @@ int meter_write_obis_octet_string(meter_t* meter, const char* obis_code, const u
     return ret;
 } 
 
+// Write A-XDR encoded data to OBIS code
+int meter_write_obis_encoded(meter_t* meter, const char* obis_code, const unsigned char* data, int length, int object_type, int attribute_index) {
+    if (!meter || !meter->connection || !obis_code || !data || length <= 0) {
+        return DLMS_ERROR_CODE_INVALID_PARAMETER;
+    }
+    
+    unsigned char ln[6];
+    int ret = parse_obis_code(obis_code, ln);
+    if (ret != DLMS_ERROR_CODE_OK) {
+        return ret;
+    }
+    
+    connection* conn = (connection*)meter->connection;
+    
+    // With byteArray set, the write sends the variant's bytes as they are
+    dlmsVARIANT writeValue;
+    var_init(&writeValue);
+    
+    gxByteBuffer bb;
+    bb_init(&bb);
+    bb_set(&bb, data, length);
+    var_addOctetString(&writeValue, &bb);
+    
+    message messages;
+    mes_init(&messages);
+    
+    ret = cl_writeLN(&conn->settings, ln, object_type, attribute_index, &writeValue, 1, &messages);
+    if (ret == DLMS_ERROR_CODE_OK) {
+        ret = send_write_messages(meter, &messages);
+    }
+    
+    var_clear(&writeValue);
+    bb_clear(&bb);
+    mes_clear(&messages);
+    
+    return ret;
+}
+
 /*******************************************************************************
  * Method Invocation Functions
  ******************************************************************************/
@@ -1559,45 +1992,30 @@ static int send_method_request_and_get_reply(meter_t* meter, message* messages)
     }
 
     connection* conn = (connection*)meter->connection;
-    int ret = 0;
+    int ret = meter_begin_io(meter);
+    if (ret != DLMS_ERROR_CODE_OK) {
+        return ret;
+    }
 
     for (uint16_t pos = 0; pos < messages->size; pos++) {
         gxByteBuffer* bb = messages->data[pos];
 
-        if (meter->debug_packets) {
-            printf("\n[DLMS METHOD CALL DEBUG] Sending message %d/%d\n", pos + 1, messages->size);
-            print_packet_hex("OUTGOING (METHOD)", bb->data, bb->size);
-        }
-
-        if (send(conn->socket, bb->data, bb->size, 0) < 0) {
-            if (meter->debug_packets) {
-                printf("[DLMS METHOD CALL DEBUG] ERROR: Failed to send packet\n");
-            }
+        if (conn_send(conn, bb->data, bb->size) < 0) {
             return DLMS_ERROR_CODE_SEND_FAILED;
         }
-
-        if (meter->debug_packets) {
-            printf("[DLMS METHOD CALL DEBUG] Packet sent successfully, waiting for reply...\n");
-        }
+        trace_frame(meter, 1, bb->data, bb->size);
 
         gxByteBuffer reply;
         bb_init(&reply);
         bb_capacity(&reply, 1024);
 
-        int bytes = recv(conn->socket, reply.data, reply.capacity, 0);
+        int bytes = conn_recv(conn, reply.data, reply.capacity);
         if (bytes <= 0) {
-            if (meter->debug_packets) {
-                printf("[DLMS METHOD CALL DEBUG] ERROR: Failed to receive reply (bytes=%d)\n", bytes);
-            }
             bb_clear(&reply);
             return DLMS_ERROR_CODE_RECEIVE_FAILED;
         }
         reply.size = bytes;
-
-        if (meter->debug_packets) {
-            printf("[DLMS METHOD CALL DEBUG] Received reply:\n");
-            print_packet_hex("INCOMING (METHOD)", reply.data, reply.size);
-        }
+        trace_frame(meter, 0, reply.data, reply.size);
 
         gxReplyData replyData;
         reply_init(&replyData);
@@ -1623,9 +2041,6 @@ static int send_method_request_and_get_reply(meter_t* meter, message* messages)
         }
     }
 
-    if (meter->debug_packets) {
-        printf("[DLMS METHOD CALL DEBUG] All messages processed successfully\n\n");
-    }
 
     return DLMS_ERROR_CODE_OK;
 }
@@ -1700,4 +2115,112 @@ int meter_call_set_time(meter_t* meter, time_t timestamp)
     ret = send_method_request_and_get_reply(meter, &messages);
     mes_clear(&messages);
     return ret;
-} 
\ No newline at end of file
+} 
+
+/*******************************************************************************
+ * Attribute Read Functions
+ ******************************************************************************/
+
+static dlms_result_t* attribute_result_error(int error_code, const char* message) {
+    dlms_result_t* result = calloc(1, sizeof(dlms_result_t));
+    if (result) {
+        result->error_code = error_code;
+        result->error_message = safe_strdup(message);
+    }
+    return result;
+}
+
+// Hex of the A-XDR encoding of value, prefixed "Hex:", or NULL
+static char* variant_to_encoded(dlmsVARIANT* value) {
+    gxByteBuffer bb;
+    bb_init(&bb);
+    if (dlms_setData(&bb, value->vt, value) != DLMS_ERROR_CODE_OK) {
+        bb_clear(&bb);
+        return NULL;
+    }
+    char* hex = malloc(4 + 2 * bb.size + 1);
+    if (hex) {
+        strcpy(hex, "Hex:");
+        for (uint32_t i = 0; i < bb.size; ++i) {
+            snprintf(hex + 4 + 2 * i, 3, "%02X", bb.data[i]);
+        }
+    }
+    bb_clear(&bb);
+    return hex;
+}
+
+static dlms_result_t* read_attribute(meter_t* meter, const char* obis_code, int object_type, int attribute_index, int encoded);
+
+dlms_result_t* meter_read_attribute(meter_t* meter, const char* obis_code, int object_type, int attribute_index) {
+    return read_attribute(meter, obis_code, object_type, attribute_index, 0);
+}
+
+dlms_result_t* meter_read_attribute_encoded(meter_t* meter, const char* obis_code, int object_type, int attribute_index) {
+    return read_attribute(meter, obis_code, object_type, attribute_index, 1);
+}
+
+static dlms_result_t* read_attribute(meter_t* meter, const char* obis_code, int object_type, int attribute_index, int encoded) {
+    if (!meter || !obis_code || attribute_index <= 0) {
+        return attribute_result_error(-1, "Invalid meter configuration, OBIS code or attribute index");
+    }
+
+    if (!meter->is_connected || !meter->connection) {
+        return attribute_result_error(-2, "Meter not connected. Call meter_connect() first.");
+    }
+
+    unsigned char ln[6];
+    if (parse_obis_code(obis_code, ln) != DLMS_ERROR_CODE_OK) {
+        return attribute_result_error(DLMS_ERROR_CODE_INVALID_LOGICAL_NAME, "Invalid OBIS code");
+    }
+
+    dlms_result_t* result = calloc(1, sizeof(dlms_result_t));
+    if (!result) return NULL;
+
+    connection* con = (connection*)meter->connection;
+    message messages;
+    gxReplyData reply;
+    mes_init(&messages);
+    reply_init(&reply);
+
+    int ret = meter_begin_io(meter);
+    if (ret != DLMS_ERROR_CODE_OK) {
+        result->error_code = ret;
+        result->error_message = safe_strdup(hlp_getErrorMessage(ret));
+        goto cleanup_read;
+    }
+
+    ret = cl_readLN(&con->settings, ln, (DLMS_OBJECT_TYPE)object_type, (unsigned char)attribute_index, NULL, &messages);
+    if (ret == DLMS_ERROR_CODE_OK) {
+        ret = com_readDataBlock(con, &messages, &reply);
+    }
+    if (ret != DLMS_ERROR_CODE_OK) {
+        result->error_code = ret;
+        result->error_message = safe_strdup(hlp_getErrorMessage(ret));
+        goto cleanup_read;
+    }
+
+    result->num_rows = 1;
+    result->num_columns = 1;
+    result->column_names = calloc(1, sizeof(char*));
+    result->data = calloc(1, sizeof(char*));
+    if (!result->column_names || !result->data) {
+        result->error_code = -1;
+        result->error_message = safe_strdup("Memory allocation failed");
+        goto cleanup_read;
+    }
+    result->column_names[0] = safe_strdup(obis_code);
+    result->data[0] = encoded ? variant_to_encoded(&reply.dataValue) : variant_to_string(&reply.dataValue);
+    if (!result->data[0]) {
+        result->error_code = DLMS_ERROR_CODE_INVALID_PARAMETER;
+        result->error_message = safe_strdup("Failed to encode value");
+        goto cleanup_read;
+    }
+
+    result->error_code = 0;
+    result->error_message = safe_strdup("Success");
+
+cleanup_read:
+    mes_clear(&messages);
+    reply_clear(&reply);
+    return result;
+}
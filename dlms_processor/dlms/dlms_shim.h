@@ -3,6 +3,7 @@
 
 #include <stdint.h>
 #include <time.h>
+#include <pthread.h>
 
 #ifdef __cplusplus
 extern "C" {
@@ -22,14 +23,34 @@ typedef struct {
     int attribute_index;
     int max_entries;
     
-    // Debug settings
-    int debug_packets;  // Enable raw packet debugging
-    
     // Connection state (private - managed by shim)
     void* connection;  // Pointer to connection struct
     int is_connected;
+
+    // Cancellation and deadline state (private - managed by shim)
+    int64_t deadline_ms;       // Absolute deadline in Unix milliseconds, 0 = none
+    volatile int aborted;      // Set by meter_abort() to stop in-flight I/O
+    int socket_fd;             // Socket currently in use, -1 when none
+    pthread_mutex_t io_lock;   // Guards socket_fd against a concurrent abort and close
+
+    // Tracing state (private - managed by shim)
+    int64_t step_end_ms[4];    // Unix milliseconds each meter_connect step finished or failed, 0 if not reached
+    int failed_step;           // Step the last meter_connect failed at, -1 if none
+    uintptr_t trace_handle;    // Frame trace passed to goTraceFrame, 0 = none
+
+    // Replay state (private - managed by shim)
+    uintptr_t replay_handle;   // Replay passed to goReplaySend/goReplayReceive instead of a socket, 0 = none
 } meter_t;
 
+// Steps of meter_connect, in order
+enum {
+    METER_STEP_SOCKET = 0,     // TCP connect
+    METER_STEP_LINK,           // HDLC SNRM/UA; nothing is sent over the wrapper
+    METER_STEP_ASSOCIATION,    // AARQ/AARE
+    METER_STEP_AUTHENTICATION, // HLS challenge, when the authentication is above LOW
+    METER_STEP_COUNT
+};
+
 // Result structure for profile data
 typedef struct {
     char* error_message;
@@ -37,6 +58,8 @@ typedef struct {
     int num_rows;
     int num_columns;
     char** column_names;
+    int* column_classes;    // Interface class of each captured object, NULL if unknown
+    int* column_attributes; // Captured attribute of each column, NULL if unknown
     char** data; // Flattened array: data[row * num_columns + col]
 } dlms_result_t;
 
@@ -73,17 +96,64 @@ int meter_set_server_address(meter_t* meter, int address);
 int meter_set_attribute_index(meter_t* meter, int index);
 int meter_set_max_entries(meter_t* meter, int max_entries);
 
-// Debug functions
-int meter_set_debug_packets(meter_t* meter, int enable);
+// Frame tracing
+// While handle is set, every chunk of data sent to or received from the meter
+// is passed to goTraceFrame with it.
+int meter_set_frame_trace(meter_t* meter, uintptr_t handle);
+// Copies the system title the meter sent in its AARE; -1 if not connected
+// or it sent none
+int meter_server_system_title(meter_t* meter, unsigned char* title);
+
+// Replay
+// While handle is set, meter_connect opens no socket and the meter's data is
+// sent to and received from goReplaySend/goReplayReceive with it.
+int meter_set_replay(meter_t* meter, uintptr_t handle);
+
+// Cancellation and deadlines
+// meter_abort may be called from another thread while an operation is in
+// progress; the blocked connect/send/recv returns with a communication error.
+int meter_set_deadline(meter_t* meter, int64_t deadline_ms);
+int meter_abort(meter_t* meter);
+int meter_clear_abort(meter_t* meter);
+
+// Tracing
+// When the last meter_connect finished or failed step, one of METER_STEP_*;
+// 0 if it did not get that far. meter_connect_failed_step is the step it
+// failed at, -1 if it succeeded.
+int64_t meter_connect_step_ms(meter_t* meter, int step);
+int meter_connect_failed_step(meter_t* meter);
+
+// Concurrency
+// A meter_t is not thread-safe: use each one from one thread at a time
+// (meter_abort excepted). The Gurux library keeps ciphering and message
+// buffers in globals, so every operation on any meter must run inside
+// gurux_enter/gurux_leave. The lock is released while blocked on the socket,
+// so operations on different meters still overlap their I/O. It is a
+// semaphore rather than a mutex: enter and leave may run on different threads.
+void gurux_enter(void);
+void gurux_leave(void);
 
 // Connection management
 int meter_connect(meter_t* meter);
 int meter_disconnect(meter_t* meter);
+// Like meter_disconnect, but reports whether the meter acknowledged the release
+int meter_release(meter_t* meter);
 int meter_is_connected(meter_t* meter);
 
+// Sends a keep-alive on an open association so the meter does not time it out
+int meter_keep_alive(meter_t* meter);
+
 // Main function to read profile generic data from DLMS meter (requires connection)
 dlms_result_t* meter_read_profile_generic(meter_t* meter, const char* obis_code);
 
+// Read a single attribute of any COSEM object (requires connection).
+// The value is returned as a 1x1 result whose only column is the OBIS code.
+dlms_result_t* meter_read_attribute(meter_t* meter, const char* obis_code, int object_type, int attribute_index);
+
+// Read a single attribute as its A-XDR encoding in hex, prefixed "Hex:", for
+// values such as arrays and structures that have no string form
+dlms_result_t* meter_read_attribute_encoded(meter_t* meter, const char* obis_code, int object_type, int attribute_index);
+
 // New separated functions for profile generic operations
 profile_generic_t* meter_read_profile_generic_object(meter_t* meter, const char* obis_code);
 dlms_result_t* profile_generic_read_rows(meter_t* meter, profile_generic_t* pg, int index, int count);
@@ -98,6 +168,10 @@ const char* dlms_result_get_data(dlms_result_t* result, int row, int col);
 // Get column name
 const char* dlms_result_get_column_name(dlms_result_t* result, int col);
 
+// Get the interface class and captured attribute of a column, 0 if unknown
+int dlms_result_get_column_class(dlms_result_t* result, int col);
+int dlms_result_get_column_attribute(dlms_result_t* result, int col);
+
 // Write operations
 int meter_write_obis_int8(meter_t* meter, const char* obis_code, int8_t value, int object_type, int attribute_index);
 int meter_write_obis_int16(meter_t* meter, const char* obis_code, int16_t value, int object_type, int attribute_index);
@@ -111,6 +185,8 @@ int meter_write_obis_string(meter_t* meter, const char* obis_code, const char* v
 int meter_write_obis_datetime(meter_t* meter, const char* obis_code, time_t timestamp, int object_type, int attribute_index);
 int meter_write_obis_boolean(meter_t* meter, const char* obis_code, unsigned char value, int object_type, int attribute_index);
 int meter_write_obis_octet_string(meter_t* meter, const char* obis_code, const unsigned char* data, int length, int object_type, int attribute_index);
+// Write data that is already A-XDR encoded, such as an array of structures
+int meter_write_obis_encoded(meter_t* meter, const char* obis_code, const unsigned char* data, int length, int object_type, int attribute_index);
 
 /*******************************************************************************
  * Method Invocation Functions
@@ -0,0 +1,122 @@
+package dlms
+
+/*
+#include "errorcodes.h"
+*/
+import "C"
+import (
+	"context"
+	"dlmsprocessor/metrics"
+	"errors"
+	"fmt"
+	"strconv"
+	"syscall"
+)
+
+// Kinds of failure reported by the DLMS library, matched with errors.Is.
+// Every *Error with a known code wraps one of them.
+var (
+	ErrConnectionRefused   = errors.New("connection refused")
+	ErrHostUnreachable     = errors.New("host unreachable")
+	ErrTimeout             = errors.New("timeout")
+	ErrConnectionLost      = errors.New("connection lost")
+	ErrAuthentication      = errors.New("authentication failed")
+	ErrDecryption          = errors.New("decryption failed")
+	ErrAssociationRejected = errors.New("association rejected")
+	ErrObjectUnavailable   = errors.New("object unavailable")
+	ErrAccessDenied        = errors.New("access denied")
+	ErrTypeMismatch        = errors.New("type mismatch")
+	ErrTemporaryFailure    = errors.New("temporary failure")
+	ErrHardwareFault       = errors.New("hardware fault")
+	ErrInvalidResponse     = errors.New("invalid response")
+)
+
+// Error is a failure the DLMS library reported with an error code
+type Error struct {
+	Code    int    // from errorcodes.h; communication errors carry errno in the low bits
+	Kind    error  // one of the Err* kinds, nil if the code is not classified
+	Message string // the library's description, if it gave one
+}
+
+// NewError classifies a DLMS library error code
+func NewError(code int, message string) *Error {
+	return &Error{Code: code, Kind: errorKind(code), Message: message}
+}
+
+func (e *Error) Error() string {
+	s := "error code " + strconv.Itoa(e.Code)
+	if e.Kind != nil {
+		s = e.Kind.Error() + " (" + s + ")"
+	}
+	if e.Message != "" {
+		s += ": " + e.Message
+	}
+	return s
+}
+
+func (e *Error) Unwrap() error {
+	return e.Kind
+}
+
+// Errno is the system error of a communication failure, 0 for other codes
+func (e *Error) Errno() syscall.Errno {
+	if e.Code&C.DLMS_ERROR_TYPE_COMMUNICATION_ERROR == 0 {
+		return 0
+	}
+	return syscall.Errno(e.Code &^ C.DLMS_ERROR_TYPE_COMMUNICATION_ERROR)
+}
+
+// errorKind maps a code from errorcodes.h onto its kind
+func errorKind(code int) error {
+	if code&C.DLMS_ERROR_TYPE_COMMUNICATION_ERROR != 0 {
+		switch syscall.Errno(code &^ C.DLMS_ERROR_TYPE_COMMUNICATION_ERROR) {
+		case syscall.ETIMEDOUT, syscall.EAGAIN:
+			return ErrTimeout
+		case syscall.ECONNREFUSED:
+			return ErrConnectionRefused
+		case syscall.EHOSTUNREACH, syscall.ENETUNREACH:
+			return ErrHostUnreachable
+		case syscall.ECONNRESET, syscall.EPIPE, syscall.ENOTCONN:
+			return ErrConnectionLost
+		}
+		return nil
+	}
+
+	switch code {
+	case C.DLMS_ERROR_CODE_RECEIVE_FAILED, C.DLMS_ERROR_CODE_NOT_REPLY:
+		// Gurux reports a read that timed out as a failed receive
+		return ErrTimeout
+	case C.DLMS_ERROR_CODE_SEND_FAILED:
+		return ErrConnectionLost
+	case C.DLMS_ERROR_CODE_AUTHENTICATION_FAILURE, C.DLMS_ERROR_CODE_AUTHENTICATION_REQUIRED,
+		C.DLMS_ERROR_CODE_AUTHENTICATION_MECHANISM_NAME_NOT_RECOGNISED, C.DLMS_ERROR_CODE_AUTHENTICATION_MECHANISM_NAME_REQUIRED:
+		return ErrAuthentication
+	case C.DLMS_ERROR_CODE_INVALID_TAG, C.DLMS_ERROR_CODE_INVALID_DECIPHERING_ERROR, C.DLMS_ERROR_CODE_INVALID_SECURITY_SUITE:
+		return ErrDecryption
+	case C.DLMS_ERROR_CODE_REJECTED_PERMAMENT, C.DLMS_ERROR_CODE_REJECTED_TRANSIENT, C.DLMS_ERROR_CODE_NO_REASON_GIVEN,
+		C.DLMS_ERROR_CODE_APPLICATION_CONTEXT_NAME_NOT_SUPPORTED, C.DLMS_ERROR_CODE_INVOCATION_COUNTER_TOO_SMALL:
+		return ErrAssociationRejected
+	case C.DLMS_ERROR_CODE_UNDEFINED_OBJECT, C.DLMS_ERROR_CODE_UNAVAILABLE_OBJECT, C.DLMS_ERROR_CODE_INCONSISTENT_CLASS_OR_OBJECT:
+		return ErrObjectUnavailable
+	case C.DLMS_ERROR_CODE_READ_WRITE_DENIED, C.DLMS_ERROR_CODE_ACCESS_VIOLATED:
+		return ErrAccessDenied
+	case C.DLMS_ERROR_CODE_UNMATCH_TYPE:
+		return ErrTypeMismatch
+	case C.DLMS_ERROR_CODE_TEMPORARY_FAILURE:
+		return ErrTemporaryFailure
+	case C.DLMS_ERROR_CODE_HARDWARE_FAULT:
+		return ErrHardwareFault
+	case C.DLMS_ERROR_CODE_UNACCEPTABLE_FRAME, C.DLMS_ERROR_CODE_INVALID_RESPONSE, C.DLMS_ERROR_CODE_WRONG_CRC,
+		C.DLMS_ERROR_CODE_INVALID_FRAME_NUMBER, C.DLMS_ERROR_CODE_INVALID_INVOKE_ID, C.DLMS_ERROR_CODE_INVALID_DATA_FORMAT:
+		return ErrInvalidResponse
+	}
+	return nil
+}
+
+// codeError counts a failure the DLMS library reported with code and
+// returns it as an *Error wrapped in what was being done, then wraps it as
+// contextError does
+func codeError(ctx context.Context, code int, message, doing string) error {
+	metrics.Errors.WithLabelValues(strconv.Itoa(code)).Inc()
+	return contextError(ctx, fmt.Errorf("%s: %w", doing, NewError(code, message)))
+}
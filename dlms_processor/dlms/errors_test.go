@@ -0,0 +1,85 @@
+package dlms
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"syscall"
+	"testing"
+)
+
+// communicationError is how the shim reports a failed system call
+func communicationError(errno syscall.Errno) int {
+	return 0x20000000 | int(errno)
+}
+
+func TestErrorKinds(t *testing.T) {
+	tests := []struct {
+		code int
+		kind error
+	}{
+		{communicationError(syscall.ECONNREFUSED), ErrConnectionRefused},
+		{communicationError(syscall.EHOSTUNREACH), ErrHostUnreachable},
+		{communicationError(syscall.ETIMEDOUT), ErrTimeout},
+		{communicationError(syscall.EPIPE), ErrConnectionLost},
+		{253, ErrTimeout},             // DLMS_ERROR_CODE_RECEIVE_FAILED
+		{279, ErrAuthentication},      // DLMS_ERROR_CODE_AUTHENTICATION_FAILURE
+		{285, ErrDecryption},          // DLMS_ERROR_CODE_INVALID_DECIPHERING_ERROR
+		{273, ErrAssociationRejected}, // DLMS_ERROR_CODE_REJECTED_PERMAMENT
+		{3, ErrAccessDenied},          // DLMS_ERROR_CODE_READ_WRITE_DENIED
+		{11, ErrObjectUnavailable},    // DLMS_ERROR_CODE_UNAVAILABLE_OBJECT
+		{12, ErrTypeMismatch},         // DLMS_ERROR_CODE_UNMATCH_TYPE
+		{2, ErrTemporaryFailure},      // DLMS_ERROR_CODE_TEMPORARY_FAILURE
+		{1, ErrHardwareFault},         // DLMS_ERROR_CODE_HARDWARE_FAULT
+		{269, ErrInvalidResponse},     // DLMS_ERROR_CODE_WRONG_CRC
+		{258, nil},                    // DLMS_ERROR_CODE_INVALID_PARAMETER
+		{communicationError(syscall.EBADF), nil},
+	}
+
+	for _, tt := range tests {
+		err := fmt.Errorf("failed to connect to meter: %w", NewError(tt.code, ""))
+		var dlmsErr *Error
+		if !errors.As(err, &dlmsErr) || dlmsErr.Code != tt.code {
+			t.Errorf("%d: expected an *Error with the code, got %v", tt.code, err)
+			continue
+		}
+		if dlmsErr.Kind != tt.kind {
+			t.Errorf("%d: expected kind %v, got %v", tt.code, tt.kind, dlmsErr.Kind)
+		}
+		if tt.kind != nil && !errors.Is(err, tt.kind) {
+			t.Errorf("%d: expected errors.Is to match %v", tt.code, tt.kind)
+		}
+	}
+}
+
+func TestErrorMessage(t *testing.T) {
+	err := NewError(3, "Access Error : Device reports Read-Write denied.")
+	if got, want := err.Error(), "access denied (error code 3): Access Error : Device reports Read-Write denied."; got != want {
+		t.Errorf("Expected %q, got %q", want, got)
+	}
+	if got := NewError(258, "").Error(); got != "error code 258" {
+		t.Errorf("Expected an unclassified code alone, got %q", got)
+	}
+	if errno := NewError(communicationError(syscall.ECONNREFUSED), "").Errno(); errno != syscall.ECONNREFUSED {
+		t.Errorf("Expected ECONNREFUSED, got %v", errno)
+	}
+}
+
+func TestConnectErrorIsTyped(t *testing.T) {
+	ip, port := refusedPort(t)
+	client := NewMeterClient()
+	defer client.Close()
+	meter := testMeterConfig(ip, port)
+	if err := client.Configure(&meter); err != nil {
+		t.Fatal(err)
+	}
+
+	err := client.Connect(context.Background())
+	if !errors.Is(err, ErrConnectionRefused) {
+		t.Fatalf("Expected a refused connection, got %v", err)
+	}
+	if !strings.HasPrefix(err.Error(), "failed to connect to meter: connection refused") {
+		t.Errorf("Unexpected message %q", err)
+	}
+}
@@ -0,0 +1,574 @@
+package dlms
+
+/*
+#include "errorcodes.h"
+*/
+import "C"
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"math/rand/v2"
+	"reflect"
+	"slices"
+	"strconv"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// LatencyDistribution is how the latencies of fake meters spread around
+// their mean
+type LatencyDistribution string
+
+const (
+	LatencyFixed       LatencyDistribution = "fixed"
+	LatencyUniform     LatencyDistribution = "uniform"     // between Mean-Spread and Mean+Spread
+	LatencyNormal      LatencyDistribution = "normal"      // with Spread as the standard deviation
+	LatencyExponential LatencyDistribution = "exponential" // Spread is unused
+)
+
+// Latency is what every operation of a fake meter waits before it completes
+type Latency struct {
+	Distribution LatencyDistribution // fixed if empty
+	Mean         time.Duration
+	Spread       time.Duration
+}
+
+// Scenario is how fake meters behave. Their readings are pseudo-random but
+// depend only on the seed, the meter and the time, so that runs repeat.
+type Scenario struct {
+	Seed               int64
+	Latency            Latency
+	ConnectFailureRate float64          // fraction of connects that fail as if the meter did not answer
+	ReadFailureRate    float64          // fraction of other operations that fail with a temporary failure
+	Interval           time.Duration    // capture period of the block load profile, 30 minutes if zero
+	Now                func() time.Time // clock of the readings, time.Now if nil
+}
+
+// Validate checks that the scenario is usable
+func (s Scenario) Validate() error {
+	switch s.Latency.Distribution {
+	case "", LatencyFixed, LatencyUniform, LatencyNormal, LatencyExponential:
+	default:
+		return fmt.Errorf("unknown latency distribution %q", s.Latency.Distribution)
+	}
+	if s.Latency.Mean < 0 || s.Latency.Spread < 0 || s.Interval < 0 {
+		return errors.New("latencies and the interval cannot be negative")
+	}
+	if s.ConnectFailureRate < 0 || s.ConnectFailureRate > 1 || s.ReadFailureRate < 0 || s.ReadFailureRate > 1 {
+		return errors.New("failure rates must be between 0 and 1")
+	}
+	return nil
+}
+
+func (s Scenario) withDefaults() Scenario {
+	if s.Interval == 0 {
+		s.Interval = 30 * time.Minute
+	}
+	if s.Now == nil {
+		s.Now = time.Now
+	}
+	return s
+}
+
+// source is a pseudo-random source safe for concurrent use. It draws the
+// latencies and failures, which depend on the order of operations.
+type source struct {
+	mu sync.Mutex
+	r  *rand.Rand
+}
+
+func newSource(seed int64) *source {
+	return &source{r: rand.New(rand.NewPCG(uint64(seed), 0))}
+}
+
+func (s *source) draw(f func(r *rand.Rand) float64) float64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return f(s.r)
+}
+
+// FakeMeters creates FakeMeters that play one scenario, drawing their
+// latencies and failures from one source
+type FakeMeters struct {
+	scenario Scenario
+	source   *source
+}
+
+// NewFakeMeters returns a factory of meters playing scenario
+func NewFakeMeters(scenario Scenario) (*FakeMeters, error) {
+	if err := scenario.Validate(); err != nil {
+		return nil, err
+	}
+	return &FakeMeters{scenario: scenario.withDefaults(), source: newSource(scenario.Seed)}, nil
+}
+
+// NewMeter returns a fake meter for config
+func (f *FakeMeters) NewMeter(config RealMeter, _ *SessionPool) (Meter, error) {
+	return newFakeMeter(config, f.scenario, f.source)
+}
+
+// FakeMeter is a Meter that answers from a Scenario without any I/O
+type FakeMeter struct {
+	id       string
+	name     string // logical device name
+	serial   string
+	scenario Scenario
+	source   *source
+}
+
+// NewFakeMeter returns a meter playing scenario for config, whose meter ID,
+// or address if it has none, tells it from other meters
+func NewFakeMeter(config RealMeter, scenario Scenario) (*FakeMeter, error) {
+	if err := scenario.Validate(); err != nil {
+		return nil, err
+	}
+	return newFakeMeter(config, scenario.withDefaults(), newSource(scenario.Seed))
+}
+
+func newFakeMeter(config RealMeter, scenario Scenario, source *source) (*FakeMeter, error) {
+	if config.MeterIP == "" {
+		return nil, errors.New("meter IP is required")
+	}
+	m := &FakeMeter{
+		id:       config.MeterID,
+		name:     config.LogicalDeviceName,
+		serial:   config.SerialNumber,
+		scenario: scenario,
+		source:   source,
+	}
+	if m.id == "" {
+		m.id = fmt.Sprintf("%s:%d", config.MeterIP, config.MeterPort)
+	}
+	if m.name == "" {
+		m.name = fmt.Sprintf("FAK%013d", binary.BigEndian.Uint64(m.hash("name", 0))%1e13)
+	}
+	if m.serial == "" {
+		m.serial = fmt.Sprintf("%08d", binary.BigEndian.Uint64(m.hash("serial", 0))%1e8)
+	}
+	return m, nil
+}
+
+// hash returns the hash of the scenario's seed, the meter, what is read and k
+func (m *FakeMeter) hash(what string, k int64) []byte {
+	h := fnv.New64a()
+	var b [8]byte
+	binary.BigEndian.PutUint64(b[:], uint64(m.scenario.Seed))
+	h.Write(b[:])
+	h.Write([]byte(m.id))
+	h.Write([]byte(what))
+	binary.BigEndian.PutUint64(b[:], uint64(k))
+	h.Write(b[:])
+	return h.Sum(nil)
+}
+
+// noise returns a number in [0, 1) that depends only on the scenario's
+// seed, the meter, what is read and k
+func (m *FakeMeter) noise(what string, k int64) float64 {
+	return float64(binary.BigEndian.Uint64(m.hash(what, k))>>11) / (1 << 53)
+}
+
+// operate waits the operation's latency, then fails it at rate with err
+func (m *FakeMeter) operate(ctx context.Context, rate float64, err func() error) error {
+	if d := m.latency(); d > 0 {
+		timer := time.NewTimer(d)
+		defer timer.Stop()
+		select {
+		case <-ctx.Done():
+		case <-timer.C:
+		}
+	}
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return ctxErr
+	}
+	if rate > 0 && m.source.draw((*rand.Rand).Float64) < rate {
+		return err()
+	}
+	return nil
+}
+
+// latency draws the latency of one operation
+func (m *FakeMeter) latency() time.Duration {
+	l := m.scenario.Latency
+	var d float64
+	switch l.Distribution {
+	case LatencyUniform:
+		d = float64(l.Mean) + (2*m.source.draw((*rand.Rand).Float64)-1)*float64(l.Spread)
+	case LatencyNormal:
+		d = float64(l.Mean) + m.source.draw((*rand.Rand).NormFloat64)*float64(l.Spread)
+	case LatencyExponential:
+		d = m.source.draw((*rand.Rand).ExpFloat64) * float64(l.Mean)
+	default:
+		d = float64(l.Mean)
+	}
+	return time.Duration(max(d, 0))
+}
+
+// read runs a read-like operation
+func (m *FakeMeter) read(ctx context.Context) error {
+	return m.operate(ctx, m.scenario.ReadFailureRate, func() error {
+		return codeError(ctx, C.DLMS_ERROR_CODE_TEMPORARY_FAILURE, "simulated failure", "failed to read from meter")
+	})
+}
+
+func (m *FakeMeter) Connect(ctx context.Context) error {
+	return m.operate(ctx, m.scenario.ConnectFailureRate, func() error {
+		return codeError(ctx, C.DLMS_ERROR_TYPE_COMMUNICATION_ERROR|int(syscall.ETIMEDOUT), "simulated failure", "failed to connect to meter")
+	})
+}
+
+func (m *FakeMeter) Close() error {
+	return nil
+}
+
+// The meter's load: its mean power in W, which each interval's varies
+// around, and its power factor
+const (
+	fakeMinLoad     = 300.0
+	fakeLoadRange   = 1200.0
+	fakePowerFactor = 0.96
+)
+
+// meanPower is the meter's mean active power in W
+func (m *FakeMeter) meanPower() float64 {
+	return fakeMinLoad + fakeLoadRange*m.noise("load", 0)
+}
+
+// interval returns the index of the interval ending at or before t
+func (m *FakeMeter) interval(t time.Time) int64 {
+	return t.Unix() / int64(m.scenario.Interval/time.Second)
+}
+
+// intervalEnd is when interval k ends
+func (m *FakeMeter) intervalEnd(k int64) time.Time {
+	return time.Unix(k*int64(m.scenario.Interval/time.Second), 0).UTC()
+}
+
+// cumulativeWh is the imported energy at the end of interval k. It grows
+// by the mean energy of an interval, give or take 40%, so that it never
+// falls.
+func (m *FakeMeter) cumulativeWh(k int64) float64 {
+	mean := m.meanPower() * m.scenario.Interval.Hours()
+	return round(float64(k)*mean+0.8*mean*(m.noise("energy", k)-0.5), 1)
+}
+
+// voltage is the average voltage over interval k
+func (m *FakeMeter) voltage(k int64) float64 {
+	return round(230+12*(m.noise("voltage", k)-0.5), 1)
+}
+
+func round(v float64, digits int) float64 {
+	p := math.Pow10(digits)
+	return math.Round(v*p) / p
+}
+
+// fakeTime formats t as the meters' clocks are read
+func fakeTime(t time.Time) string {
+	return t.UTC().Format(dateTimeLayout)
+}
+
+// blockLoad is the block load profile entry of interval k
+func (m *FakeMeter) blockLoad(k int64) BlockLoadProfile {
+	wh := round(m.cumulativeWh(k)-m.cumulativeWh(k-1), 1)
+	voltage := m.voltage(k)
+	return BlockLoadProfile{
+		DateTime:             fakeTime(m.intervalEnd(k)),
+		AverageVoltage:       voltage,
+		BlockEnergyWhImport:  wh,
+		BlockEnergyVAhImport: round(wh/fakePowerFactor, 1),
+		AverageCurrent:       round(wh/m.scenario.Interval.Hours()/voltage/fakePowerFactor, 2),
+	}
+}
+
+// BlockLoadSeries returns the block load profile entries of the intervals
+// ending after from and up to to
+func (m *FakeMeter) BlockLoadSeries(from, to time.Time) []BlockLoadProfile {
+	var series []BlockLoadProfile
+	for k := m.interval(from) + 1; k <= m.interval(to); k++ {
+		series = append(series, m.blockLoad(k))
+	}
+	return series
+}
+
+func (m *FakeMeter) GetOBIS(ctx context.Context, obis string) (string, error) {
+	if err := m.read(ctx); err != nil {
+		return "", err
+	}
+	return obis, nil
+}
+
+func (m *FakeMeter) GetBlockLoadProfile(ctx context.Context) (*BlockLoadProfile, error) {
+	if err := m.read(ctx); err != nil {
+		return nil, err
+	}
+	entry := m.blockLoad(m.interval(m.scenario.Now()))
+	return &entry, nil
+}
+
+func (m *FakeMeter) GetDailyLoadProfile(ctx context.Context) (*DailyLoadProfile, error) {
+	if err := m.read(ctx); err != nil {
+		return nil, err
+	}
+	day := m.scenario.Now().UTC().Truncate(24 * time.Hour)
+	wh := m.cumulativeWh(m.interval(day))
+	return &DailyLoadProfile{
+		DateTime:                  fakeTime(day),
+		CumulativeEnergyWhImport:  wh,
+		CumulativeEnergyVAhImport: round(wh/fakePowerFactor, 1),
+	}, nil
+}
+
+func (m *FakeMeter) GetBillingDataProfile(ctx context.Context) (*BillingDataProfile, error) {
+	if err := m.read(ctx); err != nil {
+		return nil, err
+	}
+	now := m.scenario.Now().UTC()
+	month := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	wh := m.cumulativeWh(m.interval(month))
+	vah := round(wh/fakePowerFactor, 1)
+	// The month's maximum demand, at a time of the month that depends on the meter
+	demand := round(1.4*m.meanPower(), 1)
+	previous := month.AddDate(0, -1, 0)
+	offset := time.Duration(m.noise("demand", month.Unix()) * float64(month.Sub(previous)))
+	demandAt := previous.Add(offset).Truncate(m.scenario.Interval)
+
+	profile := &BillingDataProfile{
+		BillingDate:               fakeTime(month),
+		AveragePFForBillingPeriod: fakePowerFactor,
+		CumEnergyWhImport:         wh,
+		CumEnergyWhTZ1:            round(0.2*wh, 1),
+		CumEnergyWhTZ2:            round(0.3*wh, 1),
+		CumEnergyWhTZ3:            round(0.35*wh, 1),
+		CumEnergyWhTZ4:            round(wh-round(0.2*wh, 1)-round(0.3*wh, 1)-round(0.35*wh, 1), 1),
+		CumEnergyVAhImport:        vah,
+		CumEnergyVAhTZ1:           round(0.2*vah, 1),
+		CumEnergyVAhTZ2:           round(0.3*vah, 1),
+		CumEnergyVAhTZ3:           round(0.35*vah, 1),
+		CumEnergyVAhTZ4:           round(vah-round(0.2*vah, 1)-round(0.3*vah, 1)-round(0.35*vah, 1), 1),
+		MDW:                       demand,
+		MDWDateTime:               fakeTime(demandAt),
+		MDVA:                      round(demand/fakePowerFactor, 1),
+		MDVADateTime:              fakeTime(demandAt),
+		BillingPowerOnDuration:    month.Sub(month.AddDate(0, -1, 0)).Hours(),
+	}
+	// The zones are six hour bands of the day, and the maximum demand is in
+	// the band of its time
+	for i, zone := range [][2]float64{
+		{profile.CumEnergyWhTZ1, profile.CumEnergyVAhTZ1},
+		{profile.CumEnergyWhTZ2, profile.CumEnergyVAhTZ2},
+		{profile.CumEnergyWhTZ3, profile.CumEnergyVAhTZ3},
+		{profile.CumEnergyWhTZ4, profile.CumEnergyVAhTZ4},
+	} {
+		z := TariffZone{Zone: i + 1, CumEnergyWh: zone[0], CumEnergyVAh: zone[1]}
+		if demandAt.Hour()/6 == i {
+			z.MDW, z.MDWDateTime = profile.MDW, profile.MDWDateTime
+			z.MDVA, z.MDVADateTime = profile.MDVA, profile.MDVADateTime
+		}
+		profile.TariffZones = append(profile.TariffZones, z)
+	}
+	return profile, nil
+}
+
+// instantaneous is the instantaneous profile at now, which moves with the
+// interval it falls in
+func (m *FakeMeter) instantaneous(now time.Time) InstantaneousProfile {
+	k := m.interval(now) + 1
+	entry := m.blockLoad(k)
+	power := round(entry.BlockEnergyWhImport/m.scenario.Interval.Hours(), 1)
+	return InstantaneousProfile{
+		DateTime:          fakeTime(now),
+		Voltage:           entry.AverageVoltage,
+		PhaseCurrent:      entry.AverageCurrent,
+		NeutralCurrent:    entry.AverageCurrent,
+		SignedPowerFactor: fakePowerFactor,
+		Frequency:         round(50+0.1*(m.noise("frequency", k)-0.5), 2),
+		ApparentPower:     round(power/fakePowerFactor, 1),
+		ActivePower:       power,
+		CumEnergyWh:       m.cumulativeWh(k - 1),
+		CumEnergyVAh:      round(m.cumulativeWh(k-1)/fakePowerFactor, 1),
+	}
+}
+
+func (m *FakeMeter) GetInstantaneousProfile(ctx context.Context) (*InstantaneousProfile, error) {
+	if err := m.read(ctx); err != nil {
+		return nil, err
+	}
+	entry := m.instantaneous(m.scenario.Now().UTC())
+	return &entry, nil
+}
+
+// nameplate is the meter's nameplate, the same on every read
+func (m *FakeMeter) nameplate() NameplateProfile {
+	return NameplateProfile{
+		SerialNumber:      m.serial,
+		ManufacturerName:  "FAKE METERS",
+		FirmwareVersion:   "FAKE-1.0.0",
+		MeterType:         5, // single phase, whole current
+		Category:          "C1",
+		CurrentRating:     "5-30A",
+		YearOfManufacture: uint16(2015 + m.noise("year", 0)*10),
+	}
+}
+
+func (m *FakeMeter) GetNameplate(ctx context.Context) (*NameplateProfile, error) {
+	if err := m.read(ctx); err != nil {
+		return nil, err
+	}
+	nameplate := m.nameplate()
+	return &nameplate, nil
+}
+
+// ReadProfile reads a defined profile from the profiles the fake meter
+// knows, laid out as their Go types are; other profiles are unavailable
+func (m *FakeMeter) ReadProfile(ctx context.Context, def ProfileDefinition) (*ProfileData, error) {
+	var entry any
+	var err error
+	switch def.OBIS {
+	case "1.0.99.1.0.255":
+		entry, err = m.GetBlockLoadProfile(ctx)
+	case "1.0.99.2.0.255":
+		entry, err = m.GetDailyLoadProfile(ctx)
+	case "0.0.98.1.0.255":
+		entry, err = m.GetBillingDataProfile(ctx)
+	case "1.0.94.7.0.255":
+		entry, err = m.GetInstantaneousProfile(ctx)
+	case NameplateProfileOBIS:
+		entry, err = m.GetNameplate(ctx)
+	default:
+		if err := m.read(ctx); err != nil {
+			return nil, err
+		}
+		return nil, codeError(ctx, C.DLMS_ERROR_CODE_UNDEFINED_OBJECT, "", "failed to read profile "+def.OBIS)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return mapDefinedProfile(taggedResult(entry), def), nil
+}
+
+// taggedResult lays out a profile entry as the row a meter would return,
+// one column per field with an obis tag
+func taggedResult(entry any) *DLMSResult {
+	v := reflect.Indirect(reflect.ValueOf(entry))
+	result := &DLMSResult{NumRows: 1, Data: [][]string{{}}}
+	for i := range v.NumField() {
+		tag := v.Type().Field(i).Tag
+		obis := tag.Get("obis")
+		attribute, err := Attribute(tag.Get("attribute")).Index(0)
+		if obis == "" || err != nil {
+			continue
+		}
+		if _, ok := result.column(obis, Attribute(strconv.Itoa(attribute))); ok {
+			continue
+		}
+		result.ColumnNames = append(result.ColumnNames, obis)
+		result.ColumnAttributes = append(result.ColumnAttributes, attribute)
+		result.Data[0] = append(result.Data[0], fmt.Sprint(v.Field(i).Interface()))
+	}
+	result.NumColumns = len(result.ColumnNames)
+	return result
+}
+
+// ReadAttribute reads the value attribute of the clock, the logical device
+// name, the nameplate objects and the instantaneous registers; other objects
+// are unavailable
+func (m *FakeMeter) ReadAttribute(ctx context.Context, obis string, objectType, attributeIndex int) (string, error) {
+	if err := m.read(ctx); err != nil {
+		return "", err
+	}
+	now := m.scenario.Now().UTC()
+	i := m.instantaneous(now)
+	n := m.nameplate()
+	values := map[string]string{
+		ClockOBIS:             fakeTime(now),
+		LogicalDeviceNameOBIS: m.name,
+		"0.0.96.1.0.255":      n.SerialNumber,
+		"0.0.96.1.1.255":      n.ManufacturerName,
+		FirmwareVersionOBIS:   n.FirmwareVersion,
+		"0.0.94.91.9.255":     fmt.Sprint(n.MeterType),
+		"0.0.94.91.11.255":    n.Category,
+		"0.0.94.91.12.255":    n.CurrentRating,
+		"0.0.96.1.4.255":      fmt.Sprint(n.YearOfManufacture),
+		"1.0.12.7.0.255":      fmt.Sprint(i.Voltage),
+		"1.0.11.7.0.255":      fmt.Sprint(i.PhaseCurrent),
+		"1.0.91.7.0.255":      fmt.Sprint(i.NeutralCurrent),
+		"1.0.13.7.0.255":      fmt.Sprint(i.SignedPowerFactor),
+		"1.0.14.7.0.255":      fmt.Sprint(i.Frequency),
+		"1.0.9.7.0.255":       fmt.Sprint(i.ApparentPower),
+		"1.0.1.7.0.255":       fmt.Sprint(i.ActivePower),
+		"1.0.1.8.0.255":       fmt.Sprint(i.CumEnergyWh),
+		"1.0.9.8.0.255":       fmt.Sprint(i.CumEnergyVAh),
+	}
+	value, ok := values[obis]
+	if !ok || attributeIndex != 2 {
+		return "", codeError(ctx, C.DLMS_ERROR_CODE_UNDEFINED_OBJECT, "", fmt.Sprintf("failed to read %s", obis))
+	}
+	return value, nil
+}
+
+func (m *FakeMeter) WriteAttribute(ctx context.Context, obis string, objectType, attributeIndex int, value any) error {
+	return m.read(ctx)
+}
+
+func (m *FakeMeter) SetClock(ctx context.Context, clock string) error {
+	if _, err := time.Parse(time.RFC3339, clock); err != nil {
+		return fmt.Errorf("invalid clock %q: %w", clock, err)
+	}
+	return m.read(ctx)
+}
+
+// fakeCalendar is the fake's tariff: every day switches between four six
+// hour zones, the zones its billing profile reports
+var fakeCalendar = Calendar{
+	Name:         "FAKE",
+	Seasons:      []Season{{Name: "ALL", Start: CalendarDate{Month: 1, Day: 1}, WeekProfile: "WEEK"}},
+	WeekProfiles: []WeekProfile{{Name: "WEEK", Days: [7]uint8{1, 1, 1, 1, 1, 1, 1}}},
+	DayProfiles: []DayProfile{{ID: 1, Actions: []DayAction{
+		{Start: 0, Script: TariffScriptTableOBIS, Selector: 1},
+		{Start: 6 * time.Hour, Script: TariffScriptTableOBIS, Selector: 2},
+		{Start: 12 * time.Hour, Script: TariffScriptTableOBIS, Selector: 3},
+		{Start: 18 * time.Hour, Script: TariffScriptTableOBIS, Selector: 4},
+	}}},
+}
+
+func (m *FakeMeter) ReadTariff(ctx context.Context) (*Tariff, error) {
+	if err := m.read(ctx); err != nil {
+		return nil, err
+	}
+	return &Tariff{Active: fakeCalendar, Passive: fakeCalendar}, nil
+}
+
+// ProgramTariff answers with the tariff the meter would read back, which
+// the fake does not keep
+func (m *FakeMeter) ProgramTariff(ctx context.Context, program TariffProgram) (*Tariff, error) {
+	if err := program.Validate(); err != nil {
+		return nil, err
+	}
+	if err := m.read(ctx); err != nil {
+		return nil, err
+	}
+	t := &Tariff{Active: fakeCalendar, Passive: program.Calendar, ActivationTime: program.ActivationTime.Truncate(time.Second)}
+	if program.ActivationTime.IsZero() {
+		t.Active = program.Calendar
+	}
+	if program.SpecialDays != nil {
+		t.SpecialDays = slices.Clone(program.SpecialDays)
+	}
+	return t, nil
+}
+
+func (m *FakeMeter) Invoke(ctx context.Context, obis string, objectType, methodIndex int, data Encoded) error {
+	return m.read(ctx)
+}
+
+// FOTA fails as RealMeter's does, once the meter answers
+func (m *FakeMeter) FOTA(ctx context.Context) error {
+	if err := m.read(ctx); err != nil {
+		return err
+	}
+	return fmt.Errorf("firmware upgrade: %w", ErrNotSupported)
+}
@@ -0,0 +1,90 @@
+package dlms
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestFakeMeterReadingsAreDeterministic(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 10, 0, 0, time.UTC)
+	scenario := Scenario{Seed: 7, Now: func() time.Time { return now }}
+	meter := func(id string) *FakeMeter {
+		m, err := NewFakeMeter(RealMeter{MeterID: id, MeterIP: "fd00::1", MeterPort: 4059}, scenario)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return m
+	}
+	ctx := context.Background()
+
+	a, err := meter("meter-a").GetBlockLoadProfile(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	again, _ := meter("meter-a").GetBlockLoadProfile(ctx)
+	other, _ := meter("meter-b").GetBlockLoadProfile(ctx)
+	if *a != *again {
+		t.Errorf("Expected the same meter to read the same, got %+v and %+v", a, again)
+	}
+	if *a == *other {
+		t.Errorf("Expected other meters to read otherwise, both read %+v", a)
+	}
+	if a.DateTime != "03/01/2026 12:00:00 UTC+00:00" {
+		t.Errorf("Expected the interval ending at 12:00, got %q", a.DateTime)
+	}
+
+	// The series ends with the entry read, and its cumulative energy only grows
+	m := meter("meter-a")
+	series := m.BlockLoadSeries(now.Add(-24*time.Hour), now)
+	if len(series) != 48 || series[47] != *a {
+		t.Fatalf("Expected 48 entries ending with %+v, got %d", a, len(series))
+	}
+	for i, entry := range series {
+		if entry.BlockEnergyWhImport <= 0 || entry.AverageVoltage < 224 || entry.AverageVoltage > 236 {
+			t.Errorf("Unexpected entry %d: %+v", i, entry)
+		}
+	}
+	if value, err := m.ReadAttribute(ctx, ClockOBIS, ObjectTypeClock, 2); err != nil || value != "03/01/2026 12:10:00 UTC+00:00" {
+		t.Errorf("Expected the scenario's clock, got %q, %v", value, err)
+	}
+	if n, err := m.GetNameplate(ctx); err != nil || n.SerialNumber == "" || n.YearOfManufacture < 2015 {
+		t.Errorf("Expected a nameplate, got %+v, %v", n, err)
+	} else if serial, _ := m.ReadAttribute(ctx, "0.0.96.1.0.255", ObjectTypeData, 2); serial != n.SerialNumber {
+		t.Errorf("Expected the serial number object to match the nameplate's %q, got %q", n.SerialNumber, serial)
+	}
+	if _, err := m.ReadAttribute(ctx, "0.0.96.1.9.255", ObjectTypeData, 2); !errors.Is(err, ErrObjectUnavailable) {
+		t.Errorf("Expected an unknown object to be unavailable, got %v", err)
+	}
+}
+
+func TestFakeMeterFailuresAndLatency(t *testing.T) {
+	meters, err := NewFakeMeters(Scenario{ConnectFailureRate: 1, Latency: Latency{Mean: time.Hour}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	m, err := meters.NewMeter(RealMeter{MeterIP: "fd00::1"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := m.Connect(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected the latency to end with the context, got %v", err)
+	}
+
+	meters, _ = NewFakeMeters(Scenario{ConnectFailureRate: 1, ReadFailureRate: 1})
+	m, _ = meters.NewMeter(RealMeter{MeterIP: "fd00::1"}, nil)
+	if err := m.Connect(context.Background()); !errors.Is(err, ErrTimeout) {
+		t.Errorf("Expected the connect to time out, got %v", err)
+	}
+	if _, err := m.GetInstantaneousProfile(context.Background()); !errors.Is(err, ErrTemporaryFailure) {
+		t.Errorf("Expected the read to fail temporarily, got %v", err)
+	}
+
+	if _, err := NewFakeMeters(Scenario{Latency: Latency{Distribution: "pareto"}}); err == nil {
+		t.Error("Expected an unknown latency distribution to be refused")
+	}
+}
@@ -0,0 +1,440 @@
+package dlms
+
+/*
+#include "dlms_shim.h"
+*/
+import "C"
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"runtime/cgo"
+	"sync"
+	"time"
+	"unsafe"
+)
+
+// Direction of a traced frame
+type Direction int
+
+const (
+	Sent     Direction = iota // from the processor to the meter
+	Received                  // from the meter to the processor
+)
+
+func (d Direction) String() string {
+	if d == Sent {
+		return "sent"
+	}
+	return "received"
+}
+
+// Frame is one wrapper frame exchanged with a meter
+type Frame struct {
+	Time      time.Time
+	Direction Direction
+	Data      []byte // as sent or received, wrapper header included
+	APDU      []byte // the plaintext of a ciphered APDU, nil if it is not ciphered or the keys are not known
+}
+
+// maxTraceBytes bounds the data one FrameTrace keeps; later frames are dropped
+const maxTraceBytes = 4 << 20
+
+// wrapperHeaderSize is the version, source port, destination port and length
+// of a wrapper frame
+const wrapperHeaderSize = 8
+
+// FrameTrace records the frames of every meter call made with a context it
+// is attached to. It is safe for concurrent use.
+type FrameTrace struct {
+	mu        sync.Mutex
+	frames    []Frame
+	pending   [2][]byte // data of a frame not yet complete, per direction
+	size      int
+	truncated bool
+
+	// Where the frames went and how to decrypt them, learned from the
+	// meter client after each call
+	meterIP     string
+	meterPort   int
+	clientTitle []byte
+	serverTitle []byte
+	blockKey    []byte
+	authKey     []byte
+}
+
+// NewFrameTrace returns an empty trace
+func NewFrameTrace() *FrameTrace {
+	return &FrameTrace{}
+}
+
+type frameTraceKey struct{}
+
+// WithFrameTrace returns a context whose meter calls are recorded in t
+func WithFrameTrace(ctx context.Context, t *FrameTrace) context.Context {
+	return context.WithValue(ctx, frameTraceKey{}, t)
+}
+
+func frameTraceFrom(ctx context.Context) *FrameTrace {
+	t, _ := ctx.Value(frameTraceKey{}).(*FrameTrace)
+	return t
+}
+
+//export goTraceFrame
+func goTraceFrame(handle C.uintptr_t, sent C.uchar, data *C.uchar, size C.uint32_t) {
+	t := cgo.Handle(handle).Value().(*FrameTrace)
+	dir := Received
+	if sent != 0 {
+		dir = Sent
+	}
+	t.record(dir, C.GoBytes(unsafe.Pointer(data), C.int(size)), time.Now())
+}
+
+// record adds data read from or written to the socket, which may hold part
+// of a frame or several frames
+func (t *FrameTrace) record(dir Direction, data []byte, now time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	buf := append(t.pending[dir], data...)
+	for len(buf) >= wrapperHeaderSize {
+		if binary.BigEndian.Uint16(buf) != 1 {
+			// Not a wrapper frame, keep the data as it came
+			t.add(Frame{Time: now, Direction: dir, Data: buf})
+			buf = nil
+			break
+		}
+		n := wrapperHeaderSize + int(binary.BigEndian.Uint16(buf[6:]))
+		if len(buf) < n {
+			break
+		}
+		t.add(Frame{Time: now, Direction: dir, Data: buf[:n:n]})
+		buf = buf[n:]
+	}
+	t.pending[dir] = append([]byte(nil), buf...)
+}
+
+// add appends f unless the trace is full; the caller holds t.mu
+func (t *FrameTrace) add(f Frame) {
+	if t.size+len(f.Data) > maxTraceBytes {
+		t.truncated = true
+		return
+	}
+	t.size += len(f.Data)
+	t.frames = append(t.frames, f)
+}
+
+// observe learns the meter address and keys from meter; the caller holds
+// the Gurux lock
+func (t *FrameTrace) observe(meter *C.meter_t) {
+	var title [8]C.uchar
+	hasServerTitle := C.meter_server_system_title(meter, &title[0]) == 0
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.meterIP = C.GoString(meter.meter_ip)
+	t.meterPort = int(meter.meter_port)
+	t.clientTitle = hexField(meter.system_title, t.clientTitle)
+	t.blockKey = hexField(meter.block_cipher_key, t.blockKey)
+	t.authKey = hexField(meter.authentication_key, t.authKey)
+	if hasServerTitle {
+		t.serverTitle = C.GoBytes(unsafe.Pointer(&title[0]), 8)
+	}
+}
+
+// hexField decodes a hex string of the shim, keeping old if it is not set
+func hexField(s *C.char, old []byte) []byte {
+	if s == nil {
+		return old
+	}
+	b, err := hex.DecodeString(C.GoString(s))
+	if err != nil || len(b) == 0 {
+		return old
+	}
+	return b
+}
+
+// Frames returns the recorded frames in order, with the plaintext of the
+// ciphered APDUs that the keys decrypt. Data of an incomplete frame comes
+// last. The calling authentication value of an AARQ, which holds the LLS
+// password in clear, is blanked.
+func (t *FrameTrace) Frames() []Frame {
+	frames := t.recorded()
+	for i := range frames {
+		frames[i].Data = blankAuthentication(frames[i].Data)
+	}
+	return frames
+}
+
+// recorded returns the frames as Frames does, without blanking
+// anything
+func (t *FrameTrace) recorded() []Frame {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	frames := make([]Frame, 0, len(t.frames)+2)
+	frames = append(frames, t.frames...)
+	for dir, data := range t.pending {
+		if len(data) > 0 {
+			frames = append(frames, Frame{Time: time.Now(), Direction: Direction(dir), Data: data})
+		}
+	}
+	keys := cipherKeys{t.blockKey, t.authKey}
+	for i := range frames {
+		title := t.clientTitle
+		if frames[i].Direction == Received {
+			title = t.serverTitle
+		}
+		frames[i].APDU = keys.decrypt(frames[i].Data, title)
+	}
+	return frames
+}
+
+// Truncated reports whether frames were dropped because the trace was full
+func (t *FrameTrace) Truncated() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.truncated
+}
+
+// Tags of the APDUs ciphered with the global key (IEC 62056-5-3)
+const (
+	tagAARQ                = 0x60
+	tagAARE                = 0x61
+	tagUserInformation     = 0xBE
+	tagGloInitiateRequest  = 0x21
+	tagGloInitiateResponse = 0x28
+	tagGeneralGloCiphering = 0xDB
+)
+
+// gloTags are the glo-get, -set, -event-notification and -action requests
+// and responses
+var gloTags = map[byte]bool{0xC8: true, 0xC9: true, 0xCA: true, 0xCB: true, 0xCC: true, 0xCD: true, 0xCF: true}
+
+// cipherKeys are the keys that cipher the APDUs of an association
+type cipherKeys struct {
+	block, auth []byte
+}
+
+// decrypt returns the plaintext of the ciphered APDU in a wrapper frame, nil
+// if it holds none or it cannot be decrypted
+func (k cipherKeys) decrypt(frame, title []byte) []byte {
+	if len(k.block) == 0 {
+		return nil
+	}
+	b, title := ciphered(frame, title)
+	if b == nil {
+		return nil
+	}
+	return k.open(b, title)
+}
+
+// ciphered returns the security header, invocation counter and ciphertext
+// of the ciphered APDU in a wrapper frame, as a slice of frame, and the
+// system title of its sender; nil if it holds none
+func ciphered(frame, title []byte) ([]byte, []byte) {
+	if len(frame) <= wrapperHeaderSize {
+		return nil, nil
+	}
+	apdu := frame[wrapperHeaderSize:]
+
+	var b []byte
+	switch tag := apdu[0]; {
+	case tag == tagAARQ || tag == tagAARE:
+		info := userInformation(apdu)
+		if len(info) == 0 || (info[0] != tagGloInitiateRequest && info[0] != tagGloInitiateResponse) {
+			return nil, nil
+		}
+		b = info[1:]
+	case tag == tagGeneralGloCiphering:
+		// The sender's system title comes first
+		n, rest, ok := axdrLength(apdu[1:])
+		if !ok || n > len(rest) {
+			return nil, nil
+		}
+		b, title = rest[n:], rest[:n]
+	case gloTags[tag]:
+		b = apdu[1:]
+	default:
+		return nil, nil
+	}
+
+	n, b, ok := axdrLength(b)
+	if !ok || n > len(b) || n < 5 || len(title) != 8 {
+		return nil, nil
+	}
+	return b[:n], title
+}
+
+// Security control bits of a security header
+const (
+	securityAuthenticated = 0x10
+	securityEncrypted     = 0x20
+)
+
+// open decrypts a security header, invocation counter and ciphertext with
+// the sender's system title
+func (k cipherKeys) open(b, title []byte) []byte {
+	sc, ic, text := b[0], b[1:5], b[5:]
+
+	block, err := aes.NewCipher(k.block)
+	if err != nil {
+		return nil
+	}
+	iv := append(append([]byte(nil), title...), ic...)
+
+	switch sc & (securityAuthenticated | securityEncrypted) {
+	case securityAuthenticated | securityEncrypted:
+		gcm, err := cipher.NewGCMWithTagSize(block, 12)
+		if err != nil {
+			return nil
+		}
+		plain, err := gcm.Open(nil, iv, text, append([]byte{sc}, k.auth...))
+		if err != nil {
+			return nil
+		}
+		return plain
+	case securityEncrypted:
+		// GCM without a tag: CTR mode from the counter block after J0
+		counter := append(iv, 0, 0, 0, 2)
+		plain := make([]byte, len(text))
+		cipher.NewCTR(block, counter).XORKeyStream(plain, text)
+		return plain
+	case securityAuthenticated:
+		if len(text) < 12 {
+			return nil
+		}
+		return append([]byte(nil), text[:len(text)-12]...)
+	}
+	return nil
+}
+
+// seal is the reverse of open: it returns the security header sc,
+// invocation counter ic and plain ciphered by the sender with title
+func (k cipherKeys) seal(sc byte, ic, title, plain []byte) ([]byte, error) {
+	block, err := aes.NewCipher(k.block)
+	if err != nil {
+		return nil, err
+	}
+	iv := append(append([]byte(nil), title...), ic...)
+	out := append([]byte{sc}, ic...)
+
+	switch sc & (securityAuthenticated | securityEncrypted) {
+	case securityAuthenticated | securityEncrypted:
+		gcm, err := cipher.NewGCMWithTagSize(block, 12)
+		if err != nil {
+			return nil, err
+		}
+		return gcm.Seal(out, iv, plain, append([]byte{sc}, k.auth...)), nil
+	case securityEncrypted:
+		counter := append(iv, 0, 0, 0, 2)
+		text := make([]byte, len(plain))
+		cipher.NewCTR(block, counter).XORKeyStream(text, plain)
+		return append(out, text...), nil
+	case securityAuthenticated:
+		gcm, err := cipher.NewGCMWithTagSize(block, 12)
+		if err != nil {
+			return nil, err
+		}
+		aad := append(append([]byte{sc}, k.auth...), plain...)
+		return append(append(out, plain...), gcm.Seal(nil, iv, nil, aad)...), nil
+	}
+	return nil, fmt.Errorf("security header %#x neither authenticates nor encrypts", sc)
+}
+
+// respond returns f(challenge) of HIGH_GMAC authentication, the security
+// header sc, invocation counter ic and the GMAC of the challenge by the
+// sender with title
+func (k cipherKeys) respond(sc byte, ic, title, challenge []byte) ([]byte, error) {
+	block, err := aes.NewCipher(k.block)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCMWithTagSize(block, 12)
+	if err != nil {
+		return nil, err
+	}
+	iv := append(append([]byte(nil), title...), ic...)
+	aad := append(append([]byte{sc}, k.auth...), challenge...)
+	return gcm.Seal(append([]byte{sc}, ic...), iv, nil, aad), nil
+}
+
+// blankAuthentication returns a copy of frame with the calling
+// authentication value of an AARQ zeroed, or frame itself if it holds none
+func blankAuthentication(frame []byte) []byte {
+	if len(frame) <= wrapperHeaderSize || frame[wrapperHeaderSize] != tagAARQ {
+		return frame
+	}
+	out := append([]byte(nil), frame...)
+	v := associationElement(out[wrapperHeaderSize:], tagCallingAuthenticationValue)
+	if len(v) < 2 {
+		return frame
+	}
+	// Keeps the charstring's tag and length, so the AARQ still parses
+	clear(v[2:])
+	return out
+}
+
+// userInformation returns the initiate APDU in the user-information of an
+// AARQ or AARE, nil if there is none
+func userInformation(apdu []byte) []byte {
+	// An octet string holding the APDU
+	v := associationElement(apdu, tagUserInformation)
+	if len(v) < 2 || v[0] != 0x04 || len(v) < 2+int(v[1]) {
+		return nil
+	}
+	return v[2 : 2+int(v[1])]
+}
+
+// associationElement returns the value of the element with tag in an AARQ
+// or AARE, as a slice of apdu; nil if there is none
+func associationElement(apdu []byte, tag byte) []byte {
+	for _, e := range associationElements(apdu) {
+		if e[0] == tag {
+			return e[2:]
+		}
+	}
+	return nil
+}
+
+// associationElements splits the elements of an AARQ or AARE, each a BER
+// tag and short length
+func associationElements(apdu []byte) [][]byte {
+	if len(apdu) < 2 {
+		return nil
+	}
+	var elements [][]byte
+	b := apdu[2:]
+	for len(b) >= 2 {
+		n := 2 + int(b[1])
+		if len(b) < n {
+			return nil
+		}
+		elements = append(elements, b[:n:n])
+		b = b[n:]
+	}
+	return elements
+}
+
+// axdrLength splits an A-XDR length off b
+func axdrLength(b []byte) (int, []byte, bool) {
+	if len(b) == 0 {
+		return 0, nil, false
+	}
+	if b[0] < 0x80 {
+		return int(b[0]), b[1:], true
+	}
+	size := int(b[0] & 0x7F)
+	if size == 0 || size > 2 || len(b) < 1+size {
+		return 0, nil, false
+	}
+	n := 0
+	for _, c := range b[1 : 1+size] {
+		n = n<<8 | int(c)
+	}
+	return n, b[1+size:], true
+}
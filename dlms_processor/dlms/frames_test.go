@@ -0,0 +1,185 @@
+package dlms
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"encoding/binary"
+	"encoding/hex"
+	"testing"
+	"time"
+)
+
+// wrapperFrame wraps apdu for the test meter's addresses
+func wrapperFrame(apdu []byte) []byte {
+	frame := []byte{0, 1, 0, 0x30, 0, 1, byte(len(apdu) >> 8), byte(len(apdu))}
+	return append(frame, apdu...)
+}
+
+// gloCiphered encrypts and authenticates plain as the sender with title would
+func gloCiphered(t *testing.T, tag byte, title, key []byte, plain []byte) []byte {
+	t.Helper()
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gcm, err := cipher.NewGCMWithTagSize(block, 12)
+	if err != nil {
+		t.Fatal(err)
+	}
+	const sc = 0x30
+	ic := []byte{0, 0, 0, 7}
+	sealed := gcm.Seal(nil, append(append([]byte(nil), title...), ic...), plain, append([]byte{sc}, key...))
+	body := append(append([]byte{sc}, ic...), sealed...)
+	return append([]byte{tag, byte(len(body))}, body...)
+}
+
+func TestFrameTraceReassembles(t *testing.T) {
+	first, second := wrapperFrame([]byte{0xC0, 0x01, 0xC1}), wrapperFrame([]byte{0xC4, 0x01, 0xC1, 0x00})
+	trace := NewFrameTrace()
+	now := time.Now()
+
+	// A frame split across reads, then two frames in one read with a third begun
+	trace.record(Received, first[:5], now)
+	trace.record(Received, first[5:], now)
+	trace.record(Sent, append(append(append([]byte(nil), first...), second...), 0, 1), now)
+
+	frames := trace.Frames()
+	want := []struct {
+		dir  Direction
+		data []byte
+	}{
+		{Received, first},
+		{Sent, first},
+		{Sent, second},
+		{Sent, []byte{0, 1}},
+	}
+	if len(frames) != len(want) {
+		t.Fatalf("Expected %d frames, got %d", len(want), len(frames))
+	}
+	for i, w := range want {
+		if frames[i].Direction != w.dir || !bytes.Equal(frames[i].Data, w.data) {
+			t.Errorf("Frame %d: expected %s % x, got %s % x", i, w.dir, w.data, frames[i].Direction, frames[i].Data)
+		}
+	}
+}
+
+func TestFrameTraceDecrypts(t *testing.T) {
+	meter := testMeterConfig("127.0.0.1", 4059)
+	clientTitle, _ := hex.DecodeString(meter.SystemTitle)
+	serverTitle := []byte("SERVER01")
+	key, _ := hex.DecodeString(meter.BlockCipherKey)
+
+	getRequest := []byte{0xC0, 0x01, 0xC1, 0x00, 0x01, 0x00, 0x00, 0x2A, 0x00, 0x00, 0xFF, 0x02, 0x00}
+	getResponse := []byte{0xC4, 0x01, 0xC1, 0x00, 0x09, 0x02, 0x41, 0x42}
+
+	trace := NewFrameTrace()
+	trace.clientTitle, trace.serverTitle, trace.blockKey, trace.authKey = clientTitle, serverTitle, key, key
+	trace.record(Sent, wrapperFrame(gloCiphered(t, 0xC8, clientTitle, key, getRequest)), time.Now())
+	trace.record(Received, wrapperFrame(gloCiphered(t, 0xCC, serverTitle, key, getResponse)), time.Now())
+	trace.record(Received, wrapperFrame(getResponse), time.Now())
+
+	frames := trace.Frames()
+	if !bytes.Equal(frames[0].APDU, getRequest) || !bytes.Equal(frames[1].APDU, getResponse) {
+		t.Errorf("Expected the ciphered APDUs decrypted, got % x and % x", frames[0].APDU, frames[1].APDU)
+	}
+	if frames[2].APDU != nil {
+		t.Errorf("Expected no APDU for a plain frame, got % x", frames[2].APDU)
+	}
+
+	// Frames the keys do not open are kept without their plaintext
+	trace.authKey = []byte("wrong authentication key")
+	if apdu := trace.Frames()[0].APDU; apdu != nil {
+		t.Errorf("Expected no plaintext with the wrong key, got % x", apdu)
+	}
+}
+
+func TestFrameTraceBlanksPassword(t *testing.T) {
+	password := []byte("12345678")
+	mechanism := []byte{0x8B, 0x07, 0x60, 0x85, 0x74, 0x05, 0x08, 0x02, 0x01} // LLS
+	calling := append([]byte{tagCallingAuthenticationValue, byte(2 + len(password)), 0x80, byte(len(password))}, password...)
+	body := append(append([]byte{0x8A, 0x02, 0x07, 0x80}, mechanism...), calling...)
+	aarq := wrapperFrame(append([]byte{tagAARQ, byte(len(body))}, body...))
+
+	trace := NewFrameTrace()
+	trace.record(Sent, aarq, time.Now())
+
+	data := trace.Frames()[0].Data
+	if bytes.Contains(data, password) {
+		t.Errorf("Expected the password blanked, got % x", data)
+	}
+	if len(data) != len(aarq) || !bytes.Equal(data[:len(data)-len(password)], aarq[:len(aarq)-len(password)]) {
+		t.Errorf("Expected only the authentication value to change, got % x", data)
+	}
+
+	var pcap bytes.Buffer
+	if err := trace.WritePcap(&pcap); err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Contains(pcap.Bytes(), password) {
+		t.Errorf("Expected the password blanked in the pcap file")
+	}
+
+	// The trace itself is left as recorded
+	if !bytes.Equal(trace.recorded()[0].Data, aarq) {
+		t.Errorf("Expected the recorded AARQ kept, got % x", trace.recorded()[0].Data)
+	}
+}
+
+func TestFrameTraceRecordsConnect(t *testing.T) {
+	ip, port := silentListener(t)
+	client := NewMeterClient()
+	defer client.Close()
+	meter := testMeterConfig(ip, port)
+	meter.ConnectionTimeout = 200
+	if err := client.Configure(&meter); err != nil {
+		t.Fatal(err)
+	}
+
+	trace := NewFrameTrace()
+	if err := client.Connect(WithFrameTrace(context.Background(), trace)); err == nil {
+		t.Fatal("Expected a silent meter to fail the connect")
+	}
+
+	// The AARQ, resent while the meter does not answer
+	frames := trace.Frames()
+	if len(frames) == 0 {
+		t.Fatal("Expected the AARQ to be traced")
+	}
+	for _, f := range frames {
+		if f.Direction != Sent || !bytes.Equal(f.Data, frames[0].Data) {
+			t.Fatalf("Expected only the AARQ, got %v", frames)
+		}
+	}
+	aarq := frames[0].Data[wrapperHeaderSize:]
+	if aarq[0] != tagAARQ {
+		t.Errorf("Expected an AARQ, got % x", aarq)
+	}
+	// The xDLMS InitiateRequest, deciphered from the user information
+	if len(frames[0].APDU) == 0 || frames[0].APDU[0] != 0x01 {
+		t.Errorf("Expected the deciphered InitiateRequest, got % x", frames[0].APDU)
+	}
+
+	var pcap bytes.Buffer
+	if err := trace.WritePcap(&pcap); err != nil {
+		t.Fatal(err)
+	}
+	b := pcap.Bytes()
+	if binary.LittleEndian.Uint32(b) != pcapMagic || binary.LittleEndian.Uint32(b[20:]) != pcapLinkRawIP {
+		t.Fatalf("Unexpected pcap header % x", b[:24])
+	}
+	packet := b[24+16:]
+	if got := int(binary.LittleEndian.Uint32(b[24+8:])); got != 20+20+len(frames[0].Data) {
+		t.Errorf("Expected one IPv4 packet carrying the frame, got %d bytes", got)
+	}
+	if checksum(packet[:20]) != 0 {
+		t.Error("Expected a valid IPv4 header checksum")
+	}
+	if dstPort := int(binary.BigEndian.Uint16(packet[22:])); dstPort != port {
+		t.Errorf("Expected the segment to go to port %d, got %d", port, dstPort)
+	}
+	if !bytes.Equal(packet[40:40+len(frames[0].Data)], frames[0].Data) {
+		t.Error("Expected the segment to carry the frame")
+	}
+}
@@ -264,11 +264,14 @@ int com_readSerialPort(
         {
             cnt = RECEIVE_BUFFER_SIZE;
         }
+        gurux_leave();
         bytesRead = read(connection->comPort, connection->data.data + connection->data.size, cnt);
+        int err = errno;
+        gurux_enter();
         if (bytesRead == 0xFFFF)
         {
             //If there is no data on the read buffer.
-            if (errno == EAGAIN)
+            if (err == EAGAIN)
             {
                 if (readTime > connection->waitTime)
                 {
@@ -279,7 +282,7 @@ int com_readSerialPort(
             }
             else
             {
-                return DLMS_ERROR_TYPE_COMMUNICATION_ERROR | errno;
+                return DLMS_ERROR_TYPE_COMMUNICATION_ERROR | err;
             }
         }
 #endif
@@ -530,11 +533,20 @@ int sendData(connection* connection, gxByteBuffer* data)
 #if defined(_WIN32) || defined(_WIN64)//Windows
     unsigned long sendSize = 0;
 #endif
-    
-    // Always print raw DLMS message being sent
-    print_hex_data("TX RAW DLMS", data->data, data->size);
-    
-    if (connection->comPort != INVALID_HANDLE_VALUE)
+
+    if (connection->onFrame == NULL && connection->trace == GX_TRACE_LEVEL_VERBOSE)
+    {
+        print_hex_data("TX RAW DLMS", data->data, data->size);
+    }
+
+    if (connection->onSend != NULL)
+    {
+        if (connection->onSend(connection->transportContext, data->data, data->size) != (int)data->size)
+        {
+            return DLMS_ERROR_CODE_SEND_FAILED;
+        }
+    }
+    else if (connection->comPort != INVALID_HANDLE_VALUE)
     {
 #if defined(_WIN32) || defined(_WIN64)//Windows
         ret = WriteFile(connection->comPort, data->data, data->size, &sendSize, &connection->osWrite);
@@ -564,26 +576,37 @@ int sendData(connection* connection, gxByteBuffer* data)
             }
         }
 #else
+        gurux_leave();
         ret = write(connection->comPort, data->data, data->size);
+        int err = errno;
+        gurux_enter();
         if (ret != data->size)
         {
-            ret = errno;
+            ret = err;
             return DLMS_ERROR_TYPE_COMMUNICATION_ERROR | ret;
         }
 #endif
     }
     else
     {
-        if (send(connection->socket, (const char*)data->data, data->size, 0) == -1)
+        gurux_leave();
+        ret = send(connection->socket, (const char*)data->data, data->size, 0);
+        int err = errno;
+        gurux_enter();
+        if (ret == -1)
         {
 #if defined(_WIN32) || defined(_WIN64)//If Windows
             ret = WSAGetLastError();
 #else
-            ret = errno;
+            ret = err;
 #endif
             return DLMS_ERROR_TYPE_COMMUNICATION_ERROR | ret;
         }
     }
+    if (connection->onFrame != NULL)
+    {
+        connection->onFrame(connection->frameContext, 1, data->data, data->size);
+    }
     return 0;
 }
 
@@ -592,7 +615,21 @@ int readData(connection* connection, gxByteBuffer* data, int* index)
     int ret = 0;
     int oldSize = connection->data.size;
     
-    if (connection->comPort != INVALID_HANDLE_VALUE)
+    if (connection->onReceive != NULL)
+    {
+        uint32_t cnt = connection->data.capacity - connection->data.size;
+        if (cnt < 1)
+        {
+            return DLMS_ERROR_CODE_OUTOFMEMORY;
+        }
+        ret = connection->onReceive(connection->transportContext, connection->data.data + connection->data.size, cnt);
+        if (ret <= 0)
+        {
+            return DLMS_ERROR_CODE_RECEIVE_FAILED;
+        }
+        connection->data.size += ret;
+    }
+    else if (connection->comPort != INVALID_HANDLE_VALUE)
     {
         if ((ret = com_readSerialPort(connection, 0x7E)) != 0)
         {
@@ -606,16 +643,27 @@ int readData(connection* connection, gxByteBuffer* data, int* index)
         {
             return DLMS_ERROR_CODE_OUTOFMEMORY;
         }
-        if ((ret = recv(connection->socket, (char*)connection->data.data + connection->data.size, cnt, 0)) == -1)
+        //Zero means the peer closed the connection or the socket was shut down by an abort.
+        gurux_leave();
+        ret = recv(connection->socket, (char*)connection->data.data + connection->data.size, cnt, 0);
+        gurux_enter();
+        if (ret <= 0)
         {
             return DLMS_ERROR_CODE_RECEIVE_FAILED;
         }
         connection->data.size += ret;
     }
     
-    // Always print raw DLMS message received (only new data)
-    if (connection->data.size > oldSize) {
-        print_hex_data("RX RAW DLMS", connection->data.data + oldSize, connection->data.size - oldSize);
+    if (connection->data.size > oldSize)
+    {
+        if (connection->onFrame != NULL)
+        {
+            connection->onFrame(connection->frameContext, 0, connection->data.data + oldSize, connection->data.size - oldSize);
+        }
+        else if (connection->trace == GX_TRACE_LEVEL_VERBOSE)
+        {
+            print_hex_data("RX RAW DLMS", connection->data.data + oldSize, connection->data.size - oldSize);
+        }
     }
     
     if (connection->trace > GX_TRACE_LEVEL_INFO)
@@ -58,6 +58,11 @@ void con_init(connection* con, GX_TRACE_LEVEL trace)
     con->socket = -1;
     con->receiverThread = -1;
     con->closing = 0;
+    con->onFrame = NULL;
+    con->frameContext = NULL;
+    con->onSend = NULL;
+    con->onReceive = NULL;
+    con->transportContext = NULL;
     bb_init(&con->data);
     bb_capacity(&con->data, 500);
 }
@@ -23,6 +23,10 @@
 #include "../../include/cosem.h"
 #include "connection.h"
 
+//Process-wide lock around the Gurux library, provided by the shim. It is
+//held while a meter operation runs and released around blocking I/O.
+void gurux_enter(void);
+void gurux_leave(void);
 
 //Make connection using TCP/IP connection.
 int com_makeConnect(
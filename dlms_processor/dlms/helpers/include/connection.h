@@ -54,6 +54,17 @@ extern "C" {
         unsigned char closing;
 
         dlmsSettings settings;
+
+        //Called with every chunk of data sent (sent = 1) or received (sent = 0), if set.
+        void (*onFrame)(void* context, unsigned char sent, const unsigned char* data, uint32_t size);
+        //Passed to onFrame.
+        void* frameContext;
+
+        //Used instead of the socket to send and receive, if set. They return the number of bytes like send and recv.
+        int (*onSend)(void* context, const unsigned char* data, uint32_t size);
+        int (*onReceive)(void* context, unsigned char* data, uint32_t size);
+        //Passed to onSend and onReceive.
+        void* transportContext;
     } connection;
 
     void con_initializeBuffers(
@@ -0,0 +1,71 @@
+package dlms
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// COSEM interface classes used by the processor
+const (
+	ObjectTypeData             = 1
+	ObjectTypeRegister         = 3
+	ObjectTypeExtendedRegister = 4
+	ObjectTypeDemandRegister   = 5
+	ObjectTypeClock            = 8
+	ObjectTypeProfileGeneric   = 7
+	ObjectTypeSpecialDaysTable = 11
+	ObjectTypeActivityCalendar = 20
+)
+
+// clockAdjustMethod is the clock method the shim calls to set the time
+const clockAdjustMethod = 7
+
+// LogicalDeviceNameOBIS is the COSEM logical device name object (IC 1, attribute 2)
+const LogicalDeviceNameOBIS = "0.0.42.0.0.255"
+
+// ClockOBIS is the COSEM clock object (IC 8, attribute 2 is the time)
+const ClockOBIS = "0.0.1.0.0.255"
+
+// ErrIdentityMismatch is returned when the meter answering at an address is not
+// the meter the request expected
+var ErrIdentityMismatch = errors.New("meter identity mismatch")
+
+// checkLogicalDeviceName compares the logical device name reported by the meter
+// with the expected one. An empty expected value skips the check.
+func checkLogicalDeviceName(expected, reported string) error {
+	if expected == "" {
+		return nil
+	}
+
+	reported = decodeOctetString(reported)
+	if !strings.EqualFold(strings.TrimSpace(reported), strings.TrimSpace(expected)) {
+		return fmt.Errorf("%w: expected logical device name %q, meter reported %q", ErrIdentityMismatch, expected, reported)
+	}
+
+	return nil
+}
+
+// decodeOctetString turns the "Hex:..." form produced by the shim for octet
+// strings back into text when every byte is printable, which is how logical
+// device names and serial numbers are usually encoded
+func decodeOctetString(value string) string {
+	hexValue, ok := strings.CutPrefix(value, "Hex:")
+	if !ok {
+		return value
+	}
+
+	raw, err := hex.DecodeString(hexValue)
+	if err != nil {
+		return value
+	}
+
+	for _, b := range raw {
+		if b < 0x20 || b > 0x7e {
+			return value
+		}
+	}
+
+	return string(raw)
+}
@@ -0,0 +1,42 @@
+package dlms
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCheckLogicalDeviceName(t *testing.T) {
+	testCases := []struct {
+		name     string
+		expected string
+		reported string
+		wantErr  bool
+	}{
+		{name: "no expectation", expected: "", reported: "Hex:00FF", wantErr: false},
+		{name: "plain match", expected: "ABC1234567890123", reported: "ABC1234567890123", wantErr: false},
+		{name: "octet string match", expected: "ABC123", reported: "Hex:414243313233", wantErr: false},
+		{name: "case insensitive", expected: "abc123", reported: "Hex:414243313233", wantErr: false},
+		{name: "mismatch", expected: "ABC124", reported: "Hex:414243313233", wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := checkLogicalDeviceName(tc.expected, tc.reported)
+			if tc.wantErr {
+				if !errors.Is(err, ErrIdentityMismatch) {
+					t.Errorf("Expected ErrIdentityMismatch, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("Expected no error, got %v", err)
+			}
+		})
+	}
+}
+
+func TestDecodeOctetString_NonPrintableKeptAsHex(t *testing.T) {
+	if got := decodeOctetString("Hex:0001FF"); got != "Hex:0001FF" {
+		t.Errorf("Expected non-printable octet string to stay hex, got %q", got)
+	}
+}
@@ -0,0 +1,59 @@
+package dlms
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// NameplateProfileOBIS is the nameplate profile, a profile generic whose
+// single entry captures the meter's static identity
+const NameplateProfileOBIS = "0.0.94.91.10.255"
+
+// NameplateProfile is the static identity of a meter, for checking that a
+// request reached the right meter and keeping the inventory in sync
+type NameplateProfile struct {
+	SerialNumber      string `obis:"0.0.96.1.0.255" type:"string" json:"serial_number"`       // Meter Serial Number
+	ManufacturerName  string `obis:"0.0.96.1.1.255" type:"string" json:"manufacturer_name"`   // Manufacturer Name
+	FirmwareVersion   string `obis:"1.0.0.2.0.255" type:"string" json:"firmware_version"`     // Firmware Version for Meter
+	MeterType         uint8  `obis:"0.0.94.91.9.255" type:"uint8" json:"meter_type"`          // Meter Type
+	Category          string `obis:"0.0.94.91.11.255" type:"string" json:"category"`          // Category, e.g. "C1"
+	CurrentRating     string `obis:"0.0.94.91.12.255" type:"string" json:"current_rating"`    // Current Rating, e.g. "5-30A"
+	YearOfManufacture uint16 `obis:"0.0.96.1.4.255" type:"uint16" json:"year_of_manufacture"` // Meter Year of Manufacture
+}
+
+// decode turns the octet strings the meter reports as "Hex:..." into text
+func (n *NameplateProfile) decode() {
+	for _, s := range []*string{&n.SerialNumber, &n.ManufacturerName, &n.FirmwareVersion, &n.Category, &n.CurrentRating} {
+		*s = decodeOctetString(*s)
+	}
+}
+
+// readNameplateObjects reads the objects the nameplate profile captures one
+// by one, for meters without the profile. Objects the meter does not have
+// or does not let the client read are left empty.
+func readNameplateObjects(ctx context.Context, m *RealMeter) ([]NameplateProfile, error) {
+	structType := reflect.TypeFor[NameplateProfile]()
+	result := &DLMSResult{NumRows: 1, Data: [][]string{{}}}
+	for i := range structType.NumField() {
+		obis := structType.Field(i).Tag.Get("obis")
+		value, err := m.session.ReadAttribute(ctx, obis, ObjectTypeData, 2)
+		if missingObject(err) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", obis, err)
+		}
+		result.ColumnNames = append(result.ColumnNames, obis)
+		result.Data[0] = append(result.Data[0], value)
+	}
+	result.NumColumns = len(result.ColumnNames)
+	return mapProfileDataTyped[NameplateProfile](ctx, result)
+}
+
+// missingObject reports whether err means the meter has no such object, or
+// one the association may not read: meters answer either way
+func missingObject(err error) bool {
+	return errors.Is(err, ErrObjectUnavailable) || errors.Is(err, ErrAccessDenied)
+}
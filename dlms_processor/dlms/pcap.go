@@ -0,0 +1,137 @@
+package dlms
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+)
+
+// Pcap file layout, see https://www.tcpdump.org/manpages/pcap-savefile.5.html
+const (
+	pcapMagic      = 0xa1b2c3d4 // microsecond timestamps
+	pcapLinkRawIP  = 101        // LINKTYPE_RAW: each packet starts with an IPv4 or IPv6 header
+	pcapSnapLength = 65535
+
+	// traceClientPort stands in for the processor's port, which the trace
+	// does not know
+	traceClientPort = 49152
+)
+
+// WritePcap writes the frames as a pcap file of TCP segments between the
+// processor and the meter, so tools that dissect DLMS over TCP can read it.
+// The processor's end is shown as an unspecified address.
+func (t *FrameTrace) WritePcap(w io.Writer) error {
+	frames := t.Frames()
+
+	t.mu.Lock()
+	meterIP, meterPort := net.ParseIP(t.meterIP), t.meterPort
+	t.mu.Unlock()
+
+	clientIP := net.IPv4zero
+	if meterIP == nil {
+		meterIP = net.IPv4zero
+	} else if meterIP.To4() == nil {
+		clientIP = net.IPv6unspecified
+	}
+
+	header := make([]byte, 24)
+	binary.LittleEndian.PutUint32(header[0:], pcapMagic)
+	binary.LittleEndian.PutUint16(header[4:], 2)
+	binary.LittleEndian.PutUint16(header[6:], 4)
+	binary.LittleEndian.PutUint32(header[16:], pcapSnapLength)
+	binary.LittleEndian.PutUint32(header[20:], pcapLinkRawIP)
+	if _, err := w.Write(header); err != nil {
+		return fmt.Errorf("writing pcap header: %w", err)
+	}
+
+	// Sequence numbers of the data each side sent so far
+	var seq [2]uint32
+	for _, f := range frames {
+		sent := f.Direction == Sent
+		src, dst := clientIP, meterIP
+		srcPort, dstPort := traceClientPort, meterPort
+		if !sent {
+			src, dst = dst, src
+			srcPort, dstPort = dstPort, srcPort
+		}
+		packet := ipPacket(src, dst, tcpSegment(src, dst, srcPort, dstPort, seq[f.Direction], seq[1-f.Direction], f.Data))
+		seq[f.Direction] += uint32(len(f.Data))
+
+		record := make([]byte, 16)
+		binary.LittleEndian.PutUint32(record[0:], uint32(f.Time.Unix()))
+		binary.LittleEndian.PutUint32(record[4:], uint32(f.Time.Nanosecond()/1000))
+		binary.LittleEndian.PutUint32(record[8:], uint32(len(packet)))
+		binary.LittleEndian.PutUint32(record[12:], uint32(len(packet)))
+		if _, err := w.Write(append(record, packet...)); err != nil {
+			return fmt.Errorf("writing pcap record: %w", err)
+		}
+	}
+	return nil
+}
+
+// tcpSegment builds a PSH/ACK segment carrying data
+func tcpSegment(src, dst net.IP, srcPort, dstPort int, seq, ack uint32, data []byte) []byte {
+	segment := make([]byte, 20+len(data))
+	binary.BigEndian.PutUint16(segment[0:], uint16(srcPort))
+	binary.BigEndian.PutUint16(segment[2:], uint16(dstPort))
+	binary.BigEndian.PutUint32(segment[4:], seq)
+	binary.BigEndian.PutUint32(segment[8:], ack)
+	segment[12] = 5 << 4 // header length in 32-bit words
+	segment[13] = 0x18   // PSH, ACK
+	binary.BigEndian.PutUint16(segment[14:], 65535)
+	copy(segment[20:], data)
+
+	// The checksum covers a pseudo header of the addresses, protocol and length
+	var pseudo []byte
+	if src4, dst4 := src.To4(), dst.To4(); src4 != nil && dst4 != nil {
+		pseudo = append(append(pseudo, src4...), dst4...)
+		pseudo = append(pseudo, 0, 6, byte(len(segment)>>8), byte(len(segment)))
+	} else {
+		pseudo = append(append(pseudo, src.To16()...), dst.To16()...)
+		pseudo = binary.BigEndian.AppendUint32(pseudo, uint32(len(segment)))
+		pseudo = append(pseudo, 0, 0, 0, 6)
+	}
+	binary.BigEndian.PutUint16(segment[16:], checksum(append(pseudo, segment...)))
+	return segment
+}
+
+// ipPacket wraps a TCP segment in an IPv4 or IPv6 header
+func ipPacket(src, dst net.IP, segment []byte) []byte {
+	if src4, dst4 := src.To4(), dst.To4(); src4 != nil && dst4 != nil {
+		header := make([]byte, 20)
+		header[0] = 0x45 // version 4, 5 words
+		binary.BigEndian.PutUint16(header[2:], uint16(len(header)+len(segment)))
+		header[8] = 64 // TTL
+		header[9] = 6  // TCP
+		copy(header[12:], src4)
+		copy(header[16:], dst4)
+		binary.BigEndian.PutUint16(header[10:], checksum(header))
+		return append(header, segment...)
+	}
+
+	header := make([]byte, 40)
+	header[0] = 0x60 // version 6
+	binary.BigEndian.PutUint16(header[4:], uint16(len(segment)))
+	header[6] = 6  // TCP
+	header[7] = 64 // hop limit
+	copy(header[8:], src.To16())
+	copy(header[24:], dst.To16())
+	return append(header, segment...)
+}
+
+// checksum is the Internet checksum of b (RFC 1071)
+func checksum(b []byte) uint16 {
+	var sum uint32
+	for len(b) >= 2 {
+		sum += uint32(binary.BigEndian.Uint16(b))
+		b = b[2:]
+	}
+	if len(b) == 1 {
+		sum += uint32(b[0]) << 8
+	}
+	for sum > 0xffff {
+		sum = sum>>16 + sum&0xffff
+	}
+	return ^uint16(sum)
+}
@@ -0,0 +1,190 @@
+package dlms
+
+/*
+#include "dlms_shim.h"
+*/
+import "C"
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// FirmwareVersionOBIS is the active firmware identifier (IC 1, attribute 2)
+const FirmwareVersionOBIS = "1.0.0.2.0.255"
+
+// Probe steps, in the order Probe runs them
+const (
+	ProbeStepTCPConnect        = "tcp_connect"
+	ProbeStepLinkSetup         = "link_setup"
+	ProbeStepAssociation       = "association"
+	ProbeStepAuthentication    = "authentication"
+	ProbeStepLogicalDeviceName = "logical_device_name"
+	ProbeStepFirmwareVersion   = "firmware_version"
+	ProbeStepRelease           = "release"
+)
+
+var probeSteps = []string{
+	ProbeStepTCPConnect, ProbeStepLinkSetup, ProbeStepAssociation, ProbeStepAuthentication,
+	ProbeStepLogicalDeviceName, ProbeStepFirmwareVersion, ProbeStepRelease,
+}
+
+// connectProbeSteps are the steps of MeterClient.connect, indexed like the
+// shim's METER_STEP_* values
+var connectProbeSteps = probeSteps[:C.METER_STEP_COUNT]
+
+// ProbeStep is the outcome of one probe step
+type ProbeStep struct {
+	Name     string
+	Duration time.Duration
+	Skipped  bool   // not run because an earlier step failed
+	Detail   string // what the step read or why it had nothing to do
+	Err      error  // nil when the step succeeded or was skipped
+	Reason   string // short cause of Err, e.g. "timeout"
+}
+
+// ProbeResult lists every probe step, run or skipped
+type ProbeResult struct {
+	Steps []ProbeStep
+}
+
+// OK reports whether every step succeeded
+func (r ProbeResult) OK() bool {
+	for _, step := range r.Steps {
+		if step.Err != nil || step.Skipped {
+			return false
+		}
+	}
+	return true
+}
+
+// Probe checks that meter is reachable and accepts its keys: it opens a
+// fresh association outside any session, reads the logical device name and
+// firmware version and releases the association, timing each step
+func Probe(ctx context.Context, meter RealMeter) ProbeResult {
+	var result ProbeResult
+	add := func(step ProbeStep) { result.Steps = append(result.Steps, step) }
+	skipFrom := func(name string) {
+		skipping := false
+		for _, n := range probeSteps {
+			skipping = skipping || n == name
+			if skipping {
+				add(ProbeStep{Name: n, Skipped: true})
+			}
+		}
+	}
+
+	client := NewMeterClient()
+	if client == nil {
+		err := errors.New("failed to create DLMS client")
+		add(ProbeStep{Name: ProbeStepTCPConnect, Err: err, Reason: err.Error()})
+		skipFrom(ProbeStepLinkSetup)
+		return result
+	}
+	defer client.Close()
+	if err := client.Configure(&meter); err != nil {
+		add(ProbeStep{Name: ProbeStepTCPConnect, Err: err, Reason: "invalid meter configuration"})
+		skipFrom(ProbeStepLinkSetup)
+		return result
+	}
+
+	start := time.Now()
+	err := client.Connect(ctx)
+	end := time.Now()
+	ends, failed := client.connectSteps()
+	if err != nil && failed < 0 {
+		failed = 0
+	}
+	prev := start
+	for i, name := range connectProbeSteps {
+		stepEnd := ends[i]
+		if i == failed || stepEnd.IsZero() {
+			stepEnd = end
+		}
+		// The shim's clock has millisecond resolution
+		if stepEnd.Before(prev) {
+			stepEnd = prev
+		}
+		if stepEnd.After(end) {
+			stepEnd = end
+		}
+		step := ProbeStep{Name: name, Duration: stepEnd.Sub(prev)}
+		prev = stepEnd
+
+		switch name {
+		case ProbeStepLinkSetup:
+			step.Detail = "TCP wrapper, no HDLC link to set up"
+		case ProbeStepAuthentication:
+			step.Detail = "HLS GMAC"
+		}
+		if i == failed {
+			step.Err = err
+			step.Reason = probeReason(name, err)
+			add(step)
+			skipFrom(probeSteps[i+1])
+			return result
+		}
+		add(step)
+	}
+
+	reads := []struct{ name, obis string }{
+		{ProbeStepLogicalDeviceName, LogicalDeviceNameOBIS},
+		{ProbeStepFirmwareVersion, FirmwareVersionOBIS},
+	}
+	for _, read := range reads {
+		start := time.Now()
+		value, err := client.ReadAttribute(ctx, read.obis, ObjectTypeData, 2)
+		step := ProbeStep{Name: read.name, Duration: time.Since(start), Detail: decodeOctetString(value), Err: err}
+		if err == nil && read.name == ProbeStepLogicalDeviceName {
+			err = checkLogicalDeviceName(meter.LogicalDeviceName, value)
+			step.Err = err
+		}
+		if err != nil {
+			step.Reason = probeReason(read.name, err)
+		}
+		add(step)
+	}
+
+	start = time.Now()
+	err = client.release(ctx)
+	step := ProbeStep{Name: ProbeStepRelease, Duration: time.Since(start), Err: err}
+	if err != nil {
+		step.Reason = probeReason(ProbeStepRelease, err)
+	}
+	add(step)
+
+	return result
+}
+
+// probeReason explains why a probe step failed
+func probeReason(step string, err error) string {
+	var dlmsErr *Error
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return "timeout"
+	case errors.Is(err, context.Canceled):
+		return "cancelled"
+	case errors.Is(err, ErrIdentityMismatch):
+		return "wrong meter: logical device name does not match"
+	case !errors.As(err, &dlmsErr):
+		return err.Error()
+	}
+
+	switch {
+	case step == ProbeStepAuthentication && !errors.Is(err, ErrTimeout) && !errors.Is(err, ErrConnectionLost):
+		// The meter checks the reply to its challenge with the authentication key
+		return "auth failed: wrong authentication key"
+	case errors.Is(err, ErrConnectionLost):
+		return "connection reset by meter"
+	case errors.Is(err, ErrAuthentication):
+		return "auth failed: meter rejected the credentials"
+	case errors.Is(err, ErrDecryption):
+		return "auth failed: wrong block cipher key or authentication key"
+	case dlmsErr.Kind != nil:
+		return dlmsErr.Kind.Error()
+	case dlmsErr.Errno() != 0:
+		return "communication error: " + dlmsErr.Errno().Error()
+	}
+	return fmt.Sprintf("DLMS error code %d", dlmsErr.Code)
+}
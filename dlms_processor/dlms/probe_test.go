@@ -0,0 +1,77 @@
+package dlms
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+)
+
+// refusedPort returns a local port nothing listens on
+func refusedPort(t *testing.T) (string, int) {
+	t.Helper()
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	addr := lis.Addr().(*net.TCPAddr)
+	lis.Close()
+	return addr.IP.String(), addr.Port
+}
+
+func TestProbe(t *testing.T) {
+	testCases := []struct {
+		name   string
+		peer   func(t *testing.T) (string, int)
+		failed string // step expected to fail; the ones after it are skipped
+		reason string
+	}{
+		{"refused", refusedPort, ProbeStepTCPConnect, "connection refused"},
+		{"silent", func(t *testing.T) (string, int) { return misbehavingPeer(t, peerSilent) }, ProbeStepAssociation, "timeout"},
+		{"hangs up", func(t *testing.T) (string, int) { return misbehavingPeer(t, peerCloser) }, ProbeStepAssociation, "connection reset by meter"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ip, port := tc.peer(t)
+			ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+			defer cancel()
+
+			result := Probe(ctx, testMeterConfig(ip, port))
+			if result.OK() {
+				t.Fatal("Expected the probe to fail")
+			}
+			if len(result.Steps) != len(probeSteps) {
+				t.Fatalf("Expected %d steps, got %+v", len(probeSteps), result.Steps)
+			}
+
+			failed := false
+			for i, step := range result.Steps {
+				if step.Name != probeSteps[i] {
+					t.Errorf("Step %d: expected %s, got %s", i, probeSteps[i], step.Name)
+				}
+				switch {
+				case step.Name == tc.failed:
+					failed = true
+					if step.Err == nil || step.Skipped {
+						t.Errorf("%s: expected a failure, got %+v", step.Name, step)
+					}
+					if step.Reason != tc.reason {
+						t.Errorf("%s: expected reason %q, got %q (%v)", step.Name, tc.reason, step.Reason, step.Err)
+					}
+				case failed:
+					if !step.Skipped {
+						t.Errorf("%s: expected it to be skipped after %s failed, got %+v", step.Name, tc.failed, step)
+					}
+				default:
+					if step.Err != nil || step.Skipped {
+						t.Errorf("%s: expected success, got %+v", step.Name, step)
+					}
+				}
+				if step.Duration < 0 || step.Duration > time.Second {
+					t.Errorf("%s: implausible duration %v", step.Name, step.Duration)
+				}
+			}
+		})
+	}
+}
@@ -1,12 +1,18 @@
 package dlms
 
 import (
+	"context"
+	"dlmsprocessor/metrics"
 	"fmt"
 	"log/slog"
+	"time"
 )
 
 // Meter represents the configuration for connecting to a DLMS energy meter
 type RealMeter struct {
+	MeterID           string // orchestrator's stable identifier, echoed back in responses
+	SerialNumber      string
+	LogicalDeviceName string // expected logical device name, checked on every association when set
 	MeterIP           string
 	MeterPort         int
 	ConnectionTimeout int
@@ -19,98 +25,225 @@ type RealMeter struct {
 	AttributeIndex    int
 	MaxEntries        int
 
-	client *MeterClient
+	session     *Session
+	ownsSession bool
+}
+
+// LogValue logs the meter's addressing, never its keys
+func (m RealMeter) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.String("meter_id", m.MeterID),
+		slog.String("ip", m.MeterIP),
+		slog.Int("port", m.MeterPort),
+		slog.Int("client_address", m.ClientAddress),
+		slog.Int("server_address", m.ServerAddress),
+	)
+}
+
+// String describes the meter without its keys
+func (m RealMeter) String() string {
+	return fmt.Sprintf("meter %s (%s:%d)", m.MeterID, m.MeterIP, m.MeterPort)
+}
+
+// errNotInitialized reports an operation on a meter that was never connected
+// or has been closed
+func (m *RealMeter) errNotInitialized() error {
+	slog.Error("Meter client not initialized", "meter", m)
+	return fmt.Errorf("%s: client not initialized", m)
 }
 
 func NewRealMeter(meter RealMeter) (*RealMeter, error) {
 	return &meter, nil
 }
 
-func (m *RealMeter) SetClock(clock string) error {
-	return nil
+// SetClock sets the meter clock to clock, given in RFC 3339 format
+func (m *RealMeter) SetClock(ctx context.Context, clock string) error {
+	if m.session == nil {
+		return m.errNotInitialized()
+	}
+
+	t, err := time.Parse(time.RFC3339, clock)
+	if err != nil {
+		return fmt.Errorf("invalid clock %q: %w", clock, err)
+	}
+
+	return m.session.SetClock(ctx, t)
 }
 
-func (m *RealMeter) ExecuteFunction(function string, params []string) (string, error) {
-	return "123", nil
+// ReadTariff reads the activity calendar and special days table
+func (m *RealMeter) ReadTariff(ctx context.Context) (*Tariff, error) {
+	if m.session == nil {
+		return nil, m.errNotInitialized()
+	}
+
+	return readTariff(ctx, m.session)
 }
 
-func (m *RealMeter) FOTA() error {
-	return nil
+// ProgramTariff writes program as the passive calendar, activates it or
+// sets its activation time, replaces the special days and verifies them by
+// reading the tariff back
+func (m *RealMeter) ProgramTariff(ctx context.Context, program TariffProgram) (*Tariff, error) {
+	if m.session == nil {
+		return nil, m.errNotInitialized()
+	}
+
+	return programTariff(ctx, m.session, program)
 }
 
-func (m *RealMeter) Connect() error {
-	m.client = NewMeterClient()
-	if m.client == nil {
-		fmt.Println("Failed to create meter client")
-		return fmt.Errorf("failed to create meter client")
+// Invoke calls a method of a COSEM object, with its parameter A-XDR
+// encoded, or none if data is empty
+func (m *RealMeter) Invoke(ctx context.Context, obis string, objectType, methodIndex int, data Encoded) error {
+	if m.session == nil {
+		return m.errNotInitialized()
 	}
 
-	if err := m.client.Configure(m); err != nil {
-		fmt.Printf("Failed to configure meter: %v\n", err)
-		return fmt.Errorf("failed to configure meter: %w", err)
+	return m.session.Invoke(ctx, obis, objectType, methodIndex, data)
+}
+
+// FOTA is not supported yet: image transfer is not implemented
+func (m *RealMeter) FOTA(ctx context.Context) error {
+	return fmt.Errorf("firmware upgrade: %w", ErrNotSupported)
+}
+
+// Connect prepares the meter's session; the association itself is opened by
+// the first operation and kept open until the session is idle or closed
+func (m *RealMeter) Connect(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
 	}
 
-	if m.client != nil {
-		fmt.Println("Connect() method - Client is not nil")
+	if m.session != nil {
+		return nil
 	}
 
+	session, err := NewSession(*m, SessionOptions{})
+	if err != nil {
+		slog.Error("Failed to create meter session", "meter_id", m.MeterID, "error", err)
+		return err
+	}
+	m.session = session
+	m.ownsSession = true
+
 	return nil
 }
 
-func (m *RealMeter) GetOBIS(obis string) (string, error) {
-	if m.client == nil {
-		slog.Error("client not initialized")
-		return "", fmt.Errorf("client not initialized")
+// Close releases the meter's association. A meter obtained from a
+// SessionPool leaves the pooled session open.
+func (m *RealMeter) Close() error {
+	if m.session == nil || !m.ownsSession {
+		return nil
+	}
+
+	err := m.session.Close()
+	m.session = nil
+	return err
+}
+
+// readProfile reads the first entry of a profile generic object on the
+// meter's session
+func readProfile[T any](ctx context.Context, m *RealMeter, obisCode string) ([]T, error) {
+	result, err := readProfileRows(ctx, m, obisCode)
+	if err != nil {
+		return nil, err
 	}
+	return mapProfileDataTyped[T](ctx, result)
+}
 
-	err := m.client.Connect()
-	defer m.client.Close()
+// readPhaseProfile reads the first entry of a profile generic object on the
+// meter's session as T, and as P too when its capture objects are per phase
+func readPhaseProfile[T, P any](ctx context.Context, m *RealMeter, obisCode string) (*T, *P, error) {
+	result, err := readProfileRows(ctx, m, obisCode)
 	if err != nil {
-		return "", fmt.Errorf("failed to connect to meter: %w", err)
+		return nil, nil, err
+	}
+	results, err := mapProfileDataTyped[T](ctx, result)
+	if err != nil || len(results) == 0 {
+		return nil, nil, err
+	}
+	if !IsThreePhase(result.ColumnNames) {
+		return &results[0], nil, nil
+	}
+	phases, err := mapProfileDataTyped[P](ctx, result)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &results[0], &phases[0], nil
+}
+
+// readProfileRows reads the first entry of a profile generic object on the
+// meter's session, unmapped
+func readProfileRows(ctx context.Context, m *RealMeter, obisCode string) (*DLMSResult, error) {
+	var result *DLMSResult
+	err := m.session.Do(ctx, func(ctx context.Context, c *MeterClient) error {
+		start := time.Now()
+		var err error
+		result, err = c.ProfileGenericReadRows(ctx, obisCode, 1, 1)
+		metrics.ObserveSince(metrics.ProfileReadDuration, start, err, obisCode)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	slog.Debug("profile rows", "obis", obisCode, "columns", result.ColumnNames, "rows", result.NumRows)
+	return result, nil
+}
+
+func (m *RealMeter) GetOBIS(ctx context.Context, obis string) (string, error) {
+	if m.session == nil {
+		return "", m.errNotInitialized()
+	}
+
+	if err := m.session.Do(ctx, func(ctx context.Context, c *MeterClient) error { return nil }); err != nil {
+		return "", err
 	}
 
 	return obis, nil
 }
 
-func (m *RealMeter) GetBlockLoadProfile() (*BlockLoadProfile, error) {
-	if m.client == nil {
-		slog.Error("client not initialized")
-		return nil, fmt.Errorf("client not initialized")
+// ReadAttribute reads a single attribute of a COSEM object
+func (m *RealMeter) ReadAttribute(ctx context.Context, obis string, objectType, attributeIndex int) (string, error) {
+	if m.session == nil {
+		return "", m.errNotInitialized()
 	}
 
-	err := m.client.Connect()
-	defer m.client.Close()
-	if err != nil {
-		return nil, fmt.Errorf("failed to connect to meter: %w", err)
+	return m.session.ReadAttribute(ctx, obis, objectType, attributeIndex)
+}
+
+// WriteAttribute writes a single attribute of a COSEM object
+func (m *RealMeter) WriteAttribute(ctx context.Context, obis string, objectType, attributeIndex int, value any) error {
+	if m.session == nil {
+		return m.errNotInitialized()
+	}
+
+	return m.session.WriteAttribute(ctx, obis, objectType, attributeIndex, value)
+}
+
+func (m *RealMeter) GetBlockLoadProfile(ctx context.Context) (*BlockLoadProfile, error) {
+	if m.session == nil {
+		return nil, m.errNotInitialized()
 	}
 
-	results, err := ReadProfileDataTyped[BlockLoadProfile](m.client, "1.0.99.1.0.255", 1, 1)
+	profile, phases, err := readPhaseProfile[BlockLoadProfile, ThreePhaseBlockLoadProfile](ctx, m, "1.0.99.1.0.255")
 	if err != nil {
 		return nil, fmt.Errorf("failed to read profile data: %w", err)
 	}
 
-	if len(results) == 0 {
+	if profile == nil {
 		return nil, fmt.Errorf("no data found")
 	}
+	profile.ThreePhase = phases
 
-	slog.Info("block load profile results", "results", results[0])
+	slog.Info("block load profile results", "results", *profile)
 
-	return &results[0], nil
+	return profile, nil
 }
 
-func (m *RealMeter) GetDailyLoadProfile() (*DailyLoadProfile, error) {
-	if m.client == nil {
-		slog.Error("client not initialized")
-		return nil, fmt.Errorf("client not initialized")
-	}
-
-	err := m.client.Connect()
-	defer m.client.Close()
-	if err != nil {
-		return nil, fmt.Errorf("failed to connect to meter: %w", err)
+func (m *RealMeter) GetDailyLoadProfile(ctx context.Context) (*DailyLoadProfile, error) {
+	if m.session == nil {
+		return nil, m.errNotInitialized()
 	}
 
-	results, err := ReadProfileDataTyped[DailyLoadProfile](m.client, "1.0.99.2.0.255", 1, 1)
+	results, err := readProfile[DailyLoadProfile](ctx, m, "1.0.99.2.0.255")
 	if err != nil {
 		return nil, fmt.Errorf("failed to read daily load profile data: %w", err)
 	}
@@ -124,19 +257,16 @@ func (m *RealMeter) GetDailyLoadProfile() (*DailyLoadProfile, error) {
 	return &results[0], nil
 }
 
-func (m *RealMeter) GetBillingDataProfile() (*BillingDataProfile, error) {
-	if m.client == nil {
-		slog.Error("client not initialized")
-		return nil, fmt.Errorf("client not initialized")
+func (m *RealMeter) GetBillingDataProfile(ctx context.Context) (*BillingDataProfile, error) {
+	if m.session == nil {
+		return nil, m.errNotInitialized()
 	}
 
-	err := m.client.Connect()
-	defer m.client.Close()
+	result, err := readProfileRows(ctx, m, "0.0.98.1.0.255")
 	if err != nil {
-		return nil, fmt.Errorf("failed to connect to meter: %w", err)
+		return nil, fmt.Errorf("failed to read billing data profile: %w", err)
 	}
-
-	results, err := ReadProfileDataTyped[BillingDataProfile](m.client, "0.0.98.1.0.255", 1, 1)
+	results, err := mapProfileDataTyped[BillingDataProfile](ctx, result)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read billing data profile: %w", err)
 	}
@@ -144,34 +274,73 @@ func (m *RealMeter) GetBillingDataProfile() (*BillingDataProfile, error) {
 	if len(results) == 0 {
 		return nil, fmt.Errorf("no billing data profile found")
 	}
+	results[0].TariffZones = mapTariffZones(result, 0)
 
 	slog.Info("billing data profile results", "results", results[0])
 
 	return &results[0], nil
 }
 
-func (m *RealMeter) GetInstantaneousProfile() (*InstantaneousProfile, error) {
-	if m.client == nil {
-		slog.Error("client not initialized")
-		return nil, fmt.Errorf("client not initialized")
+func (m *RealMeter) GetInstantaneousProfile(ctx context.Context) (*InstantaneousProfile, error) {
+	if m.session == nil {
+		return nil, m.errNotInitialized()
 	}
 
-	err := m.client.Connect()
-	defer m.client.Close()
+	profile, phases, err := readPhaseProfile[InstantaneousProfile, ThreePhaseInstantaneousProfile](ctx, m, "1.0.94.7.0.255")
 	if err != nil {
-		return nil, fmt.Errorf("failed to connect to meter: %w", err)
+		return nil, fmt.Errorf("failed to read instantaneous profile: %w", err)
 	}
 
-	results, err := ReadProfileDataTyped[InstantaneousProfile](m.client, "1.0.94.7.0.255", 1, 1)
+	if profile == nil {
+		return nil, fmt.Errorf("no instantaneous profile data found")
+	}
+	profile.ThreePhase = phases
+
+	slog.Info("instantaneous profile results", "results", *profile)
+
+	return profile, nil
+}
+
+// GetNameplate reads the meter's nameplate profile, or the objects it
+// captures one by one on meters without the profile or without access to it
+func (m *RealMeter) GetNameplate(ctx context.Context) (*NameplateProfile, error) {
+	if m.session == nil {
+		return nil, m.errNotInitialized()
+	}
+
+	results, err := readProfile[NameplateProfile](ctx, m, NameplateProfileOBIS)
+	if missingObject(err) {
+		slog.Debug("no nameplate profile, reading its objects", "meter", m)
+		results, err = readNameplateObjects(ctx, m)
+	}
 	if err != nil {
-		return nil, fmt.Errorf("failed to read instantaneous profile: %w", err)
+		return nil, fmt.Errorf("failed to read nameplate: %w", err)
 	}
 
 	if len(results) == 0 {
-		return nil, fmt.Errorf("no instantaneous profile data found")
+		return nil, fmt.Errorf("no nameplate data found")
 	}
+	nameplate := results[0]
+	nameplate.decode()
 
-	slog.Info("instantaneous profile results", "results", results[0])
+	slog.Info("nameplate results", "results", nameplate)
 
-	return &results[0], nil
+	return &nameplate, nil
+}
+
+// ReadProfile reads the profile def describes
+func (m *RealMeter) ReadProfile(ctx context.Context, def ProfileDefinition) (*ProfileData, error) {
+	if m.session == nil {
+		return nil, m.errNotInitialized()
+	}
+
+	result, err := readProfileRows(ctx, m, def.OBIS)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read profile %s: %w", def.Name, err)
+	}
+
+	data := mapDefinedProfile(result, def)
+	slog.Info("defined profile results", "profile", def.Name, "rows", len(data.Rows))
+
+	return data, nil
 }
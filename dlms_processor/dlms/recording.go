@@ -0,0 +1,308 @@
+package dlms
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// Recording is a conversation with a meter kept as a test fixture, to be
+// played back to a client by a Replay. Byte strings are in hex.
+type Recording struct {
+	MeterIP           string          `json:"meter_ip"`
+	MeterPort         int             `json:"meter_port"`
+	ClientAddress     int             `json:"client_address"`
+	ServerAddress     int             `json:"server_address"`
+	ClientSystemTitle string          `json:"client_system_title"`
+	ServerSystemTitle string          `json:"server_system_title"`
+	BlockCipherKey    string          `json:"block_cipher_key"`
+	AuthenticationKey string          `json:"authentication_key"`
+	Frames            []RecordedFrame `json:"frames"`
+}
+
+// RecordedFrame is one wrapper frame of a Recording
+type RecordedFrame struct {
+	Direction Direction `json:"direction"`
+	Data      string    `json:"data"`           // as sent or received, wrapper header included
+	APDU      string    `json:"apdu,omitempty"` // plaintext of a ciphered APDU, for the reader; replays decrypt Data
+}
+
+// MarshalText encodes the direction as its name
+func (d Direction) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// UnmarshalText decodes a direction's name
+func (d *Direction) UnmarshalText(text []byte) error {
+	switch string(text) {
+	case "sent":
+		*d = Sent
+	case "received":
+		*d = Received
+	default:
+		return fmt.Errorf("unknown frame direction %q", text)
+	}
+	return nil
+}
+
+// Recording returns the frames of t with the keys that cipher them. It
+// fails if frames were dropped or the last one is incomplete, as such a
+// conversation cannot be replayed.
+func (t *FrameTrace) Recording() (*Recording, error) {
+	if t.Truncated() {
+		return nil, fmt.Errorf("the trace is truncated")
+	}
+	// Replays and Rekey need the HIGH_GMAC challenge of the AARQ
+	frames := t.recorded()
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	r := &Recording{
+		MeterIP:           t.meterIP,
+		MeterPort:         t.meterPort,
+		ClientSystemTitle: hex.EncodeToString(t.clientTitle),
+		ServerSystemTitle: hex.EncodeToString(t.serverTitle),
+		BlockCipherKey:    hex.EncodeToString(t.blockKey),
+		AuthenticationKey: hex.EncodeToString(t.authKey),
+	}
+	for i, f := range frames {
+		if len(f.Data) < wrapperHeaderSize || binary.BigEndian.Uint16(f.Data[6:]) != uint16(len(f.Data)-wrapperHeaderSize) {
+			return nil, fmt.Errorf("frame %d is not a whole wrapper frame", i)
+		}
+		if i == 0 {
+			r.ClientAddress = int(binary.BigEndian.Uint16(f.Data[2:]))
+			r.ServerAddress = int(binary.BigEndian.Uint16(f.Data[4:]))
+		}
+		r.Frames = append(r.Frames, RecordedFrame{
+			Direction: f.Direction,
+			Data:      hex.EncodeToString(f.Data),
+			APDU:      hex.EncodeToString(f.APDU),
+		})
+	}
+	return r, nil
+}
+
+// LoadRecording reads a Recording saved with Save
+func LoadRecording(path string) (*Recording, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var r Recording
+	if err := json.Unmarshal(data, &r); err != nil {
+		return nil, fmt.Errorf("parsing recording %s: %w", path, err)
+	}
+	return &r, nil
+}
+
+// Save writes r to path as indented JSON
+func (r *Recording) Save(path string) error {
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
+
+// Rekey returns r with its ciphered APDUs and HIGH_GMAC challenge responses
+// ciphered with the given keys, in hex, instead of its own. A conversation
+// with a meter in the field can so be kept as a fixture without the meter's
+// keys.
+func (r *Recording) Rekey(blockCipherKey, authenticationKey string) (*Recording, error) {
+	c, err := r.decode()
+	if err != nil {
+		return nil, err
+	}
+	keys, err := decodeKeys(blockCipherKey, authenticationKey)
+	if err != nil {
+		return nil, err
+	}
+
+	out := *r
+	out.BlockCipherKey, out.AuthenticationKey = blockCipherKey, authenticationKey
+	out.Frames = make([]RecordedFrame, len(c.frames))
+	var hls challenges
+	for i, f := range c.frames {
+		hls.observe(f.Direction, f.Data, f.APDU)
+		if f.APDU != nil {
+			if _, err := hls.respond(keys, f.Direction, f.APDU, c.titles[f.Direction]); err != nil {
+				return nil, fmt.Errorf("frame %d: %w", i, err)
+			}
+			if err := reseal(keys, f.Data, f.APDU, c.titles[f.Direction]); err != nil {
+				return nil, fmt.Errorf("frame %d: %w", i, err)
+			}
+		}
+		out.Frames[i] = RecordedFrame{
+			Direction: f.Direction,
+			Data:      hex.EncodeToString(f.Data),
+			APDU:      hex.EncodeToString(f.APDU),
+		}
+	}
+	return &out, nil
+}
+
+// conversation is a decoded Recording
+type conversation struct {
+	keys   cipherKeys
+	titles [2][]byte // system titles of the client and the meter, by the direction they send in
+	frames []Frame   // with the plaintext of their ciphered APDUs
+}
+
+// decode decodes the hex of r and decrypts its ciphered APDUs, failing if
+// one cannot be decrypted with r's keys
+func (r *Recording) decode() (*conversation, error) {
+	keys, err := decodeKeys(r.BlockCipherKey, r.AuthenticationKey)
+	if err != nil {
+		return nil, err
+	}
+	c := &conversation{keys: keys}
+	for dir, title := range [2]string{r.ClientSystemTitle, r.ServerSystemTitle} {
+		if c.titles[dir], err = hex.DecodeString(title); err != nil || len(c.titles[dir]) != 8 {
+			return nil, fmt.Errorf("invalid system title %q", title)
+		}
+	}
+
+	for i, rf := range r.Frames {
+		data, err := hex.DecodeString(rf.Data)
+		if err != nil || len(data) <= wrapperHeaderSize {
+			return nil, fmt.Errorf("frame %d is not a wrapper frame", i)
+		}
+		f := Frame{Direction: rf.Direction, Data: data}
+		if b, _ := ciphered(data, c.titles[f.Direction]); b != nil {
+			if f.APDU = keys.decrypt(data, c.titles[f.Direction]); f.APDU == nil {
+				return nil, fmt.Errorf("frame %d cannot be decrypted with the recording's keys", i)
+			}
+		}
+		c.frames = append(c.frames, f)
+	}
+	return c, nil
+}
+
+// decodeKeys decodes a block cipher and authentication key in hex
+func decodeKeys(blockCipherKey, authenticationKey string) (cipherKeys, error) {
+	block, err := hex.DecodeString(blockCipherKey)
+	if err != nil || len(block) != 16 {
+		return cipherKeys{}, fmt.Errorf("invalid block cipher key")
+	}
+	auth, err := hex.DecodeString(authenticationKey)
+	if err != nil || len(auth) != 16 {
+		return cipherKeys{}, fmt.Errorf("invalid authentication key")
+	}
+	return cipherKeys{block, auth}, nil
+}
+
+// reseal replaces the ciphered APDU of frame, in place, with plain ciphered
+// by keys under the same security header and invocation counter
+func reseal(keys cipherKeys, frame, plain, title []byte) error {
+	b, title := ciphered(frame, title)
+	if b == nil {
+		return fmt.Errorf("the frame holds no ciphered APDU")
+	}
+	sealed, err := keys.seal(b[0], b[1:5], title, plain)
+	if err != nil {
+		return err
+	}
+	if len(sealed) != len(b) {
+		return fmt.Errorf("the ciphered APDU changed length")
+	}
+	copy(b, sealed)
+	return nil
+}
+
+// Tags of the authentication values of an AARQ and AARE
+const (
+	tagRespondingAuthenticationValue = 0xAA
+	tagCallingAuthenticationValue    = 0xAC
+)
+
+// challenges follows the HIGH_GMAC challenges of a conversation, which the
+// client and the meter answer in the reply_to_HLS action
+type challenges struct {
+	ctos, stoc []byte
+	hls        bool // the last frame sent was a reply_to_HLS request
+}
+
+// observe learns the challenges from a frame and its plaintext, if it was
+// ciphered
+func (c *challenges) observe(dir Direction, frame, plain []byte) {
+	if len(frame) <= wrapperHeaderSize {
+		return
+	}
+	apdu := frame[wrapperHeaderSize:]
+	switch apdu[0] {
+	case tagAARQ:
+		c.ctos = authenticationValue(apdu, tagCallingAuthenticationValue)
+	case tagAARE:
+		c.stoc = authenticationValue(apdu, tagRespondingAuthenticationValue)
+	}
+	if dir == Sent {
+		c.hls = hlsRequest(plain) != nil
+	}
+}
+
+// respond replaces, in place, the challenge response in the plaintext of a
+// reply_to_HLS request or its reply with the one keys give, reporting
+// whether plain held one
+func (c *challenges) respond(keys cipherKeys, dir Direction, plain, title []byte) (bool, error) {
+	f, challenge := hlsRequest(plain), c.stoc
+	if dir == Received {
+		if !c.hls {
+			return false, nil
+		}
+		f, challenge = hlsReply(plain), c.ctos
+	}
+	if f == nil {
+		return false, nil
+	}
+	if challenge == nil {
+		return false, fmt.Errorf("the reply_to_HLS action comes without a challenge")
+	}
+	response, err := keys.respond(f[0], f[1:5], title, challenge)
+	if err != nil {
+		return false, err
+	}
+	copy(f, response)
+	return true, nil
+}
+
+// authenticationValue returns the challenge in an authentication value
+// element of an AARQ or AARE, nil if there is none
+func authenticationValue(apdu []byte, tag byte) []byte {
+	// A charstring holding the challenge
+	v := associationElement(apdu, tag)
+	if len(v) < 2 || v[0] != 0x80 || len(v) < 2+int(v[1]) {
+		return nil
+	}
+	return append([]byte(nil), v[2:2+int(v[1])]...)
+}
+
+// hlsResponseSize is the size of f(challenge): the security header,
+// invocation counter and GMAC
+const hlsResponseSize = 17
+
+// hlsRequest returns f(StoC) in the plaintext of a reply_to_HLS action
+// request, a slice of plain; nil if it is not one
+func hlsRequest(plain []byte) []byte {
+	// action-request-normal, class 15, method 1, with an octet string parameter
+	if len(plain) != 15+hlsResponseSize || plain[0] != 0xC3 || plain[1] != 0x01 ||
+		plain[3] != 0 || plain[4] != 15 || plain[11] != 1 || plain[12] != 1 ||
+		plain[13] != 0x09 || plain[14] != hlsResponseSize {
+		return nil
+	}
+	return plain[15:]
+}
+
+// hlsReply returns f(CtoS) in the plaintext of the reply to a reply_to_HLS
+// action, a slice of plain; nil if it is not one
+func hlsReply(plain []byte) []byte {
+	// action-response-normal, success, with octet string data
+	if len(plain) != 8+hlsResponseSize || plain[0] != 0xC7 || plain[1] != 0x01 ||
+		plain[3] != 0 || plain[4] != 1 || plain[5] != 0 ||
+		plain[6] != 0x09 || plain[7] != hlsResponseSize {
+		return nil
+	}
+	return plain[8:]
+}
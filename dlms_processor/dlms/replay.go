@@ -0,0 +1,218 @@
+package dlms
+
+/*
+#include "dlms_shim.h"
+*/
+import "C"
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"runtime/cgo"
+	"sync"
+	"unsafe"
+)
+
+// Replay plays a Recording back to a MeterClient in place of a meter, so
+// that tests run the client's parsing against real meter data without a
+// socket. Each frame the client sends must match the recording's next one:
+// ciphered APDUs are compared decrypted and the client's HIGH_GMAC
+// challenge may differ, as the reply to it is ciphered again with the
+// recording's keys. Otherwise the call fails and Err tells why.
+type Replay struct {
+	recording *Recording
+
+	mu         sync.Mutex
+	c          *conversation
+	next       int    // index of the next recorded frame
+	sending    []byte // data of a frame the client has not finished sending
+	reply      []byte // rest of the reply the client is receiving
+	challenges challenges
+	err        error
+}
+
+// NewReplay returns a replay of r from its first frame
+func NewReplay(r *Recording) (*Replay, error) {
+	c, err := r.decode()
+	if err != nil {
+		return nil, err
+	}
+	return &Replay{recording: r, c: c}, nil
+}
+
+// Err returns how the client deviated from the recording, nil if it has not
+func (r *Replay) Err() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.err
+}
+
+// Remaining returns the number of recorded frames not yet played back
+func (r *Replay) Remaining() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.c.frames) - r.next
+}
+
+// Replay makes the client talk to r instead of a meter. The client takes
+// the addresses, system title and keys of r's recording, and opens no
+// socket when it connects.
+func (c *MeterClient) Replay(r *Replay) error {
+	rec := r.recording
+	err := c.Configure(&RealMeter{
+		MeterIP:           rec.MeterIP,
+		MeterPort:         rec.MeterPort,
+		SystemTitle:       rec.ClientSystemTitle,
+		BlockCipherKey:    rec.BlockCipherKey,
+		AuthenticationKey: rec.AuthenticationKey,
+		ClientAddress:     rec.ClientAddress,
+		ServerAddress:     rec.ServerAddress,
+	})
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.meter == nil {
+		return c.errNotInitialized()
+	}
+	if c.replay != 0 {
+		c.replay.Delete()
+	}
+	c.replay = cgo.NewHandle(r)
+	C.meter_set_replay(c.meter, C.uintptr_t(c.replay))
+	return nil
+}
+
+//export goReplaySend
+func goReplaySend(handle C.uintptr_t, data *C.uchar, size C.uint32_t) C.int {
+	r := cgo.Handle(handle).Value().(*Replay)
+	return C.int(r.send(C.GoBytes(unsafe.Pointer(data), C.int(size))))
+}
+
+//export goReplayReceive
+func goReplayReceive(handle C.uintptr_t, data *C.uchar, size C.uint32_t) C.int {
+	r := cgo.Handle(handle).Value().(*Replay)
+	return C.int(r.receive(unsafe.Slice((*byte)(unsafe.Pointer(data)), int(size))))
+}
+
+// send takes data the client sends, returning its size, or -1 once the
+// client deviated from the recording
+func (r *Replay) send(data []byte) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.err != nil {
+		return -1
+	}
+	r.sending = append(r.sending, data...)
+	for len(r.sending) >= wrapperHeaderSize {
+		n := wrapperHeaderSize + int(binary.BigEndian.Uint16(r.sending[6:]))
+		if len(r.sending) < n {
+			break
+		}
+		frame := r.sending[:n:n]
+		r.sending = r.sending[n:]
+		if r.err = r.expect(frame); r.err != nil {
+			return -1
+		}
+	}
+	return len(data)
+}
+
+// expect checks that frame is the recording's next one; the caller holds
+// r.mu
+func (r *Replay) expect(frame []byte) error {
+	if r.next == len(r.c.frames) {
+		return fmt.Errorf("replay: the client sent % x after the end of the recording", frame)
+	}
+	want := r.c.frames[r.next]
+	if want.Direction != Sent {
+		return fmt.Errorf("replay: the client sent % x where the meter replies with frame %d", frame, r.next)
+	}
+
+	plain := r.c.keys.decrypt(frame, r.c.titles[Sent])
+	if b, _ := ciphered(frame, r.c.titles[Sent]); b != nil && plain == nil {
+		return fmt.Errorf("replay: frame %d cannot be decrypted with the recording's keys", r.next)
+	}
+	got, expected := comparable(frame, plain), comparable(want.Data, want.APDU)
+	if !bytes.Equal(got, expected) {
+		return fmt.Errorf("replay: frame %d: the client sent % x, the recording has % x", r.next, got, expected)
+	}
+
+	r.challenges.observe(Sent, frame, plain)
+	r.next++
+	return nil
+}
+
+// receive fills buf with the recorded reply, returning its size, or -1 if
+// the recording has none
+func (r *Replay) receive(buf []byte) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.err != nil {
+		return -1
+	}
+	if len(r.reply) == 0 {
+		if r.reply, r.err = r.nextReply(); r.err != nil {
+			return -1
+		}
+	}
+	n := copy(buf, r.reply)
+	r.reply = r.reply[n:]
+	return n
+}
+
+// nextReply returns the recording's next frame, if the meter sends it, with
+// the reply to the client's challenge ciphered again; the caller holds r.mu
+func (r *Replay) nextReply() ([]byte, error) {
+	if r.next == len(r.c.frames) || r.c.frames[r.next].Direction != Received {
+		return nil, fmt.Errorf("replay: the client waits for a reply the recording does not have after frame %d", r.next-1)
+	}
+	f := r.c.frames[r.next]
+	frame := bytes.Clone(f.Data)
+
+	r.challenges.observe(Received, frame, f.APDU)
+	if f.APDU != nil {
+		plain := bytes.Clone(f.APDU)
+		answered, err := r.challenges.respond(r.c.keys, Received, plain, r.c.titles[Received])
+		if err != nil {
+			return nil, fmt.Errorf("replay: frame %d: %w", r.next, err)
+		}
+		if answered {
+			if err := reseal(r.c.keys, frame, plain, r.c.titles[Received]); err != nil {
+				return nil, fmt.Errorf("replay: frame %d: %w", r.next, err)
+			}
+		}
+	}
+	r.next++
+	return frame, nil
+}
+
+// comparable returns what of a frame the client must send as recorded: its
+// addresses and its APDU, with a ciphered APDU decrypted and an AARQ
+// without the client's challenge
+func comparable(frame, plain []byte) []byte {
+	if len(frame) <= wrapperHeaderSize {
+		return frame
+	}
+	// The version and addresses of the wrapper header
+	out := append([]byte(nil), frame[:6]...)
+	apdu := frame[wrapperHeaderSize:]
+	if apdu[0] == tagAARQ {
+		out = append(out, tagAARQ)
+		for _, e := range associationElements(apdu) {
+			if e[0] != tagCallingAuthenticationValue && e[0] != tagUserInformation {
+				out = append(out, e...)
+			}
+		}
+		return append(out, plain...)
+	}
+	if plain != nil {
+		return append(append(out, apdu[0]), plain...)
+	}
+	return append(out, apdu...)
+}
@@ -0,0 +1,110 @@
+package dlms
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+// simulatorRecording reads the block load profile and event log of the
+// meter simulator, with the keys 000102…0f. Regenerate it with
+//
+//	go test ./simulator -run TestRecordAndReplay -args -fixture $PWD/dlms/testdata/simulator.json
+const simulatorRecording = "testdata/simulator.json"
+
+type recordedEvent struct {
+	Time string `obis:"0.0.1.0.0.255" type:"string"`
+	Code uint16 `obis:"0.0.96.11.0.255" type:"uint16"`
+}
+
+// replayRecording returns a client connected to a replay of rec
+func replayRecording(t *testing.T, rec *Recording) (*MeterClient, *Replay) {
+	t.Helper()
+	replay, err := NewReplay(rec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := NewMeterClient()
+	t.Cleanup(client.Close)
+	if err := client.Replay(replay); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.Connect(context.Background()); err != nil {
+		t.Fatalf("Connect: %v (%v)", err, replay.Err())
+	}
+	return client, replay
+}
+
+func TestReplayParsesRecording(t *testing.T) {
+	rec, err := LoadRecording(simulatorRecording)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The same conversation ciphered with other keys reads the same
+	rekeyed, err := rec.Rekey("62626262626262626262626262626262", "63636363636363636363636363636363")
+	if err != nil {
+		t.Fatalf("Rekey: %v", err)
+	}
+
+	for _, rec := range []*Recording{rec, rekeyed} {
+		client, replay := replayRecording(t, rec)
+		ctx := context.Background()
+
+		blocks, err := ReadProfileDataTyped[BlockLoadProfile](ctx, client, "1.0.99.1.0.255", 1, 2)
+		if err != nil {
+			t.Fatalf("ReadProfileDataTyped: %v (%v)", err, replay.Err())
+		}
+		if len(blocks) != 2 || blocks[0].AverageVoltage != 229.8 || blocks[0].BlockEnergyVAhImport != 483.5 ||
+			blocks[1].AverageCurrent != 2.2 || !strings.HasPrefix(blocks[1].DateTime, "03/01/2026 11:30:00") {
+			t.Errorf("Unexpected block load entries %+v", blocks)
+		}
+
+		events, err := ReadProfileDataTyped[recordedEvent](ctx, client, "0.0.99.98.0.255", 1, 2)
+		if err != nil {
+			t.Fatalf("ReadProfileDataTyped: %v (%v)", err, replay.Err())
+		}
+		if len(events) != 2 || events[0].Code != 101 || events[1].Code != 102 {
+			t.Errorf("Unexpected events %+v", events)
+		}
+		if replay.Err() != nil || replay.Remaining() != 0 {
+			t.Errorf("Expected the whole recording replayed, %d frames left: %v", replay.Remaining(), replay.Err())
+		}
+	}
+}
+
+func TestReplayFailsOnDeviation(t *testing.T) {
+	rec, err := LoadRecording(simulatorRecording)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The events are read before the block load profile they follow, in the
+	// frame after the association and its HLS exchange
+	client, replay := replayRecording(t, rec)
+	if _, err := ReadProfileDataTyped[recordedEvent](context.Background(), client, "0.0.99.98.0.255", 1, 2); err == nil {
+		t.Fatal("Expected a read out of the recorded order to fail")
+	}
+	if err := replay.Err(); err == nil || !strings.Contains(err.Error(), "frame 4") {
+		t.Errorf("Expected the replay to report the fifth frame, got %v", err)
+	}
+
+	// A read past the end of the recording fails too
+	client, replay = replayRecording(t, rec)
+	for _, obis := range []string{"1.0.99.1.0.255", "0.0.99.98.0.255", "0.0.99.98.0.255"} {
+		_, err = client.ProfileGenericReadRows(context.Background(), obis, 1, 2)
+	}
+	if err == nil || replay.Err() == nil {
+		t.Errorf("Expected a read past the end of the recording to fail, got %v", err)
+	}
+}
+
+func TestNewReplayChecksKeys(t *testing.T) {
+	rec, err := LoadRecording(simulatorRecording)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rec.AuthenticationKey = "62626262626262626262626262626262"
+	if _, err := NewReplay(rec); err == nil {
+		t.Error("Expected a recording that its keys do not decrypt to be refused")
+	}
+}
@@ -0,0 +1,494 @@
+package dlms
+
+import (
+	"context"
+	"crypto/sha256"
+	"dlmsprocessor/metrics"
+	"dlmsprocessor/tracing"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Default session timings, used when SessionOptions leaves them unset
+const (
+	DefaultIdleTimeout       = 2 * time.Minute
+	DefaultKeepAliveInterval = 30 * time.Second
+)
+
+var ErrSessionClosed = errors.New("session closed")
+
+// SessionOptions controls how long a session holds its association open
+type SessionOptions struct {
+	IdleTimeout       time.Duration // release the association after this long without an operation
+	KeepAliveInterval time.Duration // send a keep-alive when the association has been quiet this long
+}
+
+func (o SessionOptions) withDefaults() SessionOptions {
+	if o.IdleTimeout <= 0 {
+		o.IdleTimeout = DefaultIdleTimeout
+	}
+	if o.KeepAliveInterval <= 0 {
+		o.KeepAliveInterval = DefaultKeepAliveInterval
+	}
+	return o
+}
+
+// Session keeps one association with a meter open across operations, so a
+// workflow such as "read, set clock, read again" needs a single handshake.
+// The association is opened on first use, kept alive while it is held and
+// released after the idle timeout; the next operation opens a new one.
+// Operations on a session are serialized, each waiting its turn only as long
+// as its context allows. Close frees it for good.
+type Session struct {
+	config RealMeter
+	opts   SessionOptions
+
+	// turn holds a token while an operation or keep-alive uses the client;
+	// the fields below it are only used by the holder
+	turn       chan struct{}
+	client     *MeterClient
+	associated bool      // counted in metrics.SessionsAssociated
+	lastSeen   time.Time // last traffic with the meter, including keep-alives
+
+	lastUsed atomic.Int64 // start or end of the last operation, in Unix nanoseconds
+	active   atomic.Int32 // operations running or waiting their turn
+	closed   atomic.Bool
+
+	mu        sync.Mutex
+	keepAlive context.CancelFunc // interrupts a keep-alive, set while one may run
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+// NewSession creates a session for the meter described by config. No
+// connection is made until the first operation.
+func NewSession(config RealMeter, opts SessionOptions) (*Session, error) {
+	client := NewMeterClient()
+	if client == nil {
+		return nil, fmt.Errorf("failed to create meter client")
+	}
+
+	if err := client.Configure(&config); err != nil {
+		client.Close()
+		return nil, fmt.Errorf("failed to configure meter: %w", err)
+	}
+
+	s := &Session{
+		config: config,
+		opts:   opts.withDefaults(),
+		client: client,
+		turn:   make(chan struct{}, 1),
+		stop:   make(chan struct{}),
+		done:   make(chan struct{}),
+	}
+	s.touch()
+	go s.maintain()
+
+	return s, nil
+}
+
+// Do runs fn with the session's client once an association is open. If fn
+// fails the association may be in an unknown state, so it is dropped and the
+// next operation opens a fresh one.
+func (s *Session) Do(ctx context.Context, fn func(ctx context.Context, c *MeterClient) error) error {
+	if s.closed.Load() {
+		return ErrSessionClosed
+	}
+	s.touch()
+	s.active.Add(1)
+	defer s.active.Add(-1)
+	if err := s.acquire(ctx); err != nil {
+		return err
+	}
+	defer s.release()
+
+	if s.closed.Load() {
+		return ErrSessionClosed
+	}
+
+	if err := s.associate(ctx); err != nil {
+		return err
+	}
+
+	err := fn(ctx, s.client)
+	s.touch()
+	s.lastSeen = time.Now()
+	if err != nil {
+		s.disconnect()
+	}
+
+	return err
+}
+
+// acquire waits for the session's turn until ctx is done. A keep-alive
+// holding it is interrupted rather than waited for.
+func (s *Session) acquire(ctx context.Context) error {
+	select {
+	case s.turn <- struct{}{}:
+		return nil
+	default:
+	}
+
+	s.mu.Lock()
+	if s.keepAlive != nil {
+		s.keepAlive()
+	}
+	s.mu.Unlock()
+
+	select {
+	case s.turn <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (s *Session) release() {
+	<-s.turn
+}
+
+// touch marks the session as used now
+func (s *Session) touch() {
+	s.lastUsed.Store(time.Now().UnixNano())
+}
+
+// idle is how long ago the session was last used, zero while an operation
+// is running or waiting
+func (s *Session) idle() time.Duration {
+	if s.active.Load() > 0 {
+		return 0
+	}
+	return time.Since(time.Unix(0, s.lastUsed.Load()))
+}
+
+// associate opens the association if needed and, when an expected logical
+// device name is configured, checks that the meter on the other end is the
+// right one. Must be called holding the session's turn.
+func (s *Session) associate(ctx context.Context) (err error) {
+	if s.client.IsConnected() {
+		return nil
+	}
+
+	ctx, span := tracer().Start(ctx, "dlms.associate", trace.WithAttributes(tracing.MeterAttributes(s.config.MeterID, s.config.MeterIP, s.config.MeterPort)...))
+	defer func() { tracing.End(span, err) }()
+
+	start := time.Now()
+	err = s.client.Connect(ctx)
+	metrics.ObserveSince(metrics.AssociationDuration, start, err)
+	if err != nil {
+		return fmt.Errorf("failed to connect to meter: %w", err)
+	}
+	s.associated = true
+	metrics.SessionsAssociated.Inc()
+
+	if s.config.LogicalDeviceName == "" {
+		return nil
+	}
+
+	ldn, err := s.client.ReadAttribute(ctx, LogicalDeviceNameOBIS, ObjectTypeData, 2)
+	if err == nil {
+		err = checkLogicalDeviceName(s.config.LogicalDeviceName, ldn)
+	} else {
+		err = fmt.Errorf("failed to read logical device name: %w", err)
+	}
+	if err != nil {
+		s.disconnect()
+		return err
+	}
+
+	return nil
+}
+
+// ReadAttribute reads a single attribute of a COSEM object
+func (s *Session) ReadAttribute(ctx context.Context, obisCode string, objectType, attributeIndex int) (string, error) {
+	var value string
+	err := s.Do(ctx, func(ctx context.Context, c *MeterClient) error {
+		var err error
+		value, err = c.ReadAttribute(ctx, obisCode, objectType, attributeIndex)
+		return err
+	})
+	return value, err
+}
+
+// WriteAttribute writes a single attribute of a COSEM object
+func (s *Session) WriteAttribute(ctx context.Context, obisCode string, objectType, attributeIndex int, value any) error {
+	return s.Do(ctx, func(ctx context.Context, c *MeterClient) error {
+		return c.WriteAttribute(ctx, obisCode, objectType, attributeIndex, value)
+	})
+}
+
+// ReadEncoded reads a single attribute of a COSEM object as its A-XDR
+// encoding
+func (s *Session) ReadEncoded(ctx context.Context, obisCode string, objectType, attributeIndex int) (Encoded, error) {
+	var value Encoded
+	err := s.Do(ctx, func(ctx context.Context, c *MeterClient) error {
+		var err error
+		value, err = c.ReadEncoded(ctx, obisCode, objectType, attributeIndex)
+		return err
+	})
+	return value, err
+}
+
+// Invoke calls a method of a COSEM object
+func (s *Session) Invoke(ctx context.Context, obisCode string, objectType, methodIndex int, data Encoded) error {
+	return s.Do(ctx, func(ctx context.Context, c *MeterClient) error {
+		return c.Invoke(ctx, obisCode, objectType, methodIndex, data)
+	})
+}
+
+// ReadClock reads the meter's current time
+func (s *Session) ReadClock(ctx context.Context) (string, error) {
+	return s.ReadAttribute(ctx, ClockOBIS, ObjectTypeClock, 2)
+}
+
+// SetClock sets the meter's current time
+func (s *Session) SetClock(ctx context.Context, t time.Time) error {
+	return s.Do(ctx, func(ctx context.Context, c *MeterClient) error {
+		return c.SetClock(ctx, t)
+	})
+}
+
+// Close releases the association and frees the client. Further operations
+// return ErrSessionClosed.
+func (s *Session) Close() error {
+	if s.closed.Swap(true) {
+		return nil
+	}
+	close(s.stop)
+
+	// Waits for the operation in flight, if any
+	s.acquire(context.Background())
+	s.client.Close()
+	s.released()
+	s.release()
+
+	<-s.done
+	return nil
+}
+
+// disconnect releases the association. Must be called holding the
+// session's turn.
+func (s *Session) disconnect() {
+	s.client.Disconnect()
+	s.released()
+}
+
+// released stops counting the association once it is gone
+func (s *Session) released() {
+	if s.associated {
+		s.associated = false
+		metrics.SessionsAssociated.Dec()
+	}
+}
+
+func (s *Session) isClosed() bool {
+	return s.closed.Load()
+}
+
+// maintain keeps a held association alive and releases it once idle
+func (s *Session) maintain() {
+	defer close(s.done)
+
+	ticker := time.NewTicker(min(s.opts.IdleTimeout, s.opts.KeepAliveInterval))
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.stop:
+			return
+		case <-ticker.C:
+			s.tick()
+		}
+	}
+}
+
+// tick releases an idle association or keeps a quiet one alive. It never
+// waits for the session: one that is in use is neither idle nor quiet.
+func (s *Session) tick() {
+	timeout := time.Duration(s.config.ConnectionTimeout) * time.Millisecond
+	if timeout <= 0 {
+		timeout = 5 * time.Second
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	// Published before taking the turn, so an operation that finds the
+	// turn taken can always interrupt the keep-alive
+	s.mu.Lock()
+	s.keepAlive = cancel
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		s.keepAlive = nil
+		s.mu.Unlock()
+	}()
+
+	select {
+	case s.turn <- struct{}{}:
+	default:
+		return
+	}
+	defer s.release()
+
+	if s.closed.Load() || !s.client.IsConnected() {
+		return
+	}
+
+	if s.idle() >= s.opts.IdleTimeout {
+		slog.Info("Releasing idle meter session", "meter_id", s.config.MeterID, "ip", s.config.MeterIP)
+		s.disconnect()
+		return
+	}
+
+	// An operation waiting already makes the association busy
+	if time.Since(s.lastSeen) < s.opts.KeepAliveInterval || ctx.Err() != nil {
+		return
+	}
+
+	if err := s.client.KeepAlive(ctx); err != nil {
+		slog.Warn("Meter session keep-alive failed", "meter_id", s.config.MeterID, "ip", s.config.MeterIP, "error", err)
+		s.disconnect()
+		return
+	}
+	s.lastSeen = time.Now()
+}
+
+// SessionPool shares sessions between callers that talk to the same meter
+// with the same credentials. Sessions are owned by the pool: callers must
+// not close them, and Close on the pool closes them all. A session left idle
+// past the idle timeout is evicted and closed, so the pool only holds the
+// meters in use.
+type SessionPool struct {
+	opts SessionOptions
+
+	mu       sync.Mutex
+	sessions map[string]*Session
+	closed   bool
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+func NewSessionPool(opts SessionOptions) *SessionPool {
+	p := &SessionPool{
+		opts:     opts.withDefaults(),
+		sessions: make(map[string]*Session),
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+	go p.evictIdle()
+
+	return p
+}
+
+// Get returns the pooled session for config, creating it on first use
+func (p *SessionPool) Get(config RealMeter) (*Session, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.closed {
+		return nil, ErrSessionClosed
+	}
+
+	key := sessionKey(config)
+	if s, ok := p.sessions[key]; ok && !s.isClosed() {
+		// Keeps it from being evicted before the caller uses it
+		s.touch()
+		return s, nil
+	}
+
+	s, err := NewSession(config, p.opts)
+	if err != nil {
+		return nil, err
+	}
+	p.sessions[key] = s
+
+	return s, nil
+}
+
+// Meter returns a RealMeter that runs its operations on the pooled session
+// for config. Closing the meter leaves the session open in the pool.
+func (p *SessionPool) Meter(config RealMeter) (*RealMeter, error) {
+	s, err := p.Get(config)
+	if err != nil {
+		return nil, err
+	}
+
+	config.session = s
+	config.ownsSession = false
+	return &config, nil
+}
+
+// Close closes every session in the pool
+func (p *SessionPool) Close() error {
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		return nil
+	}
+	sessions := p.sessions
+	p.sessions = nil
+	p.closed = true
+	p.mu.Unlock()
+
+	close(p.stop)
+	<-p.done
+
+	for _, s := range sessions {
+		s.Close()
+	}
+	return nil
+}
+
+// evictIdle removes sessions idle past the idle timeout from the pool and
+// closes them
+func (p *SessionPool) evictIdle() {
+	defer close(p.done)
+
+	ticker := time.NewTicker(p.opts.IdleTimeout / 2)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-p.stop:
+			return
+		case <-ticker.C:
+		}
+
+		var idle []*Session
+		p.mu.Lock()
+		for key, s := range p.sessions {
+			if s.isClosed() || s.idle() >= p.opts.IdleTimeout {
+				delete(p.sessions, key)
+				idle = append(idle, s)
+			}
+		}
+		p.mu.Unlock()
+
+		// Outside the lock: Close waits for an operation in flight
+		for _, s := range idle {
+			slog.Debug("Evicting idle meter session", "meter_id", s.config.MeterID, "ip", s.config.MeterIP)
+			s.Close()
+		}
+	}
+}
+
+// sessionKey identifies an association: the endpoint, the addressing and the
+// credentials. Timeouts and read options are left out so that callers with
+// different settings still share one association with the meter.
+func sessionKey(config RealMeter) string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\x00%d\x00%d\x00%d\x00%s\x00%s\x00%s\x00%s\x00%s",
+		config.MeterIP, config.MeterPort, config.ClientAddress, config.ServerAddress,
+		config.AuthPassword, config.SystemTitle, config.BlockCipherKey, config.AuthenticationKey,
+		config.LogicalDeviceName)
+	return hex.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,196 @@
+package dlms
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRealMeterReusableAcrossOperations(t *testing.T) {
+	ip, port := silentListener(t)
+
+	meter, err := NewRealMeter(testMeterConfig(ip, port))
+	if err != nil {
+		t.Fatalf("NewRealMeter: %v", err)
+	}
+	if err := meter.Connect(context.Background()); err != nil {
+		t.Fatalf("Connect: %v", err)
+	}
+	defer meter.Close()
+
+	// Each operation used to free the client on return, so the second one
+	// ran against freed memory
+	for i := range 3 {
+		ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+		_, err := meter.GetOBIS(ctx, "1.0.1.8.0.255")
+		cancel()
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("Call %d: expected context.DeadlineExceeded, got %v", i, err)
+		}
+	}
+}
+
+func TestRealMeterNotInitializedNamesMeter(t *testing.T) {
+	config := testMeterConfig("127.0.0.1", 4059)
+	config.MeterID = "MTR-0042"
+	meter, err := NewRealMeter(config)
+	if err != nil {
+		t.Fatalf("NewRealMeter: %v", err)
+	}
+
+	_, err = meter.GetOBIS(context.Background(), "1.0.1.8.0.255")
+	if err == nil {
+		t.Fatal("Expected an error before Connect")
+	}
+	if !strings.Contains(err.Error(), "MTR-0042") {
+		t.Errorf("Expected the error to name the meter, got %v", err)
+	}
+	if strings.Contains(err.Error(), config.AuthenticationKey) {
+		t.Errorf("Error leaks the meter's key: %v", err)
+	}
+}
+
+func TestSessionClose(t *testing.T) {
+	ip, port := silentListener(t)
+
+	session, err := NewSession(testMeterConfig(ip, port), SessionOptions{})
+	if err != nil {
+		t.Fatalf("NewSession: %v", err)
+	}
+
+	if err := session.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if err := session.Close(); err != nil {
+		t.Errorf("Second Close: %v", err)
+	}
+
+	if _, err := session.ReadClock(context.Background()); !errors.Is(err, ErrSessionClosed) {
+		t.Errorf("Expected ErrSessionClosed, got %v", err)
+	}
+}
+
+func TestSessionWaitHonorsContext(t *testing.T) {
+	ip, port := silentListener(t)
+
+	session, err := NewSession(testMeterConfig(ip, port), SessionOptions{})
+	if err != nil {
+		t.Fatalf("NewSession: %v", err)
+	}
+	defer session.Close()
+
+	// Holds the session's turn as a slow operation would
+	if err := session.acquire(context.Background()); err != nil {
+		t.Fatalf("acquire: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, err = session.ReadClock(ctx)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected context.DeadlineExceeded, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Waiting operation returned after %v", elapsed)
+	}
+
+	session.release()
+}
+
+func TestSessionPool(t *testing.T) {
+	pool := NewSessionPool(SessionOptions{})
+
+	config := testMeterConfig("127.0.0.1", 4059)
+	first, err := pool.Get(config)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+
+	t.Run("same meter shares a session", func(t *testing.T) {
+		other := config
+		other.ConnectionTimeout = 1000
+		other.MaxEntries = 5
+		s, err := pool.Get(other)
+		if err != nil {
+			t.Fatalf("Get: %v", err)
+		}
+		if s != first {
+			t.Errorf("Expected the pooled session to be reused")
+		}
+	})
+
+	t.Run("different credentials get their own session", func(t *testing.T) {
+		other := config
+		other.AuthenticationKey = "63636363636363636363636363636363"
+		s, err := pool.Get(other)
+		if err != nil {
+			t.Fatalf("Get: %v", err)
+		}
+		if s == first {
+			t.Errorf("Expected a separate session for different credentials")
+		}
+	})
+
+	t.Run("closing a pooled meter keeps the session", func(t *testing.T) {
+		meter, err := pool.Meter(config)
+		if err != nil {
+			t.Fatalf("Meter: %v", err)
+		}
+		if err := meter.Connect(context.Background()); err != nil {
+			t.Fatalf("Connect: %v", err)
+		}
+		if err := meter.Close(); err != nil {
+			t.Fatalf("Close: %v", err)
+		}
+		if first.isClosed() {
+			t.Errorf("Expected the pooled session to stay open")
+		}
+	})
+
+	if err := pool.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if !first.isClosed() {
+		t.Errorf("Expected pool Close to close its sessions")
+	}
+	if _, err := pool.Get(config); !errors.Is(err, ErrSessionClosed) {
+		t.Errorf("Expected ErrSessionClosed after Close, got %v", err)
+	}
+}
+
+func TestSessionPoolEvictsIdleSessions(t *testing.T) {
+	pool := NewSessionPool(SessionOptions{IdleTimeout: 50 * time.Millisecond})
+	defer pool.Close()
+
+	config := testMeterConfig("127.0.0.1", 4059)
+	first, err := pool.Get(config)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for !first.isClosed() && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if !first.isClosed() {
+		t.Fatalf("Expected the idle session to be closed")
+	}
+
+	pool.mu.Lock()
+	n := len(pool.sessions)
+	pool.mu.Unlock()
+	if n != 0 {
+		t.Errorf("Expected the idle session to be evicted, %d left", n)
+	}
+
+	s, err := pool.Get(config)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if s == first || s.isClosed() {
+		t.Errorf("Expected a fresh session after eviction")
+	}
+}
@@ -0,0 +1,193 @@
+package dlms
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net"
+	"sync"
+	"testing"
+	"time"
+)
+
+// peerBehaviour is how a misbehaving meter answers an association request
+type peerBehaviour int
+
+const (
+	peerSilent peerBehaviour = iota // never answers
+	peerCloser                      // hangs up straight away
+	peerEcho                        // sends every request back, which is not a valid reply
+)
+
+// misbehavingPeer starts a listener whose connections behave as b
+func misbehavingPeer(t *testing.T, b peerBehaviour) (string, int) {
+	t.Helper()
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var conns []net.Conn
+	t.Cleanup(func() {
+		lis.Close()
+		mu.Lock()
+		for _, conn := range conns {
+			conn.Close()
+		}
+		mu.Unlock()
+		wg.Wait()
+	})
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for {
+			conn, err := lis.Accept()
+			if err != nil {
+				return
+			}
+			switch b {
+			case peerCloser:
+				conn.Close()
+			case peerEcho:
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					defer conn.Close()
+					io.Copy(conn, conn)
+				}()
+			default:
+				mu.Lock()
+				conns = append(conns, conn)
+				mu.Unlock()
+			}
+		}
+	}()
+
+	addr := lis.Addr().(*net.TCPAddr)
+	return addr.IP.String(), addr.Port
+}
+
+// TestConcurrentSessionsStress runs thousands of sessions at once against
+// meters that never complete an association. Every session exercises the
+// Gurux encoders, socket I/O, cancellation and cleanup in parallel; run it
+// with -race to check the concurrency contract. The simulator package's test
+// of the same name covers associations that succeed.
+func TestConcurrentSessionsStress(t *testing.T) {
+	sessions := 2000
+	if testing.Short() {
+		sessions = 200
+	}
+
+	type peer struct {
+		ip   string
+		port int
+	}
+	var peers [3]peer
+	for b := range peers {
+		peers[b].ip, peers[b].port = misbehavingPeer(t, peerBehaviour(b))
+	}
+
+	pool := NewSessionPool(SessionOptions{})
+	defer pool.Close()
+
+	var wg sync.WaitGroup
+	errs := make(chan error, sessions)
+	start := time.Now()
+
+	for i := range sessions {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			b := peerBehaviour(i % len(peers))
+			config := testMeterConfig(peers[b].ip, peers[b].port)
+			config.ConnectionTimeout = 2000
+			// Distinct credentials so that every goroutine gets its own session
+			config.AuthPassword = fmt.Sprintf("password%08d", i)
+
+			var meter *RealMeter
+			var err error
+			if i%2 == 0 {
+				meter, err = pool.Meter(config)
+			} else {
+				meter, err = NewRealMeter(config)
+			}
+			if err != nil {
+				errs <- err
+				return
+			}
+			if err := meter.Connect(context.Background()); err != nil {
+				errs <- err
+				return
+			}
+			defer meter.Close()
+
+			for range 3 {
+				ctx, cancel := context.WithTimeout(context.Background(), time.Duration(50+rand.IntN(150))*time.Millisecond)
+				if rand.IntN(4) == 0 {
+					time.AfterFunc(time.Duration(rand.IntN(50))*time.Millisecond, cancel)
+				}
+				_, err := meter.GetInstantaneousProfile(ctx)
+				cancel()
+
+				if err == nil {
+					errs <- errors.New("association succeeded against a misbehaving peer")
+					return
+				}
+				if b == peerSilent && !errors.Is(err, context.DeadlineExceeded) && !errors.Is(err, context.Canceled) {
+					errs <- err
+					return
+				}
+			}
+		}()
+	}
+
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		t.Errorf("Session failed: %v", err)
+	}
+	t.Logf("%d sessions in %v", sessions, time.Since(start))
+}
+
+// TestSharedClientSerializes hammers one client from many goroutines, which
+// must be serialized rather than interleaved on the association
+func TestSharedClientSerializes(t *testing.T) {
+	ip, port := misbehavingPeer(t, peerEcho)
+
+	session, err := NewSession(testMeterConfig(ip, port), SessionOptions{})
+	if err != nil {
+		t.Fatalf("NewSession: %v", err)
+	}
+	defer session.Close()
+
+	var wg sync.WaitGroup
+	for range 100 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+			defer cancel()
+
+			switch rand.IntN(3) {
+			case 0:
+				session.ReadClock(ctx)
+			case 1:
+				session.SetClock(ctx, time.Now())
+			default:
+				session.Do(ctx, func(ctx context.Context, c *MeterClient) error {
+					c.IsConnected()
+					return c.KeepAlive(ctx)
+				})
+			}
+		}()
+	}
+	wg.Wait()
+}
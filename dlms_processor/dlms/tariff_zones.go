@@ -0,0 +1,101 @@
+package dlms
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// TariffZone is the time-of-use registers of one tariff zone in a billing
+// period. Meters capture as many zones as their tariff has, each register
+// carrying the zone in the E group of its OBIS code.
+type TariffZone struct {
+	Zone         int     `json:"zone"`
+	CumEnergyWh  float64 `json:"cum_energy_wh"`   // Cumulative Energy - Wh (OBIS: 1.0.1.8.zone.255)
+	CumEnergyVAh float64 `json:"cum_energy_vah"`  // Cumulative Energy - VAh (OBIS: 1.0.9.8.zone.255)
+	MDW          float64 `json:"md_w"`            // MD W (OBIS: 1.0.1.6.zone.255)
+	MDWDateTime  string  `json:"md_w_date_time"`  // MD W - Date & Time, its capture time
+	MDVA         float64 `json:"md_va"`           // MD VA (OBIS: 1.0.9.6.zone.255)
+	MDVADateTime string  `json:"md_va_date_time"` // MD VA - Date & Time, its capture time
+}
+
+// tariffZoneOBIS is the OBIS code of a tariff zone register: C is 1 for
+// active and 9 for apparent, D is 8 for energy and 6 for maximum demand
+func tariffZoneOBIS(c, d, zone int) string {
+	return fmt.Sprintf("1.0.%d.%d.%d.255", c, d, zone)
+}
+
+// tariffZoneOf returns the zone of a tariff zone register's OBIS code. Zone
+// 0 is the total over all zones, and tariff rates run from 1 to 63.
+func tariffZoneOf(obis string) (int, bool) {
+	groups := strings.Split(strings.TrimSpace(obis), ".")
+	if len(groups) != 6 || groups[0] != "1" || groups[1] != "0" || groups[5] != "255" {
+		return 0, false
+	}
+	if groups[2] != "1" && groups[2] != "9" || groups[3] != "8" && groups[3] != "6" {
+		return 0, false
+	}
+	zone, err := strconv.Atoi(groups[4])
+	if err != nil || zone < 1 || zone > 63 {
+		return 0, false
+	}
+	return zone, true
+}
+
+// TariffZones returns the zones a profile captures registers of, in order
+func TariffZones(captureObjects []string) []int {
+	var zones []int
+	for _, obis := range captureObjects {
+		if zone, ok := tariffZoneOf(obis); ok && !slices.Contains(zones, zone) {
+			zones = append(zones, zone)
+		}
+	}
+	slices.Sort(zones)
+	return zones
+}
+
+// mapTariffZones reads the tariff zone registers of a row of the billing
+// profile, for every zone its capture objects have
+func mapTariffZones(result *DLMSResult, rowIdx int) []TariffZone {
+	if rowIdx >= len(result.Data) {
+		return nil
+	}
+	row := result.Data[rowIdx]
+	cell := func(obis string, attribute Attribute) (string, bool) {
+		colIdx, ok := result.column(obis, attribute)
+		if !ok || colIdx >= len(row) {
+			return "", false
+		}
+		return row[colIdx], true
+	}
+	number := func(obis string) float64 {
+		value, ok := cell(obis, "")
+		if !ok {
+			return 0
+		}
+		parsed, err := parseFloat64(value)
+		if err != nil {
+			return 0 // Skip if parsing fails
+		}
+		return parsed
+	}
+	dateTime := func(obis string) string {
+		value, _ := cell(obis, "capture_time")
+		return parseDateTime(value)
+	}
+
+	var zones []TariffZone
+	for _, zone := range TariffZones(result.ColumnNames) {
+		zones = append(zones, TariffZone{
+			Zone:         zone,
+			CumEnergyWh:  number(tariffZoneOBIS(1, 8, zone)),
+			CumEnergyVAh: number(tariffZoneOBIS(9, 8, zone)),
+			MDW:          number(tariffZoneOBIS(1, 6, zone)),
+			MDWDateTime:  dateTime(tariffZoneOBIS(1, 6, zone)),
+			MDVA:         number(tariffZoneOBIS(9, 6, zone)),
+			MDVADateTime: dateTime(tariffZoneOBIS(9, 6, zone)),
+		})
+	}
+	return zones
+}
@@ -0,0 +1,47 @@
+package dlms
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestTariffZones(t *testing.T) {
+	objects := []string{
+		"0.0.0.1.2.255", "1.0.1.8.0.255", "1.0.1.8.6.255", "1.0.1.8.1.255", "1.0.9.8.1.255",
+		"1.0.1.6.0.255", "1.0.9.6.8.255", "1.0.1.29.3.255", "1.0.1.8.64.255", "0.0.1.8.2.255",
+	}
+	if got := TariffZones(objects); !slices.Equal(got, []int{1, 6, 8}) {
+		t.Errorf("Expected zones 1, 6 and 8, got %v", got)
+	}
+}
+
+func TestMapTariffZones(t *testing.T) {
+	// Six zones, with the maximum demand of the sixth and its capture time
+	result := &DLMSResult{NumRows: 1}
+	var row []string
+	for zone := 1; zone <= 6; zone++ {
+		result.ColumnNames = append(result.ColumnNames, tariffZoneOBIS(1, 8, zone), tariffZoneOBIS(9, 8, zone))
+		result.ColumnAttributes = append(result.ColumnAttributes, 2, 2)
+		row = append(row, "100", "110")
+	}
+	result.ColumnNames = append(result.ColumnNames, tariffZoneOBIS(1, 6, 6), tariffZoneOBIS(1, 6, 6))
+	result.ColumnClasses = make([]int, len(result.ColumnNames))
+	result.ColumnClasses[12], result.ColumnClasses[13] = ObjectTypeExtendedRegister, ObjectTypeExtendedRegister
+	result.ColumnAttributes = append(result.ColumnAttributes, 5, 2)
+	row = append(row, "Hex:07EA0A130116000000800000", "1500")
+	result.Data = [][]string{row}
+
+	zones := mapTariffZones(result, 0)
+	if len(zones) != 6 {
+		t.Fatalf("Expected six zones, got %+v", zones)
+	}
+	if z := zones[0]; z.Zone != 1 || z.CumEnergyWh != 100 || z.CumEnergyVAh != 110 || z.MDW != 0 || z.MDWDateTime != "" {
+		t.Errorf("Unexpected first zone %+v", z)
+	}
+	if z := zones[5]; z.Zone != 6 || z.MDW != 1500 || z.MDWDateTime != "10/19/2026 22:00:00 UTC+00:00" || z.MDVA != 0 {
+		t.Errorf("Unexpected sixth zone %+v", z)
+	}
+	if zones := mapTariffZones(result, 1); zones != nil {
+		t.Errorf("Expected no zones past the rows, got %+v", zones)
+	}
+}
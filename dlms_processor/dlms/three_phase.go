@@ -0,0 +1,76 @@
+package dlms
+
+import (
+	"strconv"
+	"strings"
+)
+
+// ThreePhaseInstantaneousProfile is the instantaneous profile entry of a
+// three-phase meter: CT and LT three-phase and HT meters report R, Y and B
+// phase values
+type ThreePhaseInstantaneousProfile struct {
+	DateTime           string  `obis:"0.0.1.0.0.255" type:"string" json:"date_time"`               // RTC - Date & Time
+	CurrentR           float64 `obis:"1.0.31.7.0.255" type:"float64" json:"current_r"`             // Current - IR
+	CurrentY           float64 `obis:"1.0.51.7.0.255" type:"float64" json:"current_y"`             // Current - IY
+	CurrentB           float64 `obis:"1.0.71.7.0.255" type:"float64" json:"current_b"`             // Current - IB
+	VoltageR           float64 `obis:"1.0.32.7.0.255" type:"float64" json:"voltage_r"`             // Voltage - VRN
+	VoltageY           float64 `obis:"1.0.52.7.0.255" type:"float64" json:"voltage_y"`             // Voltage - VYN
+	VoltageB           float64 `obis:"1.0.72.7.0.255" type:"float64" json:"voltage_b"`             // Voltage - VBN
+	PowerFactorR       float64 `obis:"1.0.33.7.0.255" type:"float64" json:"power_factor_r"`        // Signed Power Factor - R phase
+	PowerFactorY       float64 `obis:"1.0.53.7.0.255" type:"float64" json:"power_factor_y"`        // Signed Power Factor - Y phase
+	PowerFactorB       float64 `obis:"1.0.73.7.0.255" type:"float64" json:"power_factor_b"`        // Signed Power Factor - B phase
+	PowerFactor        float64 `obis:"1.0.13.7.0.255" type:"float64" json:"power_factor"`          // Three Phase Power Factor - PF
+	Frequency          float64 `obis:"1.0.14.7.0.255" type:"float64" json:"frequency"`             // Frequency
+	ActivePowerR       float64 `obis:"1.0.21.7.0.255" type:"float64" json:"active_power_r"`        // Active Power - W, R phase
+	ActivePowerY       float64 `obis:"1.0.41.7.0.255" type:"float64" json:"active_power_y"`        // Active Power - W, Y phase
+	ActivePowerB       float64 `obis:"1.0.61.7.0.255" type:"float64" json:"active_power_b"`        // Active Power - W, B phase
+	ReactivePowerR     float64 `obis:"1.0.23.7.0.255" type:"float64" json:"reactive_power_r"`      // Reactive Power - var, R phase
+	ReactivePowerY     float64 `obis:"1.0.43.7.0.255" type:"float64" json:"reactive_power_y"`      // Reactive Power - var, Y phase
+	ReactivePowerB     float64 `obis:"1.0.63.7.0.255" type:"float64" json:"reactive_power_b"`      // Reactive Power - var, B phase
+	ApparentPowerR     float64 `obis:"1.0.29.7.0.255" type:"float64" json:"apparent_power_r"`      // Apparent Power - VA, R phase
+	ApparentPowerY     float64 `obis:"1.0.49.7.0.255" type:"float64" json:"apparent_power_y"`      // Apparent Power - VA, Y phase
+	ApparentPowerB     float64 `obis:"1.0.69.7.0.255" type:"float64" json:"apparent_power_b"`      // Apparent Power - VA, B phase
+	ActivePower        float64 `obis:"1.0.1.7.0.255" type:"float64" json:"active_power"`           // Signed Active Power - W
+	ReactivePower      float64 `obis:"1.0.3.7.0.255" type:"float64" json:"reactive_power"`         // Signed Reactive Power - var
+	ApparentPower      float64 `obis:"1.0.9.7.0.255" type:"float64" json:"apparent_power"`         // Apparent Power - VA
+	CumEnergyWhImport  float64 `obis:"1.0.1.8.0.255" type:"float64" json:"cum_energy_wh_import"`   // Cumulative Energy - Wh (import)
+	CumEnergyWhExport  float64 `obis:"1.0.2.8.0.255" type:"float64" json:"cum_energy_wh_export"`   // Cumulative Energy - Wh (export)
+	CumEnergyVarhLag   float64 `obis:"1.0.5.8.0.255" type:"float64" json:"cum_energy_varh_lag"`    // Cumulative Energy - varh, lag
+	CumEnergyVarhLead  float64 `obis:"1.0.8.8.0.255" type:"float64" json:"cum_energy_varh_lead"`   // Cumulative Energy - varh, lead
+	CumEnergyVAhImport float64 `obis:"1.0.9.8.0.255" type:"float64" json:"cum_energy_vah_import"`  // Cumulative Energy - VAh (import)
+	CumEnergyVAhExport float64 `obis:"1.0.10.8.0.255" type:"float64" json:"cum_energy_vah_export"` // Cumulative Energy - VAh (export)
+}
+
+// ThreePhaseBlockLoadProfile is the block load profile entry of a
+// three-phase meter, with the R, Y and B phase averages over the block
+type ThreePhaseBlockLoadProfile struct {
+	DateTime             string  `obis:"0.0.1.0.0.255" type:"string" json:"date_time"`                  // Real Time Clock
+	CurrentR             float64 `obis:"1.0.31.27.0.255" type:"float64" json:"current_r"`               // Average Current - IR
+	CurrentY             float64 `obis:"1.0.51.27.0.255" type:"float64" json:"current_y"`               // Average Current - IY
+	CurrentB             float64 `obis:"1.0.71.27.0.255" type:"float64" json:"current_b"`               // Average Current - IB
+	VoltageR             float64 `obis:"1.0.32.27.0.255" type:"float64" json:"voltage_r"`               // Average Voltage - VRN
+	VoltageY             float64 `obis:"1.0.52.27.0.255" type:"float64" json:"voltage_y"`               // Average Voltage - VYN
+	VoltageB             float64 `obis:"1.0.72.27.0.255" type:"float64" json:"voltage_b"`               // Average Voltage - VBN
+	BlockEnergyWhImport  float64 `obis:"1.0.1.29.0.255" type:"float64" json:"block_energy_wh_import"`   // Block Energy - Wh (import)
+	BlockEnergyVarhLag   float64 `obis:"1.0.5.29.0.255" type:"float64" json:"block_energy_varh_lag"`    // Block Energy - varh, lag
+	BlockEnergyVarhLead  float64 `obis:"1.0.8.29.0.255" type:"float64" json:"block_energy_varh_lead"`   // Block Energy - varh, lead
+	BlockEnergyVAhImport float64 `obis:"1.0.9.29.0.255" type:"float64" json:"block_energy_vah_import"`  // Block Energy - VAh (import)
+	BlockEnergyWhExport  float64 `obis:"1.0.2.29.0.255" type:"float64" json:"block_energy_wh_export"`   // Block Energy - Wh (export)
+	BlockEnergyVAhExport float64 `obis:"1.0.10.29.0.255" type:"float64" json:"block_energy_vah_export"` // Block Energy - VAh (export)
+}
+
+// IsThreePhase reports whether the capture objects of a profile are per
+// phase: electricity quantities of L1, L2 or L3, whose OBIS C group is
+// between 21 and 80
+func IsThreePhase(captureObjects []string) bool {
+	for _, obis := range captureObjects {
+		groups := strings.Split(strings.TrimSpace(obis), ".")
+		if len(groups) != 6 || groups[0] != "1" {
+			continue
+		}
+		if c, err := strconv.Atoi(groups[2]); err == nil && c >= 21 && c <= 80 {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,26 @@
+package dlms
+
+import "testing"
+
+func TestIsThreePhase(t *testing.T) {
+	testCases := []struct {
+		name    string
+		objects []string
+		want    bool
+	}{
+		{name: "single phase", objects: []string{"0.0.1.0.0.255", "1.0.12.7.0.255", "1.0.11.7.0.255", "1.0.1.8.0.255"}, want: false},
+		{name: "phase currents", objects: []string{"0.0.1.0.0.255", "1.0.31.7.0.255", "1.0.51.7.0.255", "1.0.71.7.0.255"}, want: true},
+		{name: "phase block averages", objects: []string{"0.0.1.0.0.255", "1.0.32.27.0.255"}, want: true},
+		{name: "not electricity", objects: []string{"0.0.31.7.0.255", "7.0.52.7.0.255"}, want: false},
+		{name: "malformed", objects: []string{"1.0.31", ""}, want: false},
+		{name: "none", want: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := IsThreePhase(tc.objects); got != tc.want {
+				t.Errorf("IsThreePhase(%v) = %v, want %v", tc.objects, got, tc.want)
+			}
+		})
+	}
+}
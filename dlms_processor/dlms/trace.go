@@ -0,0 +1,56 @@
+package dlms
+
+import (
+	"context"
+	"dlmsprocessor/tracing"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracer looks the provider up on every span, as it may be replaced after
+// this package is loaded
+func tracer() trace.Tracer {
+	return otel.GetTracerProvider().Tracer("dlmsprocessor/dlms")
+}
+
+// startCOSEM starts the span of one COSEM service request, such as
+// cosem.get, cosem.set or cosem.action. The span covers every block of a
+// block transfer.
+func startCOSEM(ctx context.Context, name, obisCode string, objectType, index int) (context.Context, trace.Span) {
+	return tracer().Start(ctx, name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("dlms.obis", obisCode),
+			attribute.Int("dlms.object_type", objectType),
+			attribute.Int("dlms.index", index),
+		))
+}
+
+// traceConnect records an association attempt that began at start as a TCP
+// connect span and an AARQ/AARE span, split where the shim reports the
+// socket opened; socketOpenedMs is 0 if the connect never succeeded
+func traceConnect(ctx context.Context, start time.Time, socketOpenedMs int64, err error) {
+	end := time.Now()
+
+	_, connect := tracer().Start(ctx, "tcp.connect", trace.WithSpanKind(trace.SpanKindClient), trace.WithTimestamp(start))
+	if socketOpenedMs == 0 {
+		tracing.End(connect, err, trace.WithTimestamp(end))
+		return
+	}
+
+	// The shim's clock has millisecond resolution, so keep the split inside the attempt
+	opened := time.UnixMilli(socketOpenedMs)
+	if opened.Before(start) {
+		opened = start
+	}
+	if opened.After(end) {
+		opened = end
+	}
+	connect.End(trace.WithTimestamp(opened))
+
+	_, aarq := tracer().Start(ctx, "dlms.aarq", trace.WithSpanKind(trace.SpanKindClient), trace.WithTimestamp(opened))
+	tracing.End(aarq, err, trace.WithTimestamp(end))
+}
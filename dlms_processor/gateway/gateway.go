@@ -0,0 +1,178 @@
+// Package gateway serves the DLMSProcessor reads as HTTP/JSON, for callers
+// that cannot speak gRPC. Requests are translated by grpc-gateway from the
+// google.api.http annotations in dlmsprocessor.proto and forwarded to the
+// gRPC server in the same process.
+package gateway
+
+import (
+	"context"
+	"dlmsprocessor/proto"
+	_ "embed"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// Media types a caller can ask for with the Accept header. Every streamed
+// message is one {"result": ...} object; a failure part way through a stream
+// ends it with an {"error": ...} object.
+const (
+	MIMEJSON   = "application/json"     // newline-delimited, the default
+	MIMENDJSON = "application/x-ndjson" // the same, labelled as NDJSON
+	MIMESSE    = "text/event-stream"    // server-sent events, one data: event per message
+)
+
+// OpenAPIPath serves the OpenAPI (Swagger 2.0) document of the HTTP API
+const OpenAPIPath = "/openapi.json"
+
+//go:embed dlmsprocessor.swagger.json
+var openAPI []byte
+
+// Listener is the in-memory listener the gateway reaches the gRPC server
+// through. Serve a gRPC server on it before handling HTTP requests.
+type Listener struct {
+	conns     chan net.Conn
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// NewListener returns an in-memory listener for the gateway's gRPC server
+func NewListener() *Listener {
+	return &Listener{
+		conns: make(chan net.Conn),
+		done:  make(chan struct{}),
+	}
+}
+
+// Accept waits for the gateway to dial and returns the server's end of the
+// connection
+func (lis *Listener) Accept() (net.Conn, error) {
+	select {
+	case conn := <-lis.conns:
+		return conn, nil
+	case <-lis.done:
+		return nil, net.ErrClosed
+	}
+}
+
+// Close stops lis accepting; connections already made stay open
+func (lis *Listener) Close() error {
+	lis.closeOnce.Do(func() { close(lis.done) })
+	return nil
+}
+
+// Addr returns the listener's placeholder address
+func (lis *Listener) Addr() net.Addr {
+	return pipeAddr{}
+}
+
+// DialContext connects to the server accepting on lis over a net.Pipe
+func (lis *Listener) DialContext(ctx context.Context) (net.Conn, error) {
+	client, server := net.Pipe()
+	select {
+	case lis.conns <- server:
+		return client, nil
+	case <-lis.done:
+		client.Close()
+		server.Close()
+		return nil, net.ErrClosed
+	case <-ctx.Done():
+		client.Close()
+		server.Close()
+		return nil, ctx.Err()
+	}
+}
+
+// pipeAddr is the address of both ends of the gateway's connections
+type pipeAddr struct{}
+
+func (pipeAddr) Network() string { return "pipe" }
+func (pipeAddr) String() string  { return "gateway" }
+
+// Dial connects to the gRPC server serving lis
+func (lis *Listener) Dial(opts ...grpc.DialOption) (*grpc.ClientConn, error) {
+	opts = append([]grpc.DialOption{
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	}, opts...)
+	conn, err := grpc.NewClient("passthrough:///gateway", opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect the gateway: %w", err)
+	}
+	return conn, nil
+}
+
+// NewHandler returns the HTTP handler for the API, forwarding calls over conn.
+// The Authorization header is passed on, so bearer tokens authorize HTTP
+// callers as they do gRPC ones, and W3C trace context headers continue the
+// caller's trace.
+func NewHandler(ctx context.Context, conn *grpc.ClientConn) (http.Handler, error) {
+	json := &runtime.JSONPb{
+		MarshalOptions:   protojson.MarshalOptions{EmitUnpopulated: true},
+		UnmarshalOptions: protojson.UnmarshalOptions{DiscardUnknown: true},
+	}
+	mux := runtime.NewServeMux(
+		runtime.WithMarshalerOption(runtime.MIMEWildcard, json),
+		runtime.WithMarshalerOption(MIMENDJSON, &ndjson{json}),
+		runtime.WithMarshalerOption(MIMESSE, &sse{json}),
+		runtime.WithIncomingHeaderMatcher(incomingHeader),
+	)
+	if err := proto.RegisterDLMSProcessorHandler(ctx, mux, conn); err != nil {
+		return nil, fmt.Errorf("failed to register the gateway: %w", err)
+	}
+
+	handler := http.NewServeMux()
+	handler.HandleFunc("GET "+OpenAPIPath, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", MIMEJSON)
+		w.Write(openAPI)
+	})
+	handler.Handle("/", mux)
+	return handler, nil
+}
+
+// incomingHeader forwards trace context as is, so the gRPC server continues
+// the trace, and everything else the way grpc-gateway does by default
+func incomingHeader(key string) (string, bool) {
+	switch k := strings.ToLower(key); k {
+	case "traceparent", "tracestate", "baggage":
+		return k, true
+	}
+	return runtime.DefaultHeaderMatcher(key)
+}
+
+// ndjson is JSON with the NDJSON content type on streams
+type ndjson struct {
+	*runtime.JSONPb
+}
+
+func (m *ndjson) ContentType(any) string {
+	return MIMENDJSON
+}
+
+// sse frames every message as a server-sent event
+type sse struct {
+	*runtime.JSONPb
+}
+
+func (m *sse) ContentType(any) string {
+	return MIMESSE
+}
+
+func (m *sse) Marshal(v any) ([]byte, error) {
+	b, err := m.JSONPb.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte("data: "), b...), nil
+}
+
+func (m *sse) Delimiter() []byte {
+	return []byte("\n\n")
+}
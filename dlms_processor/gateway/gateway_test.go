@@ -0,0 +1,215 @@
+package gateway
+
+import (
+	"bufio"
+	"context"
+	"dlmsprocessor/proto"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// stubProcessor answers GetOBIS with one reading per meter, failing meters
+// without an IP
+type stubProcessor struct {
+	proto.UnimplementedDLMSProcessorServer
+	authorization chan string
+}
+
+func (p *stubProcessor) GetOBIS(req *proto.GetOBISRequest, stream grpc.ServerStreamingServer[proto.GetOBISResponse]) error {
+	if len(req.Meter) == 0 {
+		return status.Error(codes.InvalidArgument, "no meters provided")
+	}
+	if md, ok := metadata.FromIncomingContext(stream.Context()); ok && p.authorization != nil {
+		p.authorization <- strings.Join(md.Get("authorization"), ",")
+	}
+	for _, meter := range req.Meter {
+		if meter.Ip == "" {
+			return status.Error(codes.Unavailable, "meter unreachable")
+		}
+		if err := stream.Send(&proto.GetOBISResponse{MeterId: meter.MeterId, MeterIp: meter.Ip, Value: "42"}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func newTestGateway(t *testing.T, processor proto.DLMSProcessorServer) *httptest.Server {
+	t.Helper()
+
+	lis := NewListener()
+	s := grpc.NewServer()
+	proto.RegisterDLMSProcessorServer(s, processor)
+	go s.Serve(lis)
+	t.Cleanup(s.Stop)
+
+	conn, err := lis.Dial()
+	if err != nil {
+		t.Fatalf("Dial: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	handler, err := NewHandler(context.Background(), conn)
+	if err != nil {
+		t.Fatalf("NewHandler: %v", err)
+	}
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func post(t *testing.T, server *httptest.Server, path, accept, body string) *http.Response {
+	t.Helper()
+
+	req, err := http.NewRequest(http.MethodPost, server.URL+path, strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("NewRequest: %v", err)
+	}
+	req.Header.Set("Content-Type", MIMEJSON)
+	req.Header.Set("Authorization", "Bearer secret")
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+	resp, err := server.Client().Do(req)
+	if err != nil {
+		t.Fatalf("POST %s: %v", path, err)
+	}
+	t.Cleanup(func() { resp.Body.Close() })
+	return resp
+}
+
+// streamResult is one message of a streamed response
+type streamResult struct {
+	Result *struct {
+		MeterID string `json:"meterId"`
+		Value   string `json:"value"`
+	} `json:"result"`
+	Error *struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+func TestStreamsNDJSON(t *testing.T) {
+	processor := &stubProcessor{authorization: make(chan string, 1)}
+	server := newTestGateway(t, processor)
+
+	resp := post(t, server, "/v1/obis:read", MIMENDJSON,
+		`{"obis": "1.0.1.8.0.255", "meter": [{"meterId": "a", "ip": "10.0.0.1"}, {"meterId": "b", "ip": "10.0.0.2"}, {"meterId": "c"}]}`)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Expected 200, got %s", resp.Status)
+	}
+	if ct := resp.Header.Get("Content-Type"); ct != MIMENDJSON {
+		t.Errorf("Expected content type %s, got %s", MIMENDJSON, ct)
+	}
+	if got := <-processor.authorization; got != "Bearer secret" {
+		t.Errorf("Expected the Authorization header to be forwarded, got %q", got)
+	}
+
+	var results []streamResult
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		var r streamResult
+		if err := json.Unmarshal(scanner.Bytes(), &r); err != nil {
+			t.Fatalf("Line %q is not JSON: %v", scanner.Text(), err)
+		}
+		results = append(results, r)
+	}
+	if len(results) != 3 {
+		t.Fatalf("Expected 2 results and an error, got %d lines", len(results))
+	}
+	for i, id := range []string{"a", "b"} {
+		if r := results[i].Result; r == nil || r.MeterID != id || r.Value != "42" {
+			t.Errorf("Expected a reading from meter %s, got %+v", id, results[i])
+		}
+	}
+	if e := results[2].Error; e == nil || codes.Code(e.Code) != codes.Unavailable {
+		t.Errorf("Expected the stream to end with UNAVAILABLE, got %+v", results[2])
+	}
+}
+
+func TestStreamsServerSentEvents(t *testing.T) {
+	server := newTestGateway(t, &stubProcessor{})
+
+	resp := post(t, server, "/v1/obis:read", MIMESSE, `{"obis": "1.0.1.8.0.255", "meter": [{"meterId": "a", "ip": "10.0.0.1"}]}`)
+	if ct := resp.Header.Get("Content-Type"); ct != MIMESSE {
+		t.Errorf("Expected content type %s, got %s", MIMESSE, ct)
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("ReadAll: %v", err)
+	}
+
+	events := strings.Split(strings.TrimSuffix(string(body), "\n\n"), "\n\n")
+	if len(events) != 1 || !strings.HasPrefix(events[0], "data: ") {
+		t.Fatalf("Expected one data event, got %q", body)
+	}
+	var r streamResult
+	if err := json.Unmarshal([]byte(strings.TrimPrefix(events[0], "data: ")), &r); err != nil || r.Result == nil || r.Result.MeterID != "a" {
+		t.Errorf("Expected a reading from meter a, got %q (%v)", events[0], err)
+	}
+}
+
+func TestErrorStatus(t *testing.T) {
+	server := newTestGateway(t, &stubProcessor{})
+
+	if resp := post(t, server, "/v1/obis:read", "", `{"meter": []}`); resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("Expected INVALID_ARGUMENT as 400, got %s", resp.Status)
+	}
+	if resp := post(t, server, "/v1/profiles/daily-load:read", "", `{}`); resp.StatusCode != http.StatusNotImplemented {
+		t.Errorf("Expected UNIMPLEMENTED as 501, got %s", resp.Status)
+	}
+	if resp := post(t, server, "/v1/obis:read", "", `{"meter": `); resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("Expected a malformed body to be refused with 400, got %s", resp.Status)
+	}
+}
+
+func TestServesOpenAPI(t *testing.T) {
+	server := newTestGateway(t, &stubProcessor{})
+
+	resp, err := server.Client().Get(server.URL + OpenAPIPath)
+	if err != nil {
+		t.Fatalf("GET %s: %v", OpenAPIPath, err)
+	}
+	defer resp.Body.Close()
+
+	var doc struct {
+		Swagger string                     `json:"swagger"`
+		Paths   map[string]json.RawMessage `json:"paths"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&doc); err != nil {
+		t.Fatalf("OpenAPI document is not JSON: %v", err)
+	}
+	for _, path := range []string{"/v1/obis:read", "/v1/profiles/block-load:read", "/v1/profiles/instantaneous:read"} {
+		if _, ok := doc.Paths[path]; !ok {
+			t.Errorf("Expected %s in the OpenAPI document", path)
+		}
+	}
+}
+
+func TestListenerClose(t *testing.T) {
+	lis := NewListener()
+	if err := lis.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if err := lis.Close(); err != nil {
+		t.Errorf("Second Close: %v", err)
+	}
+
+	if _, err := lis.Accept(); !errors.Is(err, net.ErrClosed) {
+		t.Errorf("Expected Accept to report net.ErrClosed, got %v", err)
+	}
+	if _, err := lis.DialContext(context.Background()); !errors.Is(err, net.ErrClosed) {
+		t.Errorf("Expected DialContext to report net.ErrClosed, got %v", err)
+	}
+}
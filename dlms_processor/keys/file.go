@@ -0,0 +1,172 @@
+package keys
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// fileMagic starts every keystore file and authenticates its format version
+var fileMagic = []byte("DLMSKS1\n")
+
+// MasterKeySize is the size of the AES-256 key that encrypts a keystore
+const MasterKeySize = 32
+
+// Entry is one version of a meter's keys in a keystore document
+type Entry struct {
+	MeterID        string `json:"meterId"`
+	Version        uint32 `json:"version"`
+	AuthPassword   string `json:"authPassword,omitempty"`
+	AuthKey        string `json:"authKey,omitempty"`
+	BlockCipherKey string `json:"blockCipherKey,omitempty"`
+}
+
+// Document is the plaintext of a keystore
+type Document struct {
+	Keys []Entry `json:"keys"`
+}
+
+// FileStore serves keys from a keystore file, encrypted with AES-256-GCM
+// under a master key. The file is read once, when the store is opened.
+type FileStore struct {
+	keys map[string]map[uint32]Keys // meter id, version
+}
+
+// GenerateMasterKey returns a new random master key, hex encoded
+func GenerateMasterKey() (string, error) {
+	key := make([]byte, MasterKeySize)
+	if _, err := rand.Read(key); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(key), nil
+}
+
+// LoadMasterKey reads a hex encoded master key from path
+func LoadMasterKey(path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read master key: %w", err)
+	}
+	key, err := hex.DecodeString(strings.TrimSpace(string(data)))
+	if err != nil || len(key) != MasterKeySize {
+		return nil, fmt.Errorf("master key in %s must be %d hex encoded bytes", path, MasterKeySize)
+	}
+	return key, nil
+}
+
+func newGCM(masterKey []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(masterKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid master key: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
+
+// Encrypt seals a keystore document under masterKey
+func Encrypt(plaintext, masterKey []byte) ([]byte, error) {
+	gcm, err := newGCM(masterKey)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	out := append(bytes.Clone(fileMagic), nonce...)
+	return gcm.Seal(out, nonce, plaintext, fileMagic), nil
+}
+
+// Decrypt opens a keystore sealed by Encrypt
+func Decrypt(data, masterKey []byte) ([]byte, error) {
+	gcm, err := newGCM(masterKey)
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.HasPrefix(data, fileMagic) || len(data) < len(fileMagic)+gcm.NonceSize() {
+		return nil, errors.New("not a keystore file")
+	}
+	data = data[len(fileMagic):]
+	plaintext, err := gcm.Open(nil, data[:gcm.NonceSize()], data[gcm.NonceSize():], fileMagic)
+	if err != nil {
+		return nil, errors.New("keystore does not decrypt with this master key")
+	}
+	return plaintext, nil
+}
+
+// OpenFileStore decrypts and loads the keystore at path
+func OpenFileStore(path string, masterKey []byte) (*FileStore, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read keystore: %w", err)
+	}
+	plaintext, err := Decrypt(data, masterKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open keystore %s: %w", path, err)
+	}
+	store, err := NewFileStore(plaintext)
+	if err != nil {
+		return nil, fmt.Errorf("invalid keystore %s: %w", path, err)
+	}
+	return store, nil
+}
+
+// NewFileStore loads a plaintext keystore document
+func NewFileStore(plaintext []byte) (*FileStore, error) {
+	var doc Document
+	if err := json.Unmarshal(plaintext, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse keys: %w", err)
+	}
+
+	s := &FileStore{keys: make(map[string]map[uint32]Keys)}
+	for i, e := range doc.Keys {
+		ref := Ref{MeterID: e.MeterID, Version: e.Version}
+		if e.MeterID == "" || e.Version == 0 {
+			return nil, fmt.Errorf("entry %d: meterId and a version above 0 are required", i)
+		}
+		k := Keys{AuthPassword: e.AuthPassword, AuthenticationKey: e.AuthKey, BlockCipherKey: e.BlockCipherKey}
+		if err := k.Validate(); err != nil {
+			return nil, fmt.Errorf("entry %d (%s): %w", i, ref, err)
+		}
+		if s.keys[e.MeterID] == nil {
+			s.keys[e.MeterID] = make(map[uint32]Keys)
+		}
+		if _, ok := s.keys[e.MeterID][e.Version]; ok {
+			return nil, fmt.Errorf("entry %d: %s is listed twice", i, ref)
+		}
+		s.keys[e.MeterID][e.Version] = k
+	}
+	return s, nil
+}
+
+// Keys returns the keys for ref, the highest version when ref has none
+func (s *FileStore) Keys(_ context.Context, ref Ref) (Keys, error) {
+	versions := s.keys[ref.MeterID]
+	version := ref.Version
+	if version == 0 {
+		for v := range versions {
+			version = max(version, v)
+		}
+	}
+	k, ok := versions[version]
+	if !ok {
+		return Keys{}, fmt.Errorf("%w for %s", ErrNotFound, ref)
+	}
+	return k, nil
+}
+
+// Len is the number of key versions in the store
+func (s *FileStore) Len() int {
+	n := 0
+	for _, versions := range s.keys {
+		n += len(versions)
+	}
+	return n
+}
@@ -0,0 +1,77 @@
+// Package keys resolves the secrets a meter association needs from a key
+// provider, so that requests can name a meter's keys instead of carrying
+// them. Keys never print: they format and log as redacted.
+package keys
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+)
+
+// Redacted stands in for secrets in logs and messages
+const Redacted = "[REDACTED]"
+
+// ErrNotFound is returned when a provider has no keys for a reference
+var ErrNotFound = errors.New("keys not found")
+
+// Keys are the secrets for a meter association. They are hex strings, as the
+// meter client takes them.
+type Keys struct {
+	AuthPassword      string
+	AuthenticationKey string
+	BlockCipherKey    string
+}
+
+// String hides the keys from fmt
+func (k Keys) String() string {
+	return Redacted
+}
+
+// GoString hides the keys from %#v
+func (k Keys) GoString() string {
+	return Redacted
+}
+
+// LogValue hides the keys from slog
+func (k Keys) LogValue() slog.Value {
+	return slog.StringValue(Redacted)
+}
+
+// IsZero reports whether no key is set
+func (k Keys) IsZero() bool {
+	return k == Keys{}
+}
+
+// Validate checks the keys have the lengths the meter client needs, without
+// mentioning their values
+func (k Keys) Validate() error {
+	if k.AuthenticationKey != "" && len(k.AuthenticationKey) != 32 {
+		return fmt.Errorf("authentication key must be 32 hex characters")
+	}
+	if k.BlockCipherKey != "" && len(k.BlockCipherKey) != 32 {
+		return fmt.Errorf("block cipher key must be 32 hex characters")
+	}
+	return nil
+}
+
+// Ref names a meter's keys in a provider
+type Ref struct {
+	MeterID string
+	Version uint32 // 0 for the current version
+}
+
+func (r Ref) String() string {
+	if r.Version == 0 {
+		return r.MeterID + "@current"
+	}
+	return fmt.Sprintf("%s@v%d", r.MeterID, r.Version)
+}
+
+// Provider looks keys up by reference
+type Provider interface {
+	// Keys returns the keys ref names, or an error wrapping ErrNotFound if
+	// the provider has none
+	Keys(ctx context.Context, ref Ref) (Keys, error)
+}
@@ -0,0 +1,171 @@
+package keys
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const (
+	testAuthKey   = "49423031494230324942303349423034"
+	testCipherKey = "41424344454647484142434445464748"
+)
+
+const testDocument = `{"keys": [
+	{"meterId": "meter-1", "version": 1, "authPassword": "pw-one", "authKey": "` + testAuthKey + `", "blockCipherKey": "` + testCipherKey + `"},
+	{"meterId": "meter-1", "version": 2, "authPassword": "pw-two", "authKey": "` + testAuthKey + `", "blockCipherKey": "` + testCipherKey + `"}
+]}`
+
+func writeKeystore(t *testing.T, plaintext string) (path string, masterKey []byte) {
+	t.Helper()
+
+	hexKey, err := GenerateMasterKey()
+	if err != nil {
+		t.Fatalf("GenerateMasterKey: %v", err)
+	}
+	masterKey, _ = hex.DecodeString(hexKey)
+	sealed, err := Encrypt([]byte(plaintext), masterKey)
+	if err != nil {
+		t.Fatalf("Encrypt: %v", err)
+	}
+	path = filepath.Join(t.TempDir(), "keystore.enc")
+	if err := os.WriteFile(path, sealed, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path, masterKey
+}
+
+func TestFileStore(t *testing.T) {
+	path, masterKey := writeKeystore(t, testDocument)
+	if data, _ := os.ReadFile(path); bytes.Contains(data, []byte("pw-one")) {
+		t.Fatal("Keystore holds the keys in the clear")
+	}
+
+	store, err := OpenFileStore(path, masterKey)
+	if err != nil {
+		t.Fatalf("OpenFileStore: %v", err)
+	}
+	if store.Len() != 2 {
+		t.Errorf("Expected 2 key versions, got %d", store.Len())
+	}
+
+	tests := []struct {
+		ref      Ref
+		password string
+		err      error
+	}{
+		{Ref{MeterID: "meter-1", Version: 1}, "pw-one", nil},
+		{Ref{MeterID: "meter-1"}, "pw-two", nil},
+		{Ref{MeterID: "meter-1", Version: 3}, "", ErrNotFound},
+		{Ref{MeterID: "meter-2"}, "", ErrNotFound},
+	}
+	for _, tt := range tests {
+		k, err := store.Keys(context.Background(), tt.ref)
+		if !errors.Is(err, tt.err) {
+			t.Errorf("%s: expected error %v, got %v", tt.ref, tt.err, err)
+		}
+		if k.AuthPassword != tt.password {
+			t.Errorf("%s: expected the keys of %q", tt.ref, tt.password)
+		}
+	}
+}
+
+func TestFileStoreRejects(t *testing.T) {
+	path, masterKey := writeKeystore(t, testDocument)
+	other, _ := GenerateMasterKey()
+	otherKey, _ := hex.DecodeString(other)
+	if _, err := OpenFileStore(path, otherKey); err == nil {
+		t.Error("Expected the wrong master key to be refused")
+	}
+
+	data, _ := os.ReadFile(path)
+	data[len(data)-1] ^= 1
+	os.WriteFile(path, data, 0o600)
+	if _, err := OpenFileStore(path, masterKey); err == nil {
+		t.Error("Expected a tampered keystore to be refused")
+	}
+
+	for name, doc := range map[string]string{
+		"no version": `{"keys": [{"meterId": "m"}]}`,
+		"duplicate":  `{"keys": [{"meterId": "m", "version": 1}, {"meterId": "m", "version": 1}]}`,
+		"short key":  `{"keys": [{"meterId": "m", "version": 1, "authKey": "0102"}]}`,
+	} {
+		_, err := NewFileStore([]byte(doc))
+		if err == nil {
+			t.Errorf("%s: expected the document to be refused", name)
+		} else if strings.Contains(err.Error(), "0102") {
+			t.Errorf("%s: error mentions the key: %v", name, err)
+		}
+	}
+}
+
+func TestKeysAreRedacted(t *testing.T) {
+	k := Keys{AuthPassword: "pw-one", AuthenticationKey: testAuthKey, BlockCipherKey: testCipherKey}
+
+	var logged bytes.Buffer
+	slog.New(slog.NewTextHandler(&logged, nil)).Info("keys", "keys", k)
+	printed := fmt.Sprintf("%v %+v %#v %s", k, k, k, k)
+
+	for _, out := range []string{logged.String(), printed} {
+		for _, secret := range []string{"pw-one", testAuthKey, testCipherKey} {
+			if strings.Contains(out, secret) {
+				t.Errorf("Output %q reveals a key", out)
+			}
+		}
+	}
+}
+
+func TestVault(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "token" {
+			http.Error(w, `{"errors":["permission denied"]}`, http.StatusForbidden)
+			return
+		}
+		switch r.URL.Path + "?" + r.URL.RawQuery {
+		case "/v1/secret/data/dlms/meters/meter-1?":
+			fmt.Fprintf(w, `{"data": {"data": {"authPassword": "pw-two", "authKey": %q}, "metadata": {"version": 2}}}`, testAuthKey)
+		case "/v1/secret/data/dlms/meters/meter-1?version=1":
+			fmt.Fprint(w, `{"data": {"data": {"authPassword": "pw-one"}, "metadata": {"version": 1}}}`)
+		case "/v1/secret/data/dlms/meters/meter-1?version=3":
+			fmt.Fprint(w, `{"data": {"data": null, "metadata": {"version": 3, "destroyed": true}}}`)
+		default:
+			http.Error(w, `{"errors":[]}`, http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	vault := NewVault(VaultOptions{Address: server.URL + "/", Path: "/dlms/meters/", Token: "token"})
+	tests := []struct {
+		ref      Ref
+		password string
+		err      error
+	}{
+		{Ref{MeterID: "meter-1"}, "pw-two", nil},
+		{Ref{MeterID: "meter-1", Version: 1}, "pw-one", nil},
+		{Ref{MeterID: "meter-1", Version: 3}, "", ErrNotFound},
+		{Ref{MeterID: "meter-2"}, "", ErrNotFound},
+	}
+	for _, tt := range tests {
+		k, err := vault.Keys(context.Background(), tt.ref)
+		if !errors.Is(err, tt.err) {
+			t.Errorf("%s: expected error %v, got %v", tt.ref, tt.err, err)
+		}
+		if k.AuthPassword != tt.password {
+			t.Errorf("%s: expected the keys of %q", tt.ref, tt.password)
+		}
+	}
+
+	denied := NewVault(VaultOptions{Address: server.URL, Path: "dlms/meters"})
+	if _, err := denied.Keys(context.Background(), Ref{MeterID: "meter-1"}); err == nil || errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected a refused lookup to fail, got %v", err)
+	}
+}
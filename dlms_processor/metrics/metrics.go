@@ -0,0 +1,109 @@
+// Package metrics holds the Prometheus metrics the processor exports on
+// /metrics.
+package metrics
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const namespace = "dlms"
+
+// Meter I/O is slow: buckets from 50ms to a minute
+var latencyBuckets = []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60}
+
+var (
+	// MeterOperations counts per-meter operations by what was done and how it ended
+	MeterOperations = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "meter_operations_total",
+		Help:      "Meter operations completed, by operation and outcome.",
+	}, []string{"operation", "outcome"})
+
+	// MeterOperationsInFlight is the number of meter operations holding a worker
+	MeterOperationsInFlight = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "meter_operations_in_flight",
+		Help:      "Meter operations currently running.",
+	})
+
+	// WorkerQueueDepth is the number of meter operations waiting for a worker
+	WorkerQueueDepth = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "worker_queue_depth",
+		Help:      "Meter operations waiting for a free worker.",
+	})
+
+	// Retries counts repeated attempts at a meter operation
+	Retries = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "retries_total",
+		Help:      "Meter operations retried after a failed attempt, by operation.",
+	}, []string{"operation"})
+
+	// AssociationDuration times opening an association, TCP connect included
+	AssociationDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "association_duration_seconds",
+		Help:      "Time to open a meter association, by outcome.",
+		Buckets:   latencyBuckets,
+	}, []string{"outcome"})
+
+	// SessionsAssociated is the number of sessions holding an open association
+	SessionsAssociated = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "sessions_associated",
+		Help:      "Meter sessions with an open association.",
+	})
+
+	// ProfileReadDuration times reading a profile over an open association
+	ProfileReadDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "profile_read_duration_seconds",
+		Help:      "Time to read a profile generic object, by OBIS code and outcome.",
+		Buckets:   latencyBuckets,
+	}, []string{"obis", "outcome"})
+
+	// Errors counts failures reported by the DLMS library, by its error code
+	Errors = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "errors_total",
+		Help:      "Errors returned by the DLMS library, by error code.",
+	}, []string{"code"})
+)
+
+// Outcome labels how an operation ended
+func Outcome(err error) string {
+	switch {
+	case err == nil:
+		return "success"
+	case errors.Is(err, context.DeadlineExceeded), status.Code(err) == codes.DeadlineExceeded:
+		return "timeout"
+	case errors.Is(err, context.Canceled), status.Code(err) == codes.Canceled:
+		return "canceled"
+	case status.Code(err) == codes.InvalidArgument:
+		return "invalid"
+	case status.Code(err) == codes.PermissionDenied:
+		return "denied"
+	}
+	return "error"
+}
+
+// ObserveSince records the time since start in h under the outcome of err
+// and any leading labels
+func ObserveSince(h *prometheus.HistogramVec, start time.Time, err error, labels ...string) {
+	h.WithLabelValues(append(labels, Outcome(err))...).Observe(time.Since(start).Seconds())
+}
+
+// Handler serves the metrics in the Prometheus exposition format
+func Handler() http.Handler {
+	return promhttp.Handler()
+}
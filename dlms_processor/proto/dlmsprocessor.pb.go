@@ -7,6 +7,7 @@
 package proto
 
 import (
+	_ "google.golang.org/genproto/googleapis/api/annotations"
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
 	reflect "reflect"
@@ -21,6 +22,215 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+type ProbeStepStatus int32
+
+const (
+	ProbeStepStatus_PROBE_STEP_STATUS_UNSPECIFIED ProbeStepStatus = 0
+	ProbeStepStatus_PROBE_STEP_STATUS_OK          ProbeStepStatus = 1
+	ProbeStepStatus_PROBE_STEP_STATUS_FAILED      ProbeStepStatus = 2
+	ProbeStepStatus_PROBE_STEP_STATUS_SKIPPED     ProbeStepStatus = 3 // An earlier step failed
+)
+
+// Enum value maps for ProbeStepStatus.
+var (
+	ProbeStepStatus_name = map[int32]string{
+		0: "PROBE_STEP_STATUS_UNSPECIFIED",
+		1: "PROBE_STEP_STATUS_OK",
+		2: "PROBE_STEP_STATUS_FAILED",
+		3: "PROBE_STEP_STATUS_SKIPPED",
+	}
+	ProbeStepStatus_value = map[string]int32{
+		"PROBE_STEP_STATUS_UNSPECIFIED": 0,
+		"PROBE_STEP_STATUS_OK":          1,
+		"PROBE_STEP_STATUS_FAILED":      2,
+		"PROBE_STEP_STATUS_SKIPPED":     3,
+	}
+)
+
+func (x ProbeStepStatus) Enum() *ProbeStepStatus {
+	p := new(ProbeStepStatus)
+	*p = x
+	return p
+}
+
+func (x ProbeStepStatus) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ProbeStepStatus) Descriptor() protoreflect.EnumDescriptor {
+	return file_dlmsprocessor_proto_enumTypes[0].Descriptor()
+}
+
+func (ProbeStepStatus) Type() protoreflect.EnumType {
+	return &file_dlmsprocessor_proto_enumTypes[0]
+}
+
+func (x ProbeStepStatus) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ProbeStepStatus.Descriptor instead.
+func (ProbeStepStatus) EnumDescriptor() ([]byte, []int) {
+	return file_dlmsprocessor_proto_rawDescGZIP(), []int{0}
+}
+
+type ProfileType int32
+
+const (
+	ProfileType_PROFILE_TYPE_UNSPECIFIED   ProfileType = 0
+	ProfileType_PROFILE_TYPE_BLOCK_LOAD    ProfileType = 1
+	ProfileType_PROFILE_TYPE_DAILY_LOAD    ProfileType = 2
+	ProfileType_PROFILE_TYPE_BILLING_DATA  ProfileType = 3
+	ProfileType_PROFILE_TYPE_INSTANTANEOUS ProfileType = 4
+	ProfileType_PROFILE_TYPE_NAMEPLATE     ProfileType = 5
+)
+
+// Enum value maps for ProfileType.
+var (
+	ProfileType_name = map[int32]string{
+		0: "PROFILE_TYPE_UNSPECIFIED",
+		1: "PROFILE_TYPE_BLOCK_LOAD",
+		2: "PROFILE_TYPE_DAILY_LOAD",
+		3: "PROFILE_TYPE_BILLING_DATA",
+		4: "PROFILE_TYPE_INSTANTANEOUS",
+		5: "PROFILE_TYPE_NAMEPLATE",
+	}
+	ProfileType_value = map[string]int32{
+		"PROFILE_TYPE_UNSPECIFIED":   0,
+		"PROFILE_TYPE_BLOCK_LOAD":    1,
+		"PROFILE_TYPE_DAILY_LOAD":    2,
+		"PROFILE_TYPE_BILLING_DATA":  3,
+		"PROFILE_TYPE_INSTANTANEOUS": 4,
+		"PROFILE_TYPE_NAMEPLATE":     5,
+	}
+)
+
+func (x ProfileType) Enum() *ProfileType {
+	p := new(ProfileType)
+	*p = x
+	return p
+}
+
+func (x ProfileType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ProfileType) Descriptor() protoreflect.EnumDescriptor {
+	return file_dlmsprocessor_proto_enumTypes[1].Descriptor()
+}
+
+func (ProfileType) Type() protoreflect.EnumType {
+	return &file_dlmsprocessor_proto_enumTypes[1]
+}
+
+func (x ProfileType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ProfileType.Descriptor instead.
+func (ProfileType) EnumDescriptor() ([]byte, []int) {
+	return file_dlmsprocessor_proto_rawDescGZIP(), []int{1}
+}
+
+// Whether the frames exchanged with a meter for a request are traced
+type FrameTraceMode int32
+
+const (
+	FrameTraceMode_FRAME_TRACE_MODE_NONE     FrameTraceMode = 0
+	FrameTraceMode_FRAME_TRACE_MODE_RESPONSE FrameTraceMode = 1 // Returned in the response
+	FrameTraceMode_FRAME_TRACE_MODE_FILE     FrameTraceMode = 2 // Written to a pcap file in the server's frame_trace_dir
+)
+
+// Enum value maps for FrameTraceMode.
+var (
+	FrameTraceMode_name = map[int32]string{
+		0: "FRAME_TRACE_MODE_NONE",
+		1: "FRAME_TRACE_MODE_RESPONSE",
+		2: "FRAME_TRACE_MODE_FILE",
+	}
+	FrameTraceMode_value = map[string]int32{
+		"FRAME_TRACE_MODE_NONE":     0,
+		"FRAME_TRACE_MODE_RESPONSE": 1,
+		"FRAME_TRACE_MODE_FILE":     2,
+	}
+)
+
+func (x FrameTraceMode) Enum() *FrameTraceMode {
+	p := new(FrameTraceMode)
+	*p = x
+	return p
+}
+
+func (x FrameTraceMode) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (FrameTraceMode) Descriptor() protoreflect.EnumDescriptor {
+	return file_dlmsprocessor_proto_enumTypes[2].Descriptor()
+}
+
+func (FrameTraceMode) Type() protoreflect.EnumType {
+	return &file_dlmsprocessor_proto_enumTypes[2]
+}
+
+func (x FrameTraceMode) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use FrameTraceMode.Descriptor instead.
+func (FrameTraceMode) EnumDescriptor() ([]byte, []int) {
+	return file_dlmsprocessor_proto_rawDescGZIP(), []int{2}
+}
+
+type FrameDirection int32
+
+const (
+	FrameDirection_FRAME_DIRECTION_UNSPECIFIED FrameDirection = 0
+	FrameDirection_FRAME_DIRECTION_SENT        FrameDirection = 1 // To the meter
+	FrameDirection_FRAME_DIRECTION_RECEIVED    FrameDirection = 2 // From the meter
+)
+
+// Enum value maps for FrameDirection.
+var (
+	FrameDirection_name = map[int32]string{
+		0: "FRAME_DIRECTION_UNSPECIFIED",
+		1: "FRAME_DIRECTION_SENT",
+		2: "FRAME_DIRECTION_RECEIVED",
+	}
+	FrameDirection_value = map[string]int32{
+		"FRAME_DIRECTION_UNSPECIFIED": 0,
+		"FRAME_DIRECTION_SENT":        1,
+		"FRAME_DIRECTION_RECEIVED":    2,
+	}
+)
+
+func (x FrameDirection) Enum() *FrameDirection {
+	p := new(FrameDirection)
+	*p = x
+	return p
+}
+
+func (x FrameDirection) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (FrameDirection) Descriptor() protoreflect.EnumDescriptor {
+	return file_dlmsprocessor_proto_enumTypes[3].Descriptor()
+}
+
+func (FrameDirection) Type() protoreflect.EnumType {
+	return &file_dlmsprocessor_proto_enumTypes[3]
+}
+
+func (x FrameDirection) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use FrameDirection.Descriptor instead.
+func (FrameDirection) EnumDescriptor() ([]byte, []int) {
+	return file_dlmsprocessor_proto_rawDescGZIP(), []int{3}
+}
+
 type GetOBISRequest struct {
 	state             protoimpl.MessageState `protogen:"open.v1"`
 	Meter             []*Meter               `protobuf:"bytes,1,rep,name=meter,proto3" json:"meter,omitempty"`
@@ -28,6 +238,7 @@ type GetOBISRequest struct {
 	Retries           int32                  `protobuf:"varint,4,opt,name=retries,proto3" json:"retries,omitempty"`
 	RetryDelay        int32                  `protobuf:"varint,5,opt,name=retryDelay,proto3" json:"retryDelay,omitempty"`
 	ConnectionTimeout int32                  `protobuf:"varint,6,opt,name=connectionTimeout,proto3" json:"connectionTimeout,omitempty"`
+	Timeout           int32                  `protobuf:"varint,7,opt,name=timeout,proto3" json:"timeout,omitempty"` // Milliseconds for each attempt, 0 for the server default
 	unknownFields     protoimpl.UnknownFields
 	sizeCache         protoimpl.SizeCache
 }
@@ -97,19 +308,34 @@ func (x *GetOBISRequest) GetConnectionTimeout() int32 {
 	return 0
 }
 
+func (x *GetOBISRequest) GetTimeout() int32 {
+	if x != nil {
+		return x.Timeout
+	}
+	return 0
+}
+
 type Meter struct {
-	state          protoimpl.MessageState `protogen:"open.v1"`
-	Ip             string                 `protobuf:"bytes,1,opt,name=ip,proto3" json:"ip,omitempty"`
-	Port           int32                  `protobuf:"varint,2,opt,name=port,proto3" json:"port,omitempty"`
-	Obis           string                 `protobuf:"bytes,3,opt,name=obis,proto3" json:"obis,omitempty"`
-	SystemTitle    string                 `protobuf:"bytes,4,opt,name=systemTitle,proto3" json:"systemTitle,omitempty"`
-	AuthPassword   string                 `protobuf:"bytes,5,opt,name=authPassword,proto3" json:"authPassword,omitempty"`
-	AuthKey        string                 `protobuf:"bytes,6,opt,name=authKey,proto3" json:"authKey,omitempty"`
-	BlockCipherKey string                 `protobuf:"bytes,7,opt,name=blockCipherKey,proto3" json:"blockCipherKey,omitempty"`
-	ClientAddress  string                 `protobuf:"bytes,8,opt,name=clientAddress,proto3" json:"clientAddress,omitempty"`
-	ServerAddress  string                 `protobuf:"bytes,9,opt,name=serverAddress,proto3" json:"serverAddress,omitempty"`
-	unknownFields  protoimpl.UnknownFields
-	sizeCache      protoimpl.SizeCache
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	Ip                string                 `protobuf:"bytes,1,opt,name=ip,proto3" json:"ip,omitempty"`
+	Port              int32                  `protobuf:"varint,2,opt,name=port,proto3" json:"port,omitempty"`
+	Obis              string                 `protobuf:"bytes,3,opt,name=obis,proto3" json:"obis,omitempty"`
+	SystemTitle       string                 `protobuf:"bytes,4,opt,name=systemTitle,proto3" json:"systemTitle,omitempty"`
+	AuthPassword      string                 `protobuf:"bytes,5,opt,name=authPassword,proto3" json:"authPassword,omitempty"`
+	AuthKey           string                 `protobuf:"bytes,6,opt,name=authKey,proto3" json:"authKey,omitempty"`
+	BlockCipherKey    string                 `protobuf:"bytes,7,opt,name=blockCipherKey,proto3" json:"blockCipherKey,omitempty"`
+	ClientAddress     string                 `protobuf:"bytes,8,opt,name=clientAddress,proto3" json:"clientAddress,omitempty"`
+	ServerAddress     string                 `protobuf:"bytes,9,opt,name=serverAddress,proto3" json:"serverAddress,omitempty"`
+	MeterId           string                 `protobuf:"bytes,10,opt,name=meterId,proto3" json:"meterId,omitempty"`                     // Orchestrator's stable meter identifier, echoed in every response
+	SerialNumber      string                 `protobuf:"bytes,11,opt,name=serialNumber,proto3" json:"serialNumber,omitempty"`           // Optional, echoed in every response
+	LogicalDeviceName string                 `protobuf:"bytes,12,opt,name=logicalDeviceName,proto3" json:"logicalDeviceName,omitempty"` // Optional, checked against the meter's logical device name (OBIS: 0.0.42.0.0.255)
+	// Keys from the processor's key provider, in place of authPassword,
+	// authKey and blockCipherKey. Sending those raw is refused unless the
+	// processor allows it.
+	KeyRef        *KeyRef `protobuf:"bytes,13,opt,name=keyRef,proto3" json:"keyRef,omitempty"`
+	Model         string  `protobuf:"bytes,14,opt,name=model,proto3" json:"model,omitempty"` // Optional, selects the model's profile definitions
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *Meter) Reset() {
@@ -205,16 +431,107 @@ func (x *Meter) GetServerAddress() string {
 	return ""
 }
 
+func (x *Meter) GetMeterId() string {
+	if x != nil {
+		return x.MeterId
+	}
+	return ""
+}
+
+func (x *Meter) GetSerialNumber() string {
+	if x != nil {
+		return x.SerialNumber
+	}
+	return ""
+}
+
+func (x *Meter) GetLogicalDeviceName() string {
+	if x != nil {
+		return x.LogicalDeviceName
+	}
+	return ""
+}
+
+func (x *Meter) GetKeyRef() *KeyRef {
+	if x != nil {
+		return x.KeyRef
+	}
+	return nil
+}
+
+func (x *Meter) GetModel() string {
+	if x != nil {
+		return x.Model
+	}
+	return ""
+}
+
+// KeyRef names a meter's keys in the key provider
+type KeyRef struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	MeterId       string                 `protobuf:"bytes,1,opt,name=meterId,proto3" json:"meterId,omitempty"`  // Defaults to the meter's meterId
+	Version       uint32                 `protobuf:"varint,2,opt,name=version,proto3" json:"version,omitempty"` // 0 for the current version
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *KeyRef) Reset() {
+	*x = KeyRef{}
+	mi := &file_dlmsprocessor_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *KeyRef) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*KeyRef) ProtoMessage() {}
+
+func (x *KeyRef) ProtoReflect() protoreflect.Message {
+	mi := &file_dlmsprocessor_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use KeyRef.ProtoReflect.Descriptor instead.
+func (*KeyRef) Descriptor() ([]byte, []int) {
+	return file_dlmsprocessor_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *KeyRef) GetMeterId() string {
+	if x != nil {
+		return x.MeterId
+	}
+	return ""
+}
+
+func (x *KeyRef) GetVersion() uint32 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
 type GetOBISResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Value         string                 `protobuf:"bytes,1,opt,name=value,proto3" json:"value,omitempty"`
+	MeterId       string                 `protobuf:"bytes,2,opt,name=meterId,proto3" json:"meterId,omitempty"`
+	MeterIp       string                 `protobuf:"bytes,3,opt,name=meterIp,proto3" json:"meterIp,omitempty"`
+	SerialNumber  string                 `protobuf:"bytes,4,opt,name=serialNumber,proto3" json:"serialNumber,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *GetOBISResponse) Reset() {
 	*x = GetOBISResponse{}
-	mi := &file_dlmsprocessor_proto_msgTypes[2]
+	mi := &file_dlmsprocessor_proto_msgTypes[3]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -226,7 +543,7 @@ func (x *GetOBISResponse) String() string {
 func (*GetOBISResponse) ProtoMessage() {}
 
 func (x *GetOBISResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_dlmsprocessor_proto_msgTypes[2]
+	mi := &file_dlmsprocessor_proto_msgTypes[3]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -239,7 +556,7 @@ func (x *GetOBISResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetOBISResponse.ProtoReflect.Descriptor instead.
 func (*GetOBISResponse) Descriptor() ([]byte, []int) {
-	return file_dlmsprocessor_proto_rawDescGZIP(), []int{2}
+	return file_dlmsprocessor_proto_rawDescGZIP(), []int{3}
 }
 
 func (x *GetOBISResponse) GetValue() string {
@@ -249,19 +566,41 @@ func (x *GetOBISResponse) GetValue() string {
 	return ""
 }
 
+func (x *GetOBISResponse) GetMeterId() string {
+	if x != nil {
+		return x.MeterId
+	}
+	return ""
+}
+
+func (x *GetOBISResponse) GetMeterIp() string {
+	if x != nil {
+		return x.MeterIp
+	}
+	return ""
+}
+
+func (x *GetOBISResponse) GetSerialNumber() string {
+	if x != nil {
+		return x.SerialNumber
+	}
+	return ""
+}
+
 type GetBlockLoadProfileRequest struct {
 	state             protoimpl.MessageState `protogen:"open.v1"`
 	Meter             []*Meter               `protobuf:"bytes,1,rep,name=meter,proto3" json:"meter,omitempty"`
 	Retries           int32                  `protobuf:"varint,2,opt,name=retries,proto3" json:"retries,omitempty"`
 	RetryDelay        int32                  `protobuf:"varint,3,opt,name=retryDelay,proto3" json:"retryDelay,omitempty"`
 	ConnectionTimeout int32                  `protobuf:"varint,4,opt,name=connectionTimeout,proto3" json:"connectionTimeout,omitempty"`
+	Timeout           int32                  `protobuf:"varint,5,opt,name=timeout,proto3" json:"timeout,omitempty"` // Milliseconds for each attempt, 0 for the server default
 	unknownFields     protoimpl.UnknownFields
 	sizeCache         protoimpl.SizeCache
 }
 
 func (x *GetBlockLoadProfileRequest) Reset() {
 	*x = GetBlockLoadProfileRequest{}
-	mi := &file_dlmsprocessor_proto_msgTypes[3]
+	mi := &file_dlmsprocessor_proto_msgTypes[4]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -273,7 +612,7 @@ func (x *GetBlockLoadProfileRequest) String() string {
 func (*GetBlockLoadProfileRequest) ProtoMessage() {}
 
 func (x *GetBlockLoadProfileRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_dlmsprocessor_proto_msgTypes[3]
+	mi := &file_dlmsprocessor_proto_msgTypes[4]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -286,7 +625,7 @@ func (x *GetBlockLoadProfileRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetBlockLoadProfileRequest.ProtoReflect.Descriptor instead.
 func (*GetBlockLoadProfileRequest) Descriptor() ([]byte, []int) {
-	return file_dlmsprocessor_proto_rawDescGZIP(), []int{3}
+	return file_dlmsprocessor_proto_rawDescGZIP(), []int{4}
 }
 
 func (x *GetBlockLoadProfileRequest) GetMeter() []*Meter {
@@ -317,17 +656,26 @@ func (x *GetBlockLoadProfileRequest) GetConnectionTimeout() int32 {
 	return 0
 }
 
+func (x *GetBlockLoadProfileRequest) GetTimeout() int32 {
+	if x != nil {
+		return x.Timeout
+	}
+	return 0
+}
+
 type GetBlockLoadProfileResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Profile       *BlockLoadProfile      `protobuf:"bytes,1,opt,name=profile,proto3" json:"profile,omitempty"`
 	MeterIp       string                 `protobuf:"bytes,2,opt,name=meterIp,proto3" json:"meterIp,omitempty"` // To identify which meter the profile came from
+	MeterId       string                 `protobuf:"bytes,3,opt,name=meterId,proto3" json:"meterId,omitempty"`
+	SerialNumber  string                 `protobuf:"bytes,4,opt,name=serialNumber,proto3" json:"serialNumber,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *GetBlockLoadProfileResponse) Reset() {
 	*x = GetBlockLoadProfileResponse{}
-	mi := &file_dlmsprocessor_proto_msgTypes[4]
+	mi := &file_dlmsprocessor_proto_msgTypes[5]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -339,7 +687,7 @@ func (x *GetBlockLoadProfileResponse) String() string {
 func (*GetBlockLoadProfileResponse) ProtoMessage() {}
 
 func (x *GetBlockLoadProfileResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_dlmsprocessor_proto_msgTypes[4]
+	mi := &file_dlmsprocessor_proto_msgTypes[5]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -352,7 +700,7 @@ func (x *GetBlockLoadProfileResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetBlockLoadProfileResponse.ProtoReflect.Descriptor instead.
 func (*GetBlockLoadProfileResponse) Descriptor() ([]byte, []int) {
-	return file_dlmsprocessor_proto_rawDescGZIP(), []int{4}
+	return file_dlmsprocessor_proto_rawDescGZIP(), []int{5}
 }
 
 func (x *GetBlockLoadProfileResponse) GetProfile() *BlockLoadProfile {
@@ -369,23 +717,38 @@ func (x *GetBlockLoadProfileResponse) GetMeterIp() string {
 	return ""
 }
 
+func (x *GetBlockLoadProfileResponse) GetMeterId() string {
+	if x != nil {
+		return x.MeterId
+	}
+	return ""
+}
+
+func (x *GetBlockLoadProfileResponse) GetSerialNumber() string {
+	if x != nil {
+		return x.SerialNumber
+	}
+	return ""
+}
+
 type BlockLoadProfile struct {
-	state                protoimpl.MessageState `protogen:"open.v1"`
-	DateTime             string                 `protobuf:"bytes,1,opt,name=dateTime,proto3" json:"dateTime,omitempty"`                           // Real Time Clock (corrected OBIS: 0.0.1.0.0.255)
-	AverageVoltage       float64                `protobuf:"fixed64,2,opt,name=averageVoltage,proto3" json:"averageVoltage,omitempty"`             // Average Voltage (OBIS: 1.0.12.27.0.255)
-	BlockEnergyWhImport  float64                `protobuf:"fixed64,3,opt,name=blockEnergyWhImport,proto3" json:"blockEnergyWhImport,omitempty"`   // Block energy Wh-(import) (OBIS: 1.0.1.29.0.255)
-	BlockEnergyVahImport float64                `protobuf:"fixed64,4,opt,name=blockEnergyVahImport,proto3" json:"blockEnergyVahImport,omitempty"` // Block energy VAh-(import) (OBIS: 1.0.9.29.0.255)
-	BlockEnergyWhExport  float64                `protobuf:"fixed64,5,opt,name=blockEnergyWhExport,proto3" json:"blockEnergyWhExport,omitempty"`   // Block energy Wh-export (OBIS: 1.0.2.29.0.255)
-	BlockEnergyVahExport float64                `protobuf:"fixed64,6,opt,name=blockEnergyVahExport,proto3" json:"blockEnergyVahExport,omitempty"` // Block energy VAh-export (OBIS: 1.0.10.29.0.255)
-	AverageCurrent       float64                `protobuf:"fixed64,7,opt,name=averageCurrent,proto3" json:"averageCurrent,omitempty"`             // Average Current (OBIS: 1.0.11.27.0.255)
-	MeterHealthIndicator uint32                 `protobuf:"varint,8,opt,name=meterHealthIndicator,proto3" json:"meterHealthIndicator,omitempty"`  // Meter Health Indicator (OBIS: 0.0.96.10.1.255)
+	state                protoimpl.MessageState      `protogen:"open.v1"`
+	DateTime             string                      `protobuf:"bytes,1,opt,name=dateTime,proto3" json:"dateTime,omitempty"`                           // Real Time Clock (corrected OBIS: 0.0.1.0.0.255)
+	AverageVoltage       float64                     `protobuf:"fixed64,2,opt,name=averageVoltage,proto3" json:"averageVoltage,omitempty"`             // Average Voltage (OBIS: 1.0.12.27.0.255)
+	BlockEnergyWhImport  float64                     `protobuf:"fixed64,3,opt,name=blockEnergyWhImport,proto3" json:"blockEnergyWhImport,omitempty"`   // Block energy Wh-(import) (OBIS: 1.0.1.29.0.255)
+	BlockEnergyVahImport float64                     `protobuf:"fixed64,4,opt,name=blockEnergyVahImport,proto3" json:"blockEnergyVahImport,omitempty"` // Block energy VAh-(import) (OBIS: 1.0.9.29.0.255)
+	BlockEnergyWhExport  float64                     `protobuf:"fixed64,5,opt,name=blockEnergyWhExport,proto3" json:"blockEnergyWhExport,omitempty"`   // Block energy Wh-export (OBIS: 1.0.2.29.0.255)
+	BlockEnergyVahExport float64                     `protobuf:"fixed64,6,opt,name=blockEnergyVahExport,proto3" json:"blockEnergyVahExport,omitempty"` // Block energy VAh-export (OBIS: 1.0.10.29.0.255)
+	AverageCurrent       float64                     `protobuf:"fixed64,7,opt,name=averageCurrent,proto3" json:"averageCurrent,omitempty"`             // Average Current (OBIS: 1.0.11.27.0.255)
+	MeterHealthIndicator uint32                      `protobuf:"varint,8,opt,name=meterHealthIndicator,proto3" json:"meterHealthIndicator,omitempty"`  // Meter Health Indicator (OBIS: 0.0.96.10.1.255)
+	ThreePhase           *ThreePhaseBlockLoadProfile `protobuf:"bytes,9,opt,name=threePhase,proto3" json:"threePhase,omitempty"`                       // Per-phase entry of a three-phase meter, whose single-phase fields stay empty
 	unknownFields        protoimpl.UnknownFields
 	sizeCache            protoimpl.SizeCache
 }
 
 func (x *BlockLoadProfile) Reset() {
 	*x = BlockLoadProfile{}
-	mi := &file_dlmsprocessor_proto_msgTypes[5]
+	mi := &file_dlmsprocessor_proto_msgTypes[6]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -397,7 +760,7 @@ func (x *BlockLoadProfile) String() string {
 func (*BlockLoadProfile) ProtoMessage() {}
 
 func (x *BlockLoadProfile) ProtoReflect() protoreflect.Message {
-	mi := &file_dlmsprocessor_proto_msgTypes[5]
+	mi := &file_dlmsprocessor_proto_msgTypes[6]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -410,7 +773,7 @@ func (x *BlockLoadProfile) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use BlockLoadProfile.ProtoReflect.Descriptor instead.
 func (*BlockLoadProfile) Descriptor() ([]byte, []int) {
-	return file_dlmsprocessor_proto_rawDescGZIP(), []int{5}
+	return file_dlmsprocessor_proto_rawDescGZIP(), []int{6}
 }
 
 func (x *BlockLoadProfile) GetDateTime() string {
@@ -469,32 +832,48 @@ func (x *BlockLoadProfile) GetMeterHealthIndicator() uint32 {
 	return 0
 }
 
-// Daily Load Profile Messages
-type GetDailyLoadProfileRequest struct {
-	state             protoimpl.MessageState `protogen:"open.v1"`
-	Meter             []*Meter               `protobuf:"bytes,1,rep,name=meter,proto3" json:"meter,omitempty"`
-	Retries           int32                  `protobuf:"varint,2,opt,name=retries,proto3" json:"retries,omitempty"`
-	RetryDelay        int32                  `protobuf:"varint,3,opt,name=retryDelay,proto3" json:"retryDelay,omitempty"`
-	ConnectionTimeout int32                  `protobuf:"varint,4,opt,name=connectionTimeout,proto3" json:"connectionTimeout,omitempty"`
-	unknownFields     protoimpl.UnknownFields
-	sizeCache         protoimpl.SizeCache
+func (x *BlockLoadProfile) GetThreePhase() *ThreePhaseBlockLoadProfile {
+	if x != nil {
+		return x.ThreePhase
+	}
+	return nil
 }
 
-func (x *GetDailyLoadProfileRequest) Reset() {
-	*x = GetDailyLoadProfileRequest{}
-	mi := &file_dlmsprocessor_proto_msgTypes[6]
+// Block load profile entry of a three-phase meter, with R, Y and B phase averages
+type ThreePhaseBlockLoadProfile struct {
+	state                protoimpl.MessageState `protogen:"open.v1"`
+	DateTime             string                 `protobuf:"bytes,1,opt,name=dateTime,proto3" json:"dateTime,omitempty"`                            // Real Time Clock (OBIS: 0.0.1.0.0.255)
+	CurrentR             float64                `protobuf:"fixed64,2,opt,name=currentR,proto3" json:"currentR,omitempty"`                          // Average Current - IR (OBIS: 1.0.31.27.0.255)
+	CurrentY             float64                `protobuf:"fixed64,3,opt,name=currentY,proto3" json:"currentY,omitempty"`                          // Average Current - IY (OBIS: 1.0.51.27.0.255)
+	CurrentB             float64                `protobuf:"fixed64,4,opt,name=currentB,proto3" json:"currentB,omitempty"`                          // Average Current - IB (OBIS: 1.0.71.27.0.255)
+	VoltageR             float64                `protobuf:"fixed64,5,opt,name=voltageR,proto3" json:"voltageR,omitempty"`                          // Average Voltage - VRN (OBIS: 1.0.32.27.0.255)
+	VoltageY             float64                `protobuf:"fixed64,6,opt,name=voltageY,proto3" json:"voltageY,omitempty"`                          // Average Voltage - VYN (OBIS: 1.0.52.27.0.255)
+	VoltageB             float64                `protobuf:"fixed64,7,opt,name=voltageB,proto3" json:"voltageB,omitempty"`                          // Average Voltage - VBN (OBIS: 1.0.72.27.0.255)
+	BlockEnergyWhImport  float64                `protobuf:"fixed64,8,opt,name=blockEnergyWhImport,proto3" json:"blockEnergyWhImport,omitempty"`    // Block Energy - Wh (import) (OBIS: 1.0.1.29.0.255)
+	BlockEnergyVarhLag   float64                `protobuf:"fixed64,9,opt,name=blockEnergyVarhLag,proto3" json:"blockEnergyVarhLag,omitempty"`      // Block Energy - varh, lag (OBIS: 1.0.5.29.0.255)
+	BlockEnergyVarhLead  float64                `protobuf:"fixed64,10,opt,name=blockEnergyVarhLead,proto3" json:"blockEnergyVarhLead,omitempty"`   // Block Energy - varh, lead (OBIS: 1.0.8.29.0.255)
+	BlockEnergyVahImport float64                `protobuf:"fixed64,11,opt,name=blockEnergyVahImport,proto3" json:"blockEnergyVahImport,omitempty"` // Block Energy - VAh (import) (OBIS: 1.0.9.29.0.255)
+	BlockEnergyWhExport  float64                `protobuf:"fixed64,12,opt,name=blockEnergyWhExport,proto3" json:"blockEnergyWhExport,omitempty"`   // Block Energy - Wh (export) (OBIS: 1.0.2.29.0.255)
+	BlockEnergyVahExport float64                `protobuf:"fixed64,13,opt,name=blockEnergyVahExport,proto3" json:"blockEnergyVahExport,omitempty"` // Block Energy - VAh (export) (OBIS: 1.0.10.29.0.255)
+	unknownFields        protoimpl.UnknownFields
+	sizeCache            protoimpl.SizeCache
+}
+
+func (x *ThreePhaseBlockLoadProfile) Reset() {
+	*x = ThreePhaseBlockLoadProfile{}
+	mi := &file_dlmsprocessor_proto_msgTypes[7]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetDailyLoadProfileRequest) String() string {
+func (x *ThreePhaseBlockLoadProfile) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetDailyLoadProfileRequest) ProtoMessage() {}
+func (*ThreePhaseBlockLoadProfile) ProtoMessage() {}
 
-func (x *GetDailyLoadProfileRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_dlmsprocessor_proto_msgTypes[6]
+func (x *ThreePhaseBlockLoadProfile) ProtoReflect() protoreflect.Message {
+	mi := &file_dlmsprocessor_proto_msgTypes[7]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -505,26 +884,159 @@ func (x *GetDailyLoadProfileRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetDailyLoadProfileRequest.ProtoReflect.Descriptor instead.
-func (*GetDailyLoadProfileRequest) Descriptor() ([]byte, []int) {
-	return file_dlmsprocessor_proto_rawDescGZIP(), []int{6}
+// Deprecated: Use ThreePhaseBlockLoadProfile.ProtoReflect.Descriptor instead.
+func (*ThreePhaseBlockLoadProfile) Descriptor() ([]byte, []int) {
+	return file_dlmsprocessor_proto_rawDescGZIP(), []int{7}
 }
 
-func (x *GetDailyLoadProfileRequest) GetMeter() []*Meter {
+func (x *ThreePhaseBlockLoadProfile) GetDateTime() string {
 	if x != nil {
-		return x.Meter
+		return x.DateTime
 	}
-	return nil
+	return ""
 }
 
-func (x *GetDailyLoadProfileRequest) GetRetries() int32 {
+func (x *ThreePhaseBlockLoadProfile) GetCurrentR() float64 {
 	if x != nil {
-		return x.Retries
+		return x.CurrentR
 	}
 	return 0
 }
 
-func (x *GetDailyLoadProfileRequest) GetRetryDelay() int32 {
+func (x *ThreePhaseBlockLoadProfile) GetCurrentY() float64 {
+	if x != nil {
+		return x.CurrentY
+	}
+	return 0
+}
+
+func (x *ThreePhaseBlockLoadProfile) GetCurrentB() float64 {
+	if x != nil {
+		return x.CurrentB
+	}
+	return 0
+}
+
+func (x *ThreePhaseBlockLoadProfile) GetVoltageR() float64 {
+	if x != nil {
+		return x.VoltageR
+	}
+	return 0
+}
+
+func (x *ThreePhaseBlockLoadProfile) GetVoltageY() float64 {
+	if x != nil {
+		return x.VoltageY
+	}
+	return 0
+}
+
+func (x *ThreePhaseBlockLoadProfile) GetVoltageB() float64 {
+	if x != nil {
+		return x.VoltageB
+	}
+	return 0
+}
+
+func (x *ThreePhaseBlockLoadProfile) GetBlockEnergyWhImport() float64 {
+	if x != nil {
+		return x.BlockEnergyWhImport
+	}
+	return 0
+}
+
+func (x *ThreePhaseBlockLoadProfile) GetBlockEnergyVarhLag() float64 {
+	if x != nil {
+		return x.BlockEnergyVarhLag
+	}
+	return 0
+}
+
+func (x *ThreePhaseBlockLoadProfile) GetBlockEnergyVarhLead() float64 {
+	if x != nil {
+		return x.BlockEnergyVarhLead
+	}
+	return 0
+}
+
+func (x *ThreePhaseBlockLoadProfile) GetBlockEnergyVahImport() float64 {
+	if x != nil {
+		return x.BlockEnergyVahImport
+	}
+	return 0
+}
+
+func (x *ThreePhaseBlockLoadProfile) GetBlockEnergyWhExport() float64 {
+	if x != nil {
+		return x.BlockEnergyWhExport
+	}
+	return 0
+}
+
+func (x *ThreePhaseBlockLoadProfile) GetBlockEnergyVahExport() float64 {
+	if x != nil {
+		return x.BlockEnergyVahExport
+	}
+	return 0
+}
+
+// Daily Load Profile Messages
+type GetDailyLoadProfileRequest struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	Meter             []*Meter               `protobuf:"bytes,1,rep,name=meter,proto3" json:"meter,omitempty"`
+	Retries           int32                  `protobuf:"varint,2,opt,name=retries,proto3" json:"retries,omitempty"`
+	RetryDelay        int32                  `protobuf:"varint,3,opt,name=retryDelay,proto3" json:"retryDelay,omitempty"`
+	ConnectionTimeout int32                  `protobuf:"varint,4,opt,name=connectionTimeout,proto3" json:"connectionTimeout,omitempty"`
+	Timeout           int32                  `protobuf:"varint,5,opt,name=timeout,proto3" json:"timeout,omitempty"` // Milliseconds for each attempt, 0 for the server default
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *GetDailyLoadProfileRequest) Reset() {
+	*x = GetDailyLoadProfileRequest{}
+	mi := &file_dlmsprocessor_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetDailyLoadProfileRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetDailyLoadProfileRequest) ProtoMessage() {}
+
+func (x *GetDailyLoadProfileRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_dlmsprocessor_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetDailyLoadProfileRequest.ProtoReflect.Descriptor instead.
+func (*GetDailyLoadProfileRequest) Descriptor() ([]byte, []int) {
+	return file_dlmsprocessor_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *GetDailyLoadProfileRequest) GetMeter() []*Meter {
+	if x != nil {
+		return x.Meter
+	}
+	return nil
+}
+
+func (x *GetDailyLoadProfileRequest) GetRetries() int32 {
+	if x != nil {
+		return x.Retries
+	}
+	return 0
+}
+
+func (x *GetDailyLoadProfileRequest) GetRetryDelay() int32 {
 	if x != nil {
 		return x.RetryDelay
 	}
@@ -538,17 +1050,26 @@ func (x *GetDailyLoadProfileRequest) GetConnectionTimeout() int32 {
 	return 0
 }
 
+func (x *GetDailyLoadProfileRequest) GetTimeout() int32 {
+	if x != nil {
+		return x.Timeout
+	}
+	return 0
+}
+
 type GetDailyLoadProfileResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Profile       *DailyLoadProfile      `protobuf:"bytes,1,opt,name=profile,proto3" json:"profile,omitempty"`
 	MeterIp       string                 `protobuf:"bytes,2,opt,name=meterIp,proto3" json:"meterIp,omitempty"` // To identify which meter the profile came from
+	MeterId       string                 `protobuf:"bytes,3,opt,name=meterId,proto3" json:"meterId,omitempty"`
+	SerialNumber  string                 `protobuf:"bytes,4,opt,name=serialNumber,proto3" json:"serialNumber,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *GetDailyLoadProfileResponse) Reset() {
 	*x = GetDailyLoadProfileResponse{}
-	mi := &file_dlmsprocessor_proto_msgTypes[7]
+	mi := &file_dlmsprocessor_proto_msgTypes[9]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -560,7 +1081,7 @@ func (x *GetDailyLoadProfileResponse) String() string {
 func (*GetDailyLoadProfileResponse) ProtoMessage() {}
 
 func (x *GetDailyLoadProfileResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_dlmsprocessor_proto_msgTypes[7]
+	mi := &file_dlmsprocessor_proto_msgTypes[9]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -573,7 +1094,7 @@ func (x *GetDailyLoadProfileResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetDailyLoadProfileResponse.ProtoReflect.Descriptor instead.
 func (*GetDailyLoadProfileResponse) Descriptor() ([]byte, []int) {
-	return file_dlmsprocessor_proto_rawDescGZIP(), []int{7}
+	return file_dlmsprocessor_proto_rawDescGZIP(), []int{9}
 }
 
 func (x *GetDailyLoadProfileResponse) GetProfile() *DailyLoadProfile {
@@ -590,6 +1111,20 @@ func (x *GetDailyLoadProfileResponse) GetMeterIp() string {
 	return ""
 }
 
+func (x *GetDailyLoadProfileResponse) GetMeterId() string {
+	if x != nil {
+		return x.MeterId
+	}
+	return ""
+}
+
+func (x *GetDailyLoadProfileResponse) GetSerialNumber() string {
+	if x != nil {
+		return x.SerialNumber
+	}
+	return ""
+}
+
 type DailyLoadProfile struct {
 	state                     protoimpl.MessageState `protogen:"open.v1"`
 	DateTime                  string                 `protobuf:"bytes,1,opt,name=dateTime,proto3" json:"dateTime,omitempty"`                                     // RTC - Date & Time (OBIS: 0.0.1.0.0.255)
@@ -603,7 +1138,7 @@ type DailyLoadProfile struct {
 
 func (x *DailyLoadProfile) Reset() {
 	*x = DailyLoadProfile{}
-	mi := &file_dlmsprocessor_proto_msgTypes[8]
+	mi := &file_dlmsprocessor_proto_msgTypes[10]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -615,7 +1150,7 @@ func (x *DailyLoadProfile) String() string {
 func (*DailyLoadProfile) ProtoMessage() {}
 
 func (x *DailyLoadProfile) ProtoReflect() protoreflect.Message {
-	mi := &file_dlmsprocessor_proto_msgTypes[8]
+	mi := &file_dlmsprocessor_proto_msgTypes[10]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -628,7 +1163,7 @@ func (x *DailyLoadProfile) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DailyLoadProfile.ProtoReflect.Descriptor instead.
 func (*DailyLoadProfile) Descriptor() ([]byte, []int) {
-	return file_dlmsprocessor_proto_rawDescGZIP(), []int{8}
+	return file_dlmsprocessor_proto_rawDescGZIP(), []int{10}
 }
 
 func (x *DailyLoadProfile) GetDateTime() string {
@@ -673,13 +1208,14 @@ type GetBillingDataProfileRequest struct {
 	Retries           int32                  `protobuf:"varint,2,opt,name=retries,proto3" json:"retries,omitempty"`
 	RetryDelay        int32                  `protobuf:"varint,3,opt,name=retryDelay,proto3" json:"retryDelay,omitempty"`
 	ConnectionTimeout int32                  `protobuf:"varint,4,opt,name=connectionTimeout,proto3" json:"connectionTimeout,omitempty"`
+	Timeout           int32                  `protobuf:"varint,5,opt,name=timeout,proto3" json:"timeout,omitempty"` // Milliseconds for each attempt, 0 for the server default
 	unknownFields     protoimpl.UnknownFields
 	sizeCache         protoimpl.SizeCache
 }
 
 func (x *GetBillingDataProfileRequest) Reset() {
 	*x = GetBillingDataProfileRequest{}
-	mi := &file_dlmsprocessor_proto_msgTypes[9]
+	mi := &file_dlmsprocessor_proto_msgTypes[11]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -691,7 +1227,7 @@ func (x *GetBillingDataProfileRequest) String() string {
 func (*GetBillingDataProfileRequest) ProtoMessage() {}
 
 func (x *GetBillingDataProfileRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_dlmsprocessor_proto_msgTypes[9]
+	mi := &file_dlmsprocessor_proto_msgTypes[11]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -704,7 +1240,7 @@ func (x *GetBillingDataProfileRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetBillingDataProfileRequest.ProtoReflect.Descriptor instead.
 func (*GetBillingDataProfileRequest) Descriptor() ([]byte, []int) {
-	return file_dlmsprocessor_proto_rawDescGZIP(), []int{9}
+	return file_dlmsprocessor_proto_rawDescGZIP(), []int{11}
 }
 
 func (x *GetBillingDataProfileRequest) GetMeter() []*Meter {
@@ -735,17 +1271,26 @@ func (x *GetBillingDataProfileRequest) GetConnectionTimeout() int32 {
 	return 0
 }
 
+func (x *GetBillingDataProfileRequest) GetTimeout() int32 {
+	if x != nil {
+		return x.Timeout
+	}
+	return 0
+}
+
 type GetBillingDataProfileResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Profile       *BillingDataProfile    `protobuf:"bytes,1,opt,name=profile,proto3" json:"profile,omitempty"`
 	MeterIp       string                 `protobuf:"bytes,2,opt,name=meterIp,proto3" json:"meterIp,omitempty"` // To identify which meter the profile came from
+	MeterId       string                 `protobuf:"bytes,3,opt,name=meterId,proto3" json:"meterId,omitempty"`
+	SerialNumber  string                 `protobuf:"bytes,4,opt,name=serialNumber,proto3" json:"serialNumber,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *GetBillingDataProfileResponse) Reset() {
 	*x = GetBillingDataProfileResponse{}
-	mi := &file_dlmsprocessor_proto_msgTypes[10]
+	mi := &file_dlmsprocessor_proto_msgTypes[12]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -757,7 +1302,7 @@ func (x *GetBillingDataProfileResponse) String() string {
 func (*GetBillingDataProfileResponse) ProtoMessage() {}
 
 func (x *GetBillingDataProfileResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_dlmsprocessor_proto_msgTypes[10]
+	mi := &file_dlmsprocessor_proto_msgTypes[12]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -770,7 +1315,7 @@ func (x *GetBillingDataProfileResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetBillingDataProfileResponse.ProtoReflect.Descriptor instead.
 func (*GetBillingDataProfileResponse) Descriptor() ([]byte, []int) {
-	return file_dlmsprocessor_proto_rawDescGZIP(), []int{10}
+	return file_dlmsprocessor_proto_rawDescGZIP(), []int{12}
 }
 
 func (x *GetBillingDataProfileResponse) GetProfile() *BillingDataProfile {
@@ -787,34 +1332,60 @@ func (x *GetBillingDataProfileResponse) GetMeterIp() string {
 	return ""
 }
 
+func (x *GetBillingDataProfileResponse) GetMeterId() string {
+	if x != nil {
+		return x.MeterId
+	}
+	return ""
+}
+
+func (x *GetBillingDataProfileResponse) GetSerialNumber() string {
+	if x != nil {
+		return x.SerialNumber
+	}
+	return ""
+}
+
+// BillingDataProfile is one billing period. tariffZones is authoritative for
+// time-of-use registers; the fixed TZ1 to TZ4 fields are kept for existing
+// clients only.
 type BillingDataProfile struct {
 	state                     protoimpl.MessageState `protogen:"open.v1"`
 	BillingDate               string                 `protobuf:"bytes,1,opt,name=billingDate,proto3" json:"billingDate,omitempty"`                               // Billing Date (OBIS: 0.0.0.1.2.255)
 	AveragePfForBillingPeriod float64                `protobuf:"fixed64,2,opt,name=averagePfForBillingPeriod,proto3" json:"averagePfForBillingPeriod,omitempty"` // Average PF for Billing Period (OBIS: 1.0.13.0.0.255)
 	CumEnergyWhImport         float64                `protobuf:"fixed64,3,opt,name=cumEnergyWhImport,proto3" json:"cumEnergyWhImport,omitempty"`                 // Cumulative Energy - Wh(Import) (OBIS: 1.0.1.8.0.255)
-	CumEnergyWhTz1            float64                `protobuf:"fixed64,4,opt,name=cumEnergyWhTz1,proto3" json:"cumEnergyWhTz1,omitempty"`                       // Cumulative Energy - Wh - TZ1 (OBIS: 1.0.1.8.1.255)
-	CumEnergyWhTz2            float64                `protobuf:"fixed64,5,opt,name=cumEnergyWhTz2,proto3" json:"cumEnergyWhTz2,omitempty"`                       // Cumulative Energy - Wh - TZ2 (OBIS: 1.0.1.8.2.255)
-	CumEnergyWhTz3            float64                `protobuf:"fixed64,6,opt,name=cumEnergyWhTz3,proto3" json:"cumEnergyWhTz3,omitempty"`                       // Cumulative Energy - Wh - TZ3 (OBIS: 1.0.1.8.3.255)
-	CumEnergyWhTz4            float64                `protobuf:"fixed64,7,opt,name=cumEnergyWhTz4,proto3" json:"cumEnergyWhTz4,omitempty"`                       // Cumulative Energy - Wh - TZ4 (OBIS: 1.0.1.8.4.255)
-	CumEnergyVahImport        float64                `protobuf:"fixed64,8,opt,name=cumEnergyVahImport,proto3" json:"cumEnergyVahImport,omitempty"`               // Cumulative Energy - VAh(Import) (OBIS: 1.0.9.8.0.255)
-	CumEnergyVahTz1           float64                `protobuf:"fixed64,9,opt,name=cumEnergyVahTz1,proto3" json:"cumEnergyVahTz1,omitempty"`                     // Cumulative Energy - VAh - TZ1 (OBIS: 1.0.9.8.1.255)
-	CumEnergyVahTz2           float64                `protobuf:"fixed64,10,opt,name=cumEnergyVahTz2,proto3" json:"cumEnergyVahTz2,omitempty"`                    // Cumulative Energy - VAh - TZ2 (OBIS: 1.0.9.8.2.255)
-	CumEnergyVahTz3           float64                `protobuf:"fixed64,11,opt,name=cumEnergyVahTz3,proto3" json:"cumEnergyVahTz3,omitempty"`                    // Cumulative Energy - VAh - TZ3 (OBIS: 1.0.9.8.3.255)
-	CumEnergyVahTz4           float64                `protobuf:"fixed64,12,opt,name=cumEnergyVahTz4,proto3" json:"cumEnergyVahTz4,omitempty"`                    // Cumulative Energy - VAh - TZ4 (OBIS: 1.0.9.8.4.255)
-	Mdw                       float64                `protobuf:"fixed64,13,opt,name=mdw,proto3" json:"mdw,omitempty"`                                            // MD W (OBIS: 1.0.1.6.0.255)
-	MdwDateTime               string                 `protobuf:"bytes,14,opt,name=mdwDateTime,proto3" json:"mdwDateTime,omitempty"`                              // MD W - Date & Time (OBIS: 1.0.1.6.0.255)
-	Mdva                      float64                `protobuf:"fixed64,15,opt,name=mdva,proto3" json:"mdva,omitempty"`                                          // MD VA (OBIS: 1.0.9.6.0.255)
-	MdvaDateTime              string                 `protobuf:"bytes,16,opt,name=mdvaDateTime,proto3" json:"mdvaDateTime,omitempty"`                            // MD VA - Date & Time (OBIS: 1.0.9.6.0.255)
-	BillingPowerOnDuration    float64                `protobuf:"fixed64,17,opt,name=billingPowerOnDuration,proto3" json:"billingPowerOnDuration,omitempty"`      // Billing Power On Duration (OBIS: 0.0.94.91.13.255)
-	CumEnergyWh               float64                `protobuf:"fixed64,18,opt,name=cumEnergyWh,proto3" json:"cumEnergyWh,omitempty"`                            // Cumulative Energy Wh (OBIS: 1.0.2.8.0.255)
-	CumEnergyVah              float64                `protobuf:"fixed64,19,opt,name=cumEnergyVah,proto3" json:"cumEnergyVah,omitempty"`                          // Cumulative Energy VAh (OBIS: 1.0.10.8.0.255)
-	unknownFields             protoimpl.UnknownFields
-	sizeCache                 protoimpl.SizeCache
+	// Deprecated: Marked as deprecated in dlmsprocessor.proto.
+	CumEnergyWhTz1 float64 `protobuf:"fixed64,4,opt,name=cumEnergyWhTz1,proto3" json:"cumEnergyWhTz1,omitempty"` // Cumulative Energy - Wh - TZ1 (OBIS: 1.0.1.8.1.255)
+	// Deprecated: Marked as deprecated in dlmsprocessor.proto.
+	CumEnergyWhTz2 float64 `protobuf:"fixed64,5,opt,name=cumEnergyWhTz2,proto3" json:"cumEnergyWhTz2,omitempty"` // Cumulative Energy - Wh - TZ2 (OBIS: 1.0.1.8.2.255)
+	// Deprecated: Marked as deprecated in dlmsprocessor.proto.
+	CumEnergyWhTz3 float64 `protobuf:"fixed64,6,opt,name=cumEnergyWhTz3,proto3" json:"cumEnergyWhTz3,omitempty"` // Cumulative Energy - Wh - TZ3 (OBIS: 1.0.1.8.3.255)
+	// Deprecated: Marked as deprecated in dlmsprocessor.proto.
+	CumEnergyWhTz4     float64 `protobuf:"fixed64,7,opt,name=cumEnergyWhTz4,proto3" json:"cumEnergyWhTz4,omitempty"`         // Cumulative Energy - Wh - TZ4 (OBIS: 1.0.1.8.4.255)
+	CumEnergyVahImport float64 `protobuf:"fixed64,8,opt,name=cumEnergyVahImport,proto3" json:"cumEnergyVahImport,omitempty"` // Cumulative Energy - VAh(Import) (OBIS: 1.0.9.8.0.255)
+	// Deprecated: Marked as deprecated in dlmsprocessor.proto.
+	CumEnergyVahTz1 float64 `protobuf:"fixed64,9,opt,name=cumEnergyVahTz1,proto3" json:"cumEnergyVahTz1,omitempty"` // Cumulative Energy - VAh - TZ1 (OBIS: 1.0.9.8.1.255)
+	// Deprecated: Marked as deprecated in dlmsprocessor.proto.
+	CumEnergyVahTz2 float64 `protobuf:"fixed64,10,opt,name=cumEnergyVahTz2,proto3" json:"cumEnergyVahTz2,omitempty"` // Cumulative Energy - VAh - TZ2 (OBIS: 1.0.9.8.2.255)
+	// Deprecated: Marked as deprecated in dlmsprocessor.proto.
+	CumEnergyVahTz3 float64 `protobuf:"fixed64,11,opt,name=cumEnergyVahTz3,proto3" json:"cumEnergyVahTz3,omitempty"` // Cumulative Energy - VAh - TZ3 (OBIS: 1.0.9.8.3.255)
+	// Deprecated: Marked as deprecated in dlmsprocessor.proto.
+	CumEnergyVahTz4        float64       `protobuf:"fixed64,12,opt,name=cumEnergyVahTz4,proto3" json:"cumEnergyVahTz4,omitempty"`               // Cumulative Energy - VAh - TZ4 (OBIS: 1.0.9.8.4.255)
+	Mdw                    float64       `protobuf:"fixed64,13,opt,name=mdw,proto3" json:"mdw,omitempty"`                                       // MD W (OBIS: 1.0.1.6.0.255)
+	MdwDateTime            string        `protobuf:"bytes,14,opt,name=mdwDateTime,proto3" json:"mdwDateTime,omitempty"`                         // MD W - Date & Time (OBIS: 1.0.1.6.0.255)
+	Mdva                   float64       `protobuf:"fixed64,15,opt,name=mdva,proto3" json:"mdva,omitempty"`                                     // MD VA (OBIS: 1.0.9.6.0.255)
+	MdvaDateTime           string        `protobuf:"bytes,16,opt,name=mdvaDateTime,proto3" json:"mdvaDateTime,omitempty"`                       // MD VA - Date & Time (OBIS: 1.0.9.6.0.255)
+	BillingPowerOnDuration float64       `protobuf:"fixed64,17,opt,name=billingPowerOnDuration,proto3" json:"billingPowerOnDuration,omitempty"` // Billing Power On Duration (OBIS: 0.0.94.91.13.255)
+	CumEnergyWh            float64       `protobuf:"fixed64,18,opt,name=cumEnergyWh,proto3" json:"cumEnergyWh,omitempty"`                       // Cumulative Energy Wh (OBIS: 1.0.2.8.0.255)
+	CumEnergyVah           float64       `protobuf:"fixed64,19,opt,name=cumEnergyVah,proto3" json:"cumEnergyVah,omitempty"`                     // Cumulative Energy VAh (OBIS: 1.0.10.8.0.255)
+	TariffZones            []*TariffZone `protobuf:"bytes,20,rep,name=tariffZones,proto3" json:"tariffZones,omitempty"`                         // Every tariff zone the meter captures, TZ1 to TZ4 included
+	unknownFields          protoimpl.UnknownFields
+	sizeCache              protoimpl.SizeCache
 }
 
 func (x *BillingDataProfile) Reset() {
 	*x = BillingDataProfile{}
-	mi := &file_dlmsprocessor_proto_msgTypes[11]
+	mi := &file_dlmsprocessor_proto_msgTypes[13]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -826,7 +1397,7 @@ func (x *BillingDataProfile) String() string {
 func (*BillingDataProfile) ProtoMessage() {}
 
 func (x *BillingDataProfile) ProtoReflect() protoreflect.Message {
-	mi := &file_dlmsprocessor_proto_msgTypes[11]
+	mi := &file_dlmsprocessor_proto_msgTypes[13]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -839,7 +1410,7 @@ func (x *BillingDataProfile) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use BillingDataProfile.ProtoReflect.Descriptor instead.
 func (*BillingDataProfile) Descriptor() ([]byte, []int) {
-	return file_dlmsprocessor_proto_rawDescGZIP(), []int{11}
+	return file_dlmsprocessor_proto_rawDescGZIP(), []int{13}
 }
 
 func (x *BillingDataProfile) GetBillingDate() string {
@@ -863,6 +1434,7 @@ func (x *BillingDataProfile) GetCumEnergyWhImport() float64 {
 	return 0
 }
 
+// Deprecated: Marked as deprecated in dlmsprocessor.proto.
 func (x *BillingDataProfile) GetCumEnergyWhTz1() float64 {
 	if x != nil {
 		return x.CumEnergyWhTz1
@@ -870,6 +1442,7 @@ func (x *BillingDataProfile) GetCumEnergyWhTz1() float64 {
 	return 0
 }
 
+// Deprecated: Marked as deprecated in dlmsprocessor.proto.
 func (x *BillingDataProfile) GetCumEnergyWhTz2() float64 {
 	if x != nil {
 		return x.CumEnergyWhTz2
@@ -877,6 +1450,7 @@ func (x *BillingDataProfile) GetCumEnergyWhTz2() float64 {
 	return 0
 }
 
+// Deprecated: Marked as deprecated in dlmsprocessor.proto.
 func (x *BillingDataProfile) GetCumEnergyWhTz3() float64 {
 	if x != nil {
 		return x.CumEnergyWhTz3
@@ -884,6 +1458,7 @@ func (x *BillingDataProfile) GetCumEnergyWhTz3() float64 {
 	return 0
 }
 
+// Deprecated: Marked as deprecated in dlmsprocessor.proto.
 func (x *BillingDataProfile) GetCumEnergyWhTz4() float64 {
 	if x != nil {
 		return x.CumEnergyWhTz4
@@ -898,6 +1473,7 @@ func (x *BillingDataProfile) GetCumEnergyVahImport() float64 {
 	return 0
 }
 
+// Deprecated: Marked as deprecated in dlmsprocessor.proto.
 func (x *BillingDataProfile) GetCumEnergyVahTz1() float64 {
 	if x != nil {
 		return x.CumEnergyVahTz1
@@ -905,6 +1481,7 @@ func (x *BillingDataProfile) GetCumEnergyVahTz1() float64 {
 	return 0
 }
 
+// Deprecated: Marked as deprecated in dlmsprocessor.proto.
 func (x *BillingDataProfile) GetCumEnergyVahTz2() float64 {
 	if x != nil {
 		return x.CumEnergyVahTz2
@@ -912,6 +1489,7 @@ func (x *BillingDataProfile) GetCumEnergyVahTz2() float64 {
 	return 0
 }
 
+// Deprecated: Marked as deprecated in dlmsprocessor.proto.
 func (x *BillingDataProfile) GetCumEnergyVahTz3() float64 {
 	if x != nil {
 		return x.CumEnergyVahTz3
@@ -919,6 +1497,7 @@ func (x *BillingDataProfile) GetCumEnergyVahTz3() float64 {
 	return 0
 }
 
+// Deprecated: Marked as deprecated in dlmsprocessor.proto.
 func (x *BillingDataProfile) GetCumEnergyVahTz4() float64 {
 	if x != nil {
 		return x.CumEnergyVahTz4
@@ -975,6 +1554,107 @@ func (x *BillingDataProfile) GetCumEnergyVah() float64 {
 	return 0
 }
 
+func (x *BillingDataProfile) GetTariffZones() []*TariffZone {
+	if x != nil {
+		return x.TariffZones
+	}
+	return nil
+}
+
+// TariffZone is the time-of-use registers of one tariff zone, whose number
+// is the E group of the registers' OBIS codes
+type TariffZone struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Zone          int32                  `protobuf:"varint,1,opt,name=zone,proto3" json:"zone,omitempty"`
+	CumEnergyWh   float64                `protobuf:"fixed64,2,opt,name=cumEnergyWh,proto3" json:"cumEnergyWh,omitempty"`   // Cumulative Energy - Wh (OBIS: 1.0.1.8.zone.255)
+	CumEnergyVah  float64                `protobuf:"fixed64,3,opt,name=cumEnergyVah,proto3" json:"cumEnergyVah,omitempty"` // Cumulative Energy - VAh (OBIS: 1.0.9.8.zone.255)
+	Mdw           float64                `protobuf:"fixed64,4,opt,name=mdw,proto3" json:"mdw,omitempty"`                   // MD W (OBIS: 1.0.1.6.zone.255)
+	MdwDateTime   string                 `protobuf:"bytes,5,opt,name=mdwDateTime,proto3" json:"mdwDateTime,omitempty"`     // MD W - Date & Time (OBIS: 1.0.1.6.zone.255)
+	Mdva          float64                `protobuf:"fixed64,6,opt,name=mdva,proto3" json:"mdva,omitempty"`                 // MD VA (OBIS: 1.0.9.6.zone.255)
+	MdvaDateTime  string                 `protobuf:"bytes,7,opt,name=mdvaDateTime,proto3" json:"mdvaDateTime,omitempty"`   // MD VA - Date & Time (OBIS: 1.0.9.6.zone.255)
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TariffZone) Reset() {
+	*x = TariffZone{}
+	mi := &file_dlmsprocessor_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TariffZone) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TariffZone) ProtoMessage() {}
+
+func (x *TariffZone) ProtoReflect() protoreflect.Message {
+	mi := &file_dlmsprocessor_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TariffZone.ProtoReflect.Descriptor instead.
+func (*TariffZone) Descriptor() ([]byte, []int) {
+	return file_dlmsprocessor_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *TariffZone) GetZone() int32 {
+	if x != nil {
+		return x.Zone
+	}
+	return 0
+}
+
+func (x *TariffZone) GetCumEnergyWh() float64 {
+	if x != nil {
+		return x.CumEnergyWh
+	}
+	return 0
+}
+
+func (x *TariffZone) GetCumEnergyVah() float64 {
+	if x != nil {
+		return x.CumEnergyVah
+	}
+	return 0
+}
+
+func (x *TariffZone) GetMdw() float64 {
+	if x != nil {
+		return x.Mdw
+	}
+	return 0
+}
+
+func (x *TariffZone) GetMdwDateTime() string {
+	if x != nil {
+		return x.MdwDateTime
+	}
+	return ""
+}
+
+func (x *TariffZone) GetMdva() float64 {
+	if x != nil {
+		return x.Mdva
+	}
+	return 0
+}
+
+func (x *TariffZone) GetMdvaDateTime() string {
+	if x != nil {
+		return x.MdvaDateTime
+	}
+	return ""
+}
+
 // Instantaneous Profile Messages
 type GetInstantaneousProfileRequest struct {
 	state             protoimpl.MessageState `protogen:"open.v1"`
@@ -982,13 +1662,14 @@ type GetInstantaneousProfileRequest struct {
 	Retries           int32                  `protobuf:"varint,2,opt,name=retries,proto3" json:"retries,omitempty"`
 	RetryDelay        int32                  `protobuf:"varint,3,opt,name=retryDelay,proto3" json:"retryDelay,omitempty"`
 	ConnectionTimeout int32                  `protobuf:"varint,4,opt,name=connectionTimeout,proto3" json:"connectionTimeout,omitempty"`
+	Timeout           int32                  `protobuf:"varint,5,opt,name=timeout,proto3" json:"timeout,omitempty"` // Milliseconds for each attempt, 0 for the server default
 	unknownFields     protoimpl.UnknownFields
 	sizeCache         protoimpl.SizeCache
 }
 
 func (x *GetInstantaneousProfileRequest) Reset() {
 	*x = GetInstantaneousProfileRequest{}
-	mi := &file_dlmsprocessor_proto_msgTypes[12]
+	mi := &file_dlmsprocessor_proto_msgTypes[15]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1000,7 +1681,7 @@ func (x *GetInstantaneousProfileRequest) String() string {
 func (*GetInstantaneousProfileRequest) ProtoMessage() {}
 
 func (x *GetInstantaneousProfileRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_dlmsprocessor_proto_msgTypes[12]
+	mi := &file_dlmsprocessor_proto_msgTypes[15]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1013,7 +1694,7 @@ func (x *GetInstantaneousProfileRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetInstantaneousProfileRequest.ProtoReflect.Descriptor instead.
 func (*GetInstantaneousProfileRequest) Descriptor() ([]byte, []int) {
-	return file_dlmsprocessor_proto_rawDescGZIP(), []int{12}
+	return file_dlmsprocessor_proto_rawDescGZIP(), []int{15}
 }
 
 func (x *GetInstantaneousProfileRequest) GetMeter() []*Meter {
@@ -1044,29 +1725,2574 @@ func (x *GetInstantaneousProfileRequest) GetConnectionTimeout() int32 {
 	return 0
 }
 
-type GetInstantaneousProfileResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
+func (x *GetInstantaneousProfileRequest) GetTimeout() int32 {
+	if x != nil {
+		return x.Timeout
+	}
+	return 0
+}
+
+type GetInstantaneousProfileResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
 	Profile       *InstantaneousProfile  `protobuf:"bytes,1,opt,name=profile,proto3" json:"profile,omitempty"`
 	MeterIp       string                 `protobuf:"bytes,2,opt,name=meterIp,proto3" json:"meterIp,omitempty"` // To identify which meter the profile came from
+	MeterId       string                 `protobuf:"bytes,3,opt,name=meterId,proto3" json:"meterId,omitempty"`
+	SerialNumber  string                 `protobuf:"bytes,4,opt,name=serialNumber,proto3" json:"serialNumber,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetInstantaneousProfileResponse) Reset() {
+	*x = GetInstantaneousProfileResponse{}
+	mi := &file_dlmsprocessor_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetInstantaneousProfileResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetInstantaneousProfileResponse) ProtoMessage() {}
+
+func (x *GetInstantaneousProfileResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_dlmsprocessor_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetInstantaneousProfileResponse.ProtoReflect.Descriptor instead.
+func (*GetInstantaneousProfileResponse) Descriptor() ([]byte, []int) {
+	return file_dlmsprocessor_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *GetInstantaneousProfileResponse) GetProfile() *InstantaneousProfile {
+	if x != nil {
+		return x.Profile
+	}
+	return nil
+}
+
+func (x *GetInstantaneousProfileResponse) GetMeterIp() string {
+	if x != nil {
+		return x.MeterIp
+	}
+	return ""
+}
+
+func (x *GetInstantaneousProfileResponse) GetMeterId() string {
+	if x != nil {
+		return x.MeterId
+	}
+	return ""
+}
+
+func (x *GetInstantaneousProfileResponse) GetSerialNumber() string {
+	if x != nil {
+		return x.SerialNumber
+	}
+	return ""
+}
+
+type InstantaneousProfile struct {
+	state             protoimpl.MessageState          `protogen:"open.v1"`
+	DateTime          string                          `protobuf:"bytes,1,opt,name=dateTime,proto3" json:"dateTime,omitempty"`                     // RTC - Date & Time (OBIS: 0.0.1.0.0.255)
+	Voltage           float64                         `protobuf:"fixed64,2,opt,name=voltage,proto3" json:"voltage,omitempty"`                     // Voltage (instantaneous) (OBIS: 1.0.12.7.0.255)
+	PhaseCurrent      float64                         `protobuf:"fixed64,3,opt,name=phaseCurrent,proto3" json:"phaseCurrent,omitempty"`           // Phase Current (instantaneous) (OBIS: 1.0.11.7.0.255)
+	NeutralCurrent    float64                         `protobuf:"fixed64,4,opt,name=neutralCurrent,proto3" json:"neutralCurrent,omitempty"`       // Neutral Current (instantaneous) (OBIS: 1.0.91.7.0.255)
+	SignedPowerFactor float64                         `protobuf:"fixed64,5,opt,name=signedPowerFactor,proto3" json:"signedPowerFactor,omitempty"` // Signed Power Factor (instantaneous) (OBIS: 1.0.13.7.0.255)
+	Frequency         float64                         `protobuf:"fixed64,6,opt,name=frequency,proto3" json:"frequency,omitempty"`                 // Frequency (instantaneous) (OBIS: 1.0.14.7.0.255)
+	ApparentPower     float64                         `protobuf:"fixed64,7,opt,name=apparentPower,proto3" json:"apparentPower,omitempty"`         // Apparent Power - VA (instantaneous) (OBIS: 1.0.9.7.0.255)
+	ActivePower       float64                         `protobuf:"fixed64,8,opt,name=activePower,proto3" json:"activePower,omitempty"`             // Active Power - W (instantaneous) (OBIS: 1.0.1.7.0.255)
+	CumEnergyWh       float64                         `protobuf:"fixed64,9,opt,name=cumEnergyWh,proto3" json:"cumEnergyWh,omitempty"`             // Cumulative Energy - Wh (OBIS: 1.0.1.8.0.255)
+	ThreePhase        *ThreePhaseInstantaneousProfile `protobuf:"bytes,10,opt,name=threePhase,proto3" json:"threePhase,omitempty"`                // Per-phase values of a three-phase meter, which has no single voltage or phase current
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *InstantaneousProfile) Reset() {
+	*x = InstantaneousProfile{}
+	mi := &file_dlmsprocessor_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *InstantaneousProfile) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*InstantaneousProfile) ProtoMessage() {}
+
+func (x *InstantaneousProfile) ProtoReflect() protoreflect.Message {
+	mi := &file_dlmsprocessor_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use InstantaneousProfile.ProtoReflect.Descriptor instead.
+func (*InstantaneousProfile) Descriptor() ([]byte, []int) {
+	return file_dlmsprocessor_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *InstantaneousProfile) GetDateTime() string {
+	if x != nil {
+		return x.DateTime
+	}
+	return ""
+}
+
+func (x *InstantaneousProfile) GetVoltage() float64 {
+	if x != nil {
+		return x.Voltage
+	}
+	return 0
+}
+
+func (x *InstantaneousProfile) GetPhaseCurrent() float64 {
+	if x != nil {
+		return x.PhaseCurrent
+	}
+	return 0
+}
+
+func (x *InstantaneousProfile) GetNeutralCurrent() float64 {
+	if x != nil {
+		return x.NeutralCurrent
+	}
+	return 0
+}
+
+func (x *InstantaneousProfile) GetSignedPowerFactor() float64 {
+	if x != nil {
+		return x.SignedPowerFactor
+	}
+	return 0
+}
+
+func (x *InstantaneousProfile) GetFrequency() float64 {
+	if x != nil {
+		return x.Frequency
+	}
+	return 0
+}
+
+func (x *InstantaneousProfile) GetApparentPower() float64 {
+	if x != nil {
+		return x.ApparentPower
+	}
+	return 0
+}
+
+func (x *InstantaneousProfile) GetActivePower() float64 {
+	if x != nil {
+		return x.ActivePower
+	}
+	return 0
+}
+
+func (x *InstantaneousProfile) GetCumEnergyWh() float64 {
+	if x != nil {
+		return x.CumEnergyWh
+	}
+	return 0
+}
+
+func (x *InstantaneousProfile) GetThreePhase() *ThreePhaseInstantaneousProfile {
+	if x != nil {
+		return x.ThreePhase
+	}
+	return nil
+}
+
+// Instantaneous profile entry of a three-phase meter, with R, Y and B phase values
+type ThreePhaseInstantaneousProfile struct {
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	DateTime           string                 `protobuf:"bytes,1,opt,name=dateTime,proto3" json:"dateTime,omitempty"`                        // RTC - Date & Time (OBIS: 0.0.1.0.0.255)
+	CurrentR           float64                `protobuf:"fixed64,2,opt,name=currentR,proto3" json:"currentR,omitempty"`                      // Current - IR (OBIS: 1.0.31.7.0.255)
+	CurrentY           float64                `protobuf:"fixed64,3,opt,name=currentY,proto3" json:"currentY,omitempty"`                      // Current - IY (OBIS: 1.0.51.7.0.255)
+	CurrentB           float64                `protobuf:"fixed64,4,opt,name=currentB,proto3" json:"currentB,omitempty"`                      // Current - IB (OBIS: 1.0.71.7.0.255)
+	VoltageR           float64                `protobuf:"fixed64,5,opt,name=voltageR,proto3" json:"voltageR,omitempty"`                      // Voltage - VRN (OBIS: 1.0.32.7.0.255)
+	VoltageY           float64                `protobuf:"fixed64,6,opt,name=voltageY,proto3" json:"voltageY,omitempty"`                      // Voltage - VYN (OBIS: 1.0.52.7.0.255)
+	VoltageB           float64                `protobuf:"fixed64,7,opt,name=voltageB,proto3" json:"voltageB,omitempty"`                      // Voltage - VBN (OBIS: 1.0.72.7.0.255)
+	PowerFactorR       float64                `protobuf:"fixed64,8,opt,name=powerFactorR,proto3" json:"powerFactorR,omitempty"`              // Signed Power Factor - R phase (OBIS: 1.0.33.7.0.255)
+	PowerFactorY       float64                `protobuf:"fixed64,9,opt,name=powerFactorY,proto3" json:"powerFactorY,omitempty"`              // Signed Power Factor - Y phase (OBIS: 1.0.53.7.0.255)
+	PowerFactorB       float64                `protobuf:"fixed64,10,opt,name=powerFactorB,proto3" json:"powerFactorB,omitempty"`             // Signed Power Factor - B phase (OBIS: 1.0.73.7.0.255)
+	PowerFactor        float64                `protobuf:"fixed64,11,opt,name=powerFactor,proto3" json:"powerFactor,omitempty"`               // Three Phase Power Factor (OBIS: 1.0.13.7.0.255)
+	Frequency          float64                `protobuf:"fixed64,12,opt,name=frequency,proto3" json:"frequency,omitempty"`                   // Frequency (OBIS: 1.0.14.7.0.255)
+	ActivePowerR       float64                `protobuf:"fixed64,13,opt,name=activePowerR,proto3" json:"activePowerR,omitempty"`             // Active Power - W, R phase (OBIS: 1.0.21.7.0.255)
+	ActivePowerY       float64                `protobuf:"fixed64,14,opt,name=activePowerY,proto3" json:"activePowerY,omitempty"`             // Active Power - W, Y phase (OBIS: 1.0.41.7.0.255)
+	ActivePowerB       float64                `protobuf:"fixed64,15,opt,name=activePowerB,proto3" json:"activePowerB,omitempty"`             // Active Power - W, B phase (OBIS: 1.0.61.7.0.255)
+	ReactivePowerR     float64                `protobuf:"fixed64,16,opt,name=reactivePowerR,proto3" json:"reactivePowerR,omitempty"`         // Reactive Power - var, R phase (OBIS: 1.0.23.7.0.255)
+	ReactivePowerY     float64                `protobuf:"fixed64,17,opt,name=reactivePowerY,proto3" json:"reactivePowerY,omitempty"`         // Reactive Power - var, Y phase (OBIS: 1.0.43.7.0.255)
+	ReactivePowerB     float64                `protobuf:"fixed64,18,opt,name=reactivePowerB,proto3" json:"reactivePowerB,omitempty"`         // Reactive Power - var, B phase (OBIS: 1.0.63.7.0.255)
+	ApparentPowerR     float64                `protobuf:"fixed64,19,opt,name=apparentPowerR,proto3" json:"apparentPowerR,omitempty"`         // Apparent Power - VA, R phase (OBIS: 1.0.29.7.0.255)
+	ApparentPowerY     float64                `protobuf:"fixed64,20,opt,name=apparentPowerY,proto3" json:"apparentPowerY,omitempty"`         // Apparent Power - VA, Y phase (OBIS: 1.0.49.7.0.255)
+	ApparentPowerB     float64                `protobuf:"fixed64,21,opt,name=apparentPowerB,proto3" json:"apparentPowerB,omitempty"`         // Apparent Power - VA, B phase (OBIS: 1.0.69.7.0.255)
+	ActivePower        float64                `protobuf:"fixed64,22,opt,name=activePower,proto3" json:"activePower,omitempty"`               // Signed Active Power - W (OBIS: 1.0.1.7.0.255)
+	ReactivePower      float64                `protobuf:"fixed64,23,opt,name=reactivePower,proto3" json:"reactivePower,omitempty"`           // Signed Reactive Power - var (OBIS: 1.0.3.7.0.255)
+	ApparentPower      float64                `protobuf:"fixed64,24,opt,name=apparentPower,proto3" json:"apparentPower,omitempty"`           // Apparent Power - VA (OBIS: 1.0.9.7.0.255)
+	CumEnergyWhImport  float64                `protobuf:"fixed64,25,opt,name=cumEnergyWhImport,proto3" json:"cumEnergyWhImport,omitempty"`   // Cumulative Energy - Wh (import) (OBIS: 1.0.1.8.0.255)
+	CumEnergyWhExport  float64                `protobuf:"fixed64,26,opt,name=cumEnergyWhExport,proto3" json:"cumEnergyWhExport,omitempty"`   // Cumulative Energy - Wh (export) (OBIS: 1.0.2.8.0.255)
+	CumEnergyVarhLag   float64                `protobuf:"fixed64,27,opt,name=cumEnergyVarhLag,proto3" json:"cumEnergyVarhLag,omitempty"`     // Cumulative Energy - varh, lag (OBIS: 1.0.5.8.0.255)
+	CumEnergyVarhLead  float64                `protobuf:"fixed64,28,opt,name=cumEnergyVarhLead,proto3" json:"cumEnergyVarhLead,omitempty"`   // Cumulative Energy - varh, lead (OBIS: 1.0.8.8.0.255)
+	CumEnergyVahImport float64                `protobuf:"fixed64,29,opt,name=cumEnergyVahImport,proto3" json:"cumEnergyVahImport,omitempty"` // Cumulative Energy - VAh (import) (OBIS: 1.0.9.8.0.255)
+	CumEnergyVahExport float64                `protobuf:"fixed64,30,opt,name=cumEnergyVahExport,proto3" json:"cumEnergyVahExport,omitempty"` // Cumulative Energy - VAh (export) (OBIS: 1.0.10.8.0.255)
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *ThreePhaseInstantaneousProfile) Reset() {
+	*x = ThreePhaseInstantaneousProfile{}
+	mi := &file_dlmsprocessor_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ThreePhaseInstantaneousProfile) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ThreePhaseInstantaneousProfile) ProtoMessage() {}
+
+func (x *ThreePhaseInstantaneousProfile) ProtoReflect() protoreflect.Message {
+	mi := &file_dlmsprocessor_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ThreePhaseInstantaneousProfile.ProtoReflect.Descriptor instead.
+func (*ThreePhaseInstantaneousProfile) Descriptor() ([]byte, []int) {
+	return file_dlmsprocessor_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *ThreePhaseInstantaneousProfile) GetDateTime() string {
+	if x != nil {
+		return x.DateTime
+	}
+	return ""
+}
+
+func (x *ThreePhaseInstantaneousProfile) GetCurrentR() float64 {
+	if x != nil {
+		return x.CurrentR
+	}
+	return 0
+}
+
+func (x *ThreePhaseInstantaneousProfile) GetCurrentY() float64 {
+	if x != nil {
+		return x.CurrentY
+	}
+	return 0
+}
+
+func (x *ThreePhaseInstantaneousProfile) GetCurrentB() float64 {
+	if x != nil {
+		return x.CurrentB
+	}
+	return 0
+}
+
+func (x *ThreePhaseInstantaneousProfile) GetVoltageR() float64 {
+	if x != nil {
+		return x.VoltageR
+	}
+	return 0
+}
+
+func (x *ThreePhaseInstantaneousProfile) GetVoltageY() float64 {
+	if x != nil {
+		return x.VoltageY
+	}
+	return 0
+}
+
+func (x *ThreePhaseInstantaneousProfile) GetVoltageB() float64 {
+	if x != nil {
+		return x.VoltageB
+	}
+	return 0
+}
+
+func (x *ThreePhaseInstantaneousProfile) GetPowerFactorR() float64 {
+	if x != nil {
+		return x.PowerFactorR
+	}
+	return 0
+}
+
+func (x *ThreePhaseInstantaneousProfile) GetPowerFactorY() float64 {
+	if x != nil {
+		return x.PowerFactorY
+	}
+	return 0
+}
+
+func (x *ThreePhaseInstantaneousProfile) GetPowerFactorB() float64 {
+	if x != nil {
+		return x.PowerFactorB
+	}
+	return 0
+}
+
+func (x *ThreePhaseInstantaneousProfile) GetPowerFactor() float64 {
+	if x != nil {
+		return x.PowerFactor
+	}
+	return 0
+}
+
+func (x *ThreePhaseInstantaneousProfile) GetFrequency() float64 {
+	if x != nil {
+		return x.Frequency
+	}
+	return 0
+}
+
+func (x *ThreePhaseInstantaneousProfile) GetActivePowerR() float64 {
+	if x != nil {
+		return x.ActivePowerR
+	}
+	return 0
+}
+
+func (x *ThreePhaseInstantaneousProfile) GetActivePowerY() float64 {
+	if x != nil {
+		return x.ActivePowerY
+	}
+	return 0
+}
+
+func (x *ThreePhaseInstantaneousProfile) GetActivePowerB() float64 {
+	if x != nil {
+		return x.ActivePowerB
+	}
+	return 0
+}
+
+func (x *ThreePhaseInstantaneousProfile) GetReactivePowerR() float64 {
+	if x != nil {
+		return x.ReactivePowerR
+	}
+	return 0
+}
+
+func (x *ThreePhaseInstantaneousProfile) GetReactivePowerY() float64 {
+	if x != nil {
+		return x.ReactivePowerY
+	}
+	return 0
+}
+
+func (x *ThreePhaseInstantaneousProfile) GetReactivePowerB() float64 {
+	if x != nil {
+		return x.ReactivePowerB
+	}
+	return 0
+}
+
+func (x *ThreePhaseInstantaneousProfile) GetApparentPowerR() float64 {
+	if x != nil {
+		return x.ApparentPowerR
+	}
+	return 0
+}
+
+func (x *ThreePhaseInstantaneousProfile) GetApparentPowerY() float64 {
+	if x != nil {
+		return x.ApparentPowerY
+	}
+	return 0
+}
+
+func (x *ThreePhaseInstantaneousProfile) GetApparentPowerB() float64 {
+	if x != nil {
+		return x.ApparentPowerB
+	}
+	return 0
+}
+
+func (x *ThreePhaseInstantaneousProfile) GetActivePower() float64 {
+	if x != nil {
+		return x.ActivePower
+	}
+	return 0
+}
+
+func (x *ThreePhaseInstantaneousProfile) GetReactivePower() float64 {
+	if x != nil {
+		return x.ReactivePower
+	}
+	return 0
+}
+
+func (x *ThreePhaseInstantaneousProfile) GetApparentPower() float64 {
+	if x != nil {
+		return x.ApparentPower
+	}
+	return 0
+}
+
+func (x *ThreePhaseInstantaneousProfile) GetCumEnergyWhImport() float64 {
+	if x != nil {
+		return x.CumEnergyWhImport
+	}
+	return 0
+}
+
+func (x *ThreePhaseInstantaneousProfile) GetCumEnergyWhExport() float64 {
+	if x != nil {
+		return x.CumEnergyWhExport
+	}
+	return 0
+}
+
+func (x *ThreePhaseInstantaneousProfile) GetCumEnergyVarhLag() float64 {
+	if x != nil {
+		return x.CumEnergyVarhLag
+	}
+	return 0
+}
+
+func (x *ThreePhaseInstantaneousProfile) GetCumEnergyVarhLead() float64 {
+	if x != nil {
+		return x.CumEnergyVarhLead
+	}
+	return 0
+}
+
+func (x *ThreePhaseInstantaneousProfile) GetCumEnergyVahImport() float64 {
+	if x != nil {
+		return x.CumEnergyVahImport
+	}
+	return 0
+}
+
+func (x *ThreePhaseInstantaneousProfile) GetCumEnergyVahExport() float64 {
+	if x != nil {
+		return x.CumEnergyVahExport
+	}
+	return 0
+}
+
+// Probe Messages
+type ProbeRequest struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	Meter             []*Meter               `protobuf:"bytes,1,rep,name=meter,proto3" json:"meter,omitempty"`
+	ConnectionTimeout int32                  `protobuf:"varint,2,opt,name=connectionTimeout,proto3" json:"connectionTimeout,omitempty"` // Milliseconds, per I/O step with the meter
+	TraceFrames       FrameTraceMode         `protobuf:"varint,3,opt,name=traceFrames,proto3,enum=dlmsprocessor.FrameTraceMode" json:"traceFrames,omitempty"`
+	Timeout           int32                  `protobuf:"varint,4,opt,name=timeout,proto3" json:"timeout,omitempty"` // Milliseconds for each meter, 0 for the server default
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *ProbeRequest) Reset() {
+	*x = ProbeRequest{}
+	mi := &file_dlmsprocessor_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ProbeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProbeRequest) ProtoMessage() {}
+
+func (x *ProbeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_dlmsprocessor_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProbeRequest.ProtoReflect.Descriptor instead.
+func (*ProbeRequest) Descriptor() ([]byte, []int) {
+	return file_dlmsprocessor_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *ProbeRequest) GetMeter() []*Meter {
+	if x != nil {
+		return x.Meter
+	}
+	return nil
+}
+
+func (x *ProbeRequest) GetConnectionTimeout() int32 {
+	if x != nil {
+		return x.ConnectionTimeout
+	}
+	return 0
+}
+
+func (x *ProbeRequest) GetTraceFrames() FrameTraceMode {
+	if x != nil {
+		return x.TraceFrames
+	}
+	return FrameTraceMode_FRAME_TRACE_MODE_NONE
+}
+
+func (x *ProbeRequest) GetTimeout() int32 {
+	if x != nil {
+		return x.Timeout
+	}
+	return 0
+}
+
+type ProbeResponse struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	MeterId           string                 `protobuf:"bytes,1,opt,name=meterId,proto3" json:"meterId,omitempty"`
+	MeterIp           string                 `protobuf:"bytes,2,opt,name=meterIp,proto3" json:"meterIp,omitempty"`
+	SerialNumber      string                 `protobuf:"bytes,3,opt,name=serialNumber,proto3" json:"serialNumber,omitempty"`
+	Ok                bool                   `protobuf:"varint,4,opt,name=ok,proto3" json:"ok,omitempty"`                              // Every step succeeded
+	Steps             []*ProbeStep           `protobuf:"bytes,5,rep,name=steps,proto3" json:"steps,omitempty"`                         // Every step in order, including skipped ones
+	LogicalDeviceName string                 `protobuf:"bytes,6,opt,name=logicalDeviceName,proto3" json:"logicalDeviceName,omitempty"` // As read from the meter (OBIS: 0.0.42.0.0.255)
+	FirmwareVersion   string                 `protobuf:"bytes,7,opt,name=firmwareVersion,proto3" json:"firmwareVersion,omitempty"`     // As read from the meter (OBIS: 1.0.0.2.0.255)
+	Frames            *FrameTrace            `protobuf:"bytes,8,opt,name=frames,proto3" json:"frames,omitempty"`                       // Set when the request asked for a frame trace
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *ProbeResponse) Reset() {
+	*x = ProbeResponse{}
+	mi := &file_dlmsprocessor_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ProbeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProbeResponse) ProtoMessage() {}
+
+func (x *ProbeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_dlmsprocessor_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProbeResponse.ProtoReflect.Descriptor instead.
+func (*ProbeResponse) Descriptor() ([]byte, []int) {
+	return file_dlmsprocessor_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *ProbeResponse) GetMeterId() string {
+	if x != nil {
+		return x.MeterId
+	}
+	return ""
+}
+
+func (x *ProbeResponse) GetMeterIp() string {
+	if x != nil {
+		return x.MeterIp
+	}
+	return ""
+}
+
+func (x *ProbeResponse) GetSerialNumber() string {
+	if x != nil {
+		return x.SerialNumber
+	}
+	return ""
+}
+
+func (x *ProbeResponse) GetOk() bool {
+	if x != nil {
+		return x.Ok
+	}
+	return false
+}
+
+func (x *ProbeResponse) GetSteps() []*ProbeStep {
+	if x != nil {
+		return x.Steps
+	}
+	return nil
+}
+
+func (x *ProbeResponse) GetLogicalDeviceName() string {
+	if x != nil {
+		return x.LogicalDeviceName
+	}
+	return ""
+}
+
+func (x *ProbeResponse) GetFirmwareVersion() string {
+	if x != nil {
+		return x.FirmwareVersion
+	}
+	return ""
+}
+
+func (x *ProbeResponse) GetFrames() *FrameTrace {
+	if x != nil {
+		return x.Frames
+	}
+	return nil
+}
+
+type ProbeStep struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// tcp_connect, link_setup, association, authentication,
+	// logical_device_name, firmware_version or release
+	Name          string          `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Status        ProbeStepStatus `protobuf:"varint,2,opt,name=status,proto3,enum=dlmsprocessor.ProbeStepStatus" json:"status,omitempty"`
+	DurationMs    float64         `protobuf:"fixed64,3,opt,name=durationMs,proto3" json:"durationMs,omitempty"`
+	Reason        string          `protobuf:"bytes,4,opt,name=reason,proto3" json:"reason,omitempty"` // Why the step failed, e.g. "timeout" or "auth failed: wrong authentication key"
+	Error         string          `protobuf:"bytes,5,opt,name=error,proto3" json:"error,omitempty"`   // The underlying error
+	Detail        string          `protobuf:"bytes,6,opt,name=detail,proto3" json:"detail,omitempty"` // What the step read, or why it had nothing to do
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ProbeStep) Reset() {
+	*x = ProbeStep{}
+	mi := &file_dlmsprocessor_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ProbeStep) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProbeStep) ProtoMessage() {}
+
+func (x *ProbeStep) ProtoReflect() protoreflect.Message {
+	mi := &file_dlmsprocessor_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProbeStep.ProtoReflect.Descriptor instead.
+func (*ProbeStep) Descriptor() ([]byte, []int) {
+	return file_dlmsprocessor_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *ProbeStep) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ProbeStep) GetStatus() ProbeStepStatus {
+	if x != nil {
+		return x.Status
+	}
+	return ProbeStepStatus_PROBE_STEP_STATUS_UNSPECIFIED
+}
+
+func (x *ProbeStep) GetDurationMs() float64 {
+	if x != nil {
+		return x.DurationMs
+	}
+	return 0
+}
+
+func (x *ProbeStep) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *ProbeStep) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *ProbeStep) GetDetail() string {
+	if x != nil {
+		return x.Detail
+	}
+	return ""
+}
+
+type GetNameplateRequest struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	Meter             []*Meter               `protobuf:"bytes,1,rep,name=meter,proto3" json:"meter,omitempty"`
+	Retries           int32                  `protobuf:"varint,2,opt,name=retries,proto3" json:"retries,omitempty"`
+	RetryDelay        int32                  `protobuf:"varint,3,opt,name=retryDelay,proto3" json:"retryDelay,omitempty"`
+	ConnectionTimeout int32                  `protobuf:"varint,4,opt,name=connectionTimeout,proto3" json:"connectionTimeout,omitempty"`
+	Timeout           int32                  `protobuf:"varint,5,opt,name=timeout,proto3" json:"timeout,omitempty"` // Milliseconds for each attempt, 0 for the server default
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *GetNameplateRequest) Reset() {
+	*x = GetNameplateRequest{}
+	mi := &file_dlmsprocessor_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetNameplateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetNameplateRequest) ProtoMessage() {}
+
+func (x *GetNameplateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_dlmsprocessor_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetNameplateRequest.ProtoReflect.Descriptor instead.
+func (*GetNameplateRequest) Descriptor() ([]byte, []int) {
+	return file_dlmsprocessor_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *GetNameplateRequest) GetMeter() []*Meter {
+	if x != nil {
+		return x.Meter
+	}
+	return nil
+}
+
+func (x *GetNameplateRequest) GetRetries() int32 {
+	if x != nil {
+		return x.Retries
+	}
+	return 0
+}
+
+func (x *GetNameplateRequest) GetRetryDelay() int32 {
+	if x != nil {
+		return x.RetryDelay
+	}
+	return 0
+}
+
+func (x *GetNameplateRequest) GetConnectionTimeout() int32 {
+	if x != nil {
+		return x.ConnectionTimeout
+	}
+	return 0
+}
+
+func (x *GetNameplateRequest) GetTimeout() int32 {
+	if x != nil {
+		return x.Timeout
+	}
+	return 0
+}
+
+type GetNameplateResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Nameplate     *NameplateProfile      `protobuf:"bytes,1,opt,name=nameplate,proto3" json:"nameplate,omitempty"`
+	MeterIp       string                 `protobuf:"bytes,2,opt,name=meterIp,proto3" json:"meterIp,omitempty"`
+	MeterId       string                 `protobuf:"bytes,3,opt,name=meterId,proto3" json:"meterId,omitempty"`
+	SerialNumber  string                 `protobuf:"bytes,4,opt,name=serialNumber,proto3" json:"serialNumber,omitempty"` // The serial number the request gave, to compare with the nameplate's
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetNameplateResponse) Reset() {
+	*x = GetNameplateResponse{}
+	mi := &file_dlmsprocessor_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetNameplateResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetNameplateResponse) ProtoMessage() {}
+
+func (x *GetNameplateResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_dlmsprocessor_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetNameplateResponse.ProtoReflect.Descriptor instead.
+func (*GetNameplateResponse) Descriptor() ([]byte, []int) {
+	return file_dlmsprocessor_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *GetNameplateResponse) GetNameplate() *NameplateProfile {
+	if x != nil {
+		return x.Nameplate
+	}
+	return nil
+}
+
+func (x *GetNameplateResponse) GetMeterIp() string {
+	if x != nil {
+		return x.MeterIp
+	}
+	return ""
+}
+
+func (x *GetNameplateResponse) GetMeterId() string {
+	if x != nil {
+		return x.MeterId
+	}
+	return ""
+}
+
+func (x *GetNameplateResponse) GetSerialNumber() string {
+	if x != nil {
+		return x.SerialNumber
+	}
+	return ""
+}
+
+// Nameplate profile (OBIS: 0.0.94.91.10.255), or the objects it captures on
+// meters without it
+type NameplateProfile struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	SerialNumber      string                 `protobuf:"bytes,1,opt,name=serialNumber,proto3" json:"serialNumber,omitempty"`            // Meter Serial Number (OBIS: 0.0.96.1.0.255)
+	ManufacturerName  string                 `protobuf:"bytes,2,opt,name=manufacturerName,proto3" json:"manufacturerName,omitempty"`    // Manufacturer Name (OBIS: 0.0.96.1.1.255)
+	FirmwareVersion   string                 `protobuf:"bytes,3,opt,name=firmwareVersion,proto3" json:"firmwareVersion,omitempty"`      // Firmware Version for Meter (OBIS: 1.0.0.2.0.255)
+	MeterType         uint32                 `protobuf:"varint,4,opt,name=meterType,proto3" json:"meterType,omitempty"`                 // Meter Type (OBIS: 0.0.94.91.9.255)
+	Category          string                 `protobuf:"bytes,5,opt,name=category,proto3" json:"category,omitempty"`                    // Category (OBIS: 0.0.94.91.11.255)
+	CurrentRating     string                 `protobuf:"bytes,6,opt,name=currentRating,proto3" json:"currentRating,omitempty"`          // Current Rating (OBIS: 0.0.94.91.12.255)
+	YearOfManufacture uint32                 `protobuf:"varint,7,opt,name=yearOfManufacture,proto3" json:"yearOfManufacture,omitempty"` // Meter Year of Manufacture (OBIS: 0.0.96.1.4.255)
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *NameplateProfile) Reset() {
+	*x = NameplateProfile{}
+	mi := &file_dlmsprocessor_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NameplateProfile) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NameplateProfile) ProtoMessage() {}
+
+func (x *NameplateProfile) ProtoReflect() protoreflect.Message {
+	mi := &file_dlmsprocessor_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NameplateProfile.ProtoReflect.Descriptor instead.
+func (*NameplateProfile) Descriptor() ([]byte, []int) {
+	return file_dlmsprocessor_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *NameplateProfile) GetSerialNumber() string {
+	if x != nil {
+		return x.SerialNumber
+	}
+	return ""
+}
+
+func (x *NameplateProfile) GetManufacturerName() string {
+	if x != nil {
+		return x.ManufacturerName
+	}
+	return ""
+}
+
+func (x *NameplateProfile) GetFirmwareVersion() string {
+	if x != nil {
+		return x.FirmwareVersion
+	}
+	return ""
+}
+
+func (x *NameplateProfile) GetMeterType() uint32 {
+	if x != nil {
+		return x.MeterType
+	}
+	return 0
+}
+
+func (x *NameplateProfile) GetCategory() string {
+	if x != nil {
+		return x.Category
+	}
+	return ""
+}
+
+func (x *NameplateProfile) GetCurrentRating() string {
+	if x != nil {
+		return x.CurrentRating
+	}
+	return ""
+}
+
+func (x *NameplateProfile) GetYearOfManufacture() uint32 {
+	if x != nil {
+		return x.YearOfManufacture
+	}
+	return 0
+}
+
+// Process Messages
+type ProgramTariffRequest struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	Meter             []*Meter               `protobuf:"bytes,1,rep,name=meter,proto3" json:"meter,omitempty"`
+	Retries           int32                  `protobuf:"varint,2,opt,name=retries,proto3" json:"retries,omitempty"`
+	RetryDelay        int32                  `protobuf:"varint,3,opt,name=retryDelay,proto3" json:"retryDelay,omitempty"`
+	ConnectionTimeout int32                  `protobuf:"varint,4,opt,name=connectionTimeout,proto3" json:"connectionTimeout,omitempty"`
+	Timeout           int32                  `protobuf:"varint,8,opt,name=timeout,proto3" json:"timeout,omitempty"`              // Milliseconds for each attempt, 0 for the server default
+	Calendar          *TariffCalendar        `protobuf:"bytes,5,opt,name=calendar,proto3" json:"calendar,omitempty"`             // Written as the passive calendar
+	ActivationTime    string                 `protobuf:"bytes,6,opt,name=activationTime,proto3" json:"activationTime,omitempty"` // RFC 3339; empty activates the calendar at once
+	SpecialDays       *SpecialDaysTable      `protobuf:"bytes,7,opt,name=specialDays,proto3" json:"specialDays,omitempty"`       // Replaces the table's entries; left as they are if unset
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *ProgramTariffRequest) Reset() {
+	*x = ProgramTariffRequest{}
+	mi := &file_dlmsprocessor_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ProgramTariffRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProgramTariffRequest) ProtoMessage() {}
+
+func (x *ProgramTariffRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_dlmsprocessor_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProgramTariffRequest.ProtoReflect.Descriptor instead.
+func (*ProgramTariffRequest) Descriptor() ([]byte, []int) {
+	return file_dlmsprocessor_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *ProgramTariffRequest) GetMeter() []*Meter {
+	if x != nil {
+		return x.Meter
+	}
+	return nil
+}
+
+func (x *ProgramTariffRequest) GetRetries() int32 {
+	if x != nil {
+		return x.Retries
+	}
+	return 0
+}
+
+func (x *ProgramTariffRequest) GetRetryDelay() int32 {
+	if x != nil {
+		return x.RetryDelay
+	}
+	return 0
+}
+
+func (x *ProgramTariffRequest) GetConnectionTimeout() int32 {
+	if x != nil {
+		return x.ConnectionTimeout
+	}
+	return 0
+}
+
+func (x *ProgramTariffRequest) GetTimeout() int32 {
+	if x != nil {
+		return x.Timeout
+	}
+	return 0
+}
+
+func (x *ProgramTariffRequest) GetCalendar() *TariffCalendar {
+	if x != nil {
+		return x.Calendar
+	}
+	return nil
+}
+
+func (x *ProgramTariffRequest) GetActivationTime() string {
+	if x != nil {
+		return x.ActivationTime
+	}
+	return ""
+}
+
+func (x *ProgramTariffRequest) GetSpecialDays() *SpecialDaysTable {
+	if x != nil {
+		return x.SpecialDays
+	}
+	return nil
+}
+
+type ProgramTariffResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Tariff        *Tariff                `protobuf:"bytes,1,opt,name=tariff,proto3" json:"tariff,omitempty"` // As read back from the meter
+	MeterIp       string                 `protobuf:"bytes,2,opt,name=meterIp,proto3" json:"meterIp,omitempty"`
+	MeterId       string                 `protobuf:"bytes,3,opt,name=meterId,proto3" json:"meterId,omitempty"`
+	SerialNumber  string                 `protobuf:"bytes,4,opt,name=serialNumber,proto3" json:"serialNumber,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ProgramTariffResponse) Reset() {
+	*x = ProgramTariffResponse{}
+	mi := &file_dlmsprocessor_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ProgramTariffResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProgramTariffResponse) ProtoMessage() {}
+
+func (x *ProgramTariffResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_dlmsprocessor_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProgramTariffResponse.ProtoReflect.Descriptor instead.
+func (*ProgramTariffResponse) Descriptor() ([]byte, []int) {
+	return file_dlmsprocessor_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *ProgramTariffResponse) GetTariff() *Tariff {
+	if x != nil {
+		return x.Tariff
+	}
+	return nil
+}
+
+func (x *ProgramTariffResponse) GetMeterIp() string {
+	if x != nil {
+		return x.MeterIp
+	}
+	return ""
+}
+
+func (x *ProgramTariffResponse) GetMeterId() string {
+	if x != nil {
+		return x.MeterId
+	}
+	return ""
+}
+
+func (x *ProgramTariffResponse) GetSerialNumber() string {
+	if x != nil {
+		return x.SerialNumber
+	}
+	return ""
+}
+
+// Activity calendar (OBIS: 0.0.13.0.0.255) and special days table
+// (OBIS: 0.0.11.0.0.255)
+type Tariff struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Active         *TariffCalendar        `protobuf:"bytes,1,opt,name=active,proto3" json:"active,omitempty"`
+	Passive        *TariffCalendar        `protobuf:"bytes,2,opt,name=passive,proto3" json:"passive,omitempty"`
+	ActivationTime string                 `protobuf:"bytes,3,opt,name=activationTime,proto3" json:"activationTime,omitempty"` // When the passive calendar becomes active, RFC 3339; empty if not set
+	SpecialDays    []*SpecialDay          `protobuf:"bytes,4,rep,name=specialDays,proto3" json:"specialDays,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *Tariff) Reset() {
+	*x = Tariff{}
+	mi := &file_dlmsprocessor_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Tariff) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Tariff) ProtoMessage() {}
+
+func (x *Tariff) ProtoReflect() protoreflect.Message {
+	mi := &file_dlmsprocessor_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Tariff.ProtoReflect.Descriptor instead.
+func (*Tariff) Descriptor() ([]byte, []int) {
+	return file_dlmsprocessor_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *Tariff) GetActive() *TariffCalendar {
+	if x != nil {
+		return x.Active
+	}
+	return nil
+}
+
+func (x *Tariff) GetPassive() *TariffCalendar {
+	if x != nil {
+		return x.Passive
+	}
+	return nil
+}
+
+func (x *Tariff) GetActivationTime() string {
+	if x != nil {
+		return x.ActivationTime
+	}
+	return ""
+}
+
+func (x *Tariff) GetSpecialDays() []*SpecialDay {
+	if x != nil {
+		return x.SpecialDays
+	}
+	return nil
+}
+
+type TariffCalendar struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Seasons       []*Season              `protobuf:"bytes,2,rep,name=seasons,proto3" json:"seasons,omitempty"`
+	WeekProfiles  []*WeekProfile         `protobuf:"bytes,3,rep,name=weekProfiles,proto3" json:"weekProfiles,omitempty"`
+	DayProfiles   []*DayProfile          `protobuf:"bytes,4,rep,name=dayProfiles,proto3" json:"dayProfiles,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TariffCalendar) Reset() {
+	*x = TariffCalendar{}
+	mi := &file_dlmsprocessor_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TariffCalendar) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TariffCalendar) ProtoMessage() {}
+
+func (x *TariffCalendar) ProtoReflect() protoreflect.Message {
+	mi := &file_dlmsprocessor_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TariffCalendar.ProtoReflect.Descriptor instead.
+func (*TariffCalendar) Descriptor() ([]byte, []int) {
+	return file_dlmsprocessor_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *TariffCalendar) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *TariffCalendar) GetSeasons() []*Season {
+	if x != nil {
+		return x.Seasons
+	}
+	return nil
+}
+
+func (x *TariffCalendar) GetWeekProfiles() []*WeekProfile {
+	if x != nil {
+		return x.WeekProfiles
+	}
+	return nil
+}
+
+func (x *TariffCalendar) GetDayProfiles() []*DayProfile {
+	if x != nil {
+		return x.DayProfiles
+	}
+	return nil
+}
+
+type Season struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Start         *CalendarDate          `protobuf:"bytes,2,opt,name=start,proto3" json:"start,omitempty"`
+	WeekProfile   string                 `protobuf:"bytes,3,opt,name=weekProfile,proto3" json:"weekProfile,omitempty"` // Name of the week profile in force
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Season) Reset() {
+	*x = Season{}
+	mi := &file_dlmsprocessor_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Season) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Season) ProtoMessage() {}
+
+func (x *Season) ProtoReflect() protoreflect.Message {
+	mi := &file_dlmsprocessor_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Season.ProtoReflect.Descriptor instead.
+func (*Season) Descriptor() ([]byte, []int) {
+	return file_dlmsprocessor_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *Season) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Season) GetStart() *CalendarDate {
+	if x != nil {
+		return x.Start
+	}
+	return nil
+}
+
+func (x *Season) GetWeekProfile() string {
+	if x != nil {
+		return x.WeekProfile
+	}
+	return ""
+}
+
+type WeekProfile struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	DayIds        []uint32               `protobuf:"varint,2,rep,packed,name=dayIds,proto3" json:"dayIds,omitempty"` // Day profile of each day, Monday to Sunday
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WeekProfile) Reset() {
+	*x = WeekProfile{}
+	mi := &file_dlmsprocessor_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WeekProfile) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WeekProfile) ProtoMessage() {}
+
+func (x *WeekProfile) ProtoReflect() protoreflect.Message {
+	mi := &file_dlmsprocessor_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WeekProfile.ProtoReflect.Descriptor instead.
+func (*WeekProfile) Descriptor() ([]byte, []int) {
+	return file_dlmsprocessor_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *WeekProfile) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *WeekProfile) GetDayIds() []uint32 {
+	if x != nil {
+		return x.DayIds
+	}
+	return nil
+}
+
+type DayProfile struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            uint32                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Actions       []*DayAction           `protobuf:"bytes,2,rep,name=actions,proto3" json:"actions,omitempty"` // In order of their start
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DayProfile) Reset() {
+	*x = DayProfile{}
+	mi := &file_dlmsprocessor_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DayProfile) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DayProfile) ProtoMessage() {}
+
+func (x *DayProfile) ProtoReflect() protoreflect.Message {
+	mi := &file_dlmsprocessor_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DayProfile.ProtoReflect.Descriptor instead.
+func (*DayProfile) Descriptor() ([]byte, []int) {
+	return file_dlmsprocessor_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *DayProfile) GetId() uint32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *DayProfile) GetActions() []*DayAction {
+	if x != nil {
+		return x.Actions
+	}
+	return nil
+}
+
+type DayAction struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	StartTime     string                 `protobuf:"bytes,1,opt,name=startTime,proto3" json:"startTime,omitempty"` // HH:MM or HH:MM:SS
+	Script        string                 `protobuf:"bytes,2,opt,name=script,proto3" json:"script,omitempty"`       // OBIS of the script table, 0.0.10.0.100.255 for tariffs
+	Selector      uint32                 `protobuf:"varint,3,opt,name=selector,proto3" json:"selector,omitempty"`  // Script to run, the tariff zone
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DayAction) Reset() {
+	*x = DayAction{}
+	mi := &file_dlmsprocessor_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DayAction) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DayAction) ProtoMessage() {}
+
+func (x *DayAction) ProtoReflect() protoreflect.Message {
+	mi := &file_dlmsprocessor_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DayAction.ProtoReflect.Descriptor instead.
+func (*DayAction) Descriptor() ([]byte, []int) {
+	return file_dlmsprocessor_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *DayAction) GetStartTime() string {
+	if x != nil {
+		return x.StartTime
+	}
+	return ""
+}
+
+func (x *DayAction) GetScript() string {
+	if x != nil {
+		return x.Script
+	}
+	return ""
+}
+
+func (x *DayAction) GetSelector() uint32 {
+	if x != nil {
+		return x.Selector
+	}
+	return 0
+}
+
+// A date whose fields are 0 when not specified, such as a year of 0 for a
+// date repeating every year
+type CalendarDate struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Year          int32                  `protobuf:"varint,1,opt,name=year,proto3" json:"year,omitempty"`
+	Month         int32                  `protobuf:"varint,2,opt,name=month,proto3" json:"month,omitempty"`
+	Day           int32                  `protobuf:"varint,3,opt,name=day,proto3" json:"day,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CalendarDate) Reset() {
+	*x = CalendarDate{}
+	mi := &file_dlmsprocessor_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CalendarDate) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CalendarDate) ProtoMessage() {}
+
+func (x *CalendarDate) ProtoReflect() protoreflect.Message {
+	mi := &file_dlmsprocessor_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CalendarDate.ProtoReflect.Descriptor instead.
+func (*CalendarDate) Descriptor() ([]byte, []int) {
+	return file_dlmsprocessor_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *CalendarDate) GetYear() int32 {
+	if x != nil {
+		return x.Year
+	}
+	return 0
+}
+
+func (x *CalendarDate) GetMonth() int32 {
+	if x != nil {
+		return x.Month
+	}
+	return 0
+}
+
+func (x *CalendarDate) GetDay() int32 {
+	if x != nil {
+		return x.Day
+	}
+	return 0
+}
+
+type SpecialDaysTable struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Entries       []*SpecialDay          `protobuf:"bytes,1,rep,name=entries,proto3" json:"entries,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SpecialDaysTable) Reset() {
+	*x = SpecialDaysTable{}
+	mi := &file_dlmsprocessor_proto_msgTypes[34]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SpecialDaysTable) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SpecialDaysTable) ProtoMessage() {}
+
+func (x *SpecialDaysTable) ProtoReflect() protoreflect.Message {
+	mi := &file_dlmsprocessor_proto_msgTypes[34]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SpecialDaysTable.ProtoReflect.Descriptor instead.
+func (*SpecialDaysTable) Descriptor() ([]byte, []int) {
+	return file_dlmsprocessor_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *SpecialDaysTable) GetEntries() []*SpecialDay {
+	if x != nil {
+		return x.Entries
+	}
+	return nil
+}
+
+type SpecialDay struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Index         uint32                 `protobuf:"varint,1,opt,name=index,proto3" json:"index,omitempty"`
+	Date          *CalendarDate          `protobuf:"bytes,2,opt,name=date,proto3" json:"date,omitempty"`
+	DayId         uint32                 `protobuf:"varint,3,opt,name=dayId,proto3" json:"dayId,omitempty"` // Day profile run on the date
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SpecialDay) Reset() {
+	*x = SpecialDay{}
+	mi := &file_dlmsprocessor_proto_msgTypes[35]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SpecialDay) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SpecialDay) ProtoMessage() {}
+
+func (x *SpecialDay) ProtoReflect() protoreflect.Message {
+	mi := &file_dlmsprocessor_proto_msgTypes[35]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SpecialDay.ProtoReflect.Descriptor instead.
+func (*SpecialDay) Descriptor() ([]byte, []int) {
+	return file_dlmsprocessor_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *SpecialDay) GetIndex() uint32 {
+	if x != nil {
+		return x.Index
+	}
+	return 0
+}
+
+func (x *SpecialDay) GetDate() *CalendarDate {
+	if x != nil {
+		return x.Date
+	}
+	return nil
+}
+
+func (x *SpecialDay) GetDayId() uint32 {
+	if x != nil {
+		return x.DayId
+	}
+	return 0
+}
+
+type ProcessRequest struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	CorrelationId     string                 `protobuf:"bytes,1,opt,name=correlationId,proto3" json:"correlationId,omitempty"` // Chosen by the orchestrator, echoed in the matching ProcessResponse
+	Meter             *Meter                 `protobuf:"bytes,2,opt,name=meter,proto3" json:"meter,omitempty"`
+	ConnectionTimeout int32                  `protobuf:"varint,3,opt,name=connectionTimeout,proto3" json:"connectionTimeout,omitempty"` // Milliseconds, per I/O step with the meter
+	Timeout           int32                  `protobuf:"varint,4,opt,name=timeout,proto3" json:"timeout,omitempty"`                     // Milliseconds for the whole operation, 0 for no limit
+	TraceFrames       FrameTraceMode         `protobuf:"varint,5,opt,name=traceFrames,proto3,enum=dlmsprocessor.FrameTraceMode" json:"traceFrames,omitempty"`
+	// Types that are valid to be assigned to Operation:
+	//
+	//	*ProcessRequest_Read
+	//	*ProcessRequest_Write
+	//	*ProcessRequest_Execute
+	Operation     isProcessRequest_Operation `protobuf_oneof:"operation"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ProcessRequest) Reset() {
+	*x = ProcessRequest{}
+	mi := &file_dlmsprocessor_proto_msgTypes[36]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ProcessRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProcessRequest) ProtoMessage() {}
+
+func (x *ProcessRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_dlmsprocessor_proto_msgTypes[36]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProcessRequest.ProtoReflect.Descriptor instead.
+func (*ProcessRequest) Descriptor() ([]byte, []int) {
+	return file_dlmsprocessor_proto_rawDescGZIP(), []int{36}
+}
+
+func (x *ProcessRequest) GetCorrelationId() string {
+	if x != nil {
+		return x.CorrelationId
+	}
+	return ""
+}
+
+func (x *ProcessRequest) GetMeter() *Meter {
+	if x != nil {
+		return x.Meter
+	}
+	return nil
+}
+
+func (x *ProcessRequest) GetConnectionTimeout() int32 {
+	if x != nil {
+		return x.ConnectionTimeout
+	}
+	return 0
+}
+
+func (x *ProcessRequest) GetTimeout() int32 {
+	if x != nil {
+		return x.Timeout
+	}
+	return 0
+}
+
+func (x *ProcessRequest) GetTraceFrames() FrameTraceMode {
+	if x != nil {
+		return x.TraceFrames
+	}
+	return FrameTraceMode_FRAME_TRACE_MODE_NONE
+}
+
+func (x *ProcessRequest) GetOperation() isProcessRequest_Operation {
+	if x != nil {
+		return x.Operation
+	}
+	return nil
+}
+
+func (x *ProcessRequest) GetRead() *ReadOperation {
+	if x != nil {
+		if x, ok := x.Operation.(*ProcessRequest_Read); ok {
+			return x.Read
+		}
+	}
+	return nil
+}
+
+func (x *ProcessRequest) GetWrite() *WriteOperation {
+	if x != nil {
+		if x, ok := x.Operation.(*ProcessRequest_Write); ok {
+			return x.Write
+		}
+	}
+	return nil
+}
+
+func (x *ProcessRequest) GetExecute() *ExecuteOperation {
+	if x != nil {
+		if x, ok := x.Operation.(*ProcessRequest_Execute); ok {
+			return x.Execute
+		}
+	}
+	return nil
+}
+
+type isProcessRequest_Operation interface {
+	isProcessRequest_Operation()
+}
+
+type ProcessRequest_Read struct {
+	Read *ReadOperation `protobuf:"bytes,10,opt,name=read,proto3,oneof"`
+}
+
+type ProcessRequest_Write struct {
+	Write *WriteOperation `protobuf:"bytes,11,opt,name=write,proto3,oneof"`
+}
+
+type ProcessRequest_Execute struct {
+	Execute *ExecuteOperation `protobuf:"bytes,12,opt,name=execute,proto3,oneof"`
+}
+
+func (*ProcessRequest_Read) isProcessRequest_Operation() {}
+
+func (*ProcessRequest_Write) isProcessRequest_Operation() {}
+
+func (*ProcessRequest_Execute) isProcessRequest_Operation() {}
+
+type ReadOperation struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Target:
+	//
+	//	*ReadOperation_Attribute
+	//	*ReadOperation_Profile
+	//	*ReadOperation_DefinedProfile
+	Target        isReadOperation_Target `protobuf_oneof:"target"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReadOperation) Reset() {
+	*x = ReadOperation{}
+	mi := &file_dlmsprocessor_proto_msgTypes[37]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReadOperation) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReadOperation) ProtoMessage() {}
+
+func (x *ReadOperation) ProtoReflect() protoreflect.Message {
+	mi := &file_dlmsprocessor_proto_msgTypes[37]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReadOperation.ProtoReflect.Descriptor instead.
+func (*ReadOperation) Descriptor() ([]byte, []int) {
+	return file_dlmsprocessor_proto_rawDescGZIP(), []int{37}
+}
+
+func (x *ReadOperation) GetTarget() isReadOperation_Target {
+	if x != nil {
+		return x.Target
+	}
+	return nil
+}
+
+func (x *ReadOperation) GetAttribute() *AttributeReference {
+	if x != nil {
+		if x, ok := x.Target.(*ReadOperation_Attribute); ok {
+			return x.Attribute
+		}
+	}
+	return nil
+}
+
+func (x *ReadOperation) GetProfile() ProfileType {
+	if x != nil {
+		if x, ok := x.Target.(*ReadOperation_Profile); ok {
+			return x.Profile
+		}
+	}
+	return ProfileType_PROFILE_TYPE_UNSPECIFIED
+}
+
+func (x *ReadOperation) GetDefinedProfile() string {
+	if x != nil {
+		if x, ok := x.Target.(*ReadOperation_DefinedProfile); ok {
+			return x.DefinedProfile
+		}
+	}
+	return ""
+}
+
+type isReadOperation_Target interface {
+	isReadOperation_Target()
+}
+
+type ReadOperation_Attribute struct {
+	Attribute *AttributeReference `protobuf:"bytes,1,opt,name=attribute,proto3,oneof"`
+}
+
+type ReadOperation_Profile struct {
+	Profile ProfileType `protobuf:"varint,2,opt,name=profile,proto3,enum=dlmsprocessor.ProfileType,oneof"`
+}
+
+type ReadOperation_DefinedProfile struct {
+	DefinedProfile string `protobuf:"bytes,3,opt,name=definedProfile,proto3,oneof"` // Name of a profile in the processor's profile definitions
+}
+
+func (*ReadOperation_Attribute) isReadOperation_Target() {}
+
+func (*ReadOperation_Profile) isReadOperation_Target() {}
+
+func (*ReadOperation_DefinedProfile) isReadOperation_Target() {}
+
+type AttributeReference struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Obis           string                 `protobuf:"bytes,1,opt,name=obis,proto3" json:"obis,omitempty"`
+	ObjectType     int32                  `protobuf:"varint,2,opt,name=objectType,proto3" json:"objectType,omitempty"` // COSEM interface class, e.g. 1 for Data, 3 for Register
+	AttributeIndex int32                  `protobuf:"varint,3,opt,name=attributeIndex,proto3" json:"attributeIndex,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *AttributeReference) Reset() {
+	*x = AttributeReference{}
+	mi := &file_dlmsprocessor_proto_msgTypes[38]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AttributeReference) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AttributeReference) ProtoMessage() {}
+
+func (x *AttributeReference) ProtoReflect() protoreflect.Message {
+	mi := &file_dlmsprocessor_proto_msgTypes[38]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AttributeReference.ProtoReflect.Descriptor instead.
+func (*AttributeReference) Descriptor() ([]byte, []int) {
+	return file_dlmsprocessor_proto_rawDescGZIP(), []int{38}
+}
+
+func (x *AttributeReference) GetObis() string {
+	if x != nil {
+		return x.Obis
+	}
+	return ""
+}
+
+func (x *AttributeReference) GetObjectType() int32 {
+	if x != nil {
+		return x.ObjectType
+	}
+	return 0
+}
+
+func (x *AttributeReference) GetAttributeIndex() int32 {
+	if x != nil {
+		return x.AttributeIndex
+	}
+	return 0
+}
+
+type WriteOperation struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Attribute *AttributeReference    `protobuf:"bytes,1,opt,name=attribute,proto3" json:"attribute,omitempty"`
+	// Types that are valid to be assigned to Value:
+	//
+	//	*WriteOperation_Int32Value
+	//	*WriteOperation_Uint32Value
+	//	*WriteOperation_Float64Value
+	//	*WriteOperation_BoolValue
+	//	*WriteOperation_StringValue
+	//	*WriteOperation_OctetStringValue
+	//	*WriteOperation_DateTimeValue
+	Value         isWriteOperation_Value `protobuf_oneof:"value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WriteOperation) Reset() {
+	*x = WriteOperation{}
+	mi := &file_dlmsprocessor_proto_msgTypes[39]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WriteOperation) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WriteOperation) ProtoMessage() {}
+
+func (x *WriteOperation) ProtoReflect() protoreflect.Message {
+	mi := &file_dlmsprocessor_proto_msgTypes[39]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WriteOperation.ProtoReflect.Descriptor instead.
+func (*WriteOperation) Descriptor() ([]byte, []int) {
+	return file_dlmsprocessor_proto_rawDescGZIP(), []int{39}
+}
+
+func (x *WriteOperation) GetAttribute() *AttributeReference {
+	if x != nil {
+		return x.Attribute
+	}
+	return nil
+}
+
+func (x *WriteOperation) GetValue() isWriteOperation_Value {
+	if x != nil {
+		return x.Value
+	}
+	return nil
+}
+
+func (x *WriteOperation) GetInt32Value() int32 {
+	if x != nil {
+		if x, ok := x.Value.(*WriteOperation_Int32Value); ok {
+			return x.Int32Value
+		}
+	}
+	return 0
+}
+
+func (x *WriteOperation) GetUint32Value() uint32 {
+	if x != nil {
+		if x, ok := x.Value.(*WriteOperation_Uint32Value); ok {
+			return x.Uint32Value
+		}
+	}
+	return 0
+}
+
+func (x *WriteOperation) GetFloat64Value() float64 {
+	if x != nil {
+		if x, ok := x.Value.(*WriteOperation_Float64Value); ok {
+			return x.Float64Value
+		}
+	}
+	return 0
+}
+
+func (x *WriteOperation) GetBoolValue() bool {
+	if x != nil {
+		if x, ok := x.Value.(*WriteOperation_BoolValue); ok {
+			return x.BoolValue
+		}
+	}
+	return false
+}
+
+func (x *WriteOperation) GetStringValue() string {
+	if x != nil {
+		if x, ok := x.Value.(*WriteOperation_StringValue); ok {
+			return x.StringValue
+		}
+	}
+	return ""
+}
+
+func (x *WriteOperation) GetOctetStringValue() []byte {
+	if x != nil {
+		if x, ok := x.Value.(*WriteOperation_OctetStringValue); ok {
+			return x.OctetStringValue
+		}
+	}
+	return nil
+}
+
+func (x *WriteOperation) GetDateTimeValue() string {
+	if x != nil {
+		if x, ok := x.Value.(*WriteOperation_DateTimeValue); ok {
+			return x.DateTimeValue
+		}
+	}
+	return ""
+}
+
+type isWriteOperation_Value interface {
+	isWriteOperation_Value()
+}
+
+type WriteOperation_Int32Value struct {
+	Int32Value int32 `protobuf:"varint,2,opt,name=int32Value,proto3,oneof"`
+}
+
+type WriteOperation_Uint32Value struct {
+	Uint32Value uint32 `protobuf:"varint,3,opt,name=uint32Value,proto3,oneof"`
+}
+
+type WriteOperation_Float64Value struct {
+	Float64Value float64 `protobuf:"fixed64,4,opt,name=float64Value,proto3,oneof"`
+}
+
+type WriteOperation_BoolValue struct {
+	BoolValue bool `protobuf:"varint,5,opt,name=boolValue,proto3,oneof"`
+}
+
+type WriteOperation_StringValue struct {
+	StringValue string `protobuf:"bytes,6,opt,name=stringValue,proto3,oneof"`
+}
+
+type WriteOperation_OctetStringValue struct {
+	OctetStringValue []byte `protobuf:"bytes,7,opt,name=octetStringValue,proto3,oneof"`
+}
+
+type WriteOperation_DateTimeValue struct {
+	DateTimeValue string `protobuf:"bytes,8,opt,name=dateTimeValue,proto3,oneof"` // RFC 3339
+}
+
+func (*WriteOperation_Int32Value) isWriteOperation_Value() {}
+
+func (*WriteOperation_Uint32Value) isWriteOperation_Value() {}
+
+func (*WriteOperation_Float64Value) isWriteOperation_Value() {}
+
+func (*WriteOperation_BoolValue) isWriteOperation_Value() {}
+
+func (*WriteOperation_StringValue) isWriteOperation_Value() {}
+
+func (*WriteOperation_OctetStringValue) isWriteOperation_Value() {}
+
+func (*WriteOperation_DateTimeValue) isWriteOperation_Value() {}
+
+// ExecuteOperation invokes a method of a COSEM object, such as
+// remote_disconnect (method 1) of the disconnect control 0.0.96.3.10.255.
+// The "fota" function starts a firmware upgrade instead, which needs the
+// fota right rather than execute.
+type ExecuteOperation struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Function      string                 `protobuf:"bytes,1,opt,name=function,proto3" json:"function,omitempty"` // "fota", or empty to invoke method
+	Method        *MethodReference       `protobuf:"bytes,3,opt,name=method,proto3" json:"method,omitempty"`
+	Data          []byte                 `protobuf:"bytes,4,opt,name=data,proto3" json:"data,omitempty"` // The method's parameter, A-XDR encoded with its type tag, e.g. 0f00 for integer 0; empty for none
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExecuteOperation) Reset() {
+	*x = ExecuteOperation{}
+	mi := &file_dlmsprocessor_proto_msgTypes[40]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExecuteOperation) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExecuteOperation) ProtoMessage() {}
+
+func (x *ExecuteOperation) ProtoReflect() protoreflect.Message {
+	mi := &file_dlmsprocessor_proto_msgTypes[40]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExecuteOperation.ProtoReflect.Descriptor instead.
+func (*ExecuteOperation) Descriptor() ([]byte, []int) {
+	return file_dlmsprocessor_proto_rawDescGZIP(), []int{40}
+}
+
+func (x *ExecuteOperation) GetFunction() string {
+	if x != nil {
+		return x.Function
+	}
+	return ""
+}
+
+func (x *ExecuteOperation) GetMethod() *MethodReference {
+	if x != nil {
+		return x.Method
+	}
+	return nil
+}
+
+func (x *ExecuteOperation) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+type MethodReference struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Obis          string                 `protobuf:"bytes,1,opt,name=obis,proto3" json:"obis,omitempty"`
+	ObjectType    int32                  `protobuf:"varint,2,opt,name=objectType,proto3" json:"objectType,omitempty"` // COSEM interface class, e.g. 70 for Disconnect control
+	MethodIndex   int32                  `protobuf:"varint,3,opt,name=methodIndex,proto3" json:"methodIndex,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MethodReference) Reset() {
+	*x = MethodReference{}
+	mi := &file_dlmsprocessor_proto_msgTypes[41]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MethodReference) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MethodReference) ProtoMessage() {}
+
+func (x *MethodReference) ProtoReflect() protoreflect.Message {
+	mi := &file_dlmsprocessor_proto_msgTypes[41]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MethodReference.ProtoReflect.Descriptor instead.
+func (*MethodReference) Descriptor() ([]byte, []int) {
+	return file_dlmsprocessor_proto_rawDescGZIP(), []int{41}
+}
+
+func (x *MethodReference) GetObis() string {
+	if x != nil {
+		return x.Obis
+	}
+	return ""
+}
+
+func (x *MethodReference) GetObjectType() int32 {
+	if x != nil {
+		return x.ObjectType
+	}
+	return 0
+}
+
+func (x *MethodReference) GetMethodIndex() int32 {
+	if x != nil {
+		return x.MethodIndex
+	}
+	return 0
+}
+
+type ProcessResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	CorrelationId string                 `protobuf:"bytes,1,opt,name=correlationId,proto3" json:"correlationId,omitempty"`
+	MeterId       string                 `protobuf:"bytes,2,opt,name=meterId,proto3" json:"meterId,omitempty"`
+	MeterIp       string                 `protobuf:"bytes,3,opt,name=meterIp,proto3" json:"meterIp,omitempty"`
+	SerialNumber  string                 `protobuf:"bytes,4,opt,name=serialNumber,proto3" json:"serialNumber,omitempty"`
+	Credits       int32                  `protobuf:"varint,5,opt,name=credits,proto3" json:"credits,omitempty"` // Credits returned to the orchestrator with this response
+	Frames        *FrameTrace            `protobuf:"bytes,6,opt,name=frames,proto3" json:"frames,omitempty"`    // Set when the request asked for a frame trace, also if it failed
+	// Types that are valid to be assigned to Result:
+	//
+	//	*ProcessResponse_Value
+	//	*ProcessResponse_BlockLoadProfile
+	//	*ProcessResponse_DailyLoadProfile
+	//	*ProcessResponse_BillingDataProfile
+	//	*ProcessResponse_InstantaneousProfile
+	//	*ProcessResponse_Error
+	//	*ProcessResponse_Nameplate
+	//	*ProcessResponse_ProfileData
+	Result        isProcessResponse_Result `protobuf_oneof:"result"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ProcessResponse) Reset() {
+	*x = ProcessResponse{}
+	mi := &file_dlmsprocessor_proto_msgTypes[42]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ProcessResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProcessResponse) ProtoMessage() {}
+
+func (x *ProcessResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_dlmsprocessor_proto_msgTypes[42]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProcessResponse.ProtoReflect.Descriptor instead.
+func (*ProcessResponse) Descriptor() ([]byte, []int) {
+	return file_dlmsprocessor_proto_rawDescGZIP(), []int{42}
+}
+
+func (x *ProcessResponse) GetCorrelationId() string {
+	if x != nil {
+		return x.CorrelationId
+	}
+	return ""
+}
+
+func (x *ProcessResponse) GetMeterId() string {
+	if x != nil {
+		return x.MeterId
+	}
+	return ""
+}
+
+func (x *ProcessResponse) GetMeterIp() string {
+	if x != nil {
+		return x.MeterIp
+	}
+	return ""
+}
+
+func (x *ProcessResponse) GetSerialNumber() string {
+	if x != nil {
+		return x.SerialNumber
+	}
+	return ""
+}
+
+func (x *ProcessResponse) GetCredits() int32 {
+	if x != nil {
+		return x.Credits
+	}
+	return 0
+}
+
+func (x *ProcessResponse) GetFrames() *FrameTrace {
+	if x != nil {
+		return x.Frames
+	}
+	return nil
+}
+
+func (x *ProcessResponse) GetResult() isProcessResponse_Result {
+	if x != nil {
+		return x.Result
+	}
+	return nil
+}
+
+func (x *ProcessResponse) GetValue() string {
+	if x != nil {
+		if x, ok := x.Result.(*ProcessResponse_Value); ok {
+			return x.Value
+		}
+	}
+	return ""
+}
+
+func (x *ProcessResponse) GetBlockLoadProfile() *BlockLoadProfile {
+	if x != nil {
+		if x, ok := x.Result.(*ProcessResponse_BlockLoadProfile); ok {
+			return x.BlockLoadProfile
+		}
+	}
+	return nil
+}
+
+func (x *ProcessResponse) GetDailyLoadProfile() *DailyLoadProfile {
+	if x != nil {
+		if x, ok := x.Result.(*ProcessResponse_DailyLoadProfile); ok {
+			return x.DailyLoadProfile
+		}
+	}
+	return nil
+}
+
+func (x *ProcessResponse) GetBillingDataProfile() *BillingDataProfile {
+	if x != nil {
+		if x, ok := x.Result.(*ProcessResponse_BillingDataProfile); ok {
+			return x.BillingDataProfile
+		}
+	}
+	return nil
+}
+
+func (x *ProcessResponse) GetInstantaneousProfile() *InstantaneousProfile {
+	if x != nil {
+		if x, ok := x.Result.(*ProcessResponse_InstantaneousProfile); ok {
+			return x.InstantaneousProfile
+		}
+	}
+	return nil
+}
+
+func (x *ProcessResponse) GetError() *OperationError {
+	if x != nil {
+		if x, ok := x.Result.(*ProcessResponse_Error); ok {
+			return x.Error
+		}
+	}
+	return nil
+}
+
+func (x *ProcessResponse) GetNameplate() *NameplateProfile {
+	if x != nil {
+		if x, ok := x.Result.(*ProcessResponse_Nameplate); ok {
+			return x.Nameplate
+		}
+	}
+	return nil
+}
+
+func (x *ProcessResponse) GetProfileData() *ProfileData {
+	if x != nil {
+		if x, ok := x.Result.(*ProcessResponse_ProfileData); ok {
+			return x.ProfileData
+		}
+	}
+	return nil
+}
+
+type isProcessResponse_Result interface {
+	isProcessResponse_Result()
+}
+
+type ProcessResponse_Value struct {
+	Value string `protobuf:"bytes,10,opt,name=value,proto3,oneof"` // Attribute read or function result; no result at all means a write succeeded
+}
+
+type ProcessResponse_BlockLoadProfile struct {
+	BlockLoadProfile *BlockLoadProfile `protobuf:"bytes,11,opt,name=blockLoadProfile,proto3,oneof"`
+}
+
+type ProcessResponse_DailyLoadProfile struct {
+	DailyLoadProfile *DailyLoadProfile `protobuf:"bytes,12,opt,name=dailyLoadProfile,proto3,oneof"`
+}
+
+type ProcessResponse_BillingDataProfile struct {
+	BillingDataProfile *BillingDataProfile `protobuf:"bytes,13,opt,name=billingDataProfile,proto3,oneof"`
+}
+
+type ProcessResponse_InstantaneousProfile struct {
+	InstantaneousProfile *InstantaneousProfile `protobuf:"bytes,14,opt,name=instantaneousProfile,proto3,oneof"`
+}
+
+type ProcessResponse_Error struct {
+	Error *OperationError `protobuf:"bytes,15,opt,name=error,proto3,oneof"`
+}
+
+type ProcessResponse_Nameplate struct {
+	Nameplate *NameplateProfile `protobuf:"bytes,16,opt,name=nameplate,proto3,oneof"`
+}
+
+type ProcessResponse_ProfileData struct {
+	ProfileData *ProfileData `protobuf:"bytes,17,opt,name=profileData,proto3,oneof"`
+}
+
+func (*ProcessResponse_Value) isProcessResponse_Result() {}
+
+func (*ProcessResponse_BlockLoadProfile) isProcessResponse_Result() {}
+
+func (*ProcessResponse_DailyLoadProfile) isProcessResponse_Result() {}
+
+func (*ProcessResponse_BillingDataProfile) isProcessResponse_Result() {}
+
+func (*ProcessResponse_InstantaneousProfile) isProcessResponse_Result() {}
+
+func (*ProcessResponse_Error) isProcessResponse_Result() {}
+
+func (*ProcessResponse_Nameplate) isProcessResponse_Result() {}
+
+func (*ProcessResponse_ProfileData) isProcessResponse_Result() {}
+
+// A profile read through its definition in the processor's configuration
+type ProfileData struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Obis          string                 `protobuf:"bytes,2,opt,name=obis,proto3" json:"obis,omitempty"`
+	Columns       []*ProfileColumn       `protobuf:"bytes,3,rep,name=columns,proto3" json:"columns,omitempty"`
+	Rows          []*ProfileRow          `protobuf:"bytes,4,rep,name=rows,proto3" json:"rows,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ProfileData) Reset() {
+	*x = ProfileData{}
+	mi := &file_dlmsprocessor_proto_msgTypes[43]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ProfileData) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProfileData) ProtoMessage() {}
+
+func (x *ProfileData) ProtoReflect() protoreflect.Message {
+	mi := &file_dlmsprocessor_proto_msgTypes[43]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProfileData.ProtoReflect.Descriptor instead.
+func (*ProfileData) Descriptor() ([]byte, []int) {
+	return file_dlmsprocessor_proto_rawDescGZIP(), []int{43}
+}
+
+func (x *ProfileData) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ProfileData) GetObis() string {
+	if x != nil {
+		return x.Obis
+	}
+	return ""
+}
+
+func (x *ProfileData) GetColumns() []*ProfileColumn {
+	if x != nil {
+		return x.Columns
+	}
+	return nil
+}
+
+func (x *ProfileData) GetRows() []*ProfileRow {
+	if x != nil {
+		return x.Rows
+	}
+	return nil
+}
+
+type ProfileColumn struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Obis          string                 `protobuf:"bytes,2,opt,name=obis,proto3" json:"obis,omitempty"`
+	Attribute     int32                  `protobuf:"varint,3,opt,name=attribute,proto3" json:"attribute,omitempty"` // Captured attribute read, 0 if the meter does not capture the column
+	Unit          string                 `protobuf:"bytes,4,opt,name=unit,proto3" json:"unit,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetInstantaneousProfileResponse) Reset() {
-	*x = GetInstantaneousProfileResponse{}
-	mi := &file_dlmsprocessor_proto_msgTypes[13]
+func (x *ProfileColumn) Reset() {
+	*x = ProfileColumn{}
+	mi := &file_dlmsprocessor_proto_msgTypes[44]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetInstantaneousProfileResponse) String() string {
+func (x *ProfileColumn) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetInstantaneousProfileResponse) ProtoMessage() {}
+func (*ProfileColumn) ProtoMessage() {}
 
-func (x *GetInstantaneousProfileResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_dlmsprocessor_proto_msgTypes[13]
+func (x *ProfileColumn) ProtoReflect() protoreflect.Message {
+	mi := &file_dlmsprocessor_proto_msgTypes[44]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1077,55 +4303,62 @@ func (x *GetInstantaneousProfileResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetInstantaneousProfileResponse.ProtoReflect.Descriptor instead.
-func (*GetInstantaneousProfileResponse) Descriptor() ([]byte, []int) {
-	return file_dlmsprocessor_proto_rawDescGZIP(), []int{13}
+// Deprecated: Use ProfileColumn.ProtoReflect.Descriptor instead.
+func (*ProfileColumn) Descriptor() ([]byte, []int) {
+	return file_dlmsprocessor_proto_rawDescGZIP(), []int{44}
 }
 
-func (x *GetInstantaneousProfileResponse) GetProfile() *InstantaneousProfile {
+func (x *ProfileColumn) GetName() string {
 	if x != nil {
-		return x.Profile
+		return x.Name
 	}
-	return nil
+	return ""
 }
 
-func (x *GetInstantaneousProfileResponse) GetMeterIp() string {
+func (x *ProfileColumn) GetObis() string {
 	if x != nil {
-		return x.MeterIp
+		return x.Obis
 	}
 	return ""
 }
 
-type InstantaneousProfile struct {
-	state             protoimpl.MessageState `protogen:"open.v1"`
-	DateTime          string                 `protobuf:"bytes,1,opt,name=dateTime,proto3" json:"dateTime,omitempty"`                     // RTC - Date & Time (OBIS: 0.0.1.0.0.255)
-	Voltage           float64                `protobuf:"fixed64,2,opt,name=voltage,proto3" json:"voltage,omitempty"`                     // Voltage (instantaneous) (OBIS: 1.0.12.7.0.255)
-	PhaseCurrent      float64                `protobuf:"fixed64,3,opt,name=phaseCurrent,proto3" json:"phaseCurrent,omitempty"`           // Phase Current (instantaneous) (OBIS: 1.0.11.7.0.255)
-	NeutralCurrent    float64                `protobuf:"fixed64,4,opt,name=neutralCurrent,proto3" json:"neutralCurrent,omitempty"`       // Neutral Current (instantaneous) (OBIS: 1.0.91.7.0.255)
-	SignedPowerFactor float64                `protobuf:"fixed64,5,opt,name=signedPowerFactor,proto3" json:"signedPowerFactor,omitempty"` // Signed Power Factor (instantaneous) (OBIS: 1.0.13.7.0.255)
-	Frequency         float64                `protobuf:"fixed64,6,opt,name=frequency,proto3" json:"frequency,omitempty"`                 // Frequency (instantaneous) (OBIS: 1.0.14.7.0.255)
-	ApparentPower     float64                `protobuf:"fixed64,7,opt,name=apparentPower,proto3" json:"apparentPower,omitempty"`         // Apparent Power - VA (instantaneous) (OBIS: 1.0.9.7.0.255)
-	ActivePower       float64                `protobuf:"fixed64,8,opt,name=activePower,proto3" json:"activePower,omitempty"`             // Active Power - W (instantaneous) (OBIS: 1.0.1.7.0.255)
-	CumEnergyWh       float64                `protobuf:"fixed64,9,opt,name=cumEnergyWh,proto3" json:"cumEnergyWh,omitempty"`             // Cumulative Energy - Wh (OBIS: 1.0.1.8.0.255)
-	unknownFields     protoimpl.UnknownFields
-	sizeCache         protoimpl.SizeCache
+func (x *ProfileColumn) GetAttribute() int32 {
+	if x != nil {
+		return x.Attribute
+	}
+	return 0
 }
 
-func (x *InstantaneousProfile) Reset() {
-	*x = InstantaneousProfile{}
-	mi := &file_dlmsprocessor_proto_msgTypes[14]
+func (x *ProfileColumn) GetUnit() string {
+	if x != nil {
+		return x.Unit
+	}
+	return ""
+}
+
+// One profile entry, with a value per column
+type ProfileRow struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Values        []*ProfileValue        `protobuf:"bytes,1,rep,name=values,proto3" json:"values,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ProfileRow) Reset() {
+	*x = ProfileRow{}
+	mi := &file_dlmsprocessor_proto_msgTypes[45]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *InstantaneousProfile) String() string {
+func (x *ProfileRow) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*InstantaneousProfile) ProtoMessage() {}
+func (*ProfileRow) ProtoMessage() {}
 
-func (x *InstantaneousProfile) ProtoReflect() protoreflect.Message {
-	mi := &file_dlmsprocessor_proto_msgTypes[14]
+func (x *ProfileRow) ProtoReflect() protoreflect.Message {
+	mi := &file_dlmsprocessor_proto_msgTypes[45]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1136,79 +4369,297 @@ func (x *InstantaneousProfile) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use InstantaneousProfile.ProtoReflect.Descriptor instead.
-func (*InstantaneousProfile) Descriptor() ([]byte, []int) {
-	return file_dlmsprocessor_proto_rawDescGZIP(), []int{14}
+// Deprecated: Use ProfileRow.ProtoReflect.Descriptor instead.
+func (*ProfileRow) Descriptor() ([]byte, []int) {
+	return file_dlmsprocessor_proto_rawDescGZIP(), []int{45}
 }
 
-func (x *InstantaneousProfile) GetDateTime() string {
+func (x *ProfileRow) GetValues() []*ProfileValue {
 	if x != nil {
-		return x.DateTime
+		return x.Values
+	}
+	return nil
+}
+
+// A column's value, unset where the meter does not capture the column
+type ProfileValue struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Value:
+	//
+	//	*ProfileValue_StringValue
+	//	*ProfileValue_NumberValue
+	Value         isProfileValue_Value `protobuf_oneof:"value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ProfileValue) Reset() {
+	*x = ProfileValue{}
+	mi := &file_dlmsprocessor_proto_msgTypes[46]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ProfileValue) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProfileValue) ProtoMessage() {}
+
+func (x *ProfileValue) ProtoReflect() protoreflect.Message {
+	mi := &file_dlmsprocessor_proto_msgTypes[46]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProfileValue.ProtoReflect.Descriptor instead.
+func (*ProfileValue) Descriptor() ([]byte, []int) {
+	return file_dlmsprocessor_proto_rawDescGZIP(), []int{46}
+}
+
+func (x *ProfileValue) GetValue() isProfileValue_Value {
+	if x != nil {
+		return x.Value
+	}
+	return nil
+}
+
+func (x *ProfileValue) GetStringValue() string {
+	if x != nil {
+		if x, ok := x.Value.(*ProfileValue_StringValue); ok {
+			return x.StringValue
+		}
 	}
 	return ""
 }
 
-func (x *InstantaneousProfile) GetVoltage() float64 {
+func (x *ProfileValue) GetNumberValue() float64 {
 	if x != nil {
-		return x.Voltage
+		if x, ok := x.Value.(*ProfileValue_NumberValue); ok {
+			return x.NumberValue
+		}
 	}
 	return 0
 }
 
-func (x *InstantaneousProfile) GetPhaseCurrent() float64 {
+type isProfileValue_Value interface {
+	isProfileValue_Value()
+}
+
+type ProfileValue_StringValue struct {
+	StringValue string `protobuf:"bytes,1,opt,name=stringValue,proto3,oneof"`
+}
+
+type ProfileValue_NumberValue struct {
+	NumberValue float64 `protobuf:"fixed64,2,opt,name=numberValue,proto3,oneof"`
+}
+
+func (*ProfileValue_StringValue) isProfileValue_Value() {}
+
+func (*ProfileValue_NumberValue) isProfileValue_Value() {}
+
+type OperationError struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Code    int32                  `protobuf:"varint,1,opt,name=code,proto3" json:"code,omitempty"` // gRPC status code
+	Message string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	// Why the meter operation failed, e.g. TIMEOUT, CONNECTION_REFUSED or
+	// AUTHENTICATION_FAILED; empty if it was not a meter failure. Streaming
+	// reads report the same reason in a google.rpc.ErrorInfo status detail.
+	Reason        string `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OperationError) Reset() {
+	*x = OperationError{}
+	mi := &file_dlmsprocessor_proto_msgTypes[47]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OperationError) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OperationError) ProtoMessage() {}
+
+func (x *OperationError) ProtoReflect() protoreflect.Message {
+	mi := &file_dlmsprocessor_proto_msgTypes[47]
 	if x != nil {
-		return x.PhaseCurrent
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return 0
+	return mi.MessageOf(x)
 }
 
-func (x *InstantaneousProfile) GetNeutralCurrent() float64 {
+// Deprecated: Use OperationError.ProtoReflect.Descriptor instead.
+func (*OperationError) Descriptor() ([]byte, []int) {
+	return file_dlmsprocessor_proto_rawDescGZIP(), []int{47}
+}
+
+func (x *OperationError) GetCode() int32 {
 	if x != nil {
-		return x.NeutralCurrent
+		return x.Code
 	}
 	return 0
 }
 
-func (x *InstantaneousProfile) GetSignedPowerFactor() float64 {
+func (x *OperationError) GetMessage() string {
 	if x != nil {
-		return x.SignedPowerFactor
+		return x.Message
 	}
-	return 0
+	return ""
 }
 
-func (x *InstantaneousProfile) GetFrequency() float64 {
+func (x *OperationError) GetReason() string {
 	if x != nil {
-		return x.Frequency
+		return x.Reason
 	}
-	return 0
+	return ""
 }
 
-func (x *InstantaneousProfile) GetApparentPower() float64 {
+type FrameTrace struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Frames        []*Frame               `protobuf:"bytes,1,rep,name=frames,proto3" json:"frames,omitempty"`        // Empty when written to a file
+	File          string                 `protobuf:"bytes,2,opt,name=file,proto3" json:"file,omitempty"`            // Name of the pcap file in frame_trace_dir, empty if it could not be written
+	Truncated     bool                   `protobuf:"varint,3,opt,name=truncated,proto3" json:"truncated,omitempty"` // Frames beyond the trace size limit were dropped
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FrameTrace) Reset() {
+	*x = FrameTrace{}
+	mi := &file_dlmsprocessor_proto_msgTypes[48]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FrameTrace) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FrameTrace) ProtoMessage() {}
+
+func (x *FrameTrace) ProtoReflect() protoreflect.Message {
+	mi := &file_dlmsprocessor_proto_msgTypes[48]
 	if x != nil {
-		return x.ApparentPower
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return 0
+	return mi.MessageOf(x)
 }
 
-func (x *InstantaneousProfile) GetActivePower() float64 {
+// Deprecated: Use FrameTrace.ProtoReflect.Descriptor instead.
+func (*FrameTrace) Descriptor() ([]byte, []int) {
+	return file_dlmsprocessor_proto_rawDescGZIP(), []int{48}
+}
+
+func (x *FrameTrace) GetFrames() []*Frame {
 	if x != nil {
-		return x.ActivePower
+		return x.Frames
 	}
-	return 0
+	return nil
 }
 
-func (x *InstantaneousProfile) GetCumEnergyWh() float64 {
+func (x *FrameTrace) GetFile() string {
 	if x != nil {
-		return x.CumEnergyWh
+		return x.File
+	}
+	return ""
+}
+
+func (x *FrameTrace) GetTruncated() bool {
+	if x != nil {
+		return x.Truncated
+	}
+	return false
+}
+
+type Frame struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TimestampUs   int64                  `protobuf:"varint,1,opt,name=timestampUs,proto3" json:"timestampUs,omitempty"` // Unix microseconds
+	Direction     FrameDirection         `protobuf:"varint,2,opt,name=direction,proto3,enum=dlmsprocessor.FrameDirection" json:"direction,omitempty"`
+	Data          []byte                 `protobuf:"bytes,3,opt,name=data,proto3" json:"data,omitempty"` // Wrapper frame as sent or received
+	Apdu          []byte                 `protobuf:"bytes,4,opt,name=apdu,proto3" json:"apdu,omitempty"` // Plaintext of a ciphered APDU, empty if not ciphered, the keys did not open it or the caller may not write
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Frame) Reset() {
+	*x = Frame{}
+	mi := &file_dlmsprocessor_proto_msgTypes[49]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Frame) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Frame) ProtoMessage() {}
+
+func (x *Frame) ProtoReflect() protoreflect.Message {
+	mi := &file_dlmsprocessor_proto_msgTypes[49]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Frame.ProtoReflect.Descriptor instead.
+func (*Frame) Descriptor() ([]byte, []int) {
+	return file_dlmsprocessor_proto_rawDescGZIP(), []int{49}
+}
+
+func (x *Frame) GetTimestampUs() int64 {
+	if x != nil {
+		return x.TimestampUs
 	}
 	return 0
 }
 
+func (x *Frame) GetDirection() FrameDirection {
+	if x != nil {
+		return x.Direction
+	}
+	return FrameDirection_FRAME_DIRECTION_UNSPECIFIED
+}
+
+func (x *Frame) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+func (x *Frame) GetApdu() []byte {
+	if x != nil {
+		return x.Apdu
+	}
+	return nil
+}
+
 var File_dlmsprocessor_proto protoreflect.FileDescriptor
 
 const file_dlmsprocessor_proto_rawDesc = "" +
 	"\n" +
-	"\x13dlmsprocessor.proto\x12\rdlmsprocessor\"\xb8\x01\n" +
+	"\x13dlmsprocessor.proto\x12\rdlmsprocessor\x1a\x1cgoogle/api/annotations.proto\"\xd2\x01\n" +
 	"\x0eGetOBISRequest\x12*\n" +
 	"\x05meter\x18\x01 \x03(\v2\x14.dlmsprocessor.MeterR\x05meter\x12\x12\n" +
 	"\x04obis\x18\x02 \x01(\tR\x04obis\x12\x18\n" +
@@ -1216,7 +4667,8 @@ const file_dlmsprocessor_proto_rawDesc = "" +
 	"\n" +
 	"retryDelay\x18\x05 \x01(\x05R\n" +
 	"retryDelay\x12,\n" +
-	"\x11connectionTimeout\x18\x06 \x01(\x05R\x11connectionTimeout\"\x93\x02\n" +
+	"\x11connectionTimeout\x18\x06 \x01(\x05R\x11connectionTimeout\x12\x18\n" +
+	"\atimeout\x18\a \x01(\x05R\atimeout\"\xc4\x03\n" +
 	"\x05Meter\x12\x0e\n" +
 	"\x02ip\x18\x01 \x01(\tR\x02ip\x12\x12\n" +
 	"\x04port\x18\x02 \x01(\x05R\x04port\x12\x12\n" +
@@ -1226,19 +4678,34 @@ const file_dlmsprocessor_proto_rawDesc = "" +
 	"\aauthKey\x18\x06 \x01(\tR\aauthKey\x12&\n" +
 	"\x0eblockCipherKey\x18\a \x01(\tR\x0eblockCipherKey\x12$\n" +
 	"\rclientAddress\x18\b \x01(\tR\rclientAddress\x12$\n" +
-	"\rserverAddress\x18\t \x01(\tR\rserverAddress\"'\n" +
+	"\rserverAddress\x18\t \x01(\tR\rserverAddress\x12\x18\n" +
+	"\ameterId\x18\n" +
+	" \x01(\tR\ameterId\x12\"\n" +
+	"\fserialNumber\x18\v \x01(\tR\fserialNumber\x12,\n" +
+	"\x11logicalDeviceName\x18\f \x01(\tR\x11logicalDeviceName\x12-\n" +
+	"\x06keyRef\x18\r \x01(\v2\x15.dlmsprocessor.KeyRefR\x06keyRef\x12\x14\n" +
+	"\x05model\x18\x0e \x01(\tR\x05model\"<\n" +
+	"\x06KeyRef\x12\x18\n" +
+	"\ameterId\x18\x01 \x01(\tR\ameterId\x12\x18\n" +
+	"\aversion\x18\x02 \x01(\rR\aversion\"\x7f\n" +
 	"\x0fGetOBISResponse\x12\x14\n" +
-	"\x05value\x18\x01 \x01(\tR\x05value\"\xb0\x01\n" +
+	"\x05value\x18\x01 \x01(\tR\x05value\x12\x18\n" +
+	"\ameterId\x18\x02 \x01(\tR\ameterId\x12\x18\n" +
+	"\ameterIp\x18\x03 \x01(\tR\ameterIp\x12\"\n" +
+	"\fserialNumber\x18\x04 \x01(\tR\fserialNumber\"\xca\x01\n" +
 	"\x1aGetBlockLoadProfileRequest\x12*\n" +
 	"\x05meter\x18\x01 \x03(\v2\x14.dlmsprocessor.MeterR\x05meter\x12\x18\n" +
 	"\aretries\x18\x02 \x01(\x05R\aretries\x12\x1e\n" +
 	"\n" +
 	"retryDelay\x18\x03 \x01(\x05R\n" +
 	"retryDelay\x12,\n" +
-	"\x11connectionTimeout\x18\x04 \x01(\x05R\x11connectionTimeout\"r\n" +
+	"\x11connectionTimeout\x18\x04 \x01(\x05R\x11connectionTimeout\x12\x18\n" +
+	"\atimeout\x18\x05 \x01(\x05R\atimeout\"\xb0\x01\n" +
 	"\x1bGetBlockLoadProfileResponse\x129\n" +
 	"\aprofile\x18\x01 \x01(\v2\x1f.dlmsprocessor.BlockLoadProfileR\aprofile\x12\x18\n" +
-	"\ameterIp\x18\x02 \x01(\tR\ameterIp\"\xfe\x02\n" +
+	"\ameterIp\x18\x02 \x01(\tR\ameterIp\x12\x18\n" +
+	"\ameterId\x18\x03 \x01(\tR\ameterId\x12\"\n" +
+	"\fserialNumber\x18\x04 \x01(\tR\fserialNumber\"\xc9\x03\n" +
 	"\x10BlockLoadProfile\x12\x1a\n" +
 	"\bdateTime\x18\x01 \x01(\tR\bdateTime\x12&\n" +
 	"\x0eaverageVoltage\x18\x02 \x01(\x01R\x0eaverageVoltage\x120\n" +
@@ -1247,64 +4714,101 @@ const file_dlmsprocessor_proto_rawDesc = "" +
 	"\x13blockEnergyWhExport\x18\x05 \x01(\x01R\x13blockEnergyWhExport\x122\n" +
 	"\x14blockEnergyVahExport\x18\x06 \x01(\x01R\x14blockEnergyVahExport\x12&\n" +
 	"\x0eaverageCurrent\x18\a \x01(\x01R\x0eaverageCurrent\x122\n" +
-	"\x14meterHealthIndicator\x18\b \x01(\rR\x14meterHealthIndicator\"\xb0\x01\n" +
+	"\x14meterHealthIndicator\x18\b \x01(\rR\x14meterHealthIndicator\x12I\n" +
+	"\n" +
+	"threePhase\x18\t \x01(\v2).dlmsprocessor.ThreePhaseBlockLoadProfileR\n" +
+	"threePhase\"\x8e\x04\n" +
+	"\x1aThreePhaseBlockLoadProfile\x12\x1a\n" +
+	"\bdateTime\x18\x01 \x01(\tR\bdateTime\x12\x1a\n" +
+	"\bcurrentR\x18\x02 \x01(\x01R\bcurrentR\x12\x1a\n" +
+	"\bcurrentY\x18\x03 \x01(\x01R\bcurrentY\x12\x1a\n" +
+	"\bcurrentB\x18\x04 \x01(\x01R\bcurrentB\x12\x1a\n" +
+	"\bvoltageR\x18\x05 \x01(\x01R\bvoltageR\x12\x1a\n" +
+	"\bvoltageY\x18\x06 \x01(\x01R\bvoltageY\x12\x1a\n" +
+	"\bvoltageB\x18\a \x01(\x01R\bvoltageB\x120\n" +
+	"\x13blockEnergyWhImport\x18\b \x01(\x01R\x13blockEnergyWhImport\x12.\n" +
+	"\x12blockEnergyVarhLag\x18\t \x01(\x01R\x12blockEnergyVarhLag\x120\n" +
+	"\x13blockEnergyVarhLead\x18\n" +
+	" \x01(\x01R\x13blockEnergyVarhLead\x122\n" +
+	"\x14blockEnergyVahImport\x18\v \x01(\x01R\x14blockEnergyVahImport\x120\n" +
+	"\x13blockEnergyWhExport\x18\f \x01(\x01R\x13blockEnergyWhExport\x122\n" +
+	"\x14blockEnergyVahExport\x18\r \x01(\x01R\x14blockEnergyVahExport\"\xca\x01\n" +
 	"\x1aGetDailyLoadProfileRequest\x12*\n" +
 	"\x05meter\x18\x01 \x03(\v2\x14.dlmsprocessor.MeterR\x05meter\x12\x18\n" +
 	"\aretries\x18\x02 \x01(\x05R\aretries\x12\x1e\n" +
 	"\n" +
 	"retryDelay\x18\x03 \x01(\x05R\n" +
 	"retryDelay\x12,\n" +
-	"\x11connectionTimeout\x18\x04 \x01(\x05R\x11connectionTimeout\"r\n" +
+	"\x11connectionTimeout\x18\x04 \x01(\x05R\x11connectionTimeout\x12\x18\n" +
+	"\atimeout\x18\x05 \x01(\x05R\atimeout\"\xb0\x01\n" +
 	"\x1bGetDailyLoadProfileResponse\x129\n" +
 	"\aprofile\x18\x01 \x01(\v2\x1f.dlmsprocessor.DailyLoadProfileR\aprofile\x12\x18\n" +
-	"\ameterIp\x18\x02 \x01(\tR\ameterIp\"\xa2\x02\n" +
+	"\ameterIp\x18\x02 \x01(\tR\ameterIp\x12\x18\n" +
+	"\ameterId\x18\x03 \x01(\tR\ameterId\x12\"\n" +
+	"\fserialNumber\x18\x04 \x01(\tR\fserialNumber\"\xa2\x02\n" +
 	"\x10DailyLoadProfile\x12\x1a\n" +
 	"\bdateTime\x18\x01 \x01(\tR\bdateTime\x12:\n" +
 	"\x18cumulativeEnergyWhExport\x18\x02 \x01(\x01R\x18cumulativeEnergyWhExport\x12<\n" +
 	"\x19cumulativeEnergyVahExport\x18\x03 \x01(\x01R\x19cumulativeEnergyVahExport\x12:\n" +
 	"\x18cumulativeEnergyWhImport\x18\x04 \x01(\x01R\x18cumulativeEnergyWhImport\x12<\n" +
-	"\x19cumulativeEnergyVahImport\x18\x05 \x01(\x01R\x19cumulativeEnergyVahImport\"\xb2\x01\n" +
+	"\x19cumulativeEnergyVahImport\x18\x05 \x01(\x01R\x19cumulativeEnergyVahImport\"\xcc\x01\n" +
 	"\x1cGetBillingDataProfileRequest\x12*\n" +
 	"\x05meter\x18\x01 \x03(\v2\x14.dlmsprocessor.MeterR\x05meter\x12\x18\n" +
 	"\aretries\x18\x02 \x01(\x05R\aretries\x12\x1e\n" +
 	"\n" +
 	"retryDelay\x18\x03 \x01(\x05R\n" +
 	"retryDelay\x12,\n" +
-	"\x11connectionTimeout\x18\x04 \x01(\x05R\x11connectionTimeout\"v\n" +
+	"\x11connectionTimeout\x18\x04 \x01(\x05R\x11connectionTimeout\x12\x18\n" +
+	"\atimeout\x18\x05 \x01(\x05R\atimeout\"\xb4\x01\n" +
 	"\x1dGetBillingDataProfileResponse\x12;\n" +
 	"\aprofile\x18\x01 \x01(\v2!.dlmsprocessor.BillingDataProfileR\aprofile\x12\x18\n" +
-	"\ameterIp\x18\x02 \x01(\tR\ameterIp\"\x84\x06\n" +
+	"\ameterIp\x18\x02 \x01(\tR\ameterIp\x12\x18\n" +
+	"\ameterId\x18\x03 \x01(\tR\ameterId\x12\"\n" +
+	"\fserialNumber\x18\x04 \x01(\tR\fserialNumber\"\xe1\x06\n" +
 	"\x12BillingDataProfile\x12 \n" +
 	"\vbillingDate\x18\x01 \x01(\tR\vbillingDate\x12<\n" +
 	"\x19averagePfForBillingPeriod\x18\x02 \x01(\x01R\x19averagePfForBillingPeriod\x12,\n" +
-	"\x11cumEnergyWhImport\x18\x03 \x01(\x01R\x11cumEnergyWhImport\x12&\n" +
-	"\x0ecumEnergyWhTz1\x18\x04 \x01(\x01R\x0ecumEnergyWhTz1\x12&\n" +
-	"\x0ecumEnergyWhTz2\x18\x05 \x01(\x01R\x0ecumEnergyWhTz2\x12&\n" +
-	"\x0ecumEnergyWhTz3\x18\x06 \x01(\x01R\x0ecumEnergyWhTz3\x12&\n" +
-	"\x0ecumEnergyWhTz4\x18\a \x01(\x01R\x0ecumEnergyWhTz4\x12.\n" +
-	"\x12cumEnergyVahImport\x18\b \x01(\x01R\x12cumEnergyVahImport\x12(\n" +
-	"\x0fcumEnergyVahTz1\x18\t \x01(\x01R\x0fcumEnergyVahTz1\x12(\n" +
+	"\x11cumEnergyWhImport\x18\x03 \x01(\x01R\x11cumEnergyWhImport\x12*\n" +
+	"\x0ecumEnergyWhTz1\x18\x04 \x01(\x01B\x02\x18\x01R\x0ecumEnergyWhTz1\x12*\n" +
+	"\x0ecumEnergyWhTz2\x18\x05 \x01(\x01B\x02\x18\x01R\x0ecumEnergyWhTz2\x12*\n" +
+	"\x0ecumEnergyWhTz3\x18\x06 \x01(\x01B\x02\x18\x01R\x0ecumEnergyWhTz3\x12*\n" +
+	"\x0ecumEnergyWhTz4\x18\a \x01(\x01B\x02\x18\x01R\x0ecumEnergyWhTz4\x12.\n" +
+	"\x12cumEnergyVahImport\x18\b \x01(\x01R\x12cumEnergyVahImport\x12,\n" +
+	"\x0fcumEnergyVahTz1\x18\t \x01(\x01B\x02\x18\x01R\x0fcumEnergyVahTz1\x12,\n" +
 	"\x0fcumEnergyVahTz2\x18\n" +
-	" \x01(\x01R\x0fcumEnergyVahTz2\x12(\n" +
-	"\x0fcumEnergyVahTz3\x18\v \x01(\x01R\x0fcumEnergyVahTz3\x12(\n" +
-	"\x0fcumEnergyVahTz4\x18\f \x01(\x01R\x0fcumEnergyVahTz4\x12\x10\n" +
+	" \x01(\x01B\x02\x18\x01R\x0fcumEnergyVahTz2\x12,\n" +
+	"\x0fcumEnergyVahTz3\x18\v \x01(\x01B\x02\x18\x01R\x0fcumEnergyVahTz3\x12,\n" +
+	"\x0fcumEnergyVahTz4\x18\f \x01(\x01B\x02\x18\x01R\x0fcumEnergyVahTz4\x12\x10\n" +
 	"\x03mdw\x18\r \x01(\x01R\x03mdw\x12 \n" +
 	"\vmdwDateTime\x18\x0e \x01(\tR\vmdwDateTime\x12\x12\n" +
 	"\x04mdva\x18\x0f \x01(\x01R\x04mdva\x12\"\n" +
 	"\fmdvaDateTime\x18\x10 \x01(\tR\fmdvaDateTime\x126\n" +
 	"\x16billingPowerOnDuration\x18\x11 \x01(\x01R\x16billingPowerOnDuration\x12 \n" +
 	"\vcumEnergyWh\x18\x12 \x01(\x01R\vcumEnergyWh\x12\"\n" +
-	"\fcumEnergyVah\x18\x13 \x01(\x01R\fcumEnergyVah\"\xb4\x01\n" +
+	"\fcumEnergyVah\x18\x13 \x01(\x01R\fcumEnergyVah\x12;\n" +
+	"\vtariffZones\x18\x14 \x03(\v2\x19.dlmsprocessor.TariffZoneR\vtariffZones\"\xd2\x01\n" +
+	"\n" +
+	"TariffZone\x12\x12\n" +
+	"\x04zone\x18\x01 \x01(\x05R\x04zone\x12 \n" +
+	"\vcumEnergyWh\x18\x02 \x01(\x01R\vcumEnergyWh\x12\"\n" +
+	"\fcumEnergyVah\x18\x03 \x01(\x01R\fcumEnergyVah\x12\x10\n" +
+	"\x03mdw\x18\x04 \x01(\x01R\x03mdw\x12 \n" +
+	"\vmdwDateTime\x18\x05 \x01(\tR\vmdwDateTime\x12\x12\n" +
+	"\x04mdva\x18\x06 \x01(\x01R\x04mdva\x12\"\n" +
+	"\fmdvaDateTime\x18\a \x01(\tR\fmdvaDateTime\"\xce\x01\n" +
 	"\x1eGetInstantaneousProfileRequest\x12*\n" +
 	"\x05meter\x18\x01 \x03(\v2\x14.dlmsprocessor.MeterR\x05meter\x12\x18\n" +
 	"\aretries\x18\x02 \x01(\x05R\aretries\x12\x1e\n" +
 	"\n" +
 	"retryDelay\x18\x03 \x01(\x05R\n" +
 	"retryDelay\x12,\n" +
-	"\x11connectionTimeout\x18\x04 \x01(\x05R\x11connectionTimeout\"z\n" +
+	"\x11connectionTimeout\x18\x04 \x01(\x05R\x11connectionTimeout\x12\x18\n" +
+	"\atimeout\x18\x05 \x01(\x05R\atimeout\"\xb8\x01\n" +
 	"\x1fGetInstantaneousProfileResponse\x12=\n" +
 	"\aprofile\x18\x01 \x01(\v2#.dlmsprocessor.InstantaneousProfileR\aprofile\x12\x18\n" +
-	"\ameterIp\x18\x02 \x01(\tR\ameterIp\"\xce\x02\n" +
+	"\ameterIp\x18\x02 \x01(\tR\ameterIp\x12\x18\n" +
+	"\ameterId\x18\x03 \x01(\tR\ameterId\x12\"\n" +
+	"\fserialNumber\x18\x04 \x01(\tR\fserialNumber\"\x9d\x03\n" +
 	"\x14InstantaneousProfile\x12\x1a\n" +
 	"\bdateTime\x18\x01 \x01(\tR\bdateTime\x12\x18\n" +
 	"\avoltage\x18\x02 \x01(\x01R\avoltage\x12\"\n" +
@@ -1314,13 +4818,261 @@ const file_dlmsprocessor_proto_rawDesc = "" +
 	"\tfrequency\x18\x06 \x01(\x01R\tfrequency\x12$\n" +
 	"\rapparentPower\x18\a \x01(\x01R\rapparentPower\x12 \n" +
 	"\vactivePower\x18\b \x01(\x01R\vactivePower\x12 \n" +
-	"\vcumEnergyWh\x18\t \x01(\x01R\vcumEnergyWh2\xad\x04\n" +
-	"\rDLMSProcessor\x12J\n" +
-	"\aGetOBIS\x12\x1d.dlmsprocessor.GetOBISRequest\x1a\x1e.dlmsprocessor.GetOBISResponse0\x01\x12n\n" +
-	"\x13GetBlockLoadProfile\x12).dlmsprocessor.GetBlockLoadProfileRequest\x1a*.dlmsprocessor.GetBlockLoadProfileResponse0\x01\x12n\n" +
-	"\x13GetDailyLoadProfile\x12).dlmsprocessor.GetDailyLoadProfileRequest\x1a*.dlmsprocessor.GetDailyLoadProfileResponse0\x01\x12t\n" +
-	"\x15GetBillingDataProfile\x12+.dlmsprocessor.GetBillingDataProfileRequest\x1a,.dlmsprocessor.GetBillingDataProfileResponse0\x01\x12z\n" +
-	"\x17GetInstantaneousProfile\x12-.dlmsprocessor.GetInstantaneousProfileRequest\x1a..dlmsprocessor.GetInstantaneousProfileResponse0\x01B\x15Z\x13dlmsprocessor/protob\x06proto3"
+	"\vcumEnergyWh\x18\t \x01(\x01R\vcumEnergyWh\x12M\n" +
+	"\n" +
+	"threePhase\x18\n" +
+	" \x01(\v2-.dlmsprocessor.ThreePhaseInstantaneousProfileR\n" +
+	"threePhase\"\xf0\b\n" +
+	"\x1eThreePhaseInstantaneousProfile\x12\x1a\n" +
+	"\bdateTime\x18\x01 \x01(\tR\bdateTime\x12\x1a\n" +
+	"\bcurrentR\x18\x02 \x01(\x01R\bcurrentR\x12\x1a\n" +
+	"\bcurrentY\x18\x03 \x01(\x01R\bcurrentY\x12\x1a\n" +
+	"\bcurrentB\x18\x04 \x01(\x01R\bcurrentB\x12\x1a\n" +
+	"\bvoltageR\x18\x05 \x01(\x01R\bvoltageR\x12\x1a\n" +
+	"\bvoltageY\x18\x06 \x01(\x01R\bvoltageY\x12\x1a\n" +
+	"\bvoltageB\x18\a \x01(\x01R\bvoltageB\x12\"\n" +
+	"\fpowerFactorR\x18\b \x01(\x01R\fpowerFactorR\x12\"\n" +
+	"\fpowerFactorY\x18\t \x01(\x01R\fpowerFactorY\x12\"\n" +
+	"\fpowerFactorB\x18\n" +
+	" \x01(\x01R\fpowerFactorB\x12 \n" +
+	"\vpowerFactor\x18\v \x01(\x01R\vpowerFactor\x12\x1c\n" +
+	"\tfrequency\x18\f \x01(\x01R\tfrequency\x12\"\n" +
+	"\factivePowerR\x18\r \x01(\x01R\factivePowerR\x12\"\n" +
+	"\factivePowerY\x18\x0e \x01(\x01R\factivePowerY\x12\"\n" +
+	"\factivePowerB\x18\x0f \x01(\x01R\factivePowerB\x12&\n" +
+	"\x0ereactivePowerR\x18\x10 \x01(\x01R\x0ereactivePowerR\x12&\n" +
+	"\x0ereactivePowerY\x18\x11 \x01(\x01R\x0ereactivePowerY\x12&\n" +
+	"\x0ereactivePowerB\x18\x12 \x01(\x01R\x0ereactivePowerB\x12&\n" +
+	"\x0eapparentPowerR\x18\x13 \x01(\x01R\x0eapparentPowerR\x12&\n" +
+	"\x0eapparentPowerY\x18\x14 \x01(\x01R\x0eapparentPowerY\x12&\n" +
+	"\x0eapparentPowerB\x18\x15 \x01(\x01R\x0eapparentPowerB\x12 \n" +
+	"\vactivePower\x18\x16 \x01(\x01R\vactivePower\x12$\n" +
+	"\rreactivePower\x18\x17 \x01(\x01R\rreactivePower\x12$\n" +
+	"\rapparentPower\x18\x18 \x01(\x01R\rapparentPower\x12,\n" +
+	"\x11cumEnergyWhImport\x18\x19 \x01(\x01R\x11cumEnergyWhImport\x12,\n" +
+	"\x11cumEnergyWhExport\x18\x1a \x01(\x01R\x11cumEnergyWhExport\x12*\n" +
+	"\x10cumEnergyVarhLag\x18\x1b \x01(\x01R\x10cumEnergyVarhLag\x12,\n" +
+	"\x11cumEnergyVarhLead\x18\x1c \x01(\x01R\x11cumEnergyVarhLead\x12.\n" +
+	"\x12cumEnergyVahImport\x18\x1d \x01(\x01R\x12cumEnergyVahImport\x12.\n" +
+	"\x12cumEnergyVahExport\x18\x1e \x01(\x01R\x12cumEnergyVahExport\"\xc3\x01\n" +
+	"\fProbeRequest\x12*\n" +
+	"\x05meter\x18\x01 \x03(\v2\x14.dlmsprocessor.MeterR\x05meter\x12,\n" +
+	"\x11connectionTimeout\x18\x02 \x01(\x05R\x11connectionTimeout\x12?\n" +
+	"\vtraceFrames\x18\x03 \x01(\x0e2\x1d.dlmsprocessor.FrameTraceModeR\vtraceFrames\x12\x18\n" +
+	"\atimeout\x18\x04 \x01(\x05R\atimeout\"\xb2\x02\n" +
+	"\rProbeResponse\x12\x18\n" +
+	"\ameterId\x18\x01 \x01(\tR\ameterId\x12\x18\n" +
+	"\ameterIp\x18\x02 \x01(\tR\ameterIp\x12\"\n" +
+	"\fserialNumber\x18\x03 \x01(\tR\fserialNumber\x12\x0e\n" +
+	"\x02ok\x18\x04 \x01(\bR\x02ok\x12.\n" +
+	"\x05steps\x18\x05 \x03(\v2\x18.dlmsprocessor.ProbeStepR\x05steps\x12,\n" +
+	"\x11logicalDeviceName\x18\x06 \x01(\tR\x11logicalDeviceName\x12(\n" +
+	"\x0ffirmwareVersion\x18\a \x01(\tR\x0ffirmwareVersion\x121\n" +
+	"\x06frames\x18\b \x01(\v2\x19.dlmsprocessor.FrameTraceR\x06frames\"\xbd\x01\n" +
+	"\tProbeStep\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x126\n" +
+	"\x06status\x18\x02 \x01(\x0e2\x1e.dlmsprocessor.ProbeStepStatusR\x06status\x12\x1e\n" +
+	"\n" +
+	"durationMs\x18\x03 \x01(\x01R\n" +
+	"durationMs\x12\x16\n" +
+	"\x06reason\x18\x04 \x01(\tR\x06reason\x12\x14\n" +
+	"\x05error\x18\x05 \x01(\tR\x05error\x12\x16\n" +
+	"\x06detail\x18\x06 \x01(\tR\x06detail\"\xc3\x01\n" +
+	"\x13GetNameplateRequest\x12*\n" +
+	"\x05meter\x18\x01 \x03(\v2\x14.dlmsprocessor.MeterR\x05meter\x12\x18\n" +
+	"\aretries\x18\x02 \x01(\x05R\aretries\x12\x1e\n" +
+	"\n" +
+	"retryDelay\x18\x03 \x01(\x05R\n" +
+	"retryDelay\x12,\n" +
+	"\x11connectionTimeout\x18\x04 \x01(\x05R\x11connectionTimeout\x12\x18\n" +
+	"\atimeout\x18\x05 \x01(\x05R\atimeout\"\xad\x01\n" +
+	"\x14GetNameplateResponse\x12=\n" +
+	"\tnameplate\x18\x01 \x01(\v2\x1f.dlmsprocessor.NameplateProfileR\tnameplate\x12\x18\n" +
+	"\ameterIp\x18\x02 \x01(\tR\ameterIp\x12\x18\n" +
+	"\ameterId\x18\x03 \x01(\tR\ameterId\x12\"\n" +
+	"\fserialNumber\x18\x04 \x01(\tR\fserialNumber\"\x9a\x02\n" +
+	"\x10NameplateProfile\x12\"\n" +
+	"\fserialNumber\x18\x01 \x01(\tR\fserialNumber\x12*\n" +
+	"\x10manufacturerName\x18\x02 \x01(\tR\x10manufacturerName\x12(\n" +
+	"\x0ffirmwareVersion\x18\x03 \x01(\tR\x0ffirmwareVersion\x12\x1c\n" +
+	"\tmeterType\x18\x04 \x01(\rR\tmeterType\x12\x1a\n" +
+	"\bcategory\x18\x05 \x01(\tR\bcategory\x12$\n" +
+	"\rcurrentRating\x18\x06 \x01(\tR\rcurrentRating\x12,\n" +
+	"\x11yearOfManufacture\x18\a \x01(\rR\x11yearOfManufacture\"\xea\x02\n" +
+	"\x14ProgramTariffRequest\x12*\n" +
+	"\x05meter\x18\x01 \x03(\v2\x14.dlmsprocessor.MeterR\x05meter\x12\x18\n" +
+	"\aretries\x18\x02 \x01(\x05R\aretries\x12\x1e\n" +
+	"\n" +
+	"retryDelay\x18\x03 \x01(\x05R\n" +
+	"retryDelay\x12,\n" +
+	"\x11connectionTimeout\x18\x04 \x01(\x05R\x11connectionTimeout\x12\x18\n" +
+	"\atimeout\x18\b \x01(\x05R\atimeout\x129\n" +
+	"\bcalendar\x18\x05 \x01(\v2\x1d.dlmsprocessor.TariffCalendarR\bcalendar\x12&\n" +
+	"\x0eactivationTime\x18\x06 \x01(\tR\x0eactivationTime\x12A\n" +
+	"\vspecialDays\x18\a \x01(\v2\x1f.dlmsprocessor.SpecialDaysTableR\vspecialDays\"\x9e\x01\n" +
+	"\x15ProgramTariffResponse\x12-\n" +
+	"\x06tariff\x18\x01 \x01(\v2\x15.dlmsprocessor.TariffR\x06tariff\x12\x18\n" +
+	"\ameterIp\x18\x02 \x01(\tR\ameterIp\x12\x18\n" +
+	"\ameterId\x18\x03 \x01(\tR\ameterId\x12\"\n" +
+	"\fserialNumber\x18\x04 \x01(\tR\fserialNumber\"\xdd\x01\n" +
+	"\x06Tariff\x125\n" +
+	"\x06active\x18\x01 \x01(\v2\x1d.dlmsprocessor.TariffCalendarR\x06active\x127\n" +
+	"\apassive\x18\x02 \x01(\v2\x1d.dlmsprocessor.TariffCalendarR\apassive\x12&\n" +
+	"\x0eactivationTime\x18\x03 \x01(\tR\x0eactivationTime\x12;\n" +
+	"\vspecialDays\x18\x04 \x03(\v2\x19.dlmsprocessor.SpecialDayR\vspecialDays\"\xd2\x01\n" +
+	"\x0eTariffCalendar\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12/\n" +
+	"\aseasons\x18\x02 \x03(\v2\x15.dlmsprocessor.SeasonR\aseasons\x12>\n" +
+	"\fweekProfiles\x18\x03 \x03(\v2\x1a.dlmsprocessor.WeekProfileR\fweekProfiles\x12;\n" +
+	"\vdayProfiles\x18\x04 \x03(\v2\x19.dlmsprocessor.DayProfileR\vdayProfiles\"q\n" +
+	"\x06Season\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x121\n" +
+	"\x05start\x18\x02 \x01(\v2\x1b.dlmsprocessor.CalendarDateR\x05start\x12 \n" +
+	"\vweekProfile\x18\x03 \x01(\tR\vweekProfile\"9\n" +
+	"\vWeekProfile\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x16\n" +
+	"\x06dayIds\x18\x02 \x03(\rR\x06dayIds\"P\n" +
+	"\n" +
+	"DayProfile\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\rR\x02id\x122\n" +
+	"\aactions\x18\x02 \x03(\v2\x18.dlmsprocessor.DayActionR\aactions\"]\n" +
+	"\tDayAction\x12\x1c\n" +
+	"\tstartTime\x18\x01 \x01(\tR\tstartTime\x12\x16\n" +
+	"\x06script\x18\x02 \x01(\tR\x06script\x12\x1a\n" +
+	"\bselector\x18\x03 \x01(\rR\bselector\"J\n" +
+	"\fCalendarDate\x12\x12\n" +
+	"\x04year\x18\x01 \x01(\x05R\x04year\x12\x14\n" +
+	"\x05month\x18\x02 \x01(\x05R\x05month\x12\x10\n" +
+	"\x03day\x18\x03 \x01(\x05R\x03day\"G\n" +
+	"\x10SpecialDaysTable\x123\n" +
+	"\aentries\x18\x01 \x03(\v2\x19.dlmsprocessor.SpecialDayR\aentries\"i\n" +
+	"\n" +
+	"SpecialDay\x12\x14\n" +
+	"\x05index\x18\x01 \x01(\rR\x05index\x12/\n" +
+	"\x04date\x18\x02 \x01(\v2\x1b.dlmsprocessor.CalendarDateR\x04date\x12\x14\n" +
+	"\x05dayId\x18\x03 \x01(\rR\x05dayId\"\xa0\x03\n" +
+	"\x0eProcessRequest\x12$\n" +
+	"\rcorrelationId\x18\x01 \x01(\tR\rcorrelationId\x12*\n" +
+	"\x05meter\x18\x02 \x01(\v2\x14.dlmsprocessor.MeterR\x05meter\x12,\n" +
+	"\x11connectionTimeout\x18\x03 \x01(\x05R\x11connectionTimeout\x12\x18\n" +
+	"\atimeout\x18\x04 \x01(\x05R\atimeout\x12?\n" +
+	"\vtraceFrames\x18\x05 \x01(\x0e2\x1d.dlmsprocessor.FrameTraceModeR\vtraceFrames\x122\n" +
+	"\x04read\x18\n" +
+	" \x01(\v2\x1c.dlmsprocessor.ReadOperationH\x00R\x04read\x125\n" +
+	"\x05write\x18\v \x01(\v2\x1d.dlmsprocessor.WriteOperationH\x00R\x05write\x12;\n" +
+	"\aexecute\x18\f \x01(\v2\x1f.dlmsprocessor.ExecuteOperationH\x00R\aexecuteB\v\n" +
+	"\toperation\"\xbe\x01\n" +
+	"\rReadOperation\x12A\n" +
+	"\tattribute\x18\x01 \x01(\v2!.dlmsprocessor.AttributeReferenceH\x00R\tattribute\x126\n" +
+	"\aprofile\x18\x02 \x01(\x0e2\x1a.dlmsprocessor.ProfileTypeH\x00R\aprofile\x12(\n" +
+	"\x0edefinedProfile\x18\x03 \x01(\tH\x00R\x0edefinedProfileB\b\n" +
+	"\x06target\"p\n" +
+	"\x12AttributeReference\x12\x12\n" +
+	"\x04obis\x18\x01 \x01(\tR\x04obis\x12\x1e\n" +
+	"\n" +
+	"objectType\x18\x02 \x01(\x05R\n" +
+	"objectType\x12&\n" +
+	"\x0eattributeIndex\x18\x03 \x01(\x05R\x0eattributeIndex\"\xe0\x02\n" +
+	"\x0eWriteOperation\x12?\n" +
+	"\tattribute\x18\x01 \x01(\v2!.dlmsprocessor.AttributeReferenceR\tattribute\x12 \n" +
+	"\n" +
+	"int32Value\x18\x02 \x01(\x05H\x00R\n" +
+	"int32Value\x12\"\n" +
+	"\vuint32Value\x18\x03 \x01(\rH\x00R\vuint32Value\x12$\n" +
+	"\ffloat64Value\x18\x04 \x01(\x01H\x00R\ffloat64Value\x12\x1e\n" +
+	"\tboolValue\x18\x05 \x01(\bH\x00R\tboolValue\x12\"\n" +
+	"\vstringValue\x18\x06 \x01(\tH\x00R\vstringValue\x12,\n" +
+	"\x10octetStringValue\x18\a \x01(\fH\x00R\x10octetStringValue\x12&\n" +
+	"\rdateTimeValue\x18\b \x01(\tH\x00R\rdateTimeValueB\a\n" +
+	"\x05value\"\x88\x01\n" +
+	"\x10ExecuteOperation\x12\x1a\n" +
+	"\bfunction\x18\x01 \x01(\tR\bfunction\x126\n" +
+	"\x06method\x18\x03 \x01(\v2\x1e.dlmsprocessor.MethodReferenceR\x06method\x12\x12\n" +
+	"\x04data\x18\x04 \x01(\fR\x04dataJ\x04\b\x02\x10\x03R\x06params\"g\n" +
+	"\x0fMethodReference\x12\x12\n" +
+	"\x04obis\x18\x01 \x01(\tR\x04obis\x12\x1e\n" +
+	"\n" +
+	"objectType\x18\x02 \x01(\x05R\n" +
+	"objectType\x12 \n" +
+	"\vmethodIndex\x18\x03 \x01(\x05R\vmethodIndex\"\x84\x06\n" +
+	"\x0fProcessResponse\x12$\n" +
+	"\rcorrelationId\x18\x01 \x01(\tR\rcorrelationId\x12\x18\n" +
+	"\ameterId\x18\x02 \x01(\tR\ameterId\x12\x18\n" +
+	"\ameterIp\x18\x03 \x01(\tR\ameterIp\x12\"\n" +
+	"\fserialNumber\x18\x04 \x01(\tR\fserialNumber\x12\x18\n" +
+	"\acredits\x18\x05 \x01(\x05R\acredits\x121\n" +
+	"\x06frames\x18\x06 \x01(\v2\x19.dlmsprocessor.FrameTraceR\x06frames\x12\x16\n" +
+	"\x05value\x18\n" +
+	" \x01(\tH\x00R\x05value\x12M\n" +
+	"\x10blockLoadProfile\x18\v \x01(\v2\x1f.dlmsprocessor.BlockLoadProfileH\x00R\x10blockLoadProfile\x12M\n" +
+	"\x10dailyLoadProfile\x18\f \x01(\v2\x1f.dlmsprocessor.DailyLoadProfileH\x00R\x10dailyLoadProfile\x12S\n" +
+	"\x12billingDataProfile\x18\r \x01(\v2!.dlmsprocessor.BillingDataProfileH\x00R\x12billingDataProfile\x12Y\n" +
+	"\x14instantaneousProfile\x18\x0e \x01(\v2#.dlmsprocessor.InstantaneousProfileH\x00R\x14instantaneousProfile\x125\n" +
+	"\x05error\x18\x0f \x01(\v2\x1d.dlmsprocessor.OperationErrorH\x00R\x05error\x12?\n" +
+	"\tnameplate\x18\x10 \x01(\v2\x1f.dlmsprocessor.NameplateProfileH\x00R\tnameplate\x12>\n" +
+	"\vprofileData\x18\x11 \x01(\v2\x1a.dlmsprocessor.ProfileDataH\x00R\vprofileDataB\b\n" +
+	"\x06result\"\x9c\x01\n" +
+	"\vProfileData\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x12\n" +
+	"\x04obis\x18\x02 \x01(\tR\x04obis\x126\n" +
+	"\acolumns\x18\x03 \x03(\v2\x1c.dlmsprocessor.ProfileColumnR\acolumns\x12-\n" +
+	"\x04rows\x18\x04 \x03(\v2\x19.dlmsprocessor.ProfileRowR\x04rows\"i\n" +
+	"\rProfileColumn\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x12\n" +
+	"\x04obis\x18\x02 \x01(\tR\x04obis\x12\x1c\n" +
+	"\tattribute\x18\x03 \x01(\x05R\tattribute\x12\x12\n" +
+	"\x04unit\x18\x04 \x01(\tR\x04unit\"A\n" +
+	"\n" +
+	"ProfileRow\x123\n" +
+	"\x06values\x18\x01 \x03(\v2\x1b.dlmsprocessor.ProfileValueR\x06values\"_\n" +
+	"\fProfileValue\x12\"\n" +
+	"\vstringValue\x18\x01 \x01(\tH\x00R\vstringValue\x12\"\n" +
+	"\vnumberValue\x18\x02 \x01(\x01H\x00R\vnumberValueB\a\n" +
+	"\x05value\"V\n" +
+	"\x0eOperationError\x12\x12\n" +
+	"\x04code\x18\x01 \x01(\x05R\x04code\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12\x16\n" +
+	"\x06reason\x18\x03 \x01(\tR\x06reason\"l\n" +
+	"\n" +
+	"FrameTrace\x12,\n" +
+	"\x06frames\x18\x01 \x03(\v2\x14.dlmsprocessor.FrameR\x06frames\x12\x12\n" +
+	"\x04file\x18\x02 \x01(\tR\x04file\x12\x1c\n" +
+	"\ttruncated\x18\x03 \x01(\bR\ttruncated\"\x8e\x01\n" +
+	"\x05Frame\x12 \n" +
+	"\vtimestampUs\x18\x01 \x01(\x03R\vtimestampUs\x12;\n" +
+	"\tdirection\x18\x02 \x01(\x0e2\x1d.dlmsprocessor.FrameDirectionR\tdirection\x12\x12\n" +
+	"\x04data\x18\x03 \x01(\fR\x04data\x12\x12\n" +
+	"\x04apdu\x18\x04 \x01(\fR\x04apdu*\x8b\x01\n" +
+	"\x0fProbeStepStatus\x12!\n" +
+	"\x1dPROBE_STEP_STATUS_UNSPECIFIED\x10\x00\x12\x18\n" +
+	"\x14PROBE_STEP_STATUS_OK\x10\x01\x12\x1c\n" +
+	"\x18PROBE_STEP_STATUS_FAILED\x10\x02\x12\x1d\n" +
+	"\x19PROBE_STEP_STATUS_SKIPPED\x10\x03*\xc0\x01\n" +
+	"\vProfileType\x12\x1c\n" +
+	"\x18PROFILE_TYPE_UNSPECIFIED\x10\x00\x12\x1b\n" +
+	"\x17PROFILE_TYPE_BLOCK_LOAD\x10\x01\x12\x1b\n" +
+	"\x17PROFILE_TYPE_DAILY_LOAD\x10\x02\x12\x1d\n" +
+	"\x19PROFILE_TYPE_BILLING_DATA\x10\x03\x12\x1e\n" +
+	"\x1aPROFILE_TYPE_INSTANTANEOUS\x10\x04\x12\x1a\n" +
+	"\x16PROFILE_TYPE_NAMEPLATE\x10\x05*e\n" +
+	"\x0eFrameTraceMode\x12\x19\n" +
+	"\x15FRAME_TRACE_MODE_NONE\x10\x00\x12\x1d\n" +
+	"\x19FRAME_TRACE_MODE_RESPONSE\x10\x01\x12\x19\n" +
+	"\x15FRAME_TRACE_MODE_FILE\x10\x02*i\n" +
+	"\x0eFrameDirection\x12\x1f\n" +
+	"\x1bFRAME_DIRECTION_UNSPECIFIED\x10\x00\x12\x18\n" +
+	"\x14FRAME_DIRECTION_SENT\x10\x01\x12\x1c\n" +
+	"\x18FRAME_DIRECTION_RECEIVED\x10\x022\xa6\t\n" +
+	"\rDLMSProcessor\x12d\n" +
+	"\aGetOBIS\x12\x1d.dlmsprocessor.GetOBISRequest\x1a\x1e.dlmsprocessor.GetOBISResponse\"\x18\x82\xd3\xe4\x93\x02\x12:\x01*\"\r/v1/obis:read0\x01\x12\x97\x01\n" +
+	"\x13GetBlockLoadProfile\x12).dlmsprocessor.GetBlockLoadProfileRequest\x1a*.dlmsprocessor.GetBlockLoadProfileResponse\"'\x82\xd3\xe4\x93\x02!:\x01*\"\x1c/v1/profiles/block-load:read0\x01\x12\x97\x01\n" +
+	"\x13GetDailyLoadProfile\x12).dlmsprocessor.GetDailyLoadProfileRequest\x1a*.dlmsprocessor.GetDailyLoadProfileResponse\"'\x82\xd3\xe4\x93\x02!:\x01*\"\x1c/v1/profiles/daily-load:read0\x01\x12\x9f\x01\n" +
+	"\x15GetBillingDataProfile\x12+.dlmsprocessor.GetBillingDataProfileRequest\x1a,.dlmsprocessor.GetBillingDataProfileResponse\")\x82\xd3\xe4\x93\x02#:\x01*\"\x1e/v1/profiles/billing-data:read0\x01\x12\xa6\x01\n" +
+	"\x17GetInstantaneousProfile\x12-.dlmsprocessor.GetInstantaneousProfileRequest\x1a..dlmsprocessor.GetInstantaneousProfileResponse\"*\x82\xd3\xe4\x93\x02$:\x01*\"\x1f/v1/profiles/instantaneous:read0\x01\x12\x81\x01\n" +
+	"\fGetNameplate\x12\".dlmsprocessor.GetNameplateRequest\x1a#.dlmsprocessor.GetNameplateResponse\"&\x82\xd3\xe4\x93\x02 :\x01*\"\x1b/v1/profiles/nameplate:read0\x01\x12a\n" +
+	"\x05Probe\x12\x1b.dlmsprocessor.ProbeRequest\x1a\x1c.dlmsprocessor.ProbeResponse\"\x1b\x82\xd3\xe4\x93\x02\x15:\x01*\"\x10/v1/meters:probe0\x01\x12{\n" +
+	"\rProgramTariff\x12#.dlmsprocessor.ProgramTariffRequest\x1a$.dlmsprocessor.ProgramTariffResponse\"\x1d\x82\xd3\xe4\x93\x02\x17:\x01*\"\x12/v1/tariff:program0\x01\x12L\n" +
+	"\aProcess\x12\x1d.dlmsprocessor.ProcessRequest\x1a\x1e.dlmsprocessor.ProcessResponse(\x010\x01B\x15Z\x13dlmsprocessor/protob\x06proto3"
 
 var (
 	file_dlmsprocessor_proto_rawDescOnce sync.Once
@@ -1334,49 +5086,144 @@ func file_dlmsprocessor_proto_rawDescGZIP() []byte {
 	return file_dlmsprocessor_proto_rawDescData
 }
 
-var file_dlmsprocessor_proto_msgTypes = make([]protoimpl.MessageInfo, 15)
+var file_dlmsprocessor_proto_enumTypes = make([]protoimpl.EnumInfo, 4)
+var file_dlmsprocessor_proto_msgTypes = make([]protoimpl.MessageInfo, 50)
 var file_dlmsprocessor_proto_goTypes = []any{
-	(*GetOBISRequest)(nil),                  // 0: dlmsprocessor.GetOBISRequest
-	(*Meter)(nil),                           // 1: dlmsprocessor.Meter
-	(*GetOBISResponse)(nil),                 // 2: dlmsprocessor.GetOBISResponse
-	(*GetBlockLoadProfileRequest)(nil),      // 3: dlmsprocessor.GetBlockLoadProfileRequest
-	(*GetBlockLoadProfileResponse)(nil),     // 4: dlmsprocessor.GetBlockLoadProfileResponse
-	(*BlockLoadProfile)(nil),                // 5: dlmsprocessor.BlockLoadProfile
-	(*GetDailyLoadProfileRequest)(nil),      // 6: dlmsprocessor.GetDailyLoadProfileRequest
-	(*GetDailyLoadProfileResponse)(nil),     // 7: dlmsprocessor.GetDailyLoadProfileResponse
-	(*DailyLoadProfile)(nil),                // 8: dlmsprocessor.DailyLoadProfile
-	(*GetBillingDataProfileRequest)(nil),    // 9: dlmsprocessor.GetBillingDataProfileRequest
-	(*GetBillingDataProfileResponse)(nil),   // 10: dlmsprocessor.GetBillingDataProfileResponse
-	(*BillingDataProfile)(nil),              // 11: dlmsprocessor.BillingDataProfile
-	(*GetInstantaneousProfileRequest)(nil),  // 12: dlmsprocessor.GetInstantaneousProfileRequest
-	(*GetInstantaneousProfileResponse)(nil), // 13: dlmsprocessor.GetInstantaneousProfileResponse
-	(*InstantaneousProfile)(nil),            // 14: dlmsprocessor.InstantaneousProfile
+	(ProbeStepStatus)(0),                    // 0: dlmsprocessor.ProbeStepStatus
+	(ProfileType)(0),                        // 1: dlmsprocessor.ProfileType
+	(FrameTraceMode)(0),                     // 2: dlmsprocessor.FrameTraceMode
+	(FrameDirection)(0),                     // 3: dlmsprocessor.FrameDirection
+	(*GetOBISRequest)(nil),                  // 4: dlmsprocessor.GetOBISRequest
+	(*Meter)(nil),                           // 5: dlmsprocessor.Meter
+	(*KeyRef)(nil),                          // 6: dlmsprocessor.KeyRef
+	(*GetOBISResponse)(nil),                 // 7: dlmsprocessor.GetOBISResponse
+	(*GetBlockLoadProfileRequest)(nil),      // 8: dlmsprocessor.GetBlockLoadProfileRequest
+	(*GetBlockLoadProfileResponse)(nil),     // 9: dlmsprocessor.GetBlockLoadProfileResponse
+	(*BlockLoadProfile)(nil),                // 10: dlmsprocessor.BlockLoadProfile
+	(*ThreePhaseBlockLoadProfile)(nil),      // 11: dlmsprocessor.ThreePhaseBlockLoadProfile
+	(*GetDailyLoadProfileRequest)(nil),      // 12: dlmsprocessor.GetDailyLoadProfileRequest
+	(*GetDailyLoadProfileResponse)(nil),     // 13: dlmsprocessor.GetDailyLoadProfileResponse
+	(*DailyLoadProfile)(nil),                // 14: dlmsprocessor.DailyLoadProfile
+	(*GetBillingDataProfileRequest)(nil),    // 15: dlmsprocessor.GetBillingDataProfileRequest
+	(*GetBillingDataProfileResponse)(nil),   // 16: dlmsprocessor.GetBillingDataProfileResponse
+	(*BillingDataProfile)(nil),              // 17: dlmsprocessor.BillingDataProfile
+	(*TariffZone)(nil),                      // 18: dlmsprocessor.TariffZone
+	(*GetInstantaneousProfileRequest)(nil),  // 19: dlmsprocessor.GetInstantaneousProfileRequest
+	(*GetInstantaneousProfileResponse)(nil), // 20: dlmsprocessor.GetInstantaneousProfileResponse
+	(*InstantaneousProfile)(nil),            // 21: dlmsprocessor.InstantaneousProfile
+	(*ThreePhaseInstantaneousProfile)(nil),  // 22: dlmsprocessor.ThreePhaseInstantaneousProfile
+	(*ProbeRequest)(nil),                    // 23: dlmsprocessor.ProbeRequest
+	(*ProbeResponse)(nil),                   // 24: dlmsprocessor.ProbeResponse
+	(*ProbeStep)(nil),                       // 25: dlmsprocessor.ProbeStep
+	(*GetNameplateRequest)(nil),             // 26: dlmsprocessor.GetNameplateRequest
+	(*GetNameplateResponse)(nil),            // 27: dlmsprocessor.GetNameplateResponse
+	(*NameplateProfile)(nil),                // 28: dlmsprocessor.NameplateProfile
+	(*ProgramTariffRequest)(nil),            // 29: dlmsprocessor.ProgramTariffRequest
+	(*ProgramTariffResponse)(nil),           // 30: dlmsprocessor.ProgramTariffResponse
+	(*Tariff)(nil),                          // 31: dlmsprocessor.Tariff
+	(*TariffCalendar)(nil),                  // 32: dlmsprocessor.TariffCalendar
+	(*Season)(nil),                          // 33: dlmsprocessor.Season
+	(*WeekProfile)(nil),                     // 34: dlmsprocessor.WeekProfile
+	(*DayProfile)(nil),                      // 35: dlmsprocessor.DayProfile
+	(*DayAction)(nil),                       // 36: dlmsprocessor.DayAction
+	(*CalendarDate)(nil),                    // 37: dlmsprocessor.CalendarDate
+	(*SpecialDaysTable)(nil),                // 38: dlmsprocessor.SpecialDaysTable
+	(*SpecialDay)(nil),                      // 39: dlmsprocessor.SpecialDay
+	(*ProcessRequest)(nil),                  // 40: dlmsprocessor.ProcessRequest
+	(*ReadOperation)(nil),                   // 41: dlmsprocessor.ReadOperation
+	(*AttributeReference)(nil),              // 42: dlmsprocessor.AttributeReference
+	(*WriteOperation)(nil),                  // 43: dlmsprocessor.WriteOperation
+	(*ExecuteOperation)(nil),                // 44: dlmsprocessor.ExecuteOperation
+	(*MethodReference)(nil),                 // 45: dlmsprocessor.MethodReference
+	(*ProcessResponse)(nil),                 // 46: dlmsprocessor.ProcessResponse
+	(*ProfileData)(nil),                     // 47: dlmsprocessor.ProfileData
+	(*ProfileColumn)(nil),                   // 48: dlmsprocessor.ProfileColumn
+	(*ProfileRow)(nil),                      // 49: dlmsprocessor.ProfileRow
+	(*ProfileValue)(nil),                    // 50: dlmsprocessor.ProfileValue
+	(*OperationError)(nil),                  // 51: dlmsprocessor.OperationError
+	(*FrameTrace)(nil),                      // 52: dlmsprocessor.FrameTrace
+	(*Frame)(nil),                           // 53: dlmsprocessor.Frame
 }
 var file_dlmsprocessor_proto_depIdxs = []int32{
-	1,  // 0: dlmsprocessor.GetOBISRequest.meter:type_name -> dlmsprocessor.Meter
-	1,  // 1: dlmsprocessor.GetBlockLoadProfileRequest.meter:type_name -> dlmsprocessor.Meter
-	5,  // 2: dlmsprocessor.GetBlockLoadProfileResponse.profile:type_name -> dlmsprocessor.BlockLoadProfile
-	1,  // 3: dlmsprocessor.GetDailyLoadProfileRequest.meter:type_name -> dlmsprocessor.Meter
-	8,  // 4: dlmsprocessor.GetDailyLoadProfileResponse.profile:type_name -> dlmsprocessor.DailyLoadProfile
-	1,  // 5: dlmsprocessor.GetBillingDataProfileRequest.meter:type_name -> dlmsprocessor.Meter
-	11, // 6: dlmsprocessor.GetBillingDataProfileResponse.profile:type_name -> dlmsprocessor.BillingDataProfile
-	1,  // 7: dlmsprocessor.GetInstantaneousProfileRequest.meter:type_name -> dlmsprocessor.Meter
-	14, // 8: dlmsprocessor.GetInstantaneousProfileResponse.profile:type_name -> dlmsprocessor.InstantaneousProfile
-	0,  // 9: dlmsprocessor.DLMSProcessor.GetOBIS:input_type -> dlmsprocessor.GetOBISRequest
-	3,  // 10: dlmsprocessor.DLMSProcessor.GetBlockLoadProfile:input_type -> dlmsprocessor.GetBlockLoadProfileRequest
-	6,  // 11: dlmsprocessor.DLMSProcessor.GetDailyLoadProfile:input_type -> dlmsprocessor.GetDailyLoadProfileRequest
-	9,  // 12: dlmsprocessor.DLMSProcessor.GetBillingDataProfile:input_type -> dlmsprocessor.GetBillingDataProfileRequest
-	12, // 13: dlmsprocessor.DLMSProcessor.GetInstantaneousProfile:input_type -> dlmsprocessor.GetInstantaneousProfileRequest
-	2,  // 14: dlmsprocessor.DLMSProcessor.GetOBIS:output_type -> dlmsprocessor.GetOBISResponse
-	4,  // 15: dlmsprocessor.DLMSProcessor.GetBlockLoadProfile:output_type -> dlmsprocessor.GetBlockLoadProfileResponse
-	7,  // 16: dlmsprocessor.DLMSProcessor.GetDailyLoadProfile:output_type -> dlmsprocessor.GetDailyLoadProfileResponse
-	10, // 17: dlmsprocessor.DLMSProcessor.GetBillingDataProfile:output_type -> dlmsprocessor.GetBillingDataProfileResponse
-	13, // 18: dlmsprocessor.DLMSProcessor.GetInstantaneousProfile:output_type -> dlmsprocessor.GetInstantaneousProfileResponse
-	14, // [14:19] is the sub-list for method output_type
-	9,  // [9:14] is the sub-list for method input_type
-	9,  // [9:9] is the sub-list for extension type_name
-	9,  // [9:9] is the sub-list for extension extendee
-	0,  // [0:9] is the sub-list for field type_name
+	5,  // 0: dlmsprocessor.GetOBISRequest.meter:type_name -> dlmsprocessor.Meter
+	6,  // 1: dlmsprocessor.Meter.keyRef:type_name -> dlmsprocessor.KeyRef
+	5,  // 2: dlmsprocessor.GetBlockLoadProfileRequest.meter:type_name -> dlmsprocessor.Meter
+	10, // 3: dlmsprocessor.GetBlockLoadProfileResponse.profile:type_name -> dlmsprocessor.BlockLoadProfile
+	11, // 4: dlmsprocessor.BlockLoadProfile.threePhase:type_name -> dlmsprocessor.ThreePhaseBlockLoadProfile
+	5,  // 5: dlmsprocessor.GetDailyLoadProfileRequest.meter:type_name -> dlmsprocessor.Meter
+	14, // 6: dlmsprocessor.GetDailyLoadProfileResponse.profile:type_name -> dlmsprocessor.DailyLoadProfile
+	5,  // 7: dlmsprocessor.GetBillingDataProfileRequest.meter:type_name -> dlmsprocessor.Meter
+	17, // 8: dlmsprocessor.GetBillingDataProfileResponse.profile:type_name -> dlmsprocessor.BillingDataProfile
+	18, // 9: dlmsprocessor.BillingDataProfile.tariffZones:type_name -> dlmsprocessor.TariffZone
+	5,  // 10: dlmsprocessor.GetInstantaneousProfileRequest.meter:type_name -> dlmsprocessor.Meter
+	21, // 11: dlmsprocessor.GetInstantaneousProfileResponse.profile:type_name -> dlmsprocessor.InstantaneousProfile
+	22, // 12: dlmsprocessor.InstantaneousProfile.threePhase:type_name -> dlmsprocessor.ThreePhaseInstantaneousProfile
+	5,  // 13: dlmsprocessor.ProbeRequest.meter:type_name -> dlmsprocessor.Meter
+	2,  // 14: dlmsprocessor.ProbeRequest.traceFrames:type_name -> dlmsprocessor.FrameTraceMode
+	25, // 15: dlmsprocessor.ProbeResponse.steps:type_name -> dlmsprocessor.ProbeStep
+	52, // 16: dlmsprocessor.ProbeResponse.frames:type_name -> dlmsprocessor.FrameTrace
+	0,  // 17: dlmsprocessor.ProbeStep.status:type_name -> dlmsprocessor.ProbeStepStatus
+	5,  // 18: dlmsprocessor.GetNameplateRequest.meter:type_name -> dlmsprocessor.Meter
+	28, // 19: dlmsprocessor.GetNameplateResponse.nameplate:type_name -> dlmsprocessor.NameplateProfile
+	5,  // 20: dlmsprocessor.ProgramTariffRequest.meter:type_name -> dlmsprocessor.Meter
+	32, // 21: dlmsprocessor.ProgramTariffRequest.calendar:type_name -> dlmsprocessor.TariffCalendar
+	38, // 22: dlmsprocessor.ProgramTariffRequest.specialDays:type_name -> dlmsprocessor.SpecialDaysTable
+	31, // 23: dlmsprocessor.ProgramTariffResponse.tariff:type_name -> dlmsprocessor.Tariff
+	32, // 24: dlmsprocessor.Tariff.active:type_name -> dlmsprocessor.TariffCalendar
+	32, // 25: dlmsprocessor.Tariff.passive:type_name -> dlmsprocessor.TariffCalendar
+	39, // 26: dlmsprocessor.Tariff.specialDays:type_name -> dlmsprocessor.SpecialDay
+	33, // 27: dlmsprocessor.TariffCalendar.seasons:type_name -> dlmsprocessor.Season
+	34, // 28: dlmsprocessor.TariffCalendar.weekProfiles:type_name -> dlmsprocessor.WeekProfile
+	35, // 29: dlmsprocessor.TariffCalendar.dayProfiles:type_name -> dlmsprocessor.DayProfile
+	37, // 30: dlmsprocessor.Season.start:type_name -> dlmsprocessor.CalendarDate
+	36, // 31: dlmsprocessor.DayProfile.actions:type_name -> dlmsprocessor.DayAction
+	39, // 32: dlmsprocessor.SpecialDaysTable.entries:type_name -> dlmsprocessor.SpecialDay
+	37, // 33: dlmsprocessor.SpecialDay.date:type_name -> dlmsprocessor.CalendarDate
+	5,  // 34: dlmsprocessor.ProcessRequest.meter:type_name -> dlmsprocessor.Meter
+	2,  // 35: dlmsprocessor.ProcessRequest.traceFrames:type_name -> dlmsprocessor.FrameTraceMode
+	41, // 36: dlmsprocessor.ProcessRequest.read:type_name -> dlmsprocessor.ReadOperation
+	43, // 37: dlmsprocessor.ProcessRequest.write:type_name -> dlmsprocessor.WriteOperation
+	44, // 38: dlmsprocessor.ProcessRequest.execute:type_name -> dlmsprocessor.ExecuteOperation
+	42, // 39: dlmsprocessor.ReadOperation.attribute:type_name -> dlmsprocessor.AttributeReference
+	1,  // 40: dlmsprocessor.ReadOperation.profile:type_name -> dlmsprocessor.ProfileType
+	42, // 41: dlmsprocessor.WriteOperation.attribute:type_name -> dlmsprocessor.AttributeReference
+	45, // 42: dlmsprocessor.ExecuteOperation.method:type_name -> dlmsprocessor.MethodReference
+	52, // 43: dlmsprocessor.ProcessResponse.frames:type_name -> dlmsprocessor.FrameTrace
+	10, // 44: dlmsprocessor.ProcessResponse.blockLoadProfile:type_name -> dlmsprocessor.BlockLoadProfile
+	14, // 45: dlmsprocessor.ProcessResponse.dailyLoadProfile:type_name -> dlmsprocessor.DailyLoadProfile
+	17, // 46: dlmsprocessor.ProcessResponse.billingDataProfile:type_name -> dlmsprocessor.BillingDataProfile
+	21, // 47: dlmsprocessor.ProcessResponse.instantaneousProfile:type_name -> dlmsprocessor.InstantaneousProfile
+	51, // 48: dlmsprocessor.ProcessResponse.error:type_name -> dlmsprocessor.OperationError
+	28, // 49: dlmsprocessor.ProcessResponse.nameplate:type_name -> dlmsprocessor.NameplateProfile
+	47, // 50: dlmsprocessor.ProcessResponse.profileData:type_name -> dlmsprocessor.ProfileData
+	48, // 51: dlmsprocessor.ProfileData.columns:type_name -> dlmsprocessor.ProfileColumn
+	49, // 52: dlmsprocessor.ProfileData.rows:type_name -> dlmsprocessor.ProfileRow
+	50, // 53: dlmsprocessor.ProfileRow.values:type_name -> dlmsprocessor.ProfileValue
+	53, // 54: dlmsprocessor.FrameTrace.frames:type_name -> dlmsprocessor.Frame
+	3,  // 55: dlmsprocessor.Frame.direction:type_name -> dlmsprocessor.FrameDirection
+	4,  // 56: dlmsprocessor.DLMSProcessor.GetOBIS:input_type -> dlmsprocessor.GetOBISRequest
+	8,  // 57: dlmsprocessor.DLMSProcessor.GetBlockLoadProfile:input_type -> dlmsprocessor.GetBlockLoadProfileRequest
+	12, // 58: dlmsprocessor.DLMSProcessor.GetDailyLoadProfile:input_type -> dlmsprocessor.GetDailyLoadProfileRequest
+	15, // 59: dlmsprocessor.DLMSProcessor.GetBillingDataProfile:input_type -> dlmsprocessor.GetBillingDataProfileRequest
+	19, // 60: dlmsprocessor.DLMSProcessor.GetInstantaneousProfile:input_type -> dlmsprocessor.GetInstantaneousProfileRequest
+	26, // 61: dlmsprocessor.DLMSProcessor.GetNameplate:input_type -> dlmsprocessor.GetNameplateRequest
+	23, // 62: dlmsprocessor.DLMSProcessor.Probe:input_type -> dlmsprocessor.ProbeRequest
+	29, // 63: dlmsprocessor.DLMSProcessor.ProgramTariff:input_type -> dlmsprocessor.ProgramTariffRequest
+	40, // 64: dlmsprocessor.DLMSProcessor.Process:input_type -> dlmsprocessor.ProcessRequest
+	7,  // 65: dlmsprocessor.DLMSProcessor.GetOBIS:output_type -> dlmsprocessor.GetOBISResponse
+	9,  // 66: dlmsprocessor.DLMSProcessor.GetBlockLoadProfile:output_type -> dlmsprocessor.GetBlockLoadProfileResponse
+	13, // 67: dlmsprocessor.DLMSProcessor.GetDailyLoadProfile:output_type -> dlmsprocessor.GetDailyLoadProfileResponse
+	16, // 68: dlmsprocessor.DLMSProcessor.GetBillingDataProfile:output_type -> dlmsprocessor.GetBillingDataProfileResponse
+	20, // 69: dlmsprocessor.DLMSProcessor.GetInstantaneousProfile:output_type -> dlmsprocessor.GetInstantaneousProfileResponse
+	27, // 70: dlmsprocessor.DLMSProcessor.GetNameplate:output_type -> dlmsprocessor.GetNameplateResponse
+	24, // 71: dlmsprocessor.DLMSProcessor.Probe:output_type -> dlmsprocessor.ProbeResponse
+	30, // 72: dlmsprocessor.DLMSProcessor.ProgramTariff:output_type -> dlmsprocessor.ProgramTariffResponse
+	46, // 73: dlmsprocessor.DLMSProcessor.Process:output_type -> dlmsprocessor.ProcessResponse
+	65, // [65:74] is the sub-list for method output_type
+	56, // [56:65] is the sub-list for method input_type
+	56, // [56:56] is the sub-list for extension type_name
+	56, // [56:56] is the sub-list for extension extendee
+	0,  // [0:56] is the sub-list for field type_name
 }
 
 func init() { file_dlmsprocessor_proto_init() }
@@ -1384,18 +5231,52 @@ func file_dlmsprocessor_proto_init() {
 	if File_dlmsprocessor_proto != nil {
 		return
 	}
+	file_dlmsprocessor_proto_msgTypes[36].OneofWrappers = []any{
+		(*ProcessRequest_Read)(nil),
+		(*ProcessRequest_Write)(nil),
+		(*ProcessRequest_Execute)(nil),
+	}
+	file_dlmsprocessor_proto_msgTypes[37].OneofWrappers = []any{
+		(*ReadOperation_Attribute)(nil),
+		(*ReadOperation_Profile)(nil),
+		(*ReadOperation_DefinedProfile)(nil),
+	}
+	file_dlmsprocessor_proto_msgTypes[39].OneofWrappers = []any{
+		(*WriteOperation_Int32Value)(nil),
+		(*WriteOperation_Uint32Value)(nil),
+		(*WriteOperation_Float64Value)(nil),
+		(*WriteOperation_BoolValue)(nil),
+		(*WriteOperation_StringValue)(nil),
+		(*WriteOperation_OctetStringValue)(nil),
+		(*WriteOperation_DateTimeValue)(nil),
+	}
+	file_dlmsprocessor_proto_msgTypes[42].OneofWrappers = []any{
+		(*ProcessResponse_Value)(nil),
+		(*ProcessResponse_BlockLoadProfile)(nil),
+		(*ProcessResponse_DailyLoadProfile)(nil),
+		(*ProcessResponse_BillingDataProfile)(nil),
+		(*ProcessResponse_InstantaneousProfile)(nil),
+		(*ProcessResponse_Error)(nil),
+		(*ProcessResponse_Nameplate)(nil),
+		(*ProcessResponse_ProfileData)(nil),
+	}
+	file_dlmsprocessor_proto_msgTypes[46].OneofWrappers = []any{
+		(*ProfileValue_StringValue)(nil),
+		(*ProfileValue_NumberValue)(nil),
+	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_dlmsprocessor_proto_rawDesc), len(file_dlmsprocessor_proto_rawDesc)),
-			NumEnums:      0,
-			NumMessages:   15,
+			NumEnums:      4,
+			NumMessages:   50,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
 		GoTypes:           file_dlmsprocessor_proto_goTypes,
 		DependencyIndexes: file_dlmsprocessor_proto_depIdxs,
+		EnumInfos:         file_dlmsprocessor_proto_enumTypes,
 		MessageInfos:      file_dlmsprocessor_proto_msgTypes,
 	}.Build()
 	File_dlmsprocessor_proto = out.File
@@ -0,0 +1,33 @@
+#!/bin/sh
+# Generates a CA plus server and client certificates for trying out TLS and
+# mutual TLS locally. Not for production use.
+#
+#   scripts/gen-certs.sh [dir] [server-host]
+set -eu
+
+dir=${1:-certs}
+host=${2:-localhost}
+days=365
+
+mkdir -p "$dir"
+cd "$dir"
+
+openssl req -x509 -newkey ec -pkeyopt ec_paramgen_curve:P-256 -nodes \
+	-keyout ca.key -out ca.crt -days "$days" -subj "/CN=dlms-processor test CA"
+
+issue() {
+	name=$1 cn=$2 ext=$3
+	openssl req -newkey ec -pkeyopt ec_paramgen_curve:P-256 -nodes \
+		-keyout "$name.key" -out "$name.csr" -subj "/CN=$cn"
+	printf '%s\n' "$ext" > "$name.ext"
+	openssl x509 -req -in "$name.csr" -CA ca.crt -CAkey ca.key -CAcreateserial \
+		-out "$name.crt" -days "$days" -extfile "$name.ext"
+	rm "$name.csr" "$name.ext"
+}
+
+issue server "$host" "subjectAltName=DNS:$host,DNS:localhost,IP:127.0.0.1
+extendedKeyUsage=serverAuth"
+issue client dlms-consumer "extendedKeyUsage=clientAuth"
+
+chmod 600 ./*.key
+echo "Wrote ca, server and client certificates to $dir"
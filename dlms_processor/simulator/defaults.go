@@ -0,0 +1,284 @@
+package simulator
+
+import (
+	"dlmsprocessor/dlms"
+	"fmt"
+	"time"
+)
+
+// clientSystemTitle is the system title RealMeter gives the processor's
+// client. The simulated association accepts any client system title.
+const clientSystemTitle = "4142434445464748"
+
+// COSEM interface classes of the default model
+const (
+	classData             = 1
+	classRegister         = 3
+	classExtendedRegister = 4
+	classClock            = 8
+)
+
+// OBIS codes of the default model's profiles, events and disconnect control
+const (
+	BlockLoadProfileOBIS     = "1.0.99.1.0.255"
+	DailyLoadProfileOBIS     = "1.0.99.2.0.255"
+	BillingProfileOBIS       = "0.0.98.1.0.255"
+	InstantaneousProfileOBIS = "1.0.94.7.0.255"
+	EventLogOBIS             = "0.0.99.98.0.255"
+	EventCodeOBIS            = "0.0.96.11.0.255"
+	DisconnectControlOBIS    = "0.0.96.3.10.255"
+)
+
+// DLMS units of the default model
+const (
+	unitVA  = 28
+	unitW   = 27
+	unitWh  = 30
+	unitVAh = 31
+	unitA   = 33
+	unitV   = 35
+	unitHz  = 44
+)
+
+// DefaultConfig is a single phase meter with the objects the processor
+// reads: the clock, the logical device name, the nameplate profile and its
+// objects, the four profiles with rows over the days before now, an event
+// log, a disconnect control and an empty activity calendar and special days
+// table. It uses the keys of the processor's tests.
+func DefaultConfig(now time.Time) Config {
+	now = now.UTC().Truncate(time.Hour)
+	day := now.Truncate(24 * time.Hour)
+	clock := Column{ClassID: classClock, OBIS: dlms.ClockOBIS, Attribute: 2}
+	register := func(obis string) Column { return Column{ClassID: classRegister, OBIS: obis, Attribute: 2} }
+	data := func(obis string) Column { return Column{ClassID: classData, OBIS: obis, Attribute: 2} }
+
+	return Config{
+		SystemTitle:       "534D4C5349303031",
+		BlockCipherKey:    "62626262626262626262626262626262",
+		AuthenticationKey: "62626262626262626262626262626262",
+		ClientAddress:     48,
+		ServerAddress:     1,
+		LogicalDeviceName: "SIM0000000000001",
+		DisconnectControl: DisconnectControlOBIS,
+		ScriptTable:       dlms.TariffScriptTableOBIS,
+		ActivityCalendar:  dlms.ActivityCalendarOBIS,
+		SpecialDaysTable:  dlms.SpecialDaysTableOBIS,
+		Data: []Data{
+			{OBIS: "1.0.0.2.0.255", Value: "SIM-1.0.0"},   // firmware version
+			{OBIS: "0.0.96.1.0.255", Value: "SIM00001"},   // serial number
+			{OBIS: "0.0.96.1.1.255", Value: "SIMULATED"},  // manufacturer name
+			{OBIS: "0.0.94.91.9.255", Value: uint8(5)},    // meter type
+			{OBIS: "0.0.94.91.11.255", Value: "C1"},       // category
+			{OBIS: "0.0.94.91.12.255", Value: "5-30A"},    // current rating
+			{OBIS: "0.0.96.1.4.255", Value: uint16(2024)}, // year of manufacture
+		},
+		Registers: []Register{
+			{OBIS: "1.0.1.8.0.255", Value: 12345.6, Unit: unitWh},
+			{OBIS: "1.0.9.8.0.255", Value: 13579.2, Unit: unitVAh},
+			{OBIS: "1.0.12.7.0.255", Value: 230.1, Unit: unitV},
+			{OBIS: "1.0.11.7.0.255", Value: 4.2, Unit: unitA},
+			{OBIS: "1.0.14.7.0.255", Value: 50.0, Unit: unitHz},
+			{OBIS: "1.0.1.7.0.255", Value: 960.0, Unit: unitW},
+			{OBIS: "1.0.9.7.0.255", Value: 966.4, Unit: unitVA},
+		},
+		Profiles: []Profile{
+			{
+				OBIS:          BlockLoadProfileOBIS,
+				CapturePeriod: 1800,
+				Columns: []Column{
+					clock,
+					register("1.0.12.27.0.255"),
+					register("1.0.1.29.0.255"),
+					register("1.0.9.29.0.255"),
+					register("1.0.2.29.0.255"),
+					register("1.0.10.29.0.255"),
+					register("1.0.11.27.0.255"),
+					{ClassID: classData, OBIS: "0.0.96.10.1.255", Attribute: 2},
+				},
+				Rows: [][]any{
+					{now.Add(-time.Hour), 229.8, 480.0, 483.5, 0.0, 0.0, 2.1, uint8(0)},
+					{now.Add(-30 * time.Minute), 230.4, 495.0, 498.2, 0.0, 0.0, 2.2, uint8(0)},
+				},
+			},
+			{
+				OBIS:          DailyLoadProfileOBIS,
+				CapturePeriod: 86400,
+				Columns: []Column{
+					clock,
+					register("1.0.2.8.0.255"),
+					register("1.0.10.8.0.255"),
+					register("1.0.1.8.0.255"),
+					register("1.0.9.8.0.255"),
+				},
+				Rows: [][]any{
+					{day.Add(-48 * time.Hour), 0.0, 0.0, 11865.6, 13095.0},
+					{day.Add(-24 * time.Hour), 0.0, 0.0, 12105.6, 13337.1},
+				},
+			},
+			{
+				OBIS:          BillingProfileOBIS,
+				CapturePeriod: 0,
+				Columns: []Column{
+					{ClassID: classData, OBIS: "0.0.0.1.2.255", Attribute: 2},
+					register("1.0.13.0.0.255"),
+					register("1.0.1.8.0.255"),
+					register("1.0.1.8.1.255"),
+					register("1.0.1.8.2.255"),
+					register("1.0.1.8.3.255"),
+					register("1.0.1.8.4.255"),
+					register("1.0.9.8.0.255"),
+					register("1.0.9.8.1.255"),
+					register("1.0.9.8.2.255"),
+					register("1.0.9.8.3.255"),
+					register("1.0.9.8.4.255"),
+					{ClassID: classExtendedRegister, OBIS: "1.0.1.6.0.255", Attribute: 2},
+					{ClassID: classExtendedRegister, OBIS: "1.0.1.6.0.255", Attribute: 5},
+					{ClassID: classExtendedRegister, OBIS: "1.0.9.6.0.255", Attribute: 2},
+					{ClassID: classExtendedRegister, OBIS: "1.0.9.6.0.255", Attribute: 5},
+					register("0.0.94.91.13.255"),
+					register("1.0.2.8.0.255"),
+					register("1.0.10.8.0.255"),
+				},
+				Rows: [][]any{
+					{day.AddDate(0, -1, 0), 0.97, 11000.0, 2750.0, 2750.0, 2750.0, 2750.0,
+						12100.0, 3025.0, 3025.0, 3025.0, 3025.0, 2400.0, day.AddDate(0, -1, 9).Add(19 * time.Hour),
+						2480.0, day.AddDate(0, -1, 9).Add(19 * time.Hour), 720.0, 0.0, 0.0},
+				},
+			},
+			{
+				OBIS: InstantaneousProfileOBIS,
+				Columns: []Column{
+					clock,
+					register("1.0.12.7.0.255"),
+					register("1.0.11.7.0.255"),
+					register("1.0.91.7.0.255"),
+					register("1.0.13.7.0.255"),
+					register("1.0.14.7.0.255"),
+					register("1.0.9.7.0.255"),
+					register("1.0.1.7.0.255"),
+					register("1.0.1.8.0.255"),
+					register("1.0.9.8.0.255"),
+				},
+				Rows: [][]any{
+					{now, 230.1, 4.2, 4.1, 0.99, 50.0, 966.4, 960.0, 12345.6, 13579.2},
+				},
+			},
+			{
+				OBIS: dlms.NameplateProfileOBIS,
+				Columns: []Column{
+					data("0.0.96.1.0.255"),
+					data("0.0.96.1.1.255"),
+					data("1.0.0.2.0.255"),
+					data("0.0.94.91.9.255"),
+					data("0.0.94.91.11.255"),
+					data("0.0.94.91.12.255"),
+					data("0.0.96.1.4.255"),
+				},
+				Rows: [][]any{
+					{"SIM00001", "SIMULATED", "SIM-1.0.0", uint8(5), "C1", "5-30A", uint16(2024)},
+				},
+			},
+			{
+				OBIS:    EventLogOBIS,
+				Columns: []Column{clock, {ClassID: classData, OBIS: EventCodeOBIS, Attribute: 2}},
+				Rows: [][]any{
+					{day.Add(-36 * time.Hour), uint16(101)}, // power failure
+					{day.Add(-35 * time.Hour), uint16(102)}, // power restored
+				},
+			},
+		},
+	}
+}
+
+// ThreePhaseConfig is the default model as a three-phase meter: its
+// instantaneous and block load profiles capture R, Y and B phase values
+func ThreePhaseConfig(now time.Time) Config {
+	config := DefaultConfig(now)
+	now = now.UTC().Truncate(time.Hour)
+	clock := Column{ClassID: classClock, OBIS: dlms.ClockOBIS, Attribute: 2}
+	registers := func(obis ...string) []Column {
+		columns := []Column{clock}
+		for _, o := range obis {
+			columns = append(columns, Column{ClassID: classRegister, OBIS: o, Attribute: 2})
+		}
+		return columns
+	}
+
+	for i, p := range config.Profiles {
+		switch p.OBIS {
+		case BlockLoadProfileOBIS:
+			config.Profiles[i].Columns = registers(
+				"1.0.31.27.0.255", "1.0.51.27.0.255", "1.0.71.27.0.255",
+				"1.0.32.27.0.255", "1.0.52.27.0.255", "1.0.72.27.0.255",
+				"1.0.1.29.0.255", "1.0.5.29.0.255", "1.0.8.29.0.255",
+				"1.0.9.29.0.255", "1.0.2.29.0.255", "1.0.10.29.0.255")
+			config.Profiles[i].Rows = [][]any{
+				{now.Add(-time.Hour), 10.1, 9.8, 10.4, 231.2, 229.7, 230.5, 3500.0, 620.0, 15.0, 3560.0, 0.0, 0.0},
+				{now.Add(-30 * time.Minute), 10.6, 10.2, 10.9, 230.8, 229.9, 230.1, 3640.0, 650.0, 12.0, 3700.0, 0.0, 0.0},
+			}
+		case InstantaneousProfileOBIS:
+			config.Profiles[i].Columns = registers(
+				"1.0.31.7.0.255", "1.0.51.7.0.255", "1.0.71.7.0.255",
+				"1.0.32.7.0.255", "1.0.52.7.0.255", "1.0.72.7.0.255",
+				"1.0.33.7.0.255", "1.0.53.7.0.255", "1.0.73.7.0.255",
+				"1.0.13.7.0.255", "1.0.14.7.0.255",
+				"1.0.21.7.0.255", "1.0.41.7.0.255", "1.0.61.7.0.255",
+				"1.0.23.7.0.255", "1.0.43.7.0.255", "1.0.63.7.0.255",
+				"1.0.29.7.0.255", "1.0.49.7.0.255", "1.0.69.7.0.255",
+				"1.0.1.7.0.255", "1.0.3.7.0.255", "1.0.9.7.0.255",
+				"1.0.1.8.0.255", "1.0.2.8.0.255", "1.0.5.8.0.255", "1.0.8.8.0.255", "1.0.9.8.0.255", "1.0.10.8.0.255")
+			config.Profiles[i].Rows = [][]any{
+				{now, 10.5, 10.1, 10.8, 230.9, 229.8, 230.3, 0.98, 0.97, 0.99, 0.98, 50.0,
+					2376.0, 2252.0, 2462.0, 480.0, 560.0, 350.0, 2424.0, 2321.0, 2487.0,
+					7090.0, 1390.0, 7232.0, 1234567.0, 0.0, 210345.0, 4120.0, 1260012.0, 0.0},
+			}
+		}
+	}
+	return config
+}
+
+// WithTariffZones replaces the billing profile of a model with one capturing
+// zones tariff zones, with energies growing with the zone and each zone's
+// maximum demand captured on the zone's day of the billing month
+func WithTariffZones(config Config, now time.Time, zones int) Config {
+	month := now.UTC().Truncate(24*time.Hour).AddDate(0, -1, 0)
+	register := func(obis string) Column { return Column{ClassID: classRegister, OBIS: obis, Attribute: 2} }
+	var columns []Column
+	var row []any
+	add := func(column Column, value any) {
+		columns = append(columns, column)
+		row = append(row, value)
+	}
+	demand := func(obis string, value float64, at time.Time) {
+		add(Column{ClassID: classExtendedRegister, OBIS: obis, Attribute: 2}, value)
+		add(Column{ClassID: classExtendedRegister, OBIS: obis, Attribute: 5}, at)
+	}
+	// The zones' energies add up to the totals, 250 Wh and 275 VAh times n(n+1)
+	total := float64(zones * (zones + 1))
+	demandAt := func(zone int) time.Time { return month.AddDate(0, 0, zone-1).Add(19 * time.Hour) }
+
+	add(Column{ClassID: classData, OBIS: "0.0.0.1.2.255", Attribute: 2}, month)
+	add(register("1.0.13.0.0.255"), 0.97)
+	add(register("1.0.1.8.0.255"), 250*total)
+	for zone := 1; zone <= zones; zone++ {
+		add(register(fmt.Sprintf("1.0.1.8.%d.255", zone)), 500*float64(zone))
+	}
+	add(register("1.0.9.8.0.255"), 275*total)
+	for zone := 1; zone <= zones; zone++ {
+		add(register(fmt.Sprintf("1.0.9.8.%d.255", zone)), 550*float64(zone))
+	}
+	demand("1.0.1.6.0.255", 2000+50*float64(zones), demandAt(zones))
+	demand("1.0.9.6.0.255", 2060+50*float64(zones), demandAt(zones))
+	for zone := 1; zone <= zones; zone++ {
+		demand(fmt.Sprintf("1.0.1.6.%d.255", zone), 2000+50*float64(zone), demandAt(zone))
+		demand(fmt.Sprintf("1.0.9.6.%d.255", zone), 2060+50*float64(zone), demandAt(zone))
+	}
+
+	for i, p := range config.Profiles {
+		if p.OBIS == BillingProfileOBIS {
+			config.Profiles[i].Columns = columns
+			config.Profiles[i].Rows = [][]any{row}
+		}
+	}
+	return config
+}
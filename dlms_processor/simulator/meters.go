@@ -0,0 +1,102 @@
+package simulator
+
+import (
+	"dlmsprocessor/dlms"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Meters is a dlms.MeterFactory that talks to simulated meters. It starts a
+// simulated meter for each meter it is asked for, with the meter's keys,
+// addresses and logical device name where the request sets them, and
+// reaches it over TCP as it would the real one.
+type Meters struct {
+	model func(now time.Time) Config
+
+	mu     sync.Mutex
+	meters map[string]*Meter
+	closed bool
+}
+
+// NewMeters returns a factory of simulated meters of model, DefaultConfig if
+// nil
+func NewMeters(model func(now time.Time) Config) *Meters {
+	if model == nil {
+		model = DefaultConfig
+	}
+	return &Meters{model: model, meters: make(map[string]*Meter)}
+}
+
+// NewMeter returns a meter talking to the simulation of config's meter
+func (s *Meters) NewMeter(config dlms.RealMeter, sessions *dlms.SessionPool) (dlms.Meter, error) {
+	m, err := s.simulate(config)
+	if err != nil {
+		return nil, err
+	}
+
+	sim := m.RealMeter()
+	config.MeterIP, config.MeterPort = sim.MeterIP, sim.MeterPort
+	if config.BlockCipherKey == "" {
+		config.BlockCipherKey, config.AuthenticationKey = sim.BlockCipherKey, sim.AuthenticationKey
+	}
+	if config.SystemTitle == "" {
+		config.SystemTitle = sim.SystemTitle
+	}
+	if config.ClientAddress == 0 {
+		config.ClientAddress, config.ServerAddress = sim.ClientAddress, sim.ServerAddress
+	}
+	return dlms.RealMeters{}.NewMeter(config, sessions)
+}
+
+// simulate returns the simulated meter of config, starting it the first time
+func (s *Meters) simulate(config dlms.RealMeter) (*Meter, error) {
+	key := config.MeterID
+	if key == "" {
+		key = fmt.Sprintf("%s:%d", config.MeterIP, config.MeterPort)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return nil, fmt.Errorf("simulated meters are closed")
+	}
+	if m, ok := s.meters[key]; ok {
+		return m, nil
+	}
+
+	model := s.model(time.Now())
+	if config.BlockCipherKey != "" {
+		model.BlockCipherKey, model.AuthenticationKey = config.BlockCipherKey, config.AuthenticationKey
+	}
+	if config.ClientAddress != 0 {
+		model.ClientAddress, model.ServerAddress = config.ClientAddress, config.ServerAddress
+	}
+	if config.LogicalDeviceName != "" {
+		model.LogicalDeviceName = config.LogicalDeviceName
+	}
+	m, err := Start(model, "127.0.0.1:0")
+	if err != nil {
+		return nil, fmt.Errorf("simulating %s: %w", config, err)
+	}
+	s.meters[key] = m
+	return m, nil
+}
+
+// Close stops every simulated meter
+func (s *Meters) Close() error {
+	s.mu.Lock()
+	meters := s.meters
+	s.meters = nil
+	s.closed = true
+	s.mu.Unlock()
+
+	var first error
+	for _, m := range meters {
+		if err := m.Close(); err != nil && first == nil {
+			first = err
+		}
+	}
+	return first
+}
@@ -0,0 +1,157 @@
+package simulator
+
+import (
+	"context"
+	"dlmsprocessor/dlms"
+	"flag"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"testing"
+	"time"
+)
+
+var fixture = flag.String("fixture", "", "also save the recorded conversation to this file")
+
+// testKeys replace the simulator's keys in the recordings
+const testKeys = "000102030405060708090a0b0c0d0e0f"
+
+type event struct {
+	Time string `obis:"0.0.1.0.0.255" type:"string"`
+	Code uint16 `obis:"0.0.96.11.0.255" type:"uint16"`
+}
+
+// reading is what a recorded session reads from the default model
+type reading struct {
+	Blocks []dlms.BlockLoadProfile
+	Events []event
+}
+
+// read reads the block load profile and the event log
+func read(ctx context.Context, client *dlms.MeterClient) (reading, error) {
+	blocks, err := dlms.ReadProfileDataTyped[dlms.BlockLoadProfile](ctx, client, BlockLoadProfileOBIS, 1, 2)
+	if err != nil {
+		return reading{}, err
+	}
+	events, err := dlms.ReadProfileDataTyped[event](ctx, client, EventLogOBIS, 1, 2)
+	if err != nil {
+		return reading{}, err
+	}
+	return reading{blocks, events}, nil
+}
+
+// fraction is the fraction of a second of a formatted datetime. The Gurux
+// library formats a fraction that is not on the wire, so it is ignored.
+var fraction = regexp.MustCompile(`\.[0-9]+ `)
+
+// seconds drops the fractions of a second from the datetimes of r
+func (r reading) seconds() reading {
+	for i := range r.Blocks {
+		r.Blocks[i].DateTime = fraction.ReplaceAllString(r.Blocks[i].DateTime, " ")
+	}
+	for i := range r.Events {
+		r.Events[i].Time = fraction.ReplaceAllString(r.Events[i].Time, " ")
+	}
+	return r
+}
+
+// record reads a simulated meter with its frames traced, returning what it
+// read and the recorded conversation
+func record(t *testing.T) (reading, *dlms.Recording) {
+	t.Helper()
+	m, err := Start(DefaultConfig(time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)), "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer m.Close()
+
+	config := m.RealMeter()
+	client := dlms.NewMeterClient()
+	defer client.Close()
+	if err := client.Configure(&config); err != nil {
+		t.Fatal(err)
+	}
+	trace := dlms.NewFrameTrace()
+	ctx := dlms.WithFrameTrace(context.Background(), trace)
+	if err := client.Connect(ctx); err != nil {
+		t.Fatalf("Connect: %v", err)
+	}
+	live, err := read(ctx, client)
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+
+	recording, err := trace.Recording()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return live, recording
+}
+
+// replayClient returns a client connected to a replay of recording
+func replayClient(t *testing.T, recording *dlms.Recording) (*dlms.MeterClient, *dlms.Replay) {
+	t.Helper()
+	replay, err := dlms.NewReplay(recording)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := dlms.NewMeterClient()
+	t.Cleanup(client.Close)
+	if err := client.Replay(replay); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.Connect(context.Background()); err != nil {
+		t.Fatalf("Connect: %v (%v)", err, replay.Err())
+	}
+	return client, replay
+}
+
+func TestRecordAndReplay(t *testing.T) {
+	live, recording := record(t)
+
+	recording, err := recording.Rekey(testKeys, testKeys)
+	if err != nil {
+		t.Fatalf("Rekey: %v", err)
+	}
+	path := filepath.Join(t.TempDir(), "recording.json")
+	if *fixture != "" {
+		path = *fixture
+	}
+	if err := recording.Save(path); err != nil {
+		t.Fatal(err)
+	}
+	recording, err = dlms.LoadRecording(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	client, replay := replayClient(t, recording)
+	replayed, err := read(context.Background(), client)
+	if err != nil {
+		t.Fatalf("read: %v (%v)", err, replay.Err())
+	}
+	if !reflect.DeepEqual(replayed.seconds(), live.seconds()) {
+		t.Errorf("Expected the replay to read %+v, got %+v", live, replayed)
+	}
+	if replay.Err() != nil || replay.Remaining() != 0 {
+		t.Errorf("Expected the whole recording replayed, %d frames left: %v", replay.Remaining(), replay.Err())
+	}
+}
+
+func TestReplayRefusesDeviation(t *testing.T) {
+	_, recording := record(t)
+
+	client, replay := replayClient(t, recording)
+	if _, err := client.ReadAttribute(context.Background(), dlms.LogicalDeviceNameOBIS, dlms.ObjectTypeData, 2); err == nil {
+		t.Fatal("Expected a read the recording does not have to fail")
+	}
+	if replay.Err() == nil {
+		t.Error("Expected the replay to report the deviation")
+	}
+
+	// The recording's keys are the only ones that replay it
+	recording.BlockCipherKey = testKeys
+	if _, err := dlms.NewReplay(recording); err == nil {
+		t.Error("Expected a recording with the wrong keys to be refused")
+	}
+}
@@ -0,0 +1,525 @@
+#include <stdlib.h>
+#include <string.h>
+#include <time.h>
+
+#include "sim.h"
+#include "server.h"
+#include "cosem.h"
+#include "gxkey.h"
+#include "objectarray.h"
+#include "variant.h"
+#include "date.h"
+
+// Buffer sizes of a session: one wrapper frame, the negotiated PDU, and the
+// PDU buffer, which holds a whole attribute before it is sent in PDU sized
+// blocks, as long capture object lists are
+#define SIM_FRAME_SIZE 2048
+#define SIM_PDU_SIZE 1024
+#define SIM_PDU_BUFFER_SIZE 8192
+
+// Logical names of the association and its security setup
+static const unsigned char ASSOCIATION_LN[6] = {0, 0, 40, 0, 0, 255};
+static const unsigned char SECURITY_SETUP_LN[6] = {0, 0, 43, 0, 0, 255};
+
+struct sim {
+    objectArray objects;         // the object model, owned
+    unsigned char system_title[8];
+    unsigned char block_key[16];
+    unsigned char auth_key[16];
+    int client_address;
+    int server_address;
+};
+
+struct sim_session {
+    dlmsServerSettings settings; // first, so the server callbacks can find the session
+    sim_t* sim;
+    gxAssociationLogicalName association;
+    gxSecuritySetup security_setup;
+    gxByteBuffer reply;
+    unsigned char frame[SIM_FRAME_SIZE];
+    unsigned char pdu[SIM_PDU_BUFFER_SIZE];
+};
+
+struct sim_row {
+    variantArray values;
+};
+
+sim_t* sim_create(const unsigned char* system_title, const unsigned char* block_key,
+                  const unsigned char* auth_key, int client_address, int server_address) {
+    sim_t* sim = calloc(1, sizeof(sim_t));
+    if (!sim) return NULL;
+    oa_init(&sim->objects);
+    memcpy(sim->system_title, system_title, 8);
+    memcpy(sim->block_key, block_key, 16);
+    memcpy(sim->auth_key, auth_key, 16);
+    sim->client_address = client_address;
+    sim->server_address = server_address;
+    return sim;
+}
+
+void sim_free(sim_t* sim) {
+    if (!sim) return;
+    oa_clear(&sim->objects, 1);
+    free(sim);
+}
+
+// Creates an object of type at ln and adds it to the model
+static int add_object(sim_t* sim, DLMS_OBJECT_TYPE type, const unsigned char* ln, gxObject** object) {
+    gxObject* existing = NULL;
+    if (oa_findByLN(&sim->objects, type, ln, &existing) == 0 && existing != NULL) {
+        return DLMS_ERROR_CODE_INVALID_PARAMETER;
+    }
+    int ret = cosem_createObject(type, object);
+    if (ret != 0) return ret;
+    if ((ret = cosem_setLogicalName(*object, ln)) != 0 || (ret = oa_push(&sim->objects, *object)) != 0) {
+        obj_clear(*object);
+        free(*object);
+        return ret;
+    }
+    return 0;
+}
+
+int sim_add_data_string(sim_t* sim, const unsigned char* ln, const char* value) {
+    gxData* data;
+    int ret = add_object(sim, DLMS_OBJECT_TYPE_DATA, ln, (gxObject**)&data);
+    if (ret != 0) return ret;
+    var_clear(&data->value);
+    return var_addBytes(&data->value, (const unsigned char*)value, (uint16_t)strlen(value));
+}
+
+int sim_add_data_uint(sim_t* sim, const unsigned char* ln, int data_type, uint32_t value) {
+    gxData* data;
+    int ret = add_object(sim, DLMS_OBJECT_TYPE_DATA, ln, (gxObject**)&data);
+    if (ret != 0) return ret;
+    switch (data_type) {
+    case DLMS_DATA_TYPE_UINT8:
+        return var_setUInt8(&data->value, (unsigned char)value);
+    case DLMS_DATA_TYPE_UINT16:
+        return var_setUInt16(&data->value, (uint16_t)value);
+    case DLMS_DATA_TYPE_ENUM:
+        return var_setEnum(&data->value, (unsigned char)value);
+    }
+    return var_setUInt32(&data->value, value);
+}
+
+int sim_add_register(sim_t* sim, const unsigned char* ln, double value, signed char scaler, unsigned char unit) {
+    gxRegister* reg;
+    int ret = add_object(sim, DLMS_OBJECT_TYPE_REGISTER, ln, (gxObject**)&reg);
+    if (ret != 0) return ret;
+    reg->scaler = scaler;
+    reg->unit = unit;
+    return var_setDouble(&reg->value, value);
+}
+
+int sim_add_clock(sim_t* sim, const unsigned char* ln, int16_t time_zone) {
+    gxClock* clock;
+    int ret = add_object(sim, DLMS_OBJECT_TYPE_CLOCK, ln, (gxObject**)&clock);
+    if (ret != 0) return ret;
+    clock->timeZone = time_zone;
+    clock->enabled = 1;
+    time_initUnix(&clock->time, (uint32_t)time(NULL));
+    return 0;
+}
+
+int sim_add_disconnect_control(sim_t* sim, const unsigned char* ln, int connected) {
+    gxDisconnectControl* dc;
+    int ret = add_object(sim, DLMS_OBJECT_TYPE_DISCONNECT_CONTROL, ln, (gxObject**)&dc);
+    if (ret != 0) return ret;
+    dc->outputState = connected ? 1 : 0;
+    dc->controlState = connected ? DLMS_CONTROL_STATE_CONNECTED : DLMS_CONTROL_STATE_DISCONNECTED;
+    dc->controlMode = DLMS_CONTROL_MODE_MODE_4;
+    return 0;
+}
+
+int sim_add_profile(sim_t* sim, const unsigned char* ln, uint32_t capture_period) {
+    gxProfileGeneric* pg;
+    int ret = add_object(sim, DLMS_OBJECT_TYPE_PROFILE_GENERIC, ln, (gxObject**)&pg);
+    if (ret != 0) return ret;
+    pg->capturePeriod = capture_period;
+    pg->sortMethod = DLMS_SORT_METHOD_FIFO;
+    return 0;
+}
+
+int sim_add_script_table(sim_t* sim, const unsigned char* ln) {
+    gxObject* obj;
+    return add_object(sim, DLMS_OBJECT_TYPE_SCRIPT_TABLE, ln, &obj);
+}
+
+int sim_add_activity_calendar(sim_t* sim, const unsigned char* ln) {
+    gxObject* obj;
+    return add_object(sim, DLMS_OBJECT_TYPE_ACTIVITY_CALENDAR, ln, &obj);
+}
+
+int sim_add_special_days_table(sim_t* sim, const unsigned char* ln) {
+    gxObject* obj;
+    return add_object(sim, DLMS_OBJECT_TYPE_SPECIAL_DAYS_TABLE, ln, &obj);
+}
+
+static gxProfileGeneric* find_profile(sim_t* sim, const unsigned char* ln) {
+    gxObject* obj = NULL;
+    if (oa_findByLN(&sim->objects, DLMS_OBJECT_TYPE_PROFILE_GENERIC, ln, &obj) != 0) return NULL;
+    return (gxProfileGeneric*)obj;
+}
+
+int sim_profile_add_column(sim_t* sim, const unsigned char* profile_ln, int class_id,
+                           const unsigned char* ln, int attribute) {
+    gxProfileGeneric* pg = find_profile(sim, profile_ln);
+    if (!pg || pg->buffer.size != 0) return DLMS_ERROR_CODE_INVALID_PARAMETER;
+
+    gxObject* obj = NULL;
+    int ret = oa_findByLN(&sim->objects, (DLMS_OBJECT_TYPE)class_id, ln, &obj);
+    if (ret != 0) return ret;
+    if (obj == NULL && (ret = add_object(sim, (DLMS_OBJECT_TYPE)class_id, ln, &obj)) != 0) {
+        return ret;
+    }
+    return arr_push(&pg->captureObjects, key_init(obj, co_init((unsigned char)attribute, 0)));
+}
+
+sim_row_t* sim_row_new(void) {
+    sim_row_t* row = malloc(sizeof(sim_row_t));
+    if (row) va_init(&row->values);
+    return row;
+}
+
+void sim_row_free(sim_row_t* row) {
+    if (!row) return;
+    va_clear(&row->values);
+    free(row);
+}
+
+// Appends a new value to row
+static dlmsVARIANT* row_push(sim_row_t* row) {
+    dlmsVARIANT* value = malloc(sizeof(dlmsVARIANT));
+    if (!value) return NULL;
+    var_init(value);
+    if (va_push(&row->values, value) != 0) {
+        free(value);
+        return NULL;
+    }
+    return value;
+}
+
+int sim_row_add_double(sim_row_t* row, double value) {
+    dlmsVARIANT* v = row_push(row);
+    return v ? var_setDouble(v, value) : DLMS_ERROR_CODE_OUTOFMEMORY;
+}
+
+int sim_row_add_uint(sim_row_t* row, int data_type, uint32_t value) {
+    dlmsVARIANT* v = row_push(row);
+    if (!v) return DLMS_ERROR_CODE_OUTOFMEMORY;
+    switch (data_type) {
+    case DLMS_DATA_TYPE_UINT8:
+        return var_setUInt8(v, (unsigned char)value);
+    case DLMS_DATA_TYPE_UINT16:
+        return var_setUInt16(v, (uint16_t)value);
+    case DLMS_DATA_TYPE_ENUM:
+        return var_setEnum(v, (unsigned char)value);
+    }
+    return var_setUInt32(v, value);
+}
+
+int sim_row_add_datetime(sim_row_t* row, int64_t unix_seconds) {
+    dlmsVARIANT* v = row_push(row);
+    if (!v) return DLMS_ERROR_CODE_OUTOFMEMORY;
+    gxtime t;
+    memset(&t, 0, sizeof(t));
+    time_initUnix(&t, (uint32_t)unix_seconds);
+    return var_setDateTimeAsOctetString(v, &t);
+}
+
+int sim_row_add_string(sim_row_t* row, const char* value) {
+    dlmsVARIANT* v = row_push(row);
+    return v ? var_addBytes(v, (const unsigned char*)value, (uint16_t)strlen(value)) : DLMS_ERROR_CODE_OUTOFMEMORY;
+}
+
+int sim_profile_add_row(sim_t* sim, const unsigned char* profile_ln, sim_row_t* row) {
+    gxProfileGeneric* pg = find_profile(sim, profile_ln);
+    if (!pg || row->values.size != pg->captureObjects.size) {
+        sim_row_free(row);
+        return DLMS_ERROR_CODE_INVALID_PARAMETER;
+    }
+    variantArray* values = malloc(sizeof(variantArray));
+    if (!values) {
+        sim_row_free(row);
+        return DLMS_ERROR_CODE_OUTOFMEMORY;
+    }
+    *values = row->values;
+    free(row);
+    int ret = arr_push(&pg->buffer, values);
+    if (ret != 0) {
+        va_clear(values);
+        free(values);
+        return ret;
+    }
+    pg->entriesInUse = pg->profileEntries = pg->buffer.size;
+    return 0;
+}
+
+int sim_disconnect_state(sim_t* sim, const unsigned char* ln, int* output_state, int* control_state) {
+    gxObject* obj = NULL;
+    if (oa_findByLN(&sim->objects, DLMS_OBJECT_TYPE_DISCONNECT_CONTROL, ln, &obj) != 0 || obj == NULL) {
+        return DLMS_ERROR_CODE_INVALID_PARAMETER;
+    }
+    gxDisconnectControl* dc = (gxDisconnectControl*)obj;
+    *output_state = dc->outputState;
+    *control_state = dc->controlState;
+    return 0;
+}
+
+sim_session_t* sim_session_open(sim_t* sim) {
+    sim_session_t* s = calloc(1, sizeof(sim_session_t));
+    if (!s) return NULL;
+    s->sim = sim;
+    bb_init(&s->reply);
+
+    svr_init(&s->settings, 1, DLMS_INTERFACE_TYPE_WRAPPER, SIM_FRAME_SIZE, SIM_PDU_SIZE,
+             s->frame, SIM_FRAME_SIZE, s->pdu, SIM_PDU_BUFFER_SIZE);
+
+    ciphering* cipher = &s->settings.base.cipher;
+    cipher->security = DLMS_SECURITY_AUTHENTICATION_ENCRYPTION;
+    bb_clear(&cipher->systemTitle);
+    bb_set(&cipher->systemTitle, sim->system_title, 8);
+    bb_clear(&cipher->blockCipherKey);
+    bb_set(&cipher->blockCipherKey, sim->block_key, 16);
+    bb_clear(&cipher->authenticationKey);
+    bb_set(&cipher->authenticationKey, sim->auth_key, 16);
+
+    // The association and its security setup hold the state of this
+    // connection; the rest of the model is shared
+    cosem_init2((gxObject*)&s->security_setup, DLMS_OBJECT_TYPE_SECURITY_SETUP, SECURITY_SETUP_LN);
+    s->security_setup.securityPolicy = DLMS_SECURITY_POLICY_AUTHENTICATED_ENCRYPTED;
+    s->security_setup.securitySuite = DLMS_SECURITY_SUITE_V0;
+    bb_set(&s->security_setup.serverSystemTitle, sim->system_title, 8);
+
+    gxAssociationLogicalName* a = &s->association;
+    cosem_init2((gxObject*)a, DLMS_OBJECT_TYPE_ASSOCIATION_LOGICAL_NAME, ASSOCIATION_LN);
+    a->clientSAP = (signed char)sim->client_address;
+    a->serverSAP = (uint16_t)sim->server_address;
+    a->authenticationMechanismName.mechanismId = DLMS_AUTHENTICATION_HIGH_GMAC;
+    a->applicationContextName.contextId = DLMS_APPLICATION_CONTEXT_NAME_LOGICAL_NAME_WITH_CIPHERING;
+    a->xDLMSContextInfo.conformance = (DLMS_CONFORMANCE)(DLMS_CONFORMANCE_BLOCK_TRANSFER_WITH_GET_OR_READ |
+        DLMS_CONFORMANCE_BLOCK_TRANSFER_WITH_SET_OR_WRITE | DLMS_CONFORMANCE_BLOCK_TRANSFER_WITH_ACTION |
+        DLMS_CONFORMANCE_MULTIPLE_REFERENCES | DLMS_CONFORMANCE_GET | DLMS_CONFORMANCE_SET |
+        DLMS_CONFORMANCE_SELECTIVE_ACCESS | DLMS_CONFORMANCE_ACTION);
+    a->xDLMSContextInfo.maxReceivePduSize = SIM_PDU_SIZE;
+    a->xDLMSContextInfo.maxSendPduSize = SIM_PDU_SIZE;
+    a->xDLMSContextInfo.dlmsVersionNumber = 6;
+    a->securitySetup = &s->security_setup;
+    oa_copy(&a->objectList, &sim->objects);
+
+    oa_copy(&s->settings.base.objects, &sim->objects);
+    oa_push(&s->settings.base.objects, (gxObject*)a);
+    oa_push(&s->settings.base.objects, (gxObject*)&s->security_setup);
+
+    if (svr_initialize(&s->settings) != 0) {
+        sim_session_close(s);
+        return NULL;
+    }
+    return s;
+}
+
+int sim_session_handle(sim_session_t* s, const unsigned char* data, int size) {
+    bb_clear(&s->reply);
+    return svr_handleRequest2(&s->settings, (unsigned char*)data, (uint16_t)size, &s->reply);
+}
+
+const unsigned char* sim_session_reply(sim_session_t* s, int* size) {
+    *size = (int)s->reply.size;
+    return s->reply.data;
+}
+
+void sim_session_close(sim_session_t* s) {
+    if (!s) return;
+    // The shared objects belong to the meter
+    oa_empty(&s->association.objectList);
+    oa_empty(&s->settings.base.objects);
+    s->association.securitySetup = NULL;
+    obj_clear((gxObject*)&s->association);
+    obj_clear((gxObject*)&s->security_setup);
+    svr_clear(&s->settings);
+    bb_clear(&s->reply);
+    free(s);
+}
+
+// The session of the server callbacks' settings
+static sim_session_t* session_of(dlmsSettings* settings) {
+    return (sim_session_t*)settings;
+}
+
+// ---------------- Gurux server callbacks ----------------
+
+unsigned char svr_isTarget(dlmsSettings* settings, uint32_t serverAddress, uint32_t clientAddress) {
+    sim_session_t* s = session_of(settings);
+    if ((int)serverAddress != s->sim->server_address || (int)clientAddress != s->association.clientSAP) {
+        return 0;
+    }
+    settings->proposedConformance = s->association.xDLMSContextInfo.conformance;
+    settings->expectedSecurityPolicy = s->security_setup.securityPolicy;
+    settings->expectedSecuritySuite = s->security_setup.securitySuite;
+    settings->expectedClientSystemTitle = NULL;
+    return 1;
+}
+
+DLMS_ACCESS_MODE svr_getAttributeAccess(dlmsSettings* settings, gxObject* obj, unsigned char index) {
+    return index == 1 ? DLMS_ACCESS_MODE_READ : DLMS_ACCESS_MODE_READ_WRITE;
+}
+
+DLMS_METHOD_ACCESS_MODE svr_getMethodAccess(dlmsSettings* settings, gxObject* obj, unsigned char index) {
+    return DLMS_METHOD_ACCESS_MODE_ACCESS;
+}
+
+int svr_connected(dlmsServerSettings* settings) {
+    // The client's release request repeats the negotiated PDU size, where the
+    // server expects the one proposed in the AARQ; meters accept either
+    settings->base.clientPduSize = settings->base.maxPduSize;
+    return 0;
+}
+int svr_invalidConnection(dlmsServerSettings* settings) { return 0; }
+int svr_disconnected(dlmsServerSettings* settings) { return 0; }
+
+DLMS_SOURCE_DIAGNOSTIC svr_validateAuthentication(dlmsServerSettings* settings,
+                                                  DLMS_AUTHENTICATION authentication, gxByteBuffer* password) {
+    // HIGH_GMAC is checked by the server itself when the client replies to
+    // the challenge
+    return authentication == DLMS_AUTHENTICATION_HIGH_GMAC ? DLMS_SOURCE_DIAGNOSTIC_NONE
+                                                           : DLMS_SOURCE_DIAGNOSTIC_AUTHENTICATION_MECHANISM_NAME_NOT_RECOGNISED;
+}
+
+int svr_findObject(dlmsSettings* settings, DLMS_OBJECT_TYPE objectType, int sn, unsigned char* ln, gxValueEventArg* e) {
+    // The server leaves the association to us: it is the one of the session
+    if (objectType == DLMS_OBJECT_TYPE_ASSOCIATION_LOGICAL_NAME && memcmp(ln, ASSOCIATION_LN, 5) == 0) {
+        e->target = (gxObject*)&session_of(settings)->association;
+    }
+    return 0;
+}
+
+// Sets the rows of pg that a read of its buffer returns. Reads by entry
+// select entries from the first, counting from 1, to the last, 0 meaning
+// the last row; other reads return all rows.
+static void select_rows(gxProfileGeneric* pg, gxValueEventArg* e) {
+    uint32_t first = 1, last = pg->buffer.size;
+    dlmsVARIANT *from, *to;
+    if (e->selector == 2 && e->parameters.vt == DLMS_DATA_TYPE_STRUCTURE &&
+        va_getByIndex(e->parameters.Arr, 0, &from) == 0 && va_getByIndex(e->parameters.Arr, 1, &to) == 0) {
+        if (var_toInteger(from) > 1) first = (uint32_t)var_toInteger(from);
+        if (var_toInteger(to) != 0 && (uint32_t)var_toInteger(to) < last) last = (uint32_t)var_toInteger(to);
+    }
+    // The server numbers rows from 0
+    e->transactionStartIndex = first - 1;
+    e->transactionEndIndex = first <= last ? last : first - 1;
+}
+
+void svr_preRead(dlmsSettings* settings, gxValueEventCollection* args) {
+    gxValueEventArg* e;
+    for (int i = 0; i < args->size; i++) {
+        if (vec_getByIndex(args, i, &e) != 0) continue;
+        // Clocks tell the current time
+        if (e->target->objectType == DLMS_OBJECT_TYPE_CLOCK && e->index == 2) {
+            time_initUnix(&((gxClock*)e->target)->time, (uint32_t)time(NULL));
+        }
+        if (e->target->objectType == DLMS_OBJECT_TYPE_PROFILE_GENERIC && e->index == 2) {
+            select_rows((gxProfileGeneric*)e->target, e);
+        }
+    }
+}
+
+void svr_preGet(dlmsSettings* settings, gxValueEventCollection* args) {}
+void svr_postGet(dlmsSettings* settings, gxValueEventCollection* args) {}
+void svr_preWrite(dlmsSettings* settings, gxValueEventCollection* args) {}
+// copy_bytes makes to a copy of from
+static int copy_bytes(gxByteBuffer* to, const gxByteBuffer* from) {
+    bb_init(to);
+    return from->size == 0 ? 0 : bb_set(to, from->data, from->size);
+}
+
+static int copy_seasons(gxArray* to, gxArray* from) {
+    gxSeasonProfile *s, *c;
+    int ret = obj_clearSeasonProfile(to);
+    for (uint16_t i = 0; ret == 0 && i < from->size; ++i) {
+        if ((ret = arr_getByIndex(from, i, (void**)&s)) != 0) break;
+        if ((c = malloc(sizeof(gxSeasonProfile))) == NULL) return DLMS_ERROR_CODE_OUTOFMEMORY;
+        c->start = s->start;
+        if ((ret = copy_bytes(&c->name, &s->name)) != 0 || (ret = copy_bytes(&c->weekName, &s->weekName)) != 0 ||
+            (ret = arr_push(to, c)) != 0) {
+            bb_clear(&c->name);
+            bb_clear(&c->weekName);
+            free(c);
+        }
+    }
+    return ret;
+}
+
+static int copy_weeks(gxArray* to, gxArray* from) {
+    gxWeekProfile *w, *c;
+    int ret = obj_clearWeekProfileTable(to);
+    for (uint16_t i = 0; ret == 0 && i < from->size; ++i) {
+        if ((ret = arr_getByIndex(from, i, (void**)&w)) != 0) break;
+        if ((c = malloc(sizeof(gxWeekProfile))) == NULL) return DLMS_ERROR_CODE_OUTOFMEMORY;
+        *c = *w;
+        if ((ret = copy_bytes(&c->name, &w->name)) != 0 || (ret = arr_push(to, c)) != 0) {
+            bb_clear(&c->name);
+            free(c);
+        }
+    }
+    return ret;
+}
+
+static int copy_days(gxArray* to, gxArray* from) {
+    gxDayProfile *d, *c;
+    gxDayProfileAction *a, *ca;
+    int ret = obj_clearDayProfileTable(to);
+    for (uint16_t i = 0; ret == 0 && i < from->size; ++i) {
+        if ((ret = arr_getByIndex(from, i, (void**)&d)) != 0) break;
+        if ((c = malloc(sizeof(gxDayProfile))) == NULL) return DLMS_ERROR_CODE_OUTOFMEMORY;
+        c->dayId = d->dayId;
+        arr_init(&c->daySchedules);
+        // Pushed first, so clearing the table frees what is copied so far
+        if ((ret = arr_push(to, c)) != 0) {
+            free(c);
+            break;
+        }
+        for (uint16_t j = 0; ret == 0 && j < d->daySchedules.size; ++j) {
+            if ((ret = arr_getByIndex(&d->daySchedules, j, (void**)&a)) != 0) break;
+            if ((ca = malloc(sizeof(gxDayProfileAction))) == NULL) return DLMS_ERROR_CODE_OUTOFMEMORY;
+            // The script is an object of the model, which the action only points to
+            *ca = *a;
+            if ((ret = arr_push(&c->daySchedules, ca)) != 0) free(ca);
+        }
+    }
+    return ret;
+}
+
+// activate_passive_calendar copies the passive calendar to the active one.
+// The server's own activation leaves both sharing the passive entries, which
+// the next write of the passive calendar frees under the active one.
+static int activate_passive_calendar(gxActivityCalendar* ac) {
+    int ret;
+    bb_clear(&ac->calendarNameActive);
+    if ((ret = copy_bytes(&ac->calendarNameActive, &ac->calendarNamePassive)) != 0 ||
+        (ret = copy_seasons(&ac->seasonProfileActive, &ac->seasonProfilePassive)) != 0 ||
+        (ret = copy_weeks(&ac->weekProfileTableActive, &ac->weekProfileTablePassive)) != 0) {
+        return ret;
+    }
+    return copy_days(&ac->dayProfileTableActive, &ac->dayProfileTablePassive);
+}
+
+void svr_preAction(dlmsSettings* settings, gxValueEventCollection* args) {
+    gxValueEventArg* e;
+    for (int i = 0; i < args->size; i++) {
+        if (vec_getByIndex(args, i, &e) != 0) continue;
+        if (e->target->objectType == DLMS_OBJECT_TYPE_ACTIVITY_CALENDAR && e->index == 1) {
+            e->error = activate_passive_calendar((gxActivityCalendar*)e->target);
+            e->handled = 1;
+        }
+    }
+}
+void svr_postRead(dlmsSettings* settings, gxValueEventCollection* args) {}
+void svr_postWrite(dlmsSettings* settings, gxValueEventCollection* args) {}
+void svr_postAction(dlmsSettings* settings, gxValueEventCollection* args) {}
+void svr_trace(const char* str, const char* data) {}
+
+uint32_t time_elapsed(void) {
+    struct timespec ts;
+    clock_gettime(CLOCK_MONOTONIC, &ts);
+    return (uint32_t)(ts.tv_sec * 1000 + ts.tv_nsec / 1000000);
+}
@@ -0,0 +1,71 @@
+#ifndef SIM_H
+#define SIM_H
+
+#include <stdint.h>
+
+#ifdef __cplusplus
+extern "C" {
+#endif
+
+// A simulated meter: a COSEM object model served by the Gurux server over
+// the TCP wrapper, with one HIGH_GMAC association. Logical names are the 6
+// bytes of an OBIS code.
+typedef struct sim sim_t;
+
+// One client connection to a simulated meter
+typedef struct sim_session sim_session_t;
+
+// A row of profile values, handed over to the profile by sim_profile_add_row
+typedef struct sim_row sim_row_t;
+
+// Creates a meter with the server system title (8 bytes), the global block
+// cipher and authentication keys (16 bytes each) and the wrapper addresses
+sim_t* sim_create(const unsigned char* system_title, const unsigned char* block_key,
+                  const unsigned char* auth_key, int client_address, int server_address);
+void sim_free(sim_t* sim);
+
+// Object model. Each returns 0 or a DLMS error code; adding an object whose
+// logical name is already used for that class fails.
+int sim_add_data_string(sim_t* sim, const unsigned char* ln, const char* value);
+int sim_add_data_uint(sim_t* sim, const unsigned char* ln, int data_type, uint32_t value);
+int sim_add_register(sim_t* sim, const unsigned char* ln, double value, signed char scaler, unsigned char unit);
+int sim_add_clock(sim_t* sim, const unsigned char* ln, int16_t time_zone);
+int sim_add_disconnect_control(sim_t* sim, const unsigned char* ln, int connected);
+int sim_add_profile(sim_t* sim, const unsigned char* ln, uint32_t capture_period);
+
+// Adds an empty script table, activity calendar or special days table. Day
+// actions of the calendar must run a script table in the model.
+int sim_add_script_table(sim_t* sim, const unsigned char* ln);
+int sim_add_activity_calendar(sim_t* sim, const unsigned char* ln);
+int sim_add_special_days_table(sim_t* sim, const unsigned char* ln);
+
+// Adds a capture object to a profile. Objects missing from the model are
+// created, without a value.
+int sim_profile_add_column(sim_t* sim, const unsigned char* profile_ln, int class_id,
+                           const unsigned char* ln, int attribute);
+
+// Builds a row of values for sim_profile_add_row, which takes it over
+sim_row_t* sim_row_new(void);
+int sim_row_add_double(sim_row_t* row, double value);
+int sim_row_add_uint(sim_row_t* row, int data_type, uint32_t value);
+int sim_row_add_datetime(sim_row_t* row, int64_t unix_seconds);
+int sim_row_add_string(sim_row_t* row, const char* value);
+void sim_row_free(sim_row_t* row);
+int sim_profile_add_row(sim_t* sim, const unsigned char* profile_ln, sim_row_t* row);
+
+// State of the disconnect control at ln: output state and control state
+int sim_disconnect_state(sim_t* sim, const unsigned char* ln, int* output_state, int* control_state);
+
+// Sessions. sim_session_handle takes data received from the client and
+// returns what to send back, if anything, from sim_session_reply. All calls
+// on a meter and its sessions must be made under the Gurux lock.
+sim_session_t* sim_session_open(sim_t* sim);
+int sim_session_handle(sim_session_t* session, const unsigned char* data, int size);
+const unsigned char* sim_session_reply(sim_session_t* session, int* size);
+void sim_session_close(sim_session_t* session);
+
+#ifdef __cplusplus
+}
+#endif
+
+#endif // SIM_H
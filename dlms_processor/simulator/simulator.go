@@ -0,0 +1,454 @@
+// Package simulator runs simulated DLMS meters on local TCP ports, so that
+// the processor can be tested end to end without a meter on the network.
+//
+// A simulated meter serves a COSEM object model through the Gurux server
+// over the TCP wrapper, with one HIGH_GMAC association that ciphers with the
+// configured keys: the same security the processor's client uses.
+package simulator
+
+/*
+#cgo CFLAGS: -I${SRCDIR}/../dlms/include
+#cgo LDFLAGS: -L${SRCDIR}/../dlms/lib -lgurux_dlms_c -lm -lpthread
+#include <stdlib.h>
+#include "sim.h"
+#include "enums.h"
+*/
+import "C"
+import (
+	"dlmsprocessor/dlms"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unsafe"
+)
+
+// Config is the object model and security of a simulated meter
+type Config struct {
+	SystemTitle       string // server system title, 8 bytes in hex
+	BlockCipherKey    string // 16 bytes in hex
+	AuthenticationKey string // 16 bytes in hex
+	ClientAddress     int
+	ServerAddress     int
+
+	LogicalDeviceName string // served at 0.0.42.0.0.255 when set
+	TimeZone          int16  // of the clock at 0.0.1.0.0.255, in minutes
+	Data              []Data
+	Registers         []Register
+	Profiles          []Profile
+	DisconnectControl string // OBIS of a disconnect control, none if empty
+	ScriptTable       string // OBIS of a script table for the calendar's day actions, none if empty
+	ActivityCalendar  string // OBIS of an empty activity calendar, none if empty
+	SpecialDaysTable  string // OBIS of an empty special days table, none if empty
+}
+
+// Data is a data object (IC 1) holding a string or an unsigned value
+type Data struct {
+	OBIS  string
+	Value any // string, uint8, uint16 or uint32
+}
+
+// Register is a register object (IC 3)
+type Register struct {
+	OBIS   string
+	Value  float64
+	Scaler int8
+	Unit   uint8 // DLMS unit, e.g. 30 for Wh
+}
+
+// Profile is a profile generic object (IC 7). Its capture objects that are
+// not otherwise in the model are added to it without a value.
+type Profile struct {
+	OBIS          string
+	CapturePeriod uint32 // seconds
+	Columns       []Column
+	Rows          [][]any // one value per column: float64, uint8, uint16, uint32, string or time.Time
+}
+
+// Column is a capture object of a profile
+type Column struct {
+	ClassID   int
+	OBIS      string
+	Attribute int
+}
+
+// Meter is a running simulated meter
+type Meter struct {
+	config   Config
+	sim      *C.sim_t
+	listener net.Listener
+
+	mu    sync.Mutex
+	conns map[net.Conn]struct{}
+	wg    sync.WaitGroup
+}
+
+// Start builds the object model of config and serves it on addr, such as
+// "127.0.0.1:0"
+func Start(config Config, addr string) (*Meter, error) {
+	sim, err := build(config)
+	if err != nil {
+		return nil, err
+	}
+
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		dlms.WithLibraryLock(func() { C.sim_free(sim) })
+		return nil, fmt.Errorf("listening on %s: %w", addr, err)
+	}
+
+	m := &Meter{config: config, sim: sim, listener: listener, conns: make(map[net.Conn]struct{})}
+	m.wg.Add(1)
+	go m.serve()
+	return m, nil
+}
+
+// build creates the C model of config
+func build(config Config) (*C.sim_t, error) {
+	title, err := hexBytes("system title", config.SystemTitle, 8)
+	if err != nil {
+		return nil, err
+	}
+	blockKey, err := hexBytes("block cipher key", config.BlockCipherKey, 16)
+	if err != nil {
+		return nil, err
+	}
+	authKey, err := hexBytes("authentication key", config.AuthenticationKey, 16)
+	if err != nil {
+		return nil, err
+	}
+
+	var sim *C.sim_t
+	dlms.WithLibraryLock(func() {
+		sim = C.sim_create(cBytes(title), cBytes(blockKey), cBytes(authKey),
+			C.int(config.ClientAddress), C.int(config.ServerAddress))
+		if sim == nil {
+			err = errors.New("creating simulated meter: out of memory")
+			return
+		}
+		if err = populate(sim, config); err != nil {
+			C.sim_free(sim)
+		}
+	})
+	return sim, err
+}
+
+// populate adds the objects of config to sim, under the Gurux lock
+func populate(sim *C.sim_t, config Config) error {
+	clock := mustOBIS(dlms.ClockOBIS)
+	if ret := C.sim_add_clock(sim, cBytes(clock), C.int16_t(config.TimeZone)); ret != 0 {
+		return dlmsError("adding clock", ret)
+	}
+	if config.LogicalDeviceName != "" {
+		config.Data = append([]Data{{OBIS: dlms.LogicalDeviceNameOBIS, Value: config.LogicalDeviceName}}, config.Data...)
+	}
+
+	for _, d := range config.Data {
+		ln, err := parseOBIS(d.OBIS)
+		if err != nil {
+			return err
+		}
+		var ret C.int
+		switch v := d.Value.(type) {
+		case string:
+			s := C.CString(v)
+			ret = C.sim_add_data_string(sim, cBytes(ln), s)
+			C.free(unsafe.Pointer(s))
+		case uint8:
+			ret = C.sim_add_data_uint(sim, cBytes(ln), C.DLMS_DATA_TYPE_UINT8, C.uint32_t(v))
+		case uint16:
+			ret = C.sim_add_data_uint(sim, cBytes(ln), C.DLMS_DATA_TYPE_UINT16, C.uint32_t(v))
+		case uint32:
+			ret = C.sim_add_data_uint(sim, cBytes(ln), C.DLMS_DATA_TYPE_UINT32, C.uint32_t(v))
+		default:
+			return fmt.Errorf("data %s: unsupported value type %T", d.OBIS, d.Value)
+		}
+		if ret != 0 {
+			return dlmsError("adding data "+d.OBIS, ret)
+		}
+	}
+
+	for _, r := range config.Registers {
+		ln, err := parseOBIS(r.OBIS)
+		if err != nil {
+			return err
+		}
+		if ret := C.sim_add_register(sim, cBytes(ln), C.double(r.Value), C.schar(r.Scaler), C.uchar(r.Unit)); ret != 0 {
+			return dlmsError("adding register "+r.OBIS, ret)
+		}
+	}
+
+	if config.DisconnectControl != "" {
+		ln, err := parseOBIS(config.DisconnectControl)
+		if err != nil {
+			return err
+		}
+		if ret := C.sim_add_disconnect_control(sim, cBytes(ln), 1); ret != 0 {
+			return dlmsError("adding disconnect control", ret)
+		}
+	}
+
+	for _, o := range []struct {
+		obis string
+		name string
+		add  func(*C.sim_t, *C.uchar) C.int
+	}{
+		{config.ScriptTable, "script table", func(sim *C.sim_t, ln *C.uchar) C.int { return C.sim_add_script_table(sim, ln) }},
+		{config.ActivityCalendar, "activity calendar", func(sim *C.sim_t, ln *C.uchar) C.int { return C.sim_add_activity_calendar(sim, ln) }},
+		{config.SpecialDaysTable, "special days table", func(sim *C.sim_t, ln *C.uchar) C.int { return C.sim_add_special_days_table(sim, ln) }},
+	} {
+		if o.obis == "" {
+			continue
+		}
+		ln, err := parseOBIS(o.obis)
+		if err != nil {
+			return err
+		}
+		if ret := o.add(sim, cBytes(ln)); ret != 0 {
+			return dlmsError("adding "+o.name, ret)
+		}
+	}
+
+	for _, p := range config.Profiles {
+		if err := addProfile(sim, p); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// addProfile adds p with its columns and rows to sim
+func addProfile(sim *C.sim_t, p Profile) error {
+	ln, err := parseOBIS(p.OBIS)
+	if err != nil {
+		return err
+	}
+	if ret := C.sim_add_profile(sim, cBytes(ln), C.uint32_t(p.CapturePeriod)); ret != 0 {
+		return dlmsError("adding profile "+p.OBIS, ret)
+	}
+
+	for _, c := range p.Columns {
+		column, err := parseOBIS(c.OBIS)
+		if err != nil {
+			return err
+		}
+		if ret := C.sim_profile_add_column(sim, cBytes(ln), C.int(c.ClassID), cBytes(column), C.int(c.Attribute)); ret != 0 {
+			return dlmsError(fmt.Sprintf("adding column %s to profile %s", c.OBIS, p.OBIS), ret)
+		}
+	}
+
+	for i, values := range p.Rows {
+		if len(values) != len(p.Columns) {
+			return fmt.Errorf("profile %s row %d: %d values for %d columns", p.OBIS, i, len(values), len(p.Columns))
+		}
+		row := C.sim_row_new()
+		if row == nil {
+			return errors.New("creating profile row: out of memory")
+		}
+		for _, value := range values {
+			if err := addRowValue(row, value); err != nil {
+				C.sim_row_free(row)
+				return fmt.Errorf("profile %s row %d: %w", p.OBIS, i, err)
+			}
+		}
+		if ret := C.sim_profile_add_row(sim, cBytes(ln), row); ret != 0 {
+			return dlmsError(fmt.Sprintf("adding row %d to profile %s", i, p.OBIS), ret)
+		}
+	}
+	return nil
+}
+
+// addRowValue appends value to row
+func addRowValue(row *C.sim_row_t, value any) error {
+	var ret C.int
+	switch v := value.(type) {
+	case float64:
+		ret = C.sim_row_add_double(row, C.double(v))
+	case uint8:
+		ret = C.sim_row_add_uint(row, C.DLMS_DATA_TYPE_UINT8, C.uint32_t(v))
+	case uint16:
+		ret = C.sim_row_add_uint(row, C.DLMS_DATA_TYPE_UINT16, C.uint32_t(v))
+	case uint32:
+		ret = C.sim_row_add_uint(row, C.DLMS_DATA_TYPE_UINT32, C.uint32_t(v))
+	case time.Time:
+		ret = C.sim_row_add_datetime(row, C.int64_t(v.Unix()))
+	case string:
+		s := C.CString(v)
+		ret = C.sim_row_add_string(row, s)
+		C.free(unsafe.Pointer(s))
+	default:
+		return fmt.Errorf("unsupported value type %T", value)
+	}
+	if ret != 0 {
+		return dlmsError("adding value", ret)
+	}
+	return nil
+}
+
+// Addr is the address the meter listens on
+func (m *Meter) Addr() *net.TCPAddr {
+	return m.listener.Addr().(*net.TCPAddr)
+}
+
+// RealMeter returns the processor's configuration for reaching the meter
+func (m *Meter) RealMeter() dlms.RealMeter {
+	addr := m.Addr()
+	return dlms.RealMeter{
+		LogicalDeviceName: m.config.LogicalDeviceName,
+		MeterIP:           addr.IP.String(),
+		MeterPort:         addr.Port,
+		ConnectionTimeout: 5000,
+		SystemTitle:       clientSystemTitle,
+		BlockCipherKey:    m.config.BlockCipherKey,
+		AuthenticationKey: m.config.AuthenticationKey,
+		ClientAddress:     m.config.ClientAddress,
+		ServerAddress:     m.config.ServerAddress,
+	}
+}
+
+// DisconnectState reports the output state of the disconnect control and its
+// control state: 0 disconnected, 1 connected, 2 ready for reconnection
+func (m *Meter) DisconnectState() (connected bool, controlState int, err error) {
+	ln, err := parseOBIS(m.config.DisconnectControl)
+	if err != nil {
+		return false, 0, err
+	}
+	var output, control C.int
+	var ret C.int
+	dlms.WithLibraryLock(func() {
+		ret = C.sim_disconnect_state(m.sim, cBytes(ln), &output, &control)
+	})
+	if ret != 0 {
+		return false, 0, dlmsError("reading disconnect control", ret)
+	}
+	return output != 0, int(control), nil
+}
+
+// Close stops the meter, dropping its connections
+func (m *Meter) Close() error {
+	err := m.listener.Close()
+	m.mu.Lock()
+	for conn := range m.conns {
+		conn.Close()
+	}
+	m.mu.Unlock()
+	m.wg.Wait()
+
+	dlms.WithLibraryLock(func() { C.sim_free(m.sim) })
+	m.sim = nil
+	return err
+}
+
+// serve accepts connections until the listener is closed
+func (m *Meter) serve() {
+	defer m.wg.Done()
+	for {
+		conn, err := m.listener.Accept()
+		if err != nil {
+			if !errors.Is(err, net.ErrClosed) {
+				slog.Error("Simulated meter stopped accepting", "addr", m.Addr(), "error", err)
+			}
+			return
+		}
+
+		m.mu.Lock()
+		m.conns[conn] = struct{}{}
+		m.mu.Unlock()
+		m.wg.Add(1)
+		go m.handle(conn)
+	}
+}
+
+// handle runs one client connection through its own server session
+func (m *Meter) handle(conn net.Conn) {
+	defer m.wg.Done()
+	defer func() {
+		conn.Close()
+		m.mu.Lock()
+		delete(m.conns, conn)
+		m.mu.Unlock()
+	}()
+
+	var session *C.sim_session_t
+	dlms.WithLibraryLock(func() { session = C.sim_session_open(m.sim) })
+	if session == nil {
+		slog.Error("Failed to open simulated meter session", "addr", m.Addr())
+		return
+	}
+	defer dlms.WithLibraryLock(func() { C.sim_session_close(session) })
+
+	buf := make([]byte, 2048)
+	for {
+		n, err := conn.Read(buf)
+		if err != nil {
+			return
+		}
+
+		var reply []byte
+		var ret C.int
+		dlms.WithLibraryLock(func() {
+			ret = C.sim_session_handle(session, (*C.uchar)(unsafe.Pointer(&buf[0])), C.int(n))
+			var size C.int
+			data := C.sim_session_reply(session, &size)
+			if size > 0 {
+				reply = C.GoBytes(unsafe.Pointer(data), size)
+			}
+		})
+		if ret != 0 {
+			slog.Debug("Simulated meter rejected a request", "addr", m.Addr(), "error", dlmsError("handling request", ret))
+		}
+		if len(reply) > 0 {
+			if _, err := conn.Write(reply); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// parseOBIS parses an OBIS code such as "0.0.1.0.0.255" into a logical name
+func parseOBIS(obis string) ([]byte, error) {
+	parts := strings.Split(strings.TrimSpace(obis), ".")
+	if len(parts) != 6 {
+		return nil, fmt.Errorf("invalid OBIS code %q", obis)
+	}
+	ln := make([]byte, 6)
+	for i, part := range parts {
+		v, err := strconv.ParseUint(part, 10, 8)
+		if err != nil {
+			return nil, fmt.Errorf("invalid OBIS code %q", obis)
+		}
+		ln[i] = byte(v)
+	}
+	return ln, nil
+}
+
+func mustOBIS(obis string) []byte {
+	ln, err := parseOBIS(obis)
+	if err != nil {
+		panic(err)
+	}
+	return ln
+}
+
+// hexBytes decodes the hex value of a field that must be size bytes long
+func hexBytes(field, value string, size int) ([]byte, error) {
+	b, err := hex.DecodeString(value)
+	if err != nil || len(b) != size {
+		return nil, fmt.Errorf("%s must be %d bytes in hex", field, size)
+	}
+	return b, nil
+}
+
+// cBytes passes b to C, which copies it before returning
+func cBytes(b []byte) *C.uchar {
+	return (*C.uchar)(unsafe.Pointer(&b[0]))
+}
+
+func dlmsError(op string, ret C.int) error {
+	return fmt.Errorf("%s: DLMS error %d", op, int(ret))
+}
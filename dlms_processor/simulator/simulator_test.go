@@ -0,0 +1,394 @@
+package simulator
+
+import (
+	"context"
+	"dlmsprocessor/dlms"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// startDefault starts the default meter on a local port for the test
+func startDefault(t *testing.T) (*Meter, *dlms.RealMeter) {
+	t.Helper()
+	m, err := Start(DefaultConfig(time.Now()), "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	t.Cleanup(func() { m.Close() })
+
+	config := m.RealMeter()
+	meter, err := dlms.NewRealMeter(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := meter.Connect(context.Background()); err != nil {
+		t.Fatalf("Connect: %v", err)
+	}
+	t.Cleanup(func() { meter.Close() })
+	return m, meter
+}
+
+func TestReadsProfiles(t *testing.T) {
+	_, meter := startDefault(t)
+	ctx := context.Background()
+
+	block, err := meter.GetBlockLoadProfile(ctx)
+	if err != nil {
+		t.Fatalf("GetBlockLoadProfile: %v", err)
+	}
+	if block.AverageVoltage != 229.8 || block.BlockEnergyWhImport != 480 || block.DateTime == "" {
+		t.Errorf("Unexpected block load entry %+v", block)
+	}
+
+	daily, err := meter.GetDailyLoadProfile(ctx)
+	if err != nil {
+		t.Fatalf("GetDailyLoadProfile: %v", err)
+	}
+	if daily.CumulativeEnergyWhImport != 11865.6 {
+		t.Errorf("Unexpected daily load entry %+v", daily)
+	}
+
+	billing, err := meter.GetBillingDataProfile(ctx)
+	if err != nil {
+		t.Fatalf("GetBillingDataProfile: %v", err)
+	}
+	if billing.CumEnergyWhImport != 11000 || billing.CumEnergyWhTZ4 != 2750 || billing.AveragePFForBillingPeriod != 0.97 {
+		t.Errorf("Unexpected billing entry %+v", billing)
+	}
+	// Maximum demand and its capture time are attributes 2 and 5 of one register
+	demandAt := time.Now().UTC().Truncate(24*time.Hour).AddDate(0, -1, 9).Add(19 * time.Hour).Format("01/02/2006 15:04:05 UTC-07:00")
+	if billing.MDW != 2400 || billing.MDWDateTime != demandAt || billing.MDVA != 2480 || billing.MDVADateTime != demandAt {
+		t.Errorf("Expected maximum demand captured at %s, got %+v", demandAt, billing)
+	}
+	if len(billing.TariffZones) != 4 || billing.TariffZones[3].CumEnergyWh != billing.CumEnergyWhTZ4 {
+		t.Errorf("Expected the four tariff zones, got %+v", billing.TariffZones)
+	}
+
+	instant, err := meter.GetInstantaneousProfile(ctx)
+	if err != nil {
+		t.Fatalf("GetInstantaneousProfile: %v", err)
+	}
+	if instant.Voltage != 230.1 || instant.Frequency != 50 || instant.CumEnergyWh != 12345.6 {
+		t.Errorf("Unexpected instantaneous entry %+v", instant)
+	}
+}
+
+func TestReadsObjects(t *testing.T) {
+	_, meter := startDefault(t)
+	ctx := context.Background()
+
+	ldn, err := meter.ReadAttribute(ctx, dlms.LogicalDeviceNameOBIS, dlms.ObjectTypeData, 2)
+	if err != nil {
+		t.Fatalf("ReadAttribute: %v", err)
+	}
+	if !strings.Contains(ldn, "53494D") && !strings.Contains(ldn, "SIM") {
+		t.Errorf("Expected the logical device name, got %q", ldn)
+	}
+
+	register, err := meter.ReadAttribute(ctx, "1.0.12.7.0.255", 3, 2)
+	if err != nil {
+		t.Fatalf("ReadAttribute: %v", err)
+	}
+	if v, err := strconv.ParseFloat(register, 64); err != nil || v != 230.1 {
+		t.Errorf("Expected the voltage register, got %q", register)
+	}
+
+	if _, err := meter.ReadAttribute(ctx, "1.0.99.99.0.255", 3, 2); err == nil {
+		t.Error("Expected an object missing from the model to fail the read")
+	}
+}
+
+func TestReadsEvents(t *testing.T) {
+	m, _ := startDefault(t)
+	config := m.RealMeter()
+	client := dlms.NewMeterClient()
+	defer client.Close()
+	if err := client.Configure(&config); err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	if err := client.Connect(ctx); err != nil {
+		t.Fatalf("Connect: %v", err)
+	}
+
+	type event struct {
+		Time string `obis:"0.0.1.0.0.255" type:"string"`
+		Code uint16 `obis:"0.0.96.11.0.255" type:"uint16"`
+	}
+	events, err := dlms.ReadProfileDataTyped[event](ctx, client, EventLogOBIS, 1, 2)
+	if err != nil {
+		t.Fatalf("ReadProfileDataTyped: %v", err)
+	}
+	if len(events) != 2 || events[0].Code != 101 || events[1].Code != 102 || events[0].Time == "" {
+		t.Errorf("Expected the power failure and restore events, got %+v", events)
+	}
+}
+
+func TestChecksLogicalDeviceName(t *testing.T) {
+	m, err := Start(DefaultConfig(time.Now()), "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer m.Close()
+
+	config := m.RealMeter()
+	config.LogicalDeviceName = "OTHER"
+	meter, _ := dlms.NewRealMeter(config)
+	if err := meter.Connect(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	defer meter.Close()
+	if _, err := meter.GetInstantaneousProfile(context.Background()); err == nil {
+		t.Error("Expected a logical device name mismatch")
+	}
+}
+
+func TestRejectsWrongKey(t *testing.T) {
+	m, err := Start(DefaultConfig(time.Now()), "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer m.Close()
+
+	config := m.RealMeter()
+	config.ConnectionTimeout = 500
+	config.AuthenticationKey = "63636363636363636363636363636363"
+	config.BlockCipherKey = config.AuthenticationKey
+	meter, _ := dlms.NewRealMeter(config)
+	if err := meter.Connect(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	defer meter.Close()
+	if _, err := meter.GetInstantaneousProfile(context.Background()); err == nil {
+		t.Error("Expected a client with the wrong keys to be refused")
+	}
+}
+
+func TestDisconnectState(t *testing.T) {
+	m, _ := startDefault(t)
+	connected, state, err := m.DisconnectState()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !connected || state != 1 {
+		t.Errorf("Expected a connected disconnect control, got %v in state %d", connected, state)
+	}
+}
+
+func TestStartRejectsBadConfig(t *testing.T) {
+	config := DefaultConfig(time.Now())
+	config.Profiles[0].Rows[0] = config.Profiles[0].Rows[0][1:]
+	if _, err := Start(config, "127.0.0.1:0"); err == nil {
+		t.Error("Expected a row short of a value to be refused")
+	}
+
+	config = DefaultConfig(time.Now())
+	config.BlockCipherKey = "62"
+	if _, err := Start(config, "127.0.0.1:0"); err == nil {
+		t.Error("Expected a short key to be refused")
+	}
+}
+
+func TestReadsTariffZones(t *testing.T) {
+	now := time.Now()
+	m, err := Start(WithTariffZones(DefaultConfig(now), now, 8), "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	defer m.Close()
+	meter, err := dlms.NewRealMeter(m.RealMeter())
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	if err := meter.Connect(ctx); err != nil {
+		t.Fatalf("Connect: %v", err)
+	}
+	defer meter.Close()
+
+	billing, err := meter.GetBillingDataProfile(ctx)
+	if err != nil {
+		t.Fatalf("GetBillingDataProfile: %v", err)
+	}
+	if len(billing.TariffZones) != 8 || billing.CumEnergyWhImport != 18000 {
+		t.Fatalf("Expected eight tariff zones, got %+v", billing)
+	}
+	month := now.UTC().Truncate(24*time.Hour).AddDate(0, -1, 0)
+	for i, zone := range billing.TariffZones {
+		demandAt := month.AddDate(0, 0, i).Add(19 * time.Hour).Format("01/02/2006 15:04:05 UTC-07:00")
+		if zone.Zone != i+1 || zone.CumEnergyWh != 500*float64(i+1) || zone.CumEnergyVAh != 550*float64(i+1) ||
+			zone.MDW != 2000+50*float64(i+1) || zone.MDWDateTime != demandAt || zone.MDVA != 2060+50*float64(i+1) || zone.MDVADateTime != demandAt {
+			t.Errorf("Unexpected tariff zone %+v, maximum demand expected at %s", zone, demandAt)
+		}
+	}
+	if billing.CumEnergyWhTZ2 != 1000 || billing.MDWDateTime != billing.TariffZones[7].MDWDateTime {
+		t.Errorf("Expected the fixed fields too, got %+v", billing)
+	}
+}
+
+func TestReadsThreePhaseProfiles(t *testing.T) {
+	m, err := Start(ThreePhaseConfig(time.Now()), "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	defer m.Close()
+	meter, err := dlms.NewRealMeter(m.RealMeter())
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	if err := meter.Connect(ctx); err != nil {
+		t.Fatalf("Connect: %v", err)
+	}
+	defer meter.Close()
+
+	instant, err := meter.GetInstantaneousProfile(ctx)
+	if err != nil {
+		t.Fatalf("GetInstantaneousProfile: %v", err)
+	}
+	phases := instant.ThreePhase
+	if phases == nil {
+		t.Fatalf("Expected a three-phase instantaneous entry, got %+v", instant)
+	}
+	if phases.VoltageY != 229.8 || phases.CurrentB != 10.8 || phases.PowerFactorR != 0.98 || phases.ReactivePowerY != 560 ||
+		phases.CumEnergyVarhLag != 210345 || phases.CumEnergyVarhLead != 4120 || instant.Frequency != 50 || instant.Voltage != 0 {
+		t.Errorf("Unexpected three-phase instantaneous entry %+v", phases)
+	}
+
+	block, err := meter.GetBlockLoadProfile(ctx)
+	if err != nil {
+		t.Fatalf("GetBlockLoadProfile: %v", err)
+	}
+	if block.ThreePhase == nil || block.ThreePhase.VoltageR != 231.2 || block.ThreePhase.BlockEnergyVarhLag != 620 || block.BlockEnergyWhImport != 3500 {
+		t.Errorf("Unexpected three-phase block load entry %+v", block.ThreePhase)
+	}
+
+	// Single-phase meters keep the single-phase model only
+	_, single := startDefault(t)
+	if instant, err := single.GetInstantaneousProfile(ctx); err != nil || instant.ThreePhase != nil {
+		t.Errorf("Expected a single-phase instantaneous entry, got %+v, %v", instant, err)
+	}
+}
+
+func TestReadsNameplate(t *testing.T) {
+	want := dlms.NameplateProfile{
+		SerialNumber:      "SIM00001",
+		ManufacturerName:  "SIMULATED",
+		FirmwareVersion:   "SIM-1.0.0",
+		MeterType:         5,
+		Category:          "C1",
+		CurrentRating:     "5-30A",
+		YearOfManufacture: 2024,
+	}
+	ctx := context.Background()
+
+	_, meter := startDefault(t)
+	nameplate, err := meter.GetNameplate(ctx)
+	if err != nil {
+		t.Fatalf("GetNameplate: %v", err)
+	}
+	if *nameplate != want {
+		t.Errorf("Expected nameplate %+v, got %+v", want, *nameplate)
+	}
+
+	// Without the nameplate profile its objects are read one by one
+	config := DefaultConfig(time.Now())
+	config.Profiles = slices.DeleteFunc(config.Profiles, func(p Profile) bool { return p.OBIS == dlms.NameplateProfileOBIS })
+	m, err := Start(config, "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	defer m.Close()
+	objects, err := dlms.NewRealMeter(m.RealMeter())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := objects.Connect(ctx); err != nil {
+		t.Fatalf("Connect: %v", err)
+	}
+	defer objects.Close()
+	nameplate, err = objects.GetNameplate(ctx)
+	if err != nil {
+		t.Fatalf("GetNameplate without the profile: %v", err)
+	}
+	if *nameplate != want {
+		t.Errorf("Expected nameplate %+v from its objects, got %+v", want, *nameplate)
+	}
+}
+
+func TestProgramsTariff(t *testing.T) {
+	_, meter := startDefault(t)
+	ctx := context.Background()
+
+	tariff, err := meter.ReadTariff(ctx)
+	if err != nil {
+		t.Fatalf("ReadTariff: %v", err)
+	}
+	if tariff.Active.Name != "" || len(tariff.SpecialDays) != 0 || !tariff.ActivationTime.IsZero() {
+		t.Errorf("Expected an empty tariff, got %+v", tariff)
+	}
+
+	peak := []dlms.DayAction{
+		{Start: 0, Script: dlms.TariffScriptTableOBIS, Selector: 1},
+		{Start: 18 * time.Hour, Script: dlms.TariffScriptTableOBIS, Selector: 2},
+		{Start: 22*time.Hour + 30*time.Minute, Script: dlms.TariffScriptTableOBIS, Selector: 1},
+	}
+	program := dlms.TariffProgram{
+		Calendar: dlms.Calendar{
+			Name: "TOU-A",
+			Seasons: []dlms.Season{
+				{Name: "SUMMER", Start: dlms.CalendarDate{Month: 4, Day: 1}, WeekProfile: "WEEK"},
+				{Name: "WINTER", Start: dlms.CalendarDate{Month: 10, Day: 1}, WeekProfile: "WEEK"},
+			},
+			WeekProfiles: []dlms.WeekProfile{{Name: "WEEK", Days: [7]uint8{1, 1, 1, 1, 1, 2, 2}}},
+			DayProfiles: []dlms.DayProfile{
+				{ID: 1, Actions: peak},
+				{ID: 2, Actions: peak[:1]},
+			},
+		},
+		SpecialDays: []dlms.SpecialDay{
+			{Index: 1, Date: dlms.CalendarDate{Month: 1, Day: 26}, DayID: 2},
+			{Index: 2, Date: dlms.CalendarDate{Year: 2026, Month: 11, Day: 8}, DayID: 2},
+		},
+	}
+	tariff, err = meter.ProgramTariff(ctx, program)
+	if err != nil {
+		t.Fatalf("ProgramTariff: %v", err)
+	}
+	if !tariff.Active.Equal(program.Calendar) || len(tariff.SpecialDays) != 2 {
+		t.Errorf("Expected the calendar active at once, got %+v", tariff)
+	}
+
+	// A second program waits for its activation time, and leaves the
+	// special days alone but for those it replaces
+	next := program
+	next.Calendar.Name = "TOU-B"
+	next.ActivationTime = time.Date(2027, 4, 1, 0, 0, 0, 0, time.UTC)
+	next.SpecialDays = program.SpecialDays[1:]
+	tariff, err = meter.ProgramTariff(ctx, next)
+	if err != nil {
+		t.Fatalf("ProgramTariff: %v", err)
+	}
+	if tariff.Active.Name != "TOU-A" || tariff.Passive.Name != "TOU-B" || !tariff.ActivationTime.Equal(next.ActivationTime) {
+		t.Errorf("Expected TOU-B to activate at %s, got %+v", next.ActivationTime, tariff)
+	}
+	if !slices.Equal(tariff.SpecialDays, next.SpecialDays) {
+		t.Errorf("Expected special days %+v, got %+v", next.SpecialDays, tariff.SpecialDays)
+	}
+
+	// Activating it at once copies the passive calendar, which writing the
+	// passive calendar again leaves alone
+	next.ActivationTime = time.Time{}
+	for _, name := range []string{"TOU-C", "TOU-D"} {
+		next.Calendar.Name = name
+		if tariff, err = meter.ProgramTariff(ctx, next); err != nil {
+			t.Fatalf("ProgramTariff %s: %v", name, err)
+		}
+		if !tariff.Active.Equal(next.Calendar) {
+			t.Errorf("Expected %s active, got %+v", name, tariff.Active)
+		}
+	}
+}
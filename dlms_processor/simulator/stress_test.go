@@ -0,0 +1,119 @@
+package simulator
+
+import (
+	"context"
+	"dlmsprocessor/dlms"
+	"fmt"
+	"math/rand/v2"
+	"sync"
+	"testing"
+	"time"
+)
+
+// TestConcurrentSessionsStress is the successful counterpart of the dlms
+// package's stress test: many sessions at once complete ciphered
+// associations with simulated meters and read from them, some on their own
+// client and some sharing pooled sessions. Run it with -race to check the
+// concurrency contract on the paths that decode real replies.
+func TestConcurrentSessionsStress(t *testing.T) {
+	sessions := 200
+	if testing.Short() {
+		sessions = 40
+	}
+
+	var meters [4]*Meter
+	for i := range meters {
+		m, err := Start(DefaultConfig(time.Now()), "127.0.0.1:0")
+		if err != nil {
+			t.Fatalf("Start: %v", err)
+		}
+		t.Cleanup(func() { m.Close() })
+		meters[i] = m
+	}
+
+	pool := dlms.NewSessionPool(dlms.SessionOptions{})
+	defer pool.Close()
+
+	var wg sync.WaitGroup
+	errs := make(chan error, sessions)
+	start := time.Now()
+
+	for i := range sessions {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			config := meters[i%len(meters)].RealMeter()
+			config.ConnectionTimeout = 20000
+
+			// Pooled meters share one session per simulated meter, so their
+			// operations are serialized on it; the others associate on their own
+			var meter *dlms.RealMeter
+			var err error
+			if i%2 == 0 {
+				meter, err = pool.Meter(config)
+			} else {
+				meter, err = dlms.NewRealMeter(config)
+			}
+			if err != nil {
+				errs <- err
+				return
+			}
+			if err := meter.Connect(context.Background()); err != nil {
+				errs <- err
+				return
+			}
+			defer meter.Close()
+
+			for range 3 {
+				ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+				err := readAndCheck(ctx, meter, rand.IntN(3))
+				cancel()
+				if err != nil {
+					errs <- fmt.Errorf("session %d: %w", i, err)
+					return
+				}
+			}
+		}()
+	}
+
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		t.Errorf("Session failed: %v", err)
+	}
+	t.Logf("%d sessions in %v", sessions, time.Since(start))
+}
+
+// readAndCheck runs one kind of read against the default meter and checks
+// the value it returns
+func readAndCheck(ctx context.Context, meter *dlms.RealMeter, kind int) error {
+	switch kind {
+	case 0:
+		instant, err := meter.GetInstantaneousProfile(ctx)
+		if err != nil {
+			return fmt.Errorf("GetInstantaneousProfile: %w", err)
+		}
+		if instant.Voltage != 230.1 || instant.CumEnergyWh != 12345.6 {
+			return fmt.Errorf("unexpected instantaneous entry %+v", instant)
+		}
+	case 1:
+		block, err := meter.GetBlockLoadProfile(ctx)
+		if err != nil {
+			return fmt.Errorf("GetBlockLoadProfile: %w", err)
+		}
+		if block.AverageVoltage != 229.8 || block.BlockEnergyWhImport != 480 {
+			return fmt.Errorf("unexpected block load entry %+v", block)
+		}
+	default:
+		billing, err := meter.GetBillingDataProfile(ctx)
+		if err != nil {
+			return fmt.Errorf("GetBillingDataProfile: %w", err)
+		}
+		if billing.CumEnergyWhImport != 11000 || len(billing.TariffZones) != 4 {
+			return fmt.Errorf("unexpected billing entry %+v", billing)
+		}
+	}
+	return nil
+}
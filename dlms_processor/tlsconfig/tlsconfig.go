@@ -0,0 +1,182 @@
+// Package tlsconfig serves TLS certificates that can be replaced on disk
+// while the server keeps running, so rotated certificates are picked up
+// without a restart.
+package tlsconfig
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Reloader holds the server certificate and, for mutual TLS, the CAs that
+// client certificates are verified against
+type Reloader struct {
+	certFile     string
+	keyFile      string
+	clientCAFile string
+
+	mu        sync.RWMutex
+	cert      *tls.Certificate
+	clientCAs *x509.CertPool
+	stamp     string // identifies the file versions last loaded
+}
+
+// NewReloader loads the certificate, key and optional client CA bundle
+func NewReloader(certFile, keyFile, clientCAFile string) (*Reloader, error) {
+	r := &Reloader{
+		certFile:     certFile,
+		keyFile:      keyFile,
+		clientCAFile: clientCAFile,
+	}
+	if err := r.Reload(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Reload reads the files again. On failure the previous certificate stays
+// in use.
+func (r *Reloader) Reload() error {
+	stamp, err := r.fileStamp()
+	if err != nil {
+		return err
+	}
+
+	cert, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+	if err != nil {
+		return fmt.Errorf("failed to load certificate: %w", err)
+	}
+
+	var clientCAs *x509.CertPool
+	if r.clientCAFile != "" {
+		pem, err := os.ReadFile(r.clientCAFile)
+		if err != nil {
+			return fmt.Errorf("failed to read client CA file: %w", err)
+		}
+		clientCAs = x509.NewCertPool()
+		if !clientCAs.AppendCertsFromPEM(pem) {
+			return fmt.Errorf("no certificates found in client CA file %s", r.clientCAFile)
+		}
+	}
+
+	r.mu.Lock()
+	r.cert = &cert
+	r.clientCAs = clientCAs
+	r.stamp = stamp
+	r.mu.Unlock()
+
+	return nil
+}
+
+// ReloadIfChanged reloads when any of the files has changed since the last
+// load, and reports whether it did
+func (r *Reloader) ReloadIfChanged() (bool, error) {
+	stamp, err := r.fileStamp()
+	if err != nil {
+		return false, err
+	}
+
+	r.mu.RLock()
+	unchanged := stamp == r.stamp
+	r.mu.RUnlock()
+	if unchanged {
+		return false, nil
+	}
+
+	if err := r.Reload(); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// Watch checks the files every interval until ctx is done
+func (r *Reloader) Watch(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			reloaded, err := r.ReloadIfChanged()
+			if err != nil {
+				slog.Error("Failed to reload TLS certificates", "error", err)
+			} else if reloaded {
+				slog.Info("Reloaded TLS certificates", "cert_file", r.certFile)
+			}
+		}
+	}
+}
+
+// fileStamp summarises the size and modification time of the files
+func (r *Reloader) fileStamp() (string, error) {
+	var b strings.Builder
+	for _, name := range []string{r.certFile, r.keyFile, r.clientCAFile} {
+		if name == "" {
+			continue
+		}
+		info, err := os.Stat(name)
+		if err != nil {
+			return "", err
+		}
+		fmt.Fprintf(&b, "%s:%d:%d;", name, info.Size(), info.ModTime().UnixNano())
+	}
+	return b.String(), nil
+}
+
+// ServerConfig returns a server TLS config that always presents the most
+// recently loaded certificate and verifies clients as clientAuth says. It
+// negotiates the ALPN protocols given, h2 only by default as gRPC needs.
+func (r *Reloader) ServerConfig(clientAuth tls.ClientAuthType, nextProtos ...string) *tls.Config {
+	if len(nextProtos) == 0 {
+		nextProtos = []string{"h2"}
+	}
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			r.mu.RLock()
+			defer r.mu.RUnlock()
+			return &tls.Config{
+				MinVersion:   tls.VersionTLS12,
+				Certificates: []tls.Certificate{*r.cert},
+				ClientAuth:   clientAuth,
+				ClientCAs:    r.clientCAs,
+				// gRPC requires ALPN, and a per-handshake config replaces the
+				// protocols gRPC would otherwise have set
+				NextProtos: nextProtos,
+			}, nil
+		},
+	}
+}
+
+// ParseClientAuth maps a client_auth setting onto a tls.ClientAuthType. An
+// empty setting requires client certificates whenever a client CA is set.
+func ParseClientAuth(s string, haveClientCA bool) (tls.ClientAuthType, error) {
+	switch s {
+	case "":
+		if haveClientCA {
+			return tls.RequireAndVerifyClientCert, nil
+		}
+		return tls.NoClientCert, nil
+	case "none":
+		return tls.NoClientCert, nil
+	case "optional", "require":
+		if !haveClientCA {
+			return 0, errors.New("client certificate verification needs a client CA file")
+		}
+		if s == "optional" {
+			return tls.VerifyClientCertIfGiven, nil
+		}
+		return tls.RequireAndVerifyClientCert, nil
+	}
+	return 0, fmt.Errorf("invalid client auth %q, want none, optional or require", s)
+}
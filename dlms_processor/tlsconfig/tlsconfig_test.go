@@ -0,0 +1,259 @@
+package tlsconfig
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/peer"
+)
+
+// testCA issues certificates for the tests
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	pem  []byte
+}
+
+func newTestCA(t *testing.T) *testCA {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &testCA{cert: cert, key: key, pem: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})}
+}
+
+// issue returns a PEM certificate and key signed by the CA
+func (ca *testCA) issue(t *testing.T, serial int64, usage x509.ExtKeyUsage) (certPEM, keyPEM []byte) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      pkix.Name{CommonName: "localhost"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{usage},
+		DNSNames:     []string{"localhost"},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, ca.cert, &key.PublicKey, ca.key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+}
+
+func writeFile(t *testing.T, path string, data []byte, modTime time.Time) {
+	t.Helper()
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	// Distinct modification times, so a rewrite within the same clock tick is still seen
+	if err := os.Chtimes(path, modTime, modTime); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// serve starts a health-only gRPC server with the reloader's credentials
+func serve(t *testing.T, r *Reloader, clientAuth tls.ClientAuthType) string {
+	t.Helper()
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := grpc.NewServer(grpc.Creds(credentials.NewTLS(r.ServerConfig(clientAuth))))
+	healthpb.RegisterHealthServer(s, health.NewServer())
+	go s.Serve(lis)
+	t.Cleanup(s.Stop)
+	return lis.Addr().String()
+}
+
+// check calls the health service and returns the serial of the server
+// certificate it was served with
+func check(addr string, ca *testCA, clientCert *tls.Certificate) (int64, error) {
+	roots := x509.NewCertPool()
+	roots.AppendCertsFromPEM(ca.pem)
+	cfg := &tls.Config{RootCAs: roots, ServerName: "localhost"}
+	if clientCert != nil {
+		cfg.Certificates = []tls.Certificate{*clientCert}
+	}
+
+	conn, err := grpc.NewClient(addr, grpc.WithTransportCredentials(credentials.NewTLS(cfg)))
+	if err != nil {
+		return 0, err
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var p peer.Peer
+	if _, err := healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{}, grpc.Peer(&p)); err != nil {
+		return 0, err
+	}
+	state := p.AuthInfo.(credentials.TLSInfo).State
+	return state.PeerCertificates[0].SerialNumber.Int64(), nil
+}
+
+func TestMutualTLSAndReload(t *testing.T) {
+	ca := newTestCA(t)
+	dir := t.TempDir()
+	certFile := filepath.Join(dir, "server.crt")
+	keyFile := filepath.Join(dir, "server.key")
+	caFile := filepath.Join(dir, "ca.crt")
+
+	now := time.Now()
+	certPEM, keyPEM := ca.issue(t, 100, x509.ExtKeyUsageServerAuth)
+	writeFile(t, certFile, certPEM, now)
+	writeFile(t, keyFile, keyPEM, now)
+	writeFile(t, caFile, ca.pem, now)
+
+	clientCertPEM, clientKeyPEM := ca.issue(t, 200, x509.ExtKeyUsageClientAuth)
+	clientCert, err := tls.X509KeyPair(clientCertPEM, clientKeyPEM)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := NewReloader(certFile, keyFile, caFile)
+	if err != nil {
+		t.Fatalf("NewReloader: %v", err)
+	}
+	addr := serve(t, r, tls.RequireAndVerifyClientCert)
+
+	serial, err := check(addr, ca, &clientCert)
+	if err != nil {
+		t.Fatalf("Expected a client with a certificate to be accepted: %v", err)
+	}
+	if serial != 100 {
+		t.Errorf("Expected server certificate 100, got %d", serial)
+	}
+
+	if _, err := check(addr, ca, nil); err == nil {
+		t.Errorf("Expected a client without a certificate to be refused")
+	}
+
+	other := newTestCA(t)
+	otherCertPEM, otherKeyPEM := other.issue(t, 300, x509.ExtKeyUsageClientAuth)
+	otherCert, err := tls.X509KeyPair(otherCertPEM, otherKeyPEM)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := check(addr, ca, &otherCert); err == nil {
+		t.Errorf("Expected a client certificate from another CA to be refused")
+	}
+
+	t.Run("unchanged files are not reloaded", func(t *testing.T) {
+		reloaded, err := r.ReloadIfChanged()
+		if err != nil || reloaded {
+			t.Errorf("Expected no reload, got %v, %v", reloaded, err)
+		}
+	})
+
+	t.Run("rotated certificate is served without a restart", func(t *testing.T) {
+		later := now.Add(time.Second)
+		certPEM, keyPEM := ca.issue(t, 101, x509.ExtKeyUsageServerAuth)
+		writeFile(t, certFile, certPEM, later)
+		writeFile(t, keyFile, keyPEM, later)
+
+		reloaded, err := r.ReloadIfChanged()
+		if err != nil || !reloaded {
+			t.Fatalf("Expected a reload, got %v, %v", reloaded, err)
+		}
+
+		serial, err := check(addr, ca, &clientCert)
+		if err != nil {
+			t.Fatalf("Check after reload: %v", err)
+		}
+		if serial != 101 {
+			t.Errorf("Expected the rotated certificate 101, got %d", serial)
+		}
+	})
+
+	t.Run("broken files keep the previous certificate", func(t *testing.T) {
+		writeFile(t, keyFile, []byte("not a key"), now.Add(2*time.Second))
+
+		if _, err := r.ReloadIfChanged(); err == nil {
+			t.Fatalf("Expected the reload to fail")
+		}
+
+		serial, err := check(addr, ca, &clientCert)
+		if err != nil {
+			t.Fatalf("Check after failed reload: %v", err)
+		}
+		if serial != 101 {
+			t.Errorf("Expected certificate 101 to stay in use, got %d", serial)
+		}
+	})
+}
+
+func TestParseClientAuth(t *testing.T) {
+	testCases := []struct {
+		setting  string
+		haveCA   bool
+		expected tls.ClientAuthType
+		wantErr  bool
+	}{
+		{setting: "", haveCA: false, expected: tls.NoClientCert},
+		{setting: "", haveCA: true, expected: tls.RequireAndVerifyClientCert},
+		{setting: "none", haveCA: true, expected: tls.NoClientCert},
+		{setting: "optional", haveCA: true, expected: tls.VerifyClientCertIfGiven},
+		{setting: "require", haveCA: true, expected: tls.RequireAndVerifyClientCert},
+		{setting: "require", haveCA: false, wantErr: true},
+		{setting: "always", haveCA: true, wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		got, err := ParseClientAuth(tc.setting, tc.haveCA)
+		if tc.wantErr {
+			if err == nil {
+				t.Errorf("%q (CA %v): expected an error", tc.setting, tc.haveCA)
+			}
+			continue
+		}
+		if err != nil || got != tc.expected {
+			t.Errorf("%q (CA %v): expected %v, got %v, %v", tc.setting, tc.haveCA, tc.expected, got, err)
+		}
+	}
+}